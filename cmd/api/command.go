@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"todo-api/internal/config"
+)
+
+// usage describes the supported subcommands
+const usage = `Usage: todo-api [-config FILE] [command]
+
+Without a command the API server is started.
+
+Commands:
+  config print    Print the effective configuration with secrets redacted
+`
+
+// runCommand runs a command line subcommand and returns the process exit code
+func runCommand(args []string, configPath string) int {
+	switch {
+	case len(args) == 2 && args[0] == "config" && args[1] == "print":
+		return printConfig(configPath)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+}
+
+// printConfig prints the effective configuration and reports validation problems
+func printConfig(configPath string) int {
+	cfg, err := config.Read(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+
+	out, err := cfg.RedactedYAML()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to render config: %v\n", err)
+		return 1
+	}
+	fmt.Print(string(out))
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Config is not valid: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -1,54 +1,241 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 
 	"todo-api/internal/config"
-	"todo-api/internal/infrastructure/repository/postgres"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/infrastructure/audit"
+	"todo-api/internal/infrastructure/idempotency"
+	"todo-api/internal/infrastructure/metrics"
+	"todo-api/internal/infrastructure/ratelimit"
+	"todo-api/internal/infrastructure/storage"
+	"todo-api/internal/infrastructure/stream"
+	"todo-api/internal/infrastructure/tracing"
+	"todo-api/internal/infrastructure/webhook"
+	"todo-api/internal/interface/api/handler"
+	apimiddleware "todo-api/internal/interface/api/middleware"
 	"todo-api/internal/interface/api/router"
+	"todo-api/internal/util/logging"
+	"todo-api/internal/util/worker"
 )
 
 func main() {
+	// Parse command line flags
+	configPath := flag.String("config", "", "path to a YAML or TOML config file (defaults to $CONFIG_FILE)")
+	flag.Parse()
+
+	// Run a subcommand instead of the server, if one is given
+	if flag.NArg() > 0 {
+		os.Exit(runCommand(flag.Args(), *configPath))
+	}
+
 	// Initialize logger
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 	logger.SetOutput(os.Stdout)
+	logging.SetDefault(logger)
 
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(*configPath)
 	if err != nil {
 		logger.Fatalf("Failed to load config: %v", err)
 	}
 
-	// Initialize database connection
-	db, err := postgres.NewPostgresDB(cfg.Database)
+	// Initialize tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		logger.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
+	// A termination signal stops the startup or the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	// Initialize storage backend and migrate its schemas
+	store, err := storage.New(ctx, cfg.Database)
 	if err != nil {
-		logger.Fatalf("Failed to connect to database: %v", err)
+		logger.Fatalf("Failed to initialize storage: %v", err)
 	}
+	logger.WithField("driver", store.Driver).Info("Storage initialized")
 
-	// Migrate database schemas
-	if err := postgres.AutoMigrate(db); err != nil {
-		logger.Fatalf("Failed to migrate database schemas: %v", err)
+	// Initialize the audit log
+	auditor, err := audit.New(cfg.Audit, store.AuditRepo)
+	if err != nil {
+		logger.Fatalf("Failed to initialize audit log: %v", err)
+	}
+
+	// Initialize background workers
+	workers := worker.NewGroup()
+
+	// Initialize rate limiting
+	var limiter ratelimit.Store
+	if cfg.RateLimit.Enabled {
+		limiter, err = ratelimit.New(cfg.RateLimit, store.DB)
+		if err != nil {
+			logger.Fatalf("Failed to initialize rate limiting: %v", err)
+		}
+		workers.Go(func(ctx context.Context) {
+			ratelimit.RunSweeper(ctx, limiter, cfg.RateLimit.SweepInterval)
+		})
+	}
+
+	// Initialize idempotency keys
+	var keys idempotency.Store
+	if cfg.Idempotency.Enabled {
+		keys, err = idempotency.New(cfg.Idempotency, store.DB)
+		if err != nil {
+			logger.Fatalf("Failed to initialize idempotency keys: %v", err)
+		}
+		workers.Go(func(ctx context.Context) {
+			idempotency.RunSweeper(ctx, keys, cfg.Idempotency.SweepInterval)
+		})
+	}
+
+	// Initialize webhook deliveries, every instance schedules the deliveries
+	// of the todo events in the outbox
+	scheduler := usecase.NewWebhookScheduler(store.WebhookRepo, usecase.NewPermissionService(store.ProjectRepo, store.WorkspaceRepo), cfg.Webhooks.BatchSize)
+	workers.Go(func(ctx context.Context) {
+		webhook.RunScheduler(ctx, scheduler, cfg.Webhooks.BatchSize, cfg.Webhooks.PollInterval)
+	})
+	if cfg.Webhooks.Enabled {
+		sender := webhook.NewSender(cfg.Webhooks.Timeout, webhook.NewAddressPolicy(cfg.Webhooks.AllowPrivateNetworks))
+		deliverer := usecase.NewWebhookDeliverer(store.WebhookRepo, sender, usecase.WebhookDeliveryPolicy{
+			BatchSize:   cfg.Webhooks.BatchSize,
+			Timeout:     cfg.Webhooks.Timeout,
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			Backoff:     cfg.Webhooks.RetryBackoff,
+			MaxBackoff:  cfg.Webhooks.MaxRetryBackoff,
+		})
+		workers.Go(func(ctx context.Context) {
+			webhook.RunDeliverer(ctx, deliverer, cfg.Webhooks.BatchSize, cfg.Webhooks.PollInterval)
+		})
+	}
+
+	// Initialize real-time todo event streams, Postgres relays the events
+	// between instances while other drivers only serve this one
+	hub := stream.NewHub(cfg.Stream.HistorySize)
+	var streamEvents usecase.TodoEventPublisher = hub
+	if store.Driver == config.DriverPostgres {
+		streamEvents = stream.NewPostgresPublisher(store.DB)
+		workers.Go(func(ctx context.Context) {
+			stream.Listen(ctx, cfg.Database.GetDSN(), hub, cfg.Stream.ReconnectInterval)
+		})
 	}
 
 	// Initialize Echo framework
 	e := echo.New()
+	e.Server.ReadTimeout = cfg.Server.ReadTimeout
+	e.Server.WriteTimeout = cfg.Server.WriteTimeout
+	e.Server.IdleTimeout = cfg.Server.IdleTimeout
+
+	// Only trust forwarded client IPs when configured, they are used for rate limiting
+	if cfg.Server.TrustProxyHeaders {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	} else {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
 
 	// Set up middleware
-	e.Use(middleware.Logger())
+	e.Use(otelecho.Middleware(cfg.Tracing.ServiceName))
+	e.Use(apimiddleware.RequestID)
+	e.Use(apimiddleware.ClientInfo)
+	e.Use(apimiddleware.NewRequestLogger(logger).Handle)
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORS())
 
 	// Set up routes
-	router.SetupRoutes(e, db, cfg, logger)
+	healthHandler := router.NewHealthHandler(store, cfg.Server.ReadinessTimeout)
+	registry := metrics.NewRegistry()
+	router.SetupRoutes(e, store, cfg, healthHandler, registry, limiter, keys, auditor, hub, streamEvents, logger)
 
 	// Start server
 	serverAddr := fmt.Sprintf("0.0.0.0:%s", cfg.Server.Port)
-	log.Fatal(e.Start(serverAddr))
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := e.Start(serverAddr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	// Wait for a termination signal or a server failure
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		logger.Info("Shutdown signal received")
+	case err := <-serverErr:
+		logger.WithError(err).Error("Server failed")
+		exitCode = 1
+	}
+	// A second signal terminates immediately
+	stop()
+
+	shutdown(e, healthHandler, hub, workers, auditor, store, shutdownTracing, cfg.Server, logger)
+	os.Exit(exitCode)
+}
+
+// shutdown stops accepting traffic, drains in-flight requests and releases resources
+func shutdown(
+	e *echo.Echo,
+	healthHandler *handler.HealthHandler,
+	hub *stream.Hub,
+	workers *worker.Group,
+	auditor *audit.Recorder,
+	store *storage.Storage,
+	shutdownTracing func(context.Context) error,
+	cfg config.ServerConfig,
+	logger *logrus.Logger,
+) {
+	// Report not ready so load balancers stop routing new traffic
+	healthHandler.SetReady(false)
+	if cfg.ShutdownDelay > 0 {
+		logger.WithField("delay", cfg.ShutdownDelay.String()).Info("Waiting before draining connections")
+		time.Sleep(cfg.ShutdownDelay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// End the event streams, their clients reconnect to other instances
+	hub.Close()
+
+	// Drain in-flight requests
+	if err := e.Shutdown(ctx); err != nil {
+		logger.WithError(err).Error("Failed to drain in-flight requests")
+	}
+
+	// Stop background workers
+	if err := workers.Stop(ctx); err != nil {
+		logger.WithError(err).Error("Failed to stop background workers")
+	}
+
+	// Close the audit log file
+	if err := auditor.Close(); err != nil {
+		logger.WithError(err).Error("Failed to close audit log")
+	}
+
+	// Close the database connection
+	if err := store.Close(); err != nil {
+		logger.WithError(err).Error("Failed to close storage")
+	}
+
+	// Flush pending spans
+	if err := shutdownTracing(ctx); err != nil {
+		logger.WithError(err).Error("Failed to flush traces")
+	}
+
+	logger.Info("Server stopped")
 }
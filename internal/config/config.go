@@ -4,76 +4,537 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// Supported application profiles
+const (
+	ProfileDev        = "dev"
+	ProfileTest       = "test"
+	ProfileStaging    = "staging"
+	ProfileProduction = "production"
+)
+
+// DefaultJWTSecret is the placeholder secret used when JWT_SECRET is not set.
+// It is only accepted in the dev and test profiles.
+const DefaultJWTSecret = "your-super-secret-key-change-in-production"
+
 // Config represents the application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	Profile   string          `yaml:"profile" toml:"profile"`
+	Server    ServerConfig    `yaml:"server" toml:"server"`
+	Database  DatabaseConfig  `yaml:"database" toml:"database"`
+	JWT       JWTConfig       `yaml:"jwt" toml:"jwt"`
+	Tracing   TracingConfig   `yaml:"tracing" toml:"tracing"`
+	RateLimit RateLimitConfig `yaml:"rate_limit" toml:"rate_limit"`
+
+	Idempotency IdempotencyConfig `yaml:"idempotency" toml:"idempotency"`
+	Audit       AuditConfig       `yaml:"audit" toml:"audit"`
+	Webhooks    WebhookConfig     `yaml:"webhooks" toml:"webhooks"`
+	Stream      StreamConfig      `yaml:"stream" toml:"stream"`
+	Metrics     MetricsConfig     `yaml:"metrics" toml:"metrics"`
 }
 
 // ServerConfig represents the server configuration
 type ServerConfig struct {
-	Port string
+	Port string `yaml:"port" toml:"port"`
+
+	// HTTP server timeouts, zero disables the timeout
+	ReadTimeout  time.Duration `yaml:"read_timeout" toml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout" toml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+
+	// ReadinessTimeout bounds the dependency checks of the readiness probe
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout" toml:"readiness_timeout"`
+
+	// ShutdownDelay is how long the server keeps serving while reporting itself
+	// as not ready, so load balancers stop routing traffic before it drains.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" toml:"shutdown_delay"`
+
+	// ShutdownTimeout bounds how long in-flight requests may take to drain
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+
+	// TrustProxyHeaders takes the client IP from X-Forwarded-For. Only enable it
+	// behind a proxy that sets the header, clients could spoof it otherwise.
+	TrustProxyHeaders bool `yaml:"trust_proxy_headers" toml:"trust_proxy_headers"`
 }
 
+// Supported storage drivers
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
 // DatabaseConfig represents the database configuration
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	Username string
-	Password string
-	DBName   string
-	SSLMode  string
+	Driver string `yaml:"driver" toml:"driver"`
+
+	// URL is a postgres:// connection URL (DATABASE_URL). When set it takes
+	// precedence over the individual connection settings below.
+	URL string `yaml:"url" toml:"url"`
+
+	Host     string `yaml:"host" toml:"host"`
+	Port     string `yaml:"port" toml:"port"`
+	Username string `yaml:"username" toml:"username"`
+	Password string `yaml:"password" toml:"password"`
+	DBName   string `yaml:"dbname" toml:"dbname"`
+	SSLMode  string `yaml:"sslmode" toml:"sslmode"`
+
+	// Connection pool settings
+	MaxOpenConns     int           `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns     int           `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime  time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+	StatementTimeout time.Duration `yaml:"statement_timeout" toml:"statement_timeout"`
+
+	// ConnectAttempts and ConnectBackoff control retries while the database is
+	// not yet reachable at startup. The backoff doubles after every attempt.
+	ConnectAttempts int           `yaml:"connect_attempts" toml:"connect_attempts"`
+	ConnectBackoff  time.Duration `yaml:"connect_backoff" toml:"connect_backoff"`
+
+	// RowLevelSecurity backs the workspace isolation of the repositories with
+	// Postgres row-level security policies
+	RowLevelSecurity bool `yaml:"row_level_security" toml:"row_level_security"`
+
+	// SQLitePath is the database file used by the sqlite driver
+	SQLitePath string `yaml:"sqlite_path" toml:"sqlite_path"`
 }
 
 // JWTConfig represents the JWT configuration
 type JWTConfig struct {
-	SecretKey  string
-	Expiration time.Duration
+	SecretKey  string        `yaml:"secret_key" toml:"secret_key"`
+	Expiration time.Duration `yaml:"expiration" toml:"expiration"`
+}
+
+// Supported trace exporters
+const (
+	TraceExporterNone   = "none"
+	TraceExporterStdout = "stdout"
+	TraceExporterOTLP   = "otlp"
+)
+
+// TracingConfig represents the OpenTelemetry tracing configuration
+type TracingConfig struct {
+	// Exporter is one of none, stdout or otlp
+	Exporter    string  `yaml:"exporter" toml:"exporter"`
+	ServiceName string  `yaml:"service_name" toml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+
+	// OTLPEndpoint is the URL of the OTLP/HTTP collector, plain http disables TLS
+	OTLPEndpoint string `yaml:"otlp_endpoint" toml:"otlp_endpoint"`
+}
+
+// Supported rate limit stores
+const (
+	RateLimitStoreMemory   = "memory"
+	RateLimitStorePostgres = "postgres"
+)
+
+// RateLimitConfig represents the API rate limiting configuration
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+
+	// Store is memory (per instance) or postgres (shared by all instances)
+	Store string `yaml:"store" toml:"store"`
+
+	// SweepInterval is how often idle buckets are removed from the store
+	SweepInterval time.Duration `yaml:"sweep_interval" toml:"sweep_interval"`
+
+	// Auth limits the public login and registration routes per client IP
+	Auth RateLimitRule `yaml:"auth" toml:"auth"`
+
+	// API limits the authenticated routes per user
+	API RateLimitRule `yaml:"api" toml:"api"`
+}
+
+// RateLimitRule configures a token bucket
+type RateLimitRule struct {
+	// Rate is the number of requests per second the bucket refills
+	Rate float64 `yaml:"rate" toml:"rate"`
+
+	// Burst is the bucket capacity, the number of requests allowed at once
+	Burst int `yaml:"burst" toml:"burst"`
+}
+
+// Supported idempotency key stores
+const (
+	IdempotencyStoreMemory   = "memory"
+	IdempotencyStoreDatabase = "database"
+)
+
+// IdempotencyConfig represents the configuration of Idempotency-Key handling
+type IdempotencyConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+
+	// Store is memory (per instance) or database (shared through the storage backend)
+	Store string `yaml:"store" toml:"store"`
+
+	// TTL is how long the response to a key is replayed
+	TTL time.Duration `yaml:"ttl" toml:"ttl"`
+
+	// SweepInterval is how often expired keys are removed from the store
+	SweepInterval time.Duration `yaml:"sweep_interval" toml:"sweep_interval"`
+
+	// MaxBodyBytes limits the body of requests with a key, which is buffered
+	// to fingerprint the request
+	MaxBodyBytes int `yaml:"max_body_bytes" toml:"max_body_bytes"`
 }
 
-// Load loads the configuration from environment variables
-func Load() (*Config, error) {
+// AuditConfig represents the configuration of the security audit log
+type AuditConfig struct {
+	// Admins are the IDs of the users allowed to query the audit log
+	Admins []uint `yaml:"admins" toml:"admins"`
+
+	// FilePath is a file the events are additionally appended to as JSON
+	// lines, empty disables the file sink
+	FilePath string `yaml:"file_path" toml:"file_path"`
+}
+
+// WebhookConfig represents the configuration of webhook deliveries
+type WebhookConfig struct {
+	// Enabled runs the background deliverer on this instance. Webhooks can be
+	// managed and the deliveries of todo events are scheduled either way.
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+
+	// PollInterval is how often todo events and due deliveries are claimed,
+	// BatchSize how many at once
+	PollInterval time.Duration `yaml:"poll_interval" toml:"poll_interval"`
+	BatchSize    int           `yaml:"batch_size" toml:"batch_size"`
+
+	// Timeout bounds a single delivery attempt
+	Timeout time.Duration `yaml:"timeout" toml:"timeout"`
+
+	// MaxAttempts is the number of attempts before a delivery fails for good
+	MaxAttempts int `yaml:"max_attempts" toml:"max_attempts"`
+
+	// RetryBackoff is the delay before the first retry, doubled for every
+	// further retry up to MaxRetryBackoff
+	RetryBackoff    time.Duration `yaml:"retry_backoff" toml:"retry_backoff"`
+	MaxRetryBackoff time.Duration `yaml:"max_retry_backoff" toml:"max_retry_backoff"`
+
+	// AllowPrivateNetworks lets webhooks target loopback, private and
+	// link-local addresses. Only enable it when every user is trusted.
+	AllowPrivateNetworks bool `yaml:"allow_private_networks" toml:"allow_private_networks"`
+}
+
+// StreamConfig represents the configuration of the real-time todo event streams
+type StreamConfig struct {
+	// HistorySize is the number of latest events kept for clients resuming
+	// from their last event ID
+	HistorySize int `yaml:"history_size" toml:"history_size"`
+
+	// HeartbeatInterval is how often idle streams are sent a keep-alive
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval" toml:"heartbeat_interval"`
+
+	// ReconnectInterval is the delay before listening again for Postgres
+	// notifications after the connection failed
+	ReconnectInterval time.Duration `yaml:"reconnect_interval" toml:"reconnect_interval"`
+}
+
+// MetricsConfig represents the configuration of the Prometheus endpoint
+type MetricsConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+
+	// Token is the bearer token scrapers must send to /metrics. It is required
+	// outside the dev and test profiles, the endpoint is public otherwise.
+	Token string `yaml:"token" toml:"token"`
+}
+
+// Load loads the configuration from the optional config file and environment
+// variables and validates it.
+//
+// Values are resolved in order of increasing precedence: built-in defaults, the
+// file named by path (or CONFIG_FILE when path is empty), then environment variables.
+func Load(path string) (*Config, error) {
+	config, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Read resolves the configuration like Load without validating it
+func Read(path string) (*Config, error) {
 	// Set default values
-	config := &Config{
+	config := Default()
+
+	// Merge the config file, if any
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := loadFile(path, config); err != nil {
+			return nil, err
+		}
+	}
+
+	// Apply environment overrides
+	if err := applyEnv(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Default returns the built-in default configuration
+func Default() *Config {
+	return &Config{
+		Profile: ProfileProduction,
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8000"),
+			Port: "8000",
+
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 30 * time.Second,
+			IdleTimeout:  60 * time.Second,
+
+			ReadinessTimeout: 2 * time.Second,
+
+			ShutdownDelay:   5 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("PGHOST", "localhost"),
-			Port:     getEnv("PGPORT", "5432"),
-			Username: getEnv("PGUSER", "postgres"),
-			Password: getEnv("PGPASSWORD", "postgres"),
-			DBName:   getEnv("PGDATABASE", "todo_db"),
-			SSLMode:  getEnv("PGSSLMODE", "disable"),
+			Driver:   DriverPostgres,
+			Host:     "localhost",
+			Port:     "5432",
+			Username: "postgres",
+			Password: "postgres",
+			DBName:   "todo_db",
+			SSLMode:  "disable",
+
+			MaxOpenConns:     25,
+			MaxIdleConns:     5,
+			ConnMaxLifetime:  30 * time.Minute,
+			StatementTimeout: 30 * time.Second,
+
+			ConnectAttempts: 5,
+			ConnectBackoff:  time.Second,
+
+			SQLitePath: "todo.db",
 		},
 		JWT: JWTConfig{
-			SecretKey:  getEnv("JWT_SECRET", "your-super-secret-key-change-in-production"),
-			Expiration: time.Duration(getEnvAsInt("JWT_EXPIRATION_HOURS", 24)) * time.Hour,
+			SecretKey:  DefaultJWTSecret,
+			Expiration: 24 * time.Hour,
+		},
+		Tracing: TracingConfig{
+			Exporter:     TraceExporterNone,
+			ServiceName:  "todo-api",
+			SampleRatio:  1,
+			OTLPEndpoint: "http://localhost:4318",
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:       true,
+			Store:         RateLimitStoreMemory,
+			SweepInterval: time.Minute,
+			Auth: RateLimitRule{
+				Rate:  0.2,
+				Burst: 10,
+			},
+			API: RateLimitRule{
+				Rate:  10,
+				Burst: 50,
+			},
+		},
+		Idempotency: IdempotencyConfig{
+			Enabled:       true,
+			Store:         IdempotencyStoreMemory,
+			TTL:           24 * time.Hour,
+			SweepInterval: 10 * time.Minute,
+			MaxBodyBytes:  1 << 20,
+		},
+		Webhooks: WebhookConfig{
+			Enabled:         true,
+			PollInterval:    5 * time.Second,
+			BatchSize:       20,
+			Timeout:         10 * time.Second,
+			MaxAttempts:     8,
+			RetryBackoff:    30 * time.Second,
+			MaxRetryBackoff: time.Hour,
+		},
+		Stream: StreamConfig{
+			HistorySize:       1000,
+			HeartbeatInterval: 30 * time.Second,
+			ReconnectInterval: 5 * time.Second,
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
 		},
 	}
+}
 
-	// Check for DATABASE_URL (used by some PaaS providers)
-	if dbURL := os.Getenv("DATABASE_URL"); dbURL != "" {
-		// Override individual database settings if DATABASE_URL is provided
-		config.Database.Host = dbURL
-		config.Database.Port = ""
-		config.Database.Username = ""
-		config.Database.Password = ""
-		config.Database.DBName = ""
+// applyEnv overrides configuration values with environment variables
+func applyEnv(config *Config) error {
+	var err error
+
+	config.Profile = getEnv("APP_PROFILE", config.Profile)
+	config.Server.Port = getEnv("PORT", config.Server.Port)
+	if config.Server.ReadTimeout, err = getEnvAsDuration("SERVER_READ_TIMEOUT", config.Server.ReadTimeout); err != nil {
+		return err
+	}
+	if config.Server.WriteTimeout, err = getEnvAsDuration("SERVER_WRITE_TIMEOUT", config.Server.WriteTimeout); err != nil {
+		return err
+	}
+	if config.Server.IdleTimeout, err = getEnvAsDuration("SERVER_IDLE_TIMEOUT", config.Server.IdleTimeout); err != nil {
+		return err
+	}
+	if config.Server.ReadinessTimeout, err = getEnvAsDuration("SERVER_READINESS_TIMEOUT", config.Server.ReadinessTimeout); err != nil {
+		return err
+	}
+	if config.Server.ShutdownDelay, err = getEnvAsDuration("SERVER_SHUTDOWN_DELAY", config.Server.ShutdownDelay); err != nil {
+		return err
+	}
+	if config.Server.ShutdownTimeout, err = getEnvAsDuration("SERVER_SHUTDOWN_TIMEOUT", config.Server.ShutdownTimeout); err != nil {
+		return err
+	}
+	if config.Server.TrustProxyHeaders, err = getEnvAsBool("SERVER_TRUST_PROXY_HEADERS", config.Server.TrustProxyHeaders); err != nil {
+		return err
 	}
 
-	return config, nil
+	config.Database.Driver = getEnv("DB_DRIVER", config.Database.Driver)
+	config.Database.URL = getEnv("DATABASE_URL", config.Database.URL)
+	config.Database.Host = getEnv("PGHOST", config.Database.Host)
+	config.Database.Port = getEnv("PGPORT", config.Database.Port)
+	config.Database.Username = getEnv("PGUSER", config.Database.Username)
+	config.Database.Password = getEnv("PGPASSWORD", config.Database.Password)
+	config.Database.DBName = getEnv("PGDATABASE", config.Database.DBName)
+	config.Database.SSLMode = getEnv("PGSSLMODE", config.Database.SSLMode)
+	config.Database.SQLitePath = getEnv("SQLITE_PATH", config.Database.SQLitePath)
+
+	if config.Database.MaxOpenConns, err = getEnvAsInt("DB_MAX_OPEN_CONNS", config.Database.MaxOpenConns); err != nil {
+		return err
+	}
+	if config.Database.MaxIdleConns, err = getEnvAsInt("DB_MAX_IDLE_CONNS", config.Database.MaxIdleConns); err != nil {
+		return err
+	}
+	if config.Database.ConnMaxLifetime, err = getEnvAsDuration("DB_CONN_MAX_LIFETIME", config.Database.ConnMaxLifetime); err != nil {
+		return err
+	}
+	if config.Database.StatementTimeout, err = getEnvAsDuration("DB_STATEMENT_TIMEOUT", config.Database.StatementTimeout); err != nil {
+		return err
+	}
+	if config.Database.ConnectAttempts, err = getEnvAsInt("DB_CONNECT_ATTEMPTS", config.Database.ConnectAttempts); err != nil {
+		return err
+	}
+	if config.Database.ConnectBackoff, err = getEnvAsDuration("DB_CONNECT_BACKOFF", config.Database.ConnectBackoff); err != nil {
+		return err
+	}
+	if config.Database.RowLevelSecurity, err = getEnvAsBool("DB_ROW_LEVEL_SECURITY", config.Database.RowLevelSecurity); err != nil {
+		return err
+	}
+
+	config.JWT.SecretKey = getEnv("JWT_SECRET", config.JWT.SecretKey)
+	if os.Getenv("JWT_EXPIRATION_HOURS") != "" {
+		hours, err := getEnvAsInt("JWT_EXPIRATION_HOURS", 0)
+		if err != nil {
+			return err
+		}
+		config.JWT.Expiration = time.Duration(hours) * time.Hour
+	}
+
+	config.Tracing.Exporter = getEnv("TRACING_EXPORTER", config.Tracing.Exporter)
+	config.Tracing.ServiceName = getEnv("OTEL_SERVICE_NAME", config.Tracing.ServiceName)
+	config.Tracing.OTLPEndpoint = getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", config.Tracing.OTLPEndpoint)
+	if config.Tracing.SampleRatio, err = getEnvAsFloat("TRACING_SAMPLE_RATIO", config.Tracing.SampleRatio); err != nil {
+		return err
+	}
+
+	if config.RateLimit.Enabled, err = getEnvAsBool("RATE_LIMIT_ENABLED", config.RateLimit.Enabled); err != nil {
+		return err
+	}
+	config.RateLimit.Store = getEnv("RATE_LIMIT_STORE", config.RateLimit.Store)
+	if config.RateLimit.SweepInterval, err = getEnvAsDuration("RATE_LIMIT_SWEEP_INTERVAL", config.RateLimit.SweepInterval); err != nil {
+		return err
+	}
+	if config.RateLimit.Auth.Rate, err = getEnvAsFloat("RATE_LIMIT_AUTH_RATE", config.RateLimit.Auth.Rate); err != nil {
+		return err
+	}
+	if config.RateLimit.Auth.Burst, err = getEnvAsInt("RATE_LIMIT_AUTH_BURST", config.RateLimit.Auth.Burst); err != nil {
+		return err
+	}
+	if config.RateLimit.API.Rate, err = getEnvAsFloat("RATE_LIMIT_API_RATE", config.RateLimit.API.Rate); err != nil {
+		return err
+	}
+	if config.RateLimit.API.Burst, err = getEnvAsInt("RATE_LIMIT_API_BURST", config.RateLimit.API.Burst); err != nil {
+		return err
+	}
+
+	if config.Idempotency.Enabled, err = getEnvAsBool("IDEMPOTENCY_ENABLED", config.Idempotency.Enabled); err != nil {
+		return err
+	}
+	config.Idempotency.Store = getEnv("IDEMPOTENCY_STORE", config.Idempotency.Store)
+	if config.Idempotency.TTL, err = getEnvAsDuration("IDEMPOTENCY_TTL", config.Idempotency.TTL); err != nil {
+		return err
+	}
+	if config.Idempotency.SweepInterval, err = getEnvAsDuration("IDEMPOTENCY_SWEEP_INTERVAL", config.Idempotency.SweepInterval); err != nil {
+		return err
+	}
+	if config.Idempotency.MaxBodyBytes, err = getEnvAsInt("IDEMPOTENCY_MAX_BODY_BYTES", config.Idempotency.MaxBodyBytes); err != nil {
+		return err
+	}
+
+	if config.Audit.Admins, err = getEnvAsIDs("AUDIT_ADMIN_IDS", config.Audit.Admins); err != nil {
+		return err
+	}
+	config.Audit.FilePath = getEnv("AUDIT_FILE", config.Audit.FilePath)
+
+	if config.Webhooks.Enabled, err = getEnvAsBool("WEBHOOKS_ENABLED", config.Webhooks.Enabled); err != nil {
+		return err
+	}
+	if config.Webhooks.PollInterval, err = getEnvAsDuration("WEBHOOKS_POLL_INTERVAL", config.Webhooks.PollInterval); err != nil {
+		return err
+	}
+	if config.Webhooks.BatchSize, err = getEnvAsInt("WEBHOOKS_BATCH_SIZE", config.Webhooks.BatchSize); err != nil {
+		return err
+	}
+	if config.Webhooks.Timeout, err = getEnvAsDuration("WEBHOOKS_TIMEOUT", config.Webhooks.Timeout); err != nil {
+		return err
+	}
+	if config.Webhooks.MaxAttempts, err = getEnvAsInt("WEBHOOKS_MAX_ATTEMPTS", config.Webhooks.MaxAttempts); err != nil {
+		return err
+	}
+	if config.Webhooks.RetryBackoff, err = getEnvAsDuration("WEBHOOKS_RETRY_BACKOFF", config.Webhooks.RetryBackoff); err != nil {
+		return err
+	}
+	if config.Webhooks.MaxRetryBackoff, err = getEnvAsDuration("WEBHOOKS_MAX_RETRY_BACKOFF", config.Webhooks.MaxRetryBackoff); err != nil {
+		return err
+	}
+	if config.Webhooks.AllowPrivateNetworks, err = getEnvAsBool("WEBHOOKS_ALLOW_PRIVATE_NETWORKS", config.Webhooks.AllowPrivateNetworks); err != nil {
+		return err
+	}
+
+	if config.Stream.HistorySize, err = getEnvAsInt("STREAM_HISTORY_SIZE", config.Stream.HistorySize); err != nil {
+		return err
+	}
+	if config.Stream.HeartbeatInterval, err = getEnvAsDuration("STREAM_HEARTBEAT_INTERVAL", config.Stream.HeartbeatInterval); err != nil {
+		return err
+	}
+	if config.Stream.ReconnectInterval, err = getEnvAsDuration("STREAM_RECONNECT_INTERVAL", config.Stream.ReconnectInterval); err != nil {
+		return err
+	}
+
+	if config.Metrics.Enabled, err = getEnvAsBool("METRICS_ENABLED", config.Metrics.Enabled); err != nil {
+		return err
+	}
+	config.Metrics.Token = getEnv("METRICS_TOKEN", config.Metrics.Token)
+
+	return nil
+}
+
+// IsDev reports whether the configuration uses a development or test profile
+func (c *Config) IsDev() bool {
+	return c.Profile == ProfileDev || c.Profile == ProfileTest
 }
 
 // GetDSN returns the PostgreSQL connection string
 func (c *DatabaseConfig) GetDSN() string {
-	// If using the DATABASE_URL environment variable
-	if c.Host != "" && c.Port == "" && c.Username == "" {
-		return c.Host
+	// A connection URL (DATABASE_URL) takes precedence
+	if c.URL != "" {
+		return c.URL
 	}
 
 	// Build the DSN from individual parts
@@ -89,10 +550,67 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-func getEnvAsInt(key string, defaultValue int) int {
+func getEnvAsInt(key string, defaultValue int) (int, error) {
 	valueStr := getEnv(key, "")
-	if value, err := strconv.Atoi(valueStr); err == nil {
-		return value
+	if valueStr == "" {
+		return defaultValue, nil
 	}
-	return defaultValue
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer, got %q", key, valueStr)
+	}
+	return value, nil
+}
+
+func getEnvAsFloat(key string, defaultValue float64) (float64, error) {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number, got %q", key, valueStr)
+	}
+	return value, nil
+}
+
+func getEnvAsBool(key string, defaultValue bool) (bool, error) {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false, got %q", key, valueStr)
+	}
+	return value, nil
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a duration such as 30s, got %q", key, valueStr)
+	}
+	return value, nil
+}
+
+func getEnvAsIDs(key string, defaultValue []uint) ([]uint, error) {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+	parts := strings.Split(valueStr, ",")
+	values := make([]uint, 0, len(parts))
+	for _, part := range parts {
+		value, err := strconv.ParseUint(strings.TrimSpace(part), 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a comma-separated list of IDs, got %q", key, valueStr)
+		}
+		values = append(values, uint(value))
+	}
+	return values, nil
 }
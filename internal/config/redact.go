@@ -0,0 +1,49 @@
+package config
+
+import (
+	"net/url"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secret values in printed configuration
+const redacted = "[REDACTED]"
+
+// Redacted returns a copy of the configuration with secrets masked
+func (c Config) Redacted() Config {
+	if c.Database.Password != "" {
+		c.Database.Password = redacted
+	}
+	if c.JWT.SecretKey != "" {
+		c.JWT.SecretKey = redacted
+	}
+	if c.Metrics.Token != "" {
+		c.Metrics.Token = redacted
+	}
+
+	// The connection URL may embed credentials
+	if c.Database.URL != "" {
+		c.Database.URL = redactURL(c.Database.URL)
+	}
+
+	return c
+}
+
+// redactURL masks the password of a connection URL, which libpq accepts both
+// in the userinfo and as a password query parameter
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redacted
+	}
+	if query := u.Query(); query.Has("password") {
+		query.Set("password", "xxxxx")
+		u.RawQuery = query.Encode()
+	}
+	return u.Redacted()
+}
+
+// RedactedYAML renders the configuration as YAML with secrets masked
+func (c Config) RedactedYAML() ([]byte, error) {
+	return yaml.Marshal(c.Redacted())
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedacted(t *testing.T) {
+	var c Config
+	c.Database.Password = "db-secret"
+	c.Database.URL = "postgres://todo:url-secret@db:5432/todo?password=query-secret&sslmode=disable"
+	c.JWT.SecretKey = "jwt-secret"
+	c.Metrics.Token = "metrics-secret"
+
+	out, err := c.RedactedYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"db-secret", "url-secret", "query-secret", "jwt-secret", "metrics-secret"} {
+		if strings.Contains(string(out), secret) {
+			t.Errorf("redacted configuration contains %q", secret)
+		}
+	}
+
+	redacted := c.Redacted()
+	if want := "postgres://todo:xxxxx@db:5432/todo?password=xxxxx&sslmode=disable"; redacted.Database.URL != want {
+		t.Errorf("got URL %q, want %q", redacted.Database.URL, want)
+	}
+	if c.Database.Password != "db-secret" {
+		t.Error("Redacted changed the original configuration")
+	}
+}
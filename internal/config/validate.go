@@ -0,0 +1,344 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrDefaultJWTSecret is returned when the placeholder JWT secret is used outside the dev profile
+var ErrDefaultJWTSecret = errors.New("JWT_SECRET must be set outside the dev and test profiles")
+
+// ValidationError lists every invalid configuration value
+type ValidationError struct {
+	Problems []string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks that all configuration values are usable
+func (c *Config) Validate() error {
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	switch c.Profile {
+	case ProfileDev, ProfileTest, ProfileStaging, ProfileProduction:
+	default:
+		addProblem("profile must be one of dev, test, staging or production, got %q", c.Profile)
+	}
+
+	if err := validatePort(c.Server.Port); err != nil {
+		addProblem("server port: %v", err)
+	}
+	if c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		addProblem("server timeouts must not be negative")
+	}
+	if c.Server.ReadinessTimeout <= 0 {
+		addProblem("server readiness timeout must be positive, got %s", c.Server.ReadinessTimeout)
+	}
+	if c.Server.ShutdownDelay < 0 {
+		addProblem("server shutdown delay must not be negative, got %s", c.Server.ShutdownDelay)
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		addProblem("server shutdown timeout must be positive, got %s", c.Server.ShutdownTimeout)
+	}
+
+	if err := c.Database.Validate(); err != nil {
+		addProblem("database: %v", err)
+	}
+
+	if c.JWT.SecretKey == "" {
+		addProblem("jwt secret key must not be empty")
+	}
+	if c.JWT.Expiration <= 0 {
+		addProblem("jwt expiration must be positive, got %s", c.JWT.Expiration)
+	}
+
+	switch c.Tracing.Exporter {
+	case TraceExporterNone, TraceExporterStdout:
+	case TraceExporterOTLP:
+		if u, err := url.Parse(c.Tracing.OTLPEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			addProblem("tracing otlp endpoint must be an http or https URL, got %q", c.Tracing.OTLPEndpoint)
+		}
+	default:
+		addProblem("tracing exporter must be one of none, stdout or otlp, got %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		addProblem("tracing sample ratio must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	}
+
+	if err := c.validateRateLimit(); err != nil {
+		addProblem("rate limit: %v", err)
+	}
+
+	if err := c.validateIdempotency(); err != nil {
+		addProblem("idempotency: %v", err)
+	}
+
+	if err := c.validateWebhooks(); err != nil {
+		addProblem("webhooks: %v", err)
+	}
+
+	if err := c.validateStream(); err != nil {
+		addProblem("stream: %v", err)
+	}
+
+	// Metrics expose the traffic of every route and workspace
+	if c.Metrics.Enabled && c.Metrics.Token == "" && !c.IsDev() {
+		addProblem("metrics token must be set outside the dev and test profiles, or metrics disabled")
+	}
+
+	for _, id := range c.Audit.Admins {
+		if id == 0 {
+			addProblem("audit admin IDs must be positive")
+			break
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	// Refuse to sign tokens with a publicly known secret
+	if c.JWT.SecretKey == DefaultJWTSecret && !c.IsDev() {
+		return ErrDefaultJWTSecret
+	}
+
+	return nil
+}
+
+// Validate checks the database settings of the selected driver
+func (c *DatabaseConfig) Validate() error {
+	switch c.Driver {
+	case DriverPostgres:
+	case DriverSQLite:
+		if c.SQLitePath == "" {
+			return errors.New("sqlite path must not be empty")
+		}
+		if c.RowLevelSecurity {
+			return errors.New("row-level security requires the postgres driver")
+		}
+		return nil
+	case DriverMemory:
+		if c.RowLevelSecurity {
+			return errors.New("row-level security requires the postgres driver")
+		}
+		return nil
+	default:
+		return fmt.Errorf("driver must be one of postgres, sqlite or memory, got %q", c.Driver)
+	}
+
+	if err := c.validatePool(); err != nil {
+		return err
+	}
+
+	if c.URL != "" {
+		_, err := ParseDatabaseURL(c.URL)
+		return err
+	}
+
+	if c.Host == "" {
+		return errors.New("host must not be empty")
+	}
+	if err := validatePort(c.Port); err != nil {
+		return fmt.Errorf("port: %w", err)
+	}
+	if c.Username == "" {
+		return errors.New("username must not be empty")
+	}
+	if c.DBName == "" {
+		return errors.New("database name must not be empty")
+	}
+	if !validSSLMode(c.SSLMode) {
+		return fmt.Errorf("unsupported sslmode %q", c.SSLMode)
+	}
+
+	return nil
+}
+
+// validatePool checks the connection pool and retry settings
+func (c *DatabaseConfig) validatePool() error {
+	if c.MaxOpenConns < 0 {
+		return fmt.Errorf("max open connections must not be negative, got %d", c.MaxOpenConns)
+	}
+	if c.MaxIdleConns < 0 {
+		return fmt.Errorf("max idle connections must not be negative, got %d", c.MaxIdleConns)
+	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		return fmt.Errorf("max idle connections (%d) must not exceed max open connections (%d)", c.MaxIdleConns, c.MaxOpenConns)
+	}
+	if c.ConnMaxLifetime < 0 {
+		return fmt.Errorf("connection max lifetime must not be negative, got %s", c.ConnMaxLifetime)
+	}
+	if c.StatementTimeout < 0 {
+		return fmt.Errorf("statement timeout must not be negative, got %s", c.StatementTimeout)
+	}
+	if c.ConnectAttempts < 1 {
+		return fmt.Errorf("connect attempts must be at least 1, got %d", c.ConnectAttempts)
+	}
+	if c.ConnectBackoff < 0 {
+		return fmt.Errorf("connect backoff must not be negative, got %s", c.ConnectBackoff)
+	}
+	return nil
+}
+
+// validateRateLimit checks the rate limit store and rules
+func (c *Config) validateRateLimit() error {
+	if !c.RateLimit.Enabled {
+		return nil
+	}
+
+	switch c.RateLimit.Store {
+	case RateLimitStoreMemory:
+	case RateLimitStorePostgres:
+		if c.Database.Driver != DriverPostgres {
+			return fmt.Errorf("the postgres store requires the postgres database driver, got %q", c.Database.Driver)
+		}
+	default:
+		return fmt.Errorf("store must be memory or postgres, got %q", c.RateLimit.Store)
+	}
+	if c.RateLimit.SweepInterval <= 0 {
+		return fmt.Errorf("sweep interval must be positive, got %s", c.RateLimit.SweepInterval)
+	}
+	if err := c.RateLimit.Auth.validate(); err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+	if err := c.RateLimit.API.validate(); err != nil {
+		return fmt.Errorf("api: %w", err)
+	}
+	return nil
+}
+
+// validateIdempotency checks the idempotency key store and its timings
+func (c *Config) validateIdempotency() error {
+	if !c.Idempotency.Enabled {
+		return nil
+	}
+
+	switch c.Idempotency.Store {
+	case IdempotencyStoreMemory:
+	case IdempotencyStoreDatabase:
+		if c.Database.Driver == DriverMemory {
+			return errors.New("the database store requires the postgres or sqlite database driver")
+		}
+	default:
+		return fmt.Errorf("store must be memory or database, got %q", c.Idempotency.Store)
+	}
+	if c.Idempotency.TTL <= 0 {
+		return fmt.Errorf("ttl must be positive, got %s", c.Idempotency.TTL)
+	}
+	if c.Idempotency.SweepInterval <= 0 {
+		return fmt.Errorf("sweep interval must be positive, got %s", c.Idempotency.SweepInterval)
+	}
+	if c.Idempotency.MaxBodyBytes <= 0 {
+		return fmt.Errorf("max body bytes must be positive, got %d", c.Idempotency.MaxBodyBytes)
+	}
+	return nil
+}
+
+// validateWebhooks checks the delivery timings and limits
+func (c *Config) validateWebhooks() error {
+	if !c.Webhooks.Enabled {
+		return nil
+	}
+
+	if c.Webhooks.PollInterval <= 0 {
+		return fmt.Errorf("poll interval must be positive, got %s", c.Webhooks.PollInterval)
+	}
+	if c.Webhooks.BatchSize < 1 {
+		return fmt.Errorf("batch size must be at least 1, got %d", c.Webhooks.BatchSize)
+	}
+	if c.Webhooks.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive, got %s", c.Webhooks.Timeout)
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		return fmt.Errorf("max attempts must be at least 1, got %d", c.Webhooks.MaxAttempts)
+	}
+	if c.Webhooks.RetryBackoff <= 0 {
+		return fmt.Errorf("retry backoff must be positive, got %s", c.Webhooks.RetryBackoff)
+	}
+	if c.Webhooks.MaxRetryBackoff < c.Webhooks.RetryBackoff {
+		return fmt.Errorf("max retry backoff must be at least the retry backoff, got %s", c.Webhooks.MaxRetryBackoff)
+	}
+	return nil
+}
+
+// validateStream checks the event history and stream timings
+func (c *Config) validateStream() error {
+	if c.Stream.HistorySize < 0 {
+		return fmt.Errorf("history size must not be negative, got %d", c.Stream.HistorySize)
+	}
+	if c.Stream.HeartbeatInterval <= 0 {
+		return fmt.Errorf("heartbeat interval must be positive, got %s", c.Stream.HeartbeatInterval)
+	}
+	if c.Stream.ReconnectInterval <= 0 {
+		return fmt.Errorf("reconnect interval must be positive, got %s", c.Stream.ReconnectInterval)
+	}
+	return nil
+}
+
+// validate checks that the token bucket can hold and refill at least one request
+func (r RateLimitRule) validate() error {
+	if r.Rate <= 0 {
+		return fmt.Errorf("rate must be positive, got %g", r.Rate)
+	}
+	if r.Burst < 1 {
+		return fmt.Errorf("burst must be at least 1, got %d", r.Burst)
+	}
+	return nil
+}
+
+// ParseDatabaseURL parses and validates a postgres:// connection URL
+func ParseDatabaseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		// The parse error may echo the password, so keep it out of the message
+		return nil, errors.New("malformed database URL")
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return nil, fmt.Errorf("database URL scheme must be postgres or postgresql, got %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("database URL is missing a host")
+	}
+	if port := u.Port(); port != "" {
+		if err := validatePort(port); err != nil {
+			return nil, fmt.Errorf("database URL port: %w", err)
+		}
+	}
+	if strings.TrimPrefix(u.Path, "/") == "" {
+		return nil, errors.New("database URL is missing a database name")
+	}
+	if sslMode := u.Query().Get("sslmode"); sslMode != "" && !validSSLMode(sslMode) {
+		return nil, fmt.Errorf("unsupported sslmode %q in database URL", sslMode)
+	}
+	return u, nil
+}
+
+// validSSLMode reports whether mode is an sslmode understood by libpq
+func validSSLMode(mode string) bool {
+	switch mode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+		return true
+	}
+	return false
+}
+
+// validatePort checks that port is a valid TCP port number
+func validatePort(port string) error {
+	value, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("must be a number, got %q", port)
+	}
+	if value < 1 || value > 65535 {
+		return fmt.Errorf("must be between 1 and 65535, got %d", value)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"reflect"
+	"time"
+)
+
+// ActivityAction is the kind of change recorded by an activity
+type ActivityAction string
+
+// Activity actions
+const (
+	ActivityCreated   ActivityAction = "created"
+	ActivityUpdated   ActivityAction = "updated"
+	ActivityCompleted ActivityAction = "completed"
+	ActivityDeleted   ActivityAction = "deleted"
+)
+
+// Activity is an entry of the append-only change history of a todo. It is
+// kept after the todo is deleted.
+type Activity struct {
+	ID     uint           `gorm:"primaryKey;index:idx_activities_todo,priority:2;index:idx_activities_user,priority:2"`
+	TodoID uint           `gorm:"not null;index:idx_activities_todo,priority:1"`
+	UserID uint           `gorm:"not null;index:idx_activities_user,priority:1"`
+	Action ActivityAction `gorm:"size:20;not null"`
+
+	// Changes are the fields changed by the action
+	Changes []FieldChange `gorm:"type:text;serializer:json"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	// WorkspaceID is the workspace of the todo, nil outside any workspace.
+	// Repositories set it from the context on creation.
+	WorkspaceID *uint `gorm:"index"`
+}
+
+// FieldChange is the change of a single field of a todo
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// NewActivity creates a new Activity entity for a change of a todo by userID
+func NewActivity(todoID, userID uint, action ActivityAction, changes []FieldChange) *Activity {
+	return &Activity{
+		TodoID:    todoID,
+		UserID:    userID,
+		Action:    action,
+		Changes:   changes,
+		CreatedAt: time.Now(),
+	}
+}
+
+// DiffTodos returns the fields that differ between two versions of a todo. A
+// nil version stands for a todo with zero fields, so that the diff of a
+// created todo has a nil before and the diff of a deleted one a nil after.
+func DiffTodos(before, after *Todo) []FieldChange {
+	old, current := todoFields(before), todoFields(after)
+	changes := make([]FieldChange, 0, len(old))
+	for i := range old {
+		if !reflect.DeepEqual(old[i].value, current[i].value) {
+			changes = append(changes, FieldChange{Field: old[i].name, Old: old[i].value, New: current[i].value})
+		}
+	}
+	return changes
+}
+
+// todoField is a named field value of a todo
+type todoField struct {
+	name  string
+	value interface{}
+}
+
+// todoFields returns the fields of a todo tracked by its history, absent
+// optional fields are nil
+func todoFields(todo *Todo) []todoField {
+	if todo == nil {
+		todo = &Todo{}
+	}
+	return []todoField{
+		{"title", todo.Title},
+		{"description", todo.Description},
+		{"completed", todo.Completed},
+		{"project_id", optionalID(todo.ProjectID)},
+		{"assignee_id", optionalID(todo.AssigneeID)},
+		{"tags", tagList(todo.Tags)},
+	}
+}
+
+// tagList returns the tags of a todo, an empty list stays nil
+func tagList(tags []string) interface{} {
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// optionalID dereferences an optional ID, a nil ID stays nil
+func optionalID(id *uint) interface{} {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
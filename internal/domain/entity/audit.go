@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"time"
+)
+
+// AuditEventType is the kind of a security audit event
+type AuditEventType string
+
+// Audit event types
+const (
+	AuditUserRegistered       AuditEventType = "user.registered"
+	AuditLoginSucceeded       AuditEventType = "auth.login_succeeded"
+	AuditLoginFailed          AuditEventType = "auth.login_failed"
+	AuditTokenIssued          AuditEventType = "auth.token_issued"
+	AuditTokensRevoked        AuditEventType = "auth.tokens_revoked"
+	AuditProfileUpdated       AuditEventType = "user.profile_updated"
+	AuditPasswordChanged      AuditEventType = "user.password_changed"
+	AuditPasswordChangeFailed AuditEventType = "user.password_change_failed"
+	AuditLogViewed            AuditEventType = "admin.audit_log_viewed"
+)
+
+// AuditEvent is an entry of the append-only security audit log
+type AuditEvent struct {
+	ID   uint           `gorm:"primaryKey"`
+	Type AuditEventType `gorm:"size:50;not null;index"`
+
+	// UserID is the account the event is about, nil for a failed login with
+	// an unknown email
+	UserID *uint `gorm:"index"`
+
+	// RemoteIP, UserAgent and RequestID describe the request that caused the event
+	RemoteIP  string `gorm:"size:64"`
+	UserAgent string `gorm:"size:255"`
+	RequestID string `gorm:"size:128"`
+
+	// Details holds event specific values, such as the old and new email of a profile update
+	Details map[string]interface{} `gorm:"type:text;serializer:json"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime;index"`
+}
+
+// AuditFilter represents the filters for querying audit events, newest first
+type AuditFilter struct {
+	// UserID and Type restrict the events to an account and a kind, when set
+	UserID *uint
+	Type   AuditEventType
+
+	// Since and Until restrict the events to [Since, Until), zero values are unbounded
+	Since time.Time
+	Until time.Time
+
+	Page     int
+	PageSize int
+}
+
+// NewAuditEvent creates a new AuditEvent entity
+func NewAuditEvent(eventType AuditEventType, userID *uint, details map[string]interface{}) *AuditEvent {
+	return &AuditEvent{
+		Type:      eventType,
+		UserID:    userID,
+		Details:   details,
+		CreatedAt: time.Now(),
+	}
+}
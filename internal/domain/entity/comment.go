@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Comment represents a Markdown comment on a todo
+type Comment struct {
+	ID        uint      `gorm:"primaryKey;index:idx_comments_todo_created,priority:3"`
+	TodoID    uint      `gorm:"not null;index:idx_comments_todo_created,priority:1"`
+	Todo      Todo      `gorm:"foreignKey:TodoID;constraint:OnDelete:CASCADE"`
+	UserID    uint      `gorm:"not null;index"`
+	User      User      `gorm:"foreignKey:UserID"`
+	Body      string    `gorm:"type:text;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index:idx_comments_todo_created,priority:2"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	// WorkspaceID is the workspace of the todo, nil outside any workspace.
+	// Repositories set it from the context on creation.
+	WorkspaceID *uint `gorm:"index"`
+}
+
+var (
+	// mentionPattern matches @username mentions, which are not part of a word
+	// or an email address
+	mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([\w.-]+)`)
+
+	// codePattern matches Markdown code blocks and code spans, which are not
+	// searched for mentions
+	codePattern = regexp.MustCompile("(?s)```.*?```|`[^`\n]*`")
+)
+
+// NewComment creates a new Comment entity
+func NewComment(todoID, userID uint, body string) *Comment {
+	return &Comment{
+		TodoID:    todoID,
+		UserID:    userID,
+		Body:      body,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Edit replaces the body of the comment
+func (c *Comment) Edit(body string) {
+	c.Body = body
+	c.UpdatedAt = time.Now()
+}
+
+// IsAuthor reports whether the comment was written by the specified user
+func (c *Comment) IsAuthor(userID uint) bool {
+	return c.UserID == userID
+}
+
+// Mentions returns the usernames mentioned with @username in the body, in the
+// order they first appear. Mentions in code are ignored.
+func (c *Comment) Mentions() []string {
+	body := codePattern.ReplaceAllString(c.Body, " ")
+
+	usernames := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		// A mention can end a sentence
+		username := strings.TrimRight(match[1], ".-")
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
@@ -0,0 +1,594 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits of filter expressions, they keep parsing and the generated queries cheap
+const (
+	MaxFilterLength     = 1000
+	MaxFilterConditions = 50
+	MaxFilterDepth      = 20
+)
+
+// FilterField is a todo field that filter expressions can compare
+type FilterField string
+
+// Filterable todo fields
+const (
+	FilterFieldCompleted   FilterField = "completed"
+	FilterFieldTitle       FilterField = "title"
+	FilterFieldDescription FilterField = "description"
+	FilterFieldCreated     FilterField = "created"
+	FilterFieldUpdated     FilterField = "updated"
+	FilterFieldTag         FilterField = "tag"
+)
+
+// filterFields lists the filterable fields in the order they are documented
+var filterFields = []FilterField{
+	FilterFieldCompleted, FilterFieldTitle, FilterFieldDescription, FilterFieldCreated, FilterFieldUpdated, FilterFieldTag,
+}
+
+// unsupportedFilterFields are the fields clients are known to try that cannot
+// be filtered on, mapped to the reason reported for them
+var unsupportedFilterFields = map[FilterField]string{
+	"priority": "todos have no priority",
+}
+
+// Codes of filter errors
+const (
+	FilterErrorInvalid          = "invalid_filter"
+	FilterErrorUnsupportedField = "unsupported_filter_field"
+)
+
+// FilterOp is a comparison operator of a filter condition
+type FilterOp string
+
+// Comparison operators. FilterOpContains only applies to text fields, the ':'
+// of a filter expression means equality for the other fields.
+const (
+	FilterOpContains FilterOp = ":"
+	FilterOpEq       FilterOp = "="
+	FilterOpNe       FilterOp = "!="
+	FilterOpLt       FilterOp = "<"
+	FilterOpLe       FilterOp = "<="
+	FilterOpGt       FilterOp = ">"
+	FilterOpGe       FilterOp = ">="
+)
+
+// FilterExpr is a node of a parsed filter expression
+type FilterExpr interface {
+	// Match evaluates the expression against a todo in memory
+	Match(todo *Todo) bool
+}
+
+// FilterAnd matches todos matching all of its expressions
+type FilterAnd struct {
+	Exprs []FilterExpr
+}
+
+// FilterOr matches todos matching any of its expressions
+type FilterOr struct {
+	Exprs []FilterExpr
+}
+
+// FilterNot matches todos not matching its expression
+type FilterNot struct {
+	Expr FilterExpr
+}
+
+// FilterCondition compares a todo field with a value. Value is a bool for
+// completed, a string for title, description and tag and a time.Time for
+// created and updated. A tag condition is true when the todo has the tag.
+type FilterCondition struct {
+	Field FilterField
+	Op    FilterOp
+	Value interface{}
+}
+
+// Match implements FilterExpr
+func (e FilterAnd) Match(todo *Todo) bool {
+	for _, expr := range e.Exprs {
+		if !expr.Match(todo) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match implements FilterExpr
+func (e FilterOr) Match(todo *Todo) bool {
+	for _, expr := range e.Exprs {
+		if expr.Match(todo) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match implements FilterExpr
+func (e FilterNot) Match(todo *Todo) bool {
+	return !e.Expr.Match(todo)
+}
+
+// Match implements FilterExpr. Text comparisons are case-insensitive.
+func (c FilterCondition) Match(todo *Todo) bool {
+	switch value := c.Value.(type) {
+	case bool:
+		if c.Field != FilterFieldCompleted {
+			return false
+		}
+		return compareFilterValues(c.Op, todo.Completed == value, false)
+	case string:
+		if c.Field == FilterFieldTag {
+			return compareFilterValues(c.Op, todo.HasTag(value), false)
+		}
+		var text string
+		switch c.Field {
+		case FilterFieldTitle:
+			text = todo.Title
+		case FilterFieldDescription:
+			text = todo.Description
+		default:
+			return false
+		}
+		text, value = strings.ToLower(text), strings.ToLower(value)
+		if c.Op == FilterOpContains {
+			return strings.Contains(text, value)
+		}
+		return compareFilterValues(c.Op, text == value, text < value)
+	case time.Time:
+		var t time.Time
+		switch c.Field {
+		case FilterFieldCreated:
+			t = todo.CreatedAt
+		case FilterFieldUpdated:
+			t = todo.UpdatedAt
+		default:
+			return false
+		}
+		return compareFilterValues(c.Op, t.Equal(value), t.Before(value))
+	default:
+		return false
+	}
+}
+
+// compareFilterValues applies a comparison operator given whether the field
+// value is equal to and less than the condition value
+func compareFilterValues(op FilterOp, equal, less bool) bool {
+	switch op {
+	case FilterOpEq:
+		return equal
+	case FilterOpNe:
+		return !equal
+	case FilterOpLt:
+		return less
+	case FilterOpLe:
+		return less || equal
+	case FilterOpGt:
+		return !less && !equal
+	case FilterOpGe:
+		return !less
+	default:
+		return false
+	}
+}
+
+// FilterError is a syntax or type error in a filter expression
+type FilterError struct {
+	// Position is the 1-based character position of the offending token
+	Position int
+
+	// Token is the offending token, empty at the end of the expression
+	Token string
+
+	// Code is FilterErrorUnsupportedField for a known field that cannot be
+	// filtered on, FilterErrorInvalid otherwise
+	Code string
+
+	Message string
+}
+
+// Error implements the error interface
+func (e *FilterError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+	}
+	return fmt.Sprintf("%s at position %d (%q)", e.Message, e.Position, e.Token)
+}
+
+// ParseTodoFilter parses a filter expression such as
+//
+//	completed:false AND (title:report OR description:"quarterly report") AND created>=2026-01-01
+//
+// Conditions compare a field with a bare or "quoted" value and are combined with
+// AND, OR, NOT and parentheses. A blank expression returns a nil FilterExpr.
+func ParseTodoFilter(input string) (FilterExpr, error) {
+	if len(input) > MaxFilterLength {
+		return nil, &FilterError{
+			Position: utf8.RuneCountInString(input[:MaxFilterLength]) + 1,
+			Code:     FilterErrorInvalid,
+			Message:  fmt.Sprintf("filter is longer than %d bytes", MaxFilterLength),
+		}
+	}
+
+	tokens, err := lexFilter(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, nil
+	}
+
+	p := &filterParser{input: input, tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != filterTokenEOF {
+		if tok.kind == filterTokenRParen {
+			return nil, p.errorAt(tok, "unexpected closing parenthesis")
+		}
+		return nil, p.errorAt(tok, "expected AND or OR")
+	}
+	return expr, nil
+}
+
+// filterTokenKind classifies the tokens of a filter expression
+type filterTokenKind int
+
+const (
+	filterTokenEOF filterTokenKind = iota
+	filterTokenWord
+	filterTokenString
+	filterTokenOp
+	filterTokenLParen
+	filterTokenRParen
+)
+
+// filterToken is a token of a filter expression and its byte offset
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+// lexFilter splits a filter expression into tokens, ending with an EOF token.
+// A bare value following an operator extends to the next space or parenthesis,
+// so that times like 2026-01-01T10:00:00Z need no quotes.
+func lexFilter(input string) ([]filterToken, error) {
+	var tokens []filterToken
+	afterOp := false
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+			continue
+		case r == '(':
+			tokens = append(tokens, filterToken{kind: filterTokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{kind: filterTokenRParen, text: ")", pos: i})
+			i++
+		case r == '"':
+			text, end, ok := lexFilterString(input, i)
+			if !ok {
+				return nil, &FilterError{
+					Position: utf8.RuneCountInString(input[:i]) + 1,
+					Token:    input[i:],
+					Code:     FilterErrorInvalid,
+					Message:  "unterminated quoted value",
+				}
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenString, text: text, pos: i})
+			i = end
+		case !afterOp && strings.ContainsRune(":=!<>", r):
+			op := string(r)
+			if next := i + 1; next < len(input) && input[next] == '=' && r != ':' && r != '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &FilterError{
+					Position: utf8.RuneCountInString(input[:i]) + 1,
+					Token:    op,
+					Code:     FilterErrorInvalid,
+					Message:  "unexpected character, did you mean !=",
+				}
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenOp, text: op, pos: i})
+			i += len(op)
+			afterOp = true
+			continue
+		default:
+			end := i
+			for end < len(input) {
+				r, size := utf8.DecodeRuneInString(input[end:])
+				if unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' || (!afterOp && strings.ContainsRune(":=!<>", r)) {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenWord, text: input[i:end], pos: i})
+			i = end
+		}
+		afterOp = false
+	}
+	return append(tokens, filterToken{kind: filterTokenEOF, pos: len(input)}), nil
+}
+
+// lexFilterString reads the quoted value starting at input[start], in which
+// \" and \\ escape quotes and backslashes
+func lexFilterString(input string, start int) (string, int, bool) {
+	var b strings.Builder
+	for i := start + 1; i < len(input); i++ {
+		switch c := input[i]; c {
+		case '"':
+			return b.String(), i + 1, true
+		case '\\':
+			if i+1 < len(input) && (input[i+1] == '"' || input[i+1] == '\\') {
+				i++
+				c = input[i]
+			}
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, false
+}
+
+// filterParser is a recursive descent parser of filter expressions
+type filterParser struct {
+	input      string
+	tokens     []filterToken
+	next       int
+	depth      int
+	conditions int
+}
+
+// peek returns the next token without consuming it
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.next]
+}
+
+// advance consumes the next token
+func (p *filterParser) advance() filterToken {
+	tok := p.tokens[p.next]
+	if tok.kind != filterTokenEOF {
+		p.next++
+	}
+	return tok
+}
+
+// isKeyword reports whether tok is the keyword, keywords are case-insensitive
+func isKeyword(tok filterToken, keyword string) bool {
+	return tok.kind == filterTokenWord && strings.EqualFold(tok.text, keyword)
+}
+
+// errorAt returns an error pointing at tok
+func (p *filterParser) errorAt(tok filterToken, message string) *FilterError {
+	err := &FilterError{Position: utf8.RuneCountInString(p.input[:tok.pos]) + 1, Token: tok.text, Code: FilterErrorInvalid, Message: message}
+	if tok.kind == filterTokenString {
+		err.Token = strconv.Quote(tok.text)
+	}
+	if tok.kind == filterTokenEOF {
+		err.Message = "unexpected end of filter, " + message
+	}
+	return err
+}
+
+// parseOr parses expressions separated by OR
+func (p *filterParser) parseOr() (FilterExpr, error) {
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []FilterExpr{expr}
+	for isKeyword(p.peek(), "OR") {
+		p.advance()
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return FilterOr{Exprs: exprs}, nil
+}
+
+// parseAnd parses expressions separated by AND
+func (p *filterParser) parseAnd() (FilterExpr, error) {
+	expr, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []FilterExpr{expr}
+	for isKeyword(p.peek(), "AND") {
+		p.advance()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return FilterAnd{Exprs: exprs}, nil
+}
+
+// parseNot parses a possibly negated primary expression
+func (p *filterParser) parseNot() (FilterExpr, error) {
+	if tok := p.peek(); isKeyword(tok, "NOT") {
+		p.advance()
+		if err := p.enter(tok); err != nil {
+			return nil, err
+		}
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		p.depth--
+		return FilterNot{Expr: expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+// enter descends into a nested expression
+func (p *filterParser) enter(tok filterToken) error {
+	p.depth++
+	if p.depth > MaxFilterDepth {
+		return p.errorAt(tok, fmt.Sprintf("filter is nested deeper than %d levels", MaxFilterDepth))
+	}
+	return nil
+}
+
+// parsePrimary parses a parenthesized expression or a condition
+func (p *filterParser) parsePrimary() (FilterExpr, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == filterTokenLParen:
+		p.advance()
+		if err := p.enter(tok); err != nil {
+			return nil, err
+		}
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing.kind != filterTokenRParen {
+			return nil, p.errorAt(closing, "expected closing parenthesis")
+		}
+		p.advance()
+		p.depth--
+		return expr, nil
+	case tok.kind == filterTokenWord && !isKeyword(tok, "AND") && !isKeyword(tok, "OR"):
+		return p.parseCondition()
+	default:
+		return nil, p.errorAt(tok, "expected a condition such as completed:false")
+	}
+}
+
+// parseCondition parses a field, an operator and a value
+func (p *filterParser) parseCondition() (FilterExpr, error) {
+	fieldTok := p.advance()
+	field := FilterField(strings.ToLower(fieldTok.text))
+	if reason, ok := unsupportedFilterFields[field]; ok {
+		err := p.errorAt(fieldTok, string(field)+" cannot be filtered on, "+reason)
+		err.Code = FilterErrorUnsupportedField
+		return nil, err
+	}
+	if !isFilterField(field) {
+		names := make([]string, len(filterFields))
+		for i, f := range filterFields {
+			names[i] = string(f)
+		}
+		return nil, p.errorAt(fieldTok, "unknown field, expected one of "+strings.Join(names, ", "))
+	}
+
+	p.conditions++
+	if p.conditions > MaxFilterConditions {
+		return nil, p.errorAt(fieldTok, fmt.Sprintf("filter has more than %d conditions", MaxFilterConditions))
+	}
+
+	opTok := p.peek()
+	if opTok.kind != filterTokenOp {
+		return nil, p.errorAt(opTok, "expected an operator (:, =, !=, <, <=, >, >=) after "+string(field))
+	}
+	p.advance()
+	op := FilterOp(opTok.text)
+
+	valueTok := p.peek()
+	if valueTok.kind != filterTokenWord && valueTok.kind != filterTokenString {
+		return nil, p.errorAt(valueTok, "expected a value after "+opTok.text)
+	}
+	p.advance()
+
+	switch field {
+	case FilterFieldCompleted:
+		if op == FilterOpContains {
+			op = FilterOpEq
+		}
+		if op != FilterOpEq && op != FilterOpNe {
+			return nil, p.errorAt(opTok, "completed can only be compared with :, = or !=")
+		}
+		value, err := strconv.ParseBool(valueTok.text)
+		if err != nil {
+			return nil, p.errorAt(valueTok, "expected true or false")
+		}
+		return FilterCondition{Field: field, Op: op, Value: value}, nil
+	case FilterFieldTitle, FilterFieldDescription:
+		if op != FilterOpContains && op != FilterOpEq && op != FilterOpNe {
+			return nil, p.errorAt(opTok, string(field)+" can only be compared with :, = or !=")
+		}
+		return FilterCondition{Field: field, Op: op, Value: valueTok.text}, nil
+	case FilterFieldTag:
+		if op == FilterOpContains {
+			op = FilterOpEq
+		}
+		if op != FilterOpEq && op != FilterOpNe {
+			return nil, p.errorAt(opTok, "tag can only be compared with :, = or !=")
+		}
+		tag, ok := NormalizeTag(valueTok.text)
+		if !ok {
+			return nil, p.errorAt(valueTok, "expected a tag of letters, digits, - or _")
+		}
+		return FilterCondition{Field: field, Op: op, Value: tag}, nil
+	default:
+		if op == FilterOpContains {
+			op = FilterOpEq
+		}
+		return p.timeCondition(field, op, valueTok)
+	}
+}
+
+// timeCondition compares a time field with a time, or with a whole day for a
+// date value: created=2026-01-01 matches the todos created that day (UTC)
+func (p *filterParser) timeCondition(field FilterField, op FilterOp, valueTok filterToken) (FilterExpr, error) {
+	if t, err := time.Parse(time.RFC3339, valueTok.text); err == nil {
+		return FilterCondition{Field: field, Op: op, Value: t}, nil
+	}
+
+	day, err := time.Parse("2006-01-02", valueTok.text)
+	if err != nil {
+		return nil, p.errorAt(valueTok, "expected a date like 2026-01-31 or a time like 2026-01-31T15:04:05Z")
+	}
+	next := day.AddDate(0, 0, 1)
+
+	switch op {
+	case FilterOpEq:
+		return FilterAnd{Exprs: []FilterExpr{
+			FilterCondition{Field: field, Op: FilterOpGe, Value: day},
+			FilterCondition{Field: field, Op: FilterOpLt, Value: next},
+		}}, nil
+	case FilterOpNe:
+		return FilterOr{Exprs: []FilterExpr{
+			FilterCondition{Field: field, Op: FilterOpLt, Value: day},
+			FilterCondition{Field: field, Op: FilterOpGe, Value: next},
+		}}, nil
+	case FilterOpLt, FilterOpGe:
+		return FilterCondition{Field: field, Op: op, Value: day}, nil
+	case FilterOpLe:
+		return FilterCondition{Field: field, Op: FilterOpLt, Value: next}, nil
+	default:
+		return FilterCondition{Field: field, Op: FilterOpGe, Value: next}, nil
+	}
+}
+
+// isFilterField reports whether field can be filtered on
+func isFilterField(field FilterField) bool {
+	for _, f := range filterFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTodoFilter(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input string
+		want  FilterExpr
+	}{
+		{"  ", nil},
+		{"completed:false", FilterCondition{Field: FilterFieldCompleted, Op: FilterOpEq, Value: false}},
+		{`Title:"call plumber"`, FilterCondition{Field: FilterFieldTitle, Op: FilterOpContains, Value: "call plumber"}},
+		{"tag:Work", FilterCondition{Field: FilterFieldTag, Op: FilterOpEq, Value: "work"}},
+		{"tag!=urgent", FilterCondition{Field: FilterFieldTag, Op: FilterOpNe, Value: "urgent"}},
+		{"title:a OR title:b and NOT completed=true", FilterOr{Exprs: []FilterExpr{
+			FilterCondition{Field: FilterFieldTitle, Op: FilterOpContains, Value: "a"},
+			FilterAnd{Exprs: []FilterExpr{
+				FilterCondition{Field: FilterFieldTitle, Op: FilterOpContains, Value: "b"},
+				FilterNot{Expr: FilterCondition{Field: FilterFieldCompleted, Op: FilterOpEq, Value: true}},
+			}},
+		}}},
+		{"(title:a OR title:b) AND tag:c", FilterAnd{Exprs: []FilterExpr{
+			FilterOr{Exprs: []FilterExpr{
+				FilterCondition{Field: FilterFieldTitle, Op: FilterOpContains, Value: "a"},
+				FilterCondition{Field: FilterFieldTitle, Op: FilterOpContains, Value: "b"},
+			}},
+			FilterCondition{Field: FilterFieldTag, Op: FilterOpEq, Value: "c"},
+		}}},
+		{"created=2026-01-01", FilterAnd{Exprs: []FilterExpr{
+			FilterCondition{Field: FilterFieldCreated, Op: FilterOpGe, Value: day},
+			FilterCondition{Field: FilterFieldCreated, Op: FilterOpLt, Value: day.AddDate(0, 0, 1)},
+		}}},
+		{"updated<=2026-01-01", FilterCondition{Field: FilterFieldUpdated, Op: FilterOpLt, Value: day.AddDate(0, 0, 1)}},
+		{"created>2026-01-01T00:00:00Z", FilterCondition{Field: FilterFieldCreated, Op: FilterOpGt, Value: day}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTodoFilter(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTodoFilterErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		position int
+		token    string
+		code     string
+	}{
+		{"titel:report", 1, "titel", FilterErrorInvalid},
+		{"completed:false AND priority>2", 21, "priority", FilterErrorUnsupportedField},
+		{"completed:maybe", 11, "maybe", FilterErrorInvalid},
+		{"completed<true", 10, "<", FilterErrorInvalid},
+		{"tag>work", 4, ">", FilterErrorInvalid},
+		{`tag:"two words"`, 5, `"two words"`, FilterErrorInvalid},
+		{`title:"open`, 7, `"open`, FilterErrorInvalid},
+		{"title ! x", 7, "!", FilterErrorInvalid},
+		{"title:", 7, "", FilterErrorInvalid},
+		{"(completed:true", 16, "", FilterErrorInvalid},
+		{"completed:true)", 15, ")", FilterErrorInvalid},
+		{"completed:true title:a", 16, "title", FilterErrorInvalid},
+		{"created>yesterday", 9, "yesterday", FilterErrorInvalid},
+		{"title:café AND x", 16, "x", FilterErrorInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseTodoFilter(tt.input)
+			var filterErr *FilterError
+			if !errors.As(err, &filterErr) {
+				t.Fatalf("got error %v, want a *FilterError", err)
+			}
+			if filterErr.Position != tt.position || filterErr.Token != tt.token || filterErr.Code != tt.code {
+				t.Errorf("got position %d, token %q, code %s, want %d, %q, %s (%v)",
+					filterErr.Position, filterErr.Token, filterErr.Code, tt.position, tt.token, tt.code, err)
+			}
+		})
+	}
+}
+
+func TestLexFilter(t *testing.T) {
+	tokens, err := lexFilter(`NOT (created>=2026-01-01T10:00:00Z OR title:"a \"b\"")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []filterToken{
+		{kind: filterTokenWord, text: "NOT", pos: 0},
+		{kind: filterTokenLParen, text: "(", pos: 4},
+		{kind: filterTokenWord, text: "created", pos: 5},
+		{kind: filterTokenOp, text: ">=", pos: 12},
+		{kind: filterTokenWord, text: "2026-01-01T10:00:00Z", pos: 14},
+		{kind: filterTokenWord, text: "OR", pos: 35},
+		{kind: filterTokenWord, text: "title", pos: 38},
+		{kind: filterTokenOp, text: ":", pos: 43},
+		{kind: filterTokenString, text: `a "b"`, pos: 44},
+		{kind: filterTokenRParen, text: ")", pos: 53},
+		{kind: filterTokenEOF, pos: 54},
+	}
+	if !reflect.DeepEqual(tokens, want) {
+		t.Errorf("got tokens %+v, want %+v", tokens, want)
+	}
+}
+
+func TestFilterConditionMatchesTags(t *testing.T) {
+	todo := &Todo{Title: "report"}
+	todo.AddTags("work")
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"tag:work", true},
+		{"tag:WORK", true},
+		{"tag:wor", false},
+		{"tag!=work", false},
+		{"tag!=urgent", true},
+		{"title:rep AND NOT tag:urgent", true},
+	}
+	for _, tt := range tests {
+		expr, err := ParseTodoFilter(tt.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := expr.Match(todo); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"time"
+)
+
+// Project represents a list of todos shared by its members
+type Project struct {
+	ID        uint      `gorm:"primaryKey"`
+	Name      string    `gorm:"size:100;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	// WorkspaceID is the workspace the project belongs to, nil outside any workspace.
+	// Repositories set it from the context on creation.
+	WorkspaceID *uint `gorm:"index"`
+}
+
+// ProjectRole is the role of a member in a project
+type ProjectRole string
+
+// Project roles, each grants the permissions of the previous ones
+const (
+	ProjectRoleViewer ProjectRole = "viewer"
+	ProjectRoleEditor ProjectRole = "editor"
+	ProjectRoleOwner  ProjectRole = "owner"
+)
+
+// Permission is an action on todos or projects that requires access
+type Permission string
+
+// Permissions
+const (
+	// PermissionView allows reading todos
+	PermissionView Permission = "view"
+
+	// PermissionEdit allows creating, changing and deleting todos
+	PermissionEdit Permission = "edit"
+
+	// PermissionManage allows renaming and deleting projects and managing their members
+	PermissionManage Permission = "manage"
+)
+
+// IsValid reports whether r is a known role
+func (r ProjectRole) IsValid() bool {
+	switch r {
+	case ProjectRoleViewer, ProjectRoleEditor, ProjectRoleOwner:
+		return true
+	default:
+		return false
+	}
+}
+
+// Allows reports whether the role grants the permission
+func (r ProjectRole) Allows(permission Permission) bool {
+	switch permission {
+	case PermissionView:
+		return r.IsValid()
+	case PermissionEdit:
+		return r == ProjectRoleEditor || r == ProjectRoleOwner
+	case PermissionManage:
+		return r == ProjectRoleOwner
+	default:
+		return false
+	}
+}
+
+// ProjectMember represents the membership of a user in a project
+type ProjectMember struct {
+	ProjectID uint        `gorm:"primaryKey;autoIncrement:false"`
+	Project   Project     `gorm:"foreignKey:ProjectID"`
+	UserID    uint        `gorm:"primaryKey;autoIncrement:false;index"`
+	User      User        `gorm:"foreignKey:UserID"`
+	Role      ProjectRole `gorm:"size:20;not null"`
+	CreatedAt time.Time   `gorm:"autoCreateTime"`
+	UpdatedAt time.Time   `gorm:"autoUpdateTime"`
+}
+
+// NewProject creates a new Project entity
+func NewProject(name string) *Project {
+	return &Project{
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Rename renames the project
+func (p *Project) Rename(name string) {
+	p.Name = name
+	p.UpdatedAt = time.Now()
+}
+
+// NewProjectMember creates a new ProjectMember entity
+func NewProjectMember(projectID, userID uint, role ProjectRole) *ProjectMember {
+	return &ProjectMember{
+		ProjectID: projectID,
+		UserID:    userID,
+		Role:      role,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// ChangeRole changes the role of the member
+func (m *ProjectMember) ChangeRole(role ProjectRole) {
+	m.Role = role
+	m.UpdatedAt = time.Now()
+}
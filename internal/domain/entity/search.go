@@ -0,0 +1,235 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Highlight markers delimit the matched words in search highlights. They are
+// control characters, so they cannot be confused with the text itself.
+const (
+	HighlightStart = "\x02"
+	HighlightEnd   = "\x03"
+)
+
+// SearchQuery is a parsed full-text search query. A todo matches when it
+// contains every positive term and none of the negated ones.
+type SearchQuery struct {
+	Terms []SearchTerm
+}
+
+// SearchTerm is a word or a phrase of a search query
+type SearchTerm struct {
+	// Words are lower-cased, a phrase has several words that must appear in order
+	Words []string
+
+	// Prefix matches words starting with the last word
+	Prefix bool
+
+	// Negated excludes todos containing the term
+	Negated bool
+}
+
+// TodoMatch is a todo matching a full-text search
+type TodoMatch struct {
+	Todo *Todo
+	Rank float64
+
+	// TitleHighlight and DescriptionHighlight mark the matched words
+	// between HighlightStart and HighlightEnd
+	TitleHighlight       string
+	DescriptionHighlight string
+}
+
+// ParseSearchQuery parses a web search style query: words, "quoted phrases",
+// prefix* and -negated terms. Punctuation separates words and is otherwise ignored.
+func ParseSearchQuery(query string) SearchQuery {
+	var parsed SearchQuery
+
+	for query != "" {
+		query = strings.TrimLeftFunc(query, unicode.IsSpace)
+		if query == "" {
+			break
+		}
+
+		var term SearchTerm
+		if query[0] == '-' {
+			term.Negated = true
+			query = query[1:]
+		}
+
+		var text string
+		if strings.HasPrefix(query, `"`) {
+			end := strings.IndexByte(query[1:], '"')
+			if end < 0 {
+				text, query = query[1:], ""
+			} else {
+				text, query = query[1:end+1], query[end+2:]
+			}
+		} else {
+			end := strings.IndexFunc(query, unicode.IsSpace)
+			if end < 0 {
+				end = len(query)
+			}
+			text, query = query[:end], query[end:]
+			term.Prefix = strings.HasSuffix(text, "*")
+		}
+
+		term.Words = searchWords(text)
+		if len(term.Words) > 0 {
+			parsed.Terms = append(parsed.Terms, term)
+		}
+	}
+
+	return parsed
+}
+
+// HasPositiveTerms reports whether the query has a term that todos must contain
+func (q SearchQuery) HasPositiveTerms() bool {
+	for _, term := range q.Terms {
+		if !term.Negated {
+			return true
+		}
+	}
+	return false
+}
+
+// Match matches the todo against the query in memory. It finds exact words
+// only, storage backends with a text search engine also match word stems.
+func (q SearchQuery) Match(todo *Todo) (*TodoMatch, bool) {
+	if !q.HasPositiveTerms() {
+		return nil, false
+	}
+
+	titleWords := textWords(todo.Title)
+	descriptionWords := textWords(todo.Description)
+
+	var titleHits, descriptionHits []wordRange
+	for _, term := range q.Terms {
+		inTitle := term.find(titleWords)
+		inDescription := term.find(descriptionWords)
+		found := len(inTitle) > 0 || len(inDescription) > 0
+		if found == term.Negated {
+			return nil, false
+		}
+		if !term.Negated {
+			titleHits = append(titleHits, inTitle...)
+			descriptionHits = append(descriptionHits, inDescription...)
+		}
+	}
+
+	return &TodoMatch{
+		Todo: todo,
+		// Title matches weigh more, like the weights of the Postgres search vector
+		Rank:                 float64(2*len(titleHits)+len(descriptionHits)) / float64(1+len(titleWords)+len(descriptionWords)),
+		TitleHighlight:       highlight(todo.Title, titleWords, titleHits),
+		DescriptionHighlight: highlight(todo.Description, descriptionWords, descriptionHits),
+	}, true
+}
+
+// SortTodoMatches orders matches by relevance, then newest first
+func SortTodoMatches(matches []*TodoMatch) {
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Rank != b.Rank {
+			return a.Rank > b.Rank
+		}
+		if !a.Todo.CreatedAt.Equal(b.Todo.CreatedAt) {
+			return a.Todo.CreatedAt.After(b.Todo.CreatedAt)
+		}
+		return a.Todo.ID > b.Todo.ID
+	})
+}
+
+// textWord is a lower-cased word of a text and its byte offsets
+type textWord struct {
+	word       string
+	start, end int
+}
+
+// wordRange is a range of consecutive words, end exclusive
+type wordRange struct {
+	start, end int
+}
+
+// find returns the ranges of words matching the term
+func (t SearchTerm) find(words []textWord) []wordRange {
+	var found []wordRange
+	for i := 0; i+len(t.Words) <= len(words); i++ {
+		matched := true
+		for j, want := range t.Words {
+			got := words[i+j].word
+			last := j == len(t.Words)-1
+			if got != want && !(last && t.Prefix && strings.HasPrefix(got, want)) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			found = append(found, wordRange{start: i, end: i + len(t.Words)})
+		}
+	}
+	return found
+}
+
+// searchWords splits text into lower-cased words
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), isNotWordRune)
+}
+
+// textWords splits text into lower-cased words with their offsets
+func textWords(text string) []textWord {
+	var words []textWord
+	start := -1
+	for i, r := range text {
+		if isNotWordRune(r) {
+			if start >= 0 {
+				words = append(words, textWord{word: strings.ToLower(text[start:i]), start: start, end: i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, textWord{word: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return words
+}
+
+// isNotWordRune reports whether r separates words
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+// highlight marks the word ranges in text
+func highlight(text string, words []textWord, hits []wordRange) string {
+	if len(hits) == 0 {
+		return text
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		return hits[i].start < hits[j].start
+	})
+
+	var b strings.Builder
+	pos, covered := 0, 0
+	for _, hit := range hits {
+		if hit.end <= covered {
+			continue
+		}
+		if hit.start < covered {
+			hit.start = covered
+		}
+		start, end := words[hit.start].start, words[hit.end-1].end
+		b.WriteString(text[pos:start])
+		b.WriteString(HighlightStart)
+		b.WriteString(text[start:end])
+		b.WriteString(HighlightEnd)
+		pos, covered = end, hit.end
+	}
+	b.WriteString(text[pos:])
+
+	return b.String()
+}
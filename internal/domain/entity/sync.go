@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"time"
+)
+
+// TodoTombstone records the deletion of a todo, so that delta sync clients
+// learn about it
+type TodoTombstone struct {
+	ID     uint `gorm:"primaryKey"`
+	TodoID uint `gorm:"not null"`
+
+	// UserID and ProjectID are those of the deleted todo, which scope the
+	// tombstone like the todo
+	UserID    uint  `gorm:"not null;index"`
+	ProjectID *uint `gorm:"index"`
+
+	// ChangeSeq is the change sequence number of the deletion, and ChangeXID
+	// its transaction like for todos
+	ChangeSeq uint64 `gorm:"not null;uniqueIndex"`
+	ChangeXID uint64 `gorm:"column:change_xid;not null;default:0;index"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	// WorkspaceID is the workspace of the todo, nil outside any workspace
+	WorkspaceID *uint `gorm:"index"`
+}
+
+// NewTodoTombstone creates a new TodoTombstone for a todo deleted by the change changeSeq
+func NewTodoTombstone(todo *Todo, changeSeq uint64) *TodoTombstone {
+	return &TodoTombstone{
+		TodoID:      todo.ID,
+		UserID:      todo.UserID,
+		ProjectID:   todo.ProjectID,
+		ChangeSeq:   changeSeq,
+		CreatedAt:   time.Now(),
+		WorkspaceID: todo.WorkspaceID,
+	}
+}
+
+// ChangePosition is the position of a change of a todo in the order delta
+// sync clients receive them in, by transaction, then by change sequence
+// number. Concurrent transactions may commit out of that order, so
+// repositories only return the changes no running transaction can precede,
+// see TodoChanges.Until.
+type ChangePosition struct {
+	XID uint64
+	Seq uint64
+}
+
+// Before reports whether the position comes before other
+func (p ChangePosition) Before(other ChangePosition) bool {
+	if p.XID != other.XID {
+		return p.XID < other.XID
+	}
+	return p.Seq < other.Seq
+}
+
+// ChangePosition returns the position of the deletion
+func (t *TodoTombstone) ChangePosition() ChangePosition {
+	return ChangePosition{XID: t.ChangeXID, Seq: t.ChangeSeq}
+}
+
+// TodoChangeFilter selects the changes of the personal todos of UserID and of
+// the todos of ProjectIDs after the position Since
+type TodoChangeFilter struct {
+	UserID     uint
+	ProjectIDs []uint
+	Since      ChangePosition
+
+	// Limit caps the number of todos and, separately, of tombstones
+	Limit int
+}
+
+// TodoChanges are the changes selected by a TodoChangeFilter, each in the
+// order of their positions
+type TodoChanges struct {
+	// Todos are the todos written after Since, in their current state
+	Todos []*Todo
+
+	// Tombstones record the todos deleted after Since
+	Tombstones []*TodoTombstone
+
+	// Until is the last position of the changes that were final when the
+	// changes were read. They stop at it, and none up to it are left out but
+	// for Limit. Changes made later come after it.
+	Until ChangePosition
+}
+
+// SyncToken marks the position of a delta sync client in the changes of todos
+type SyncToken struct {
+	// Position is the position of the last change the client received
+	Position ChangePosition
+
+	// Scope identifies the workspace and the projects the client synced,
+	// the changes since Position are incomplete when it differs
+	Scope uint64
+}
+
+// IsZero reports whether the token selects a full sync
+func (t SyncToken) IsZero() bool {
+	return t.Position == ChangePosition{}
+}
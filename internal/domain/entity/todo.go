@@ -1,19 +1,57 @@
 package entity
 
 import (
+	"sort"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits of the tags of a todo
+const (
+	MaxTodoTags  = 20
+	MaxTagLength = 32
 )
 
 // Todo represents a todo item entity
 type Todo struct {
-	ID          uint      `gorm:"primaryKey"`
+	ID          uint      `gorm:"primaryKey;index:idx_todos_user_created,priority:3;index:idx_todos_project_created,priority:3"`
 	Title       string    `gorm:"size:255;not null"`
 	Description string    `gorm:"type:text"`
 	Completed   bool      `gorm:"default:false"`
-	UserID      uint      `gorm:"not null"`
+	UserID      uint      `gorm:"not null;index:idx_todos_user_created,priority:1"`
 	User        User      `gorm:"foreignKey:UserID"`
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;index:idx_todos_user_created,priority:2;index:idx_todos_project_created,priority:2"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+
+	// ProjectID is the project sharing the todo, nil for a personal todo of UserID
+	ProjectID *uint `gorm:"index:idx_todos_project_created,priority:1"`
+
+	// AssigneeID is the user responsible for the todo, nil when unassigned.
+	// UserID remains the creator of the todo.
+	AssigneeID *uint `gorm:"index"`
+
+	// WorkspaceID is the workspace the todo belongs to, nil outside any workspace.
+	// Repositories set it from the context on creation.
+	WorkspaceID *uint `gorm:"index"`
+
+	// Tags label the todo, they are normalized by NormalizeTag and sorted
+	Tags []string `gorm:"type:text;serializer:json"`
+
+	// ChangeSeq is the change sequence number of the last write of the todo.
+	// Repositories allocate increasing numbers, unique across all todos.
+	ChangeSeq uint64 `gorm:"not null;default:0;index"`
+
+	// ChangeXID identifies the transaction of the last write of the todo
+	// where the database numbers transactions, zero otherwise. The database
+	// sets it, see ChangePosition.
+	ChangeXID uint64 `gorm:"column:change_xid;not null;default:0;index"`
+}
+
+// ChangePosition returns the position of the last write of the todo
+func (t *Todo) ChangePosition() ChangePosition {
+	return ChangePosition{XID: t.ChangeXID, Seq: t.ChangeSeq}
 }
 
 // TodoFilter represents the filters for querying todos
@@ -23,6 +61,106 @@ type TodoFilter struct {
 	Search    string
 	Page      int
 	PageSize  int
+
+	// ProjectID selects the todos of a project instead of the personal todos of UserID
+	ProjectID *uint
+
+	// AssigneeID restricts the todos to those assigned to a user. Without
+	// ProjectID it selects the todos assigned to the user across the personal
+	// todos and the projects in scope instead of the personal todos of UserID.
+	AssigneeID *uint
+
+	// Sort orders offset pages, the zero value orders newest first. Keyset
+	// pages are always newest first and full-text searches most relevant first.
+	Sort TodoSort
+
+	// Expr restricts the todos to those matching a filter expression, nil matches all
+	Expr FilterExpr
+
+	// FullText restricts the todos to those matching a full-text search query,
+	// it cannot be combined with Cursor
+	FullText *SearchQuery
+
+	// Cursor selects keyset pagination instead of Page, nil uses Page
+	Cursor *TodoCursor
+}
+
+// TodoSort is an order of todos
+type TodoSort string
+
+// Todo orders, ties are broken by ID in the same direction
+const (
+	TodoSortCreatedDesc TodoSort = "created_desc"
+	TodoSortCreatedAsc  TodoSort = "created_asc"
+	TodoSortUpdatedDesc TodoSort = "updated_desc"
+	TodoSortTitleAsc    TodoSort = "title_asc"
+)
+
+// IsValid reports whether s is a known order, the zero value orders newest first
+func (s TodoSort) IsValid() bool {
+	switch s {
+	case "", TodoSortCreatedDesc, TodoSortCreatedAsc, TodoSortUpdatedDesc, TodoSortTitleAsc:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsDefault reports whether s orders newest first
+func (s TodoSort) IsDefault() bool {
+	return s == "" || s == TodoSortCreatedDesc
+}
+
+// Less reports whether a comes before b in this order. Titles are compared
+// ignoring case.
+func (s TodoSort) Less(a, b *Todo) bool {
+	switch s {
+	case TodoSortCreatedAsc:
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	case TodoSortUpdatedDesc:
+		if !a.UpdatedAt.Equal(b.UpdatedAt) {
+			return a.UpdatedAt.After(b.UpdatedAt)
+		}
+		return a.ID > b.ID
+	case TodoSortTitleAsc:
+		if titleA, titleB := strings.ToLower(a.Title), strings.ToLower(b.Title); titleA != titleB {
+			return titleA < titleB
+		}
+		return a.ID < b.ID
+	default:
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	}
+}
+
+// TodoCursor marks a position in the list of todos, which is ordered by
+// creation time and ID, newest first. A zero cursor selects the first page.
+type TodoCursor struct {
+	CreatedAt time.Time
+	ID        uint
+
+	// Backward selects the todos before the cursor instead of those after it
+	Backward bool
+}
+
+// IsZero reports whether the cursor selects the first page
+func (c TodoCursor) IsZero() bool {
+	return c.ID == 0
+}
+
+// TodoCursorAfter returns the cursor selecting the todos after todo
+func TodoCursorAfter(todo *Todo) *TodoCursor {
+	return &TodoCursor{CreatedAt: todo.CreatedAt, ID: todo.ID}
+}
+
+// TodoCursorBefore returns the cursor selecting the todos before todo
+func TodoCursorBefore(todo *Todo) *TodoCursor {
+	return &TodoCursor{CreatedAt: todo.CreatedAt, ID: todo.ID, Backward: true}
 }
 
 // NewTodo creates a new Todo entity
@@ -57,7 +195,100 @@ func (t *Todo) Update(title, description string, completed bool) {
 	t.UpdatedAt = time.Now()
 }
 
+// AssignTo assigns the todo to a user
+func (t *Todo) AssignTo(userID uint) {
+	t.AssigneeID = &userID
+	t.UpdatedAt = time.Now()
+}
+
+// Unassign removes the assignee of the todo
+func (t *Todo) Unassign() {
+	t.AssigneeID = nil
+	t.UpdatedAt = time.Now()
+}
+
+// MoveTo moves the todo to a project
+func (t *Todo) MoveTo(projectID uint) {
+	t.ProjectID = &projectID
+	t.UpdatedAt = time.Now()
+}
+
+// AddTags adds normalized tags to the todo and reports whether any was missing
+func (t *Todo) AddTags(tags ...string) bool {
+	added := make([]string, 0, len(t.Tags)+len(tags))
+	added = append(added, t.Tags...)
+	for _, tag := range tags {
+		if !t.HasTag(tag) && !containsTag(added[len(t.Tags):], tag) {
+			added = append(added, tag)
+		}
+	}
+	if len(added) == len(t.Tags) {
+		return false
+	}
+	sort.Strings(added)
+	t.Tags = added
+	t.UpdatedAt = time.Now()
+	return true
+}
+
+// RemoveTags removes tags from the todo and reports whether any was present
+func (t *Todo) RemoveTags(tags ...string) bool {
+	kept := make([]string, 0, len(t.Tags))
+	for _, tag := range t.Tags {
+		if !containsTag(tags, tag) {
+			kept = append(kept, tag)
+		}
+	}
+	if len(kept) == len(t.Tags) {
+		return false
+	}
+	t.Tags = kept
+	t.UpdatedAt = time.Now()
+	return true
+}
+
+// HasTag reports whether the todo has a normalized tag
+func (t *Todo) HasTag(tag string) bool {
+	return containsTag(t.Tags, tag)
+}
+
+// containsTag reports whether tags contains tag
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTag returns the lowercase form of a tag without surrounding
+// spaces. It reports false for an empty tag, a tag longer than MaxTagLength
+// characters or a tag with other characters than letters, digits, - and _.
+func NormalizeTag(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || utf8.RuneCountInString(tag) > MaxTagLength {
+		return "", false
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return "", false
+		}
+	}
+	return tag, true
+}
+
+// IsAssignedTo reports whether the todo is assigned to the specified user
+func (t *Todo) IsAssignedTo(userID uint) bool {
+	return t.AssigneeID != nil && *t.AssigneeID == userID
+}
+
 // BelongsToUser checks if the todo belongs to the specified user
 func (t *Todo) BelongsToUser(userID uint) bool {
 	return t.UserID == userID
 }
+
+// IsShared reports whether the todo belongs to a project rather than to its creator
+func (t *Todo) IsShared() bool {
+	return t.ProjectID != nil
+}
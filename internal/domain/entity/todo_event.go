@@ -0,0 +1,98 @@
+package entity
+
+import "time"
+
+// TodoEventType is the type of a todo event published to subscribers such as webhooks
+type TodoEventType string
+
+// Todo event types, one per activity action
+const (
+	TodoEventCreated   TodoEventType = "todo.created"
+	TodoEventUpdated   TodoEventType = "todo.updated"
+	TodoEventCompleted TodoEventType = "todo.completed"
+	TodoEventDeleted   TodoEventType = "todo.deleted"
+)
+
+// TodoEventTypes lists all todo event types
+var TodoEventTypes = []TodoEventType{TodoEventCreated, TodoEventUpdated, TodoEventCompleted, TodoEventDeleted}
+
+// IsValid reports whether the event type is one of TodoEventTypes
+func (t TodoEventType) IsValid() bool {
+	for _, eventType := range TodoEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// TodoEvent is a stored change of a todo, published once it was recorded in
+// the history of the todo
+type TodoEvent struct {
+	// Activity is the recorded change, its ID identifies the event
+	Activity *Activity
+
+	// Todo is a snapshot of the todo after the change, or before it for a
+	// deleted todo
+	Todo Todo
+
+	// Truncated is true when the description was left out of Todo and of the
+	// changes, because the event was too large to be relayed as is
+	Truncated bool
+}
+
+// NewTodoEvent creates a new TodoEvent for a change of a todo by userID
+func NewTodoEvent(todo *Todo, userID uint, action ActivityAction, changes []FieldChange) *TodoEvent {
+	return &TodoEvent{
+		Activity: NewActivity(todo.ID, userID, action, changes),
+		Todo:     *todo,
+	}
+}
+
+// Type returns the type of the event
+func (e *TodoEvent) Type() TodoEventType {
+	return TodoEventType("todo." + string(e.Activity.Action))
+}
+
+// OutboxEvent is a todo event waiting in the outbox for its webhook deliveries
+// to be scheduled. The todo repository writes it in the transaction of the
+// change, so that no committed change misses its deliveries.
+type OutboxEvent struct {
+	ID uint `gorm:"primaryKey"`
+
+	// Event is the todo event, stored as JSON
+	Event TodoEvent `gorm:"type:text;serializer:json;not null"`
+
+	// WorkspaceID is the workspace of the todo, nil outside any workspace
+	WorkspaceID *uint
+
+	// ClaimedUntil is when the claim of the scheduler handling the event
+	// expires, the zero time for an event that was never claimed
+	ClaimedUntil time.Time `gorm:"not null;index"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// NewOutboxEvents creates the OutboxEvents of the recorded changes of todos,
+// with snapshots of the todos as written. Activities on other todos are left out.
+func NewOutboxEvents(activities []*Activity, todos []*Todo) []*OutboxEvent {
+	byID := make(map[uint]*Todo, len(todos))
+	for _, todo := range todos {
+		byID[todo.ID] = todo
+	}
+
+	events := make([]*OutboxEvent, 0, len(activities))
+	for _, activity := range activities {
+		todo, ok := byID[activity.TodoID]
+		if !ok {
+			continue
+		}
+		snapshot := *todo
+		snapshot.User = User{}
+		events = append(events, &OutboxEvent{
+			Event:       TodoEvent{Activity: activity, Todo: snapshot},
+			WorkspaceID: activity.WorkspaceID,
+		})
+	}
+	return events
+}
@@ -13,6 +13,10 @@ type User struct {
 	Todos     []Todo    `gorm:"foreignKey:UserID"`
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	// TokenVersion is embedded in the access tokens issued to the user.
+	// Incrementing it revokes all tokens issued before.
+	TokenVersion uint `gorm:"not null;default:0"`
 }
 
 // NewUser creates a new User entity
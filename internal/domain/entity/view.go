@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"time"
+)
+
+// View represents a todo filter saved under a name by a user, a smart list
+type View struct {
+	ID     uint   `gorm:"primaryKey"`
+	UserID uint   `gorm:"not null;uniqueIndex:idx_views_user_name,priority:1"`
+	User   User   `gorm:"foreignKey:UserID"`
+	Name   string `gorm:"size:100;not null;uniqueIndex:idx_views_user_name,priority:2"`
+
+	// Completed, Search and Filter are the serialized filters of the view,
+	// Filter is the source of a filter expression
+	Completed *bool
+	Search    string   `gorm:"size:255"`
+	Filter    string   `gorm:"type:text"`
+	Sort      TodoSort `gorm:"size:20"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// NewView creates a new View entity
+func NewView(userID uint, name string, completed *bool, search, filter string, sort TodoSort) *View {
+	return &View{
+		UserID:    userID,
+		Name:      name,
+		Completed: completed,
+		Search:    search,
+		Filter:    filter,
+		Sort:      sort,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Update updates the view with the provided details
+func (v *View) Update(name string, completed *bool, search, filter string, sort TodoSort) {
+	v.Name = name
+	v.Completed = completed
+	v.Search = search
+	v.Filter = filter
+	v.Sort = sort
+	v.UpdatedAt = time.Now()
+}
+
+// TodoFilter deserializes the filters of the view. It fails with a
+// *FilterError when the filter expression does not parse.
+func (v *View) TodoFilter() (TodoFilter, error) {
+	expr, err := ParseTodoFilter(v.Filter)
+	if err != nil {
+		return TodoFilter{}, err
+	}
+	return TodoFilter{
+		UserID:    v.UserID,
+		Completed: v.Completed,
+		Search:    v.Search,
+		Expr:      expr,
+		Sort:      v.Sort,
+	}, nil
+}
+
+// BelongsToUser checks if the view belongs to the specified user
+func (v *View) BelongsToUser(userID uint) bool {
+	return v.UserID == userID
+}
@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"time"
+)
+
+// Webhook is an HTTP endpoint registered by a user to receive the todo events
+// of a workspace
+type Webhook struct {
+	ID     uint   `gorm:"primaryKey"`
+	UserID uint   `gorm:"not null;index"`
+	User   User   `gorm:"foreignKey:UserID"`
+	URL    string `gorm:"size:2048;not null"`
+
+	// Secret is the key of the HMAC-SHA256 signature of the deliveries
+	Secret string `gorm:"size:128;not null"`
+
+	// Events are the event types delivered to the endpoint
+	Events []TodoEventType `gorm:"type:text;serializer:json"`
+
+	// Active webhooks receive new events and have their pending deliveries sent
+	Active bool `gorm:"not null"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	// WorkspaceID is the workspace whose events are delivered, nil outside any
+	// workspace. Repositories set it from the context on creation.
+	WorkspaceID *uint `gorm:"index"`
+}
+
+// NewWebhook creates a new active Webhook entity
+func NewWebhook(userID uint, url, secret string, events []TodoEventType) *Webhook {
+	return &Webhook{
+		UserID:    userID,
+		URL:       url,
+		Secret:    secret,
+		Events:    events,
+		Active:    true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Update updates the webhook with the provided details
+func (w *Webhook) Update(url string, events []TodoEventType, active bool) {
+	w.URL = url
+	w.Events = events
+	w.Active = active
+	w.UpdatedAt = time.Now()
+}
+
+// BelongsToUser checks if the webhook belongs to the specified user
+func (w *Webhook) BelongsToUser(userID uint) bool {
+	return w.UserID == userID
+}
+
+// Subscribes reports whether the webhook receives events of a type
+func (w *Webhook) Subscribes(eventType TodoEventType) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryStatus is the state of a webhook delivery
+type DeliveryStatus string
+
+// Delivery statuses
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookDelivery is the delivery of an event to a webhook, kept as the log
+// of its attempts
+type WebhookDelivery struct {
+	ID        uint     `gorm:"primaryKey;index:idx_webhook_deliveries_webhook,priority:2"`
+	WebhookID uint     `gorm:"not null;index:idx_webhook_deliveries_webhook,priority:1"`
+	Webhook   *Webhook `gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE"`
+
+	// EventID and EventType identify the delivered event, redeliveries share them
+	EventID   uint          `gorm:"not null"`
+	EventType TodoEventType `gorm:"size:50;not null"`
+
+	// Payload is the JSON request body
+	Payload string `gorm:"type:text;not null"`
+
+	Status   DeliveryStatus `gorm:"size:20;not null;index:idx_webhook_deliveries_due,priority:1"`
+	Attempts int            `gorm:"not null"`
+
+	// NextAttemptAt is when a pending delivery is due
+	NextAttemptAt time.Time `gorm:"not null;index:idx_webhook_deliveries_due,priority:2"`
+
+	// LastAttemptAt, ResponseStatus and Error describe the latest attempt,
+	// ResponseStatus is zero when no response was received
+	LastAttemptAt  *time.Time
+	ResponseStatus int
+	Error          string `gorm:"size:1024"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	// WorkspaceID is the workspace of the webhook, nil outside any workspace.
+	// Repositories set it from the context on creation.
+	WorkspaceID *uint `gorm:"index"`
+}
+
+// NewWebhookDelivery creates a new WebhookDelivery entity, due immediately
+func NewWebhookDelivery(webhookID, eventID uint, eventType TodoEventType, payload string) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Succeed records a successful attempt
+func (d *WebhookDelivery) Succeed(responseStatus int, at time.Time) {
+	d.Status = DeliverySucceeded
+	d.Attempts++
+	d.LastAttemptAt = &at
+	d.ResponseStatus = responseStatus
+	d.Error = ""
+	d.UpdatedAt = at
+}
+
+// Fail records a failed attempt. The delivery is retried at retryAt, or
+// given up when retryAt is nil.
+func (d *WebhookDelivery) Fail(responseStatus int, message string, at time.Time, retryAt *time.Time) {
+	d.Attempts++
+	d.LastAttemptAt = &at
+	d.ResponseStatus = responseStatus
+	d.Error = message
+	d.UpdatedAt = at
+	if retryAt == nil {
+		d.Status = DeliveryFailed
+		return
+	}
+	d.NextAttemptAt = *retryAt
+}
+
+// Redeliver creates a new delivery of the same event, due immediately
+func (d *WebhookDelivery) Redeliver() *WebhookDelivery {
+	return NewWebhookDelivery(d.WebhookID, d.EventID, d.EventType, d.Payload)
+}
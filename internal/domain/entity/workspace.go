@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"time"
+)
+
+// Workspace represents a team, whose todos and projects are isolated from
+// those of other workspaces
+type Workspace struct {
+	ID        uint      `gorm:"primaryKey"`
+	Name      string    `gorm:"size:100;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// WorkspaceRole is the role of a member in a workspace
+type WorkspaceRole string
+
+// Workspace roles, each grants the permissions of the previous ones
+const (
+	WorkspaceRoleMember WorkspaceRole = "member"
+	WorkspaceRoleAdmin  WorkspaceRole = "admin"
+	WorkspaceRoleOwner  WorkspaceRole = "owner"
+)
+
+// IsValid reports whether r is a known role
+func (r WorkspaceRole) IsValid() bool {
+	switch r {
+	case WorkspaceRoleMember, WorkspaceRoleAdmin, WorkspaceRoleOwner:
+		return true
+	default:
+		return false
+	}
+}
+
+// Allows reports whether the role grants the permission. Members work with
+// the todos and projects of the workspace, admins and owners also rename it
+// and manage its members.
+func (r WorkspaceRole) Allows(permission Permission) bool {
+	switch permission {
+	case PermissionView, PermissionEdit:
+		return r.IsValid()
+	case PermissionManage:
+		return r == WorkspaceRoleAdmin || r == WorkspaceRoleOwner
+	default:
+		return false
+	}
+}
+
+// WorkspaceMember represents the membership of a user in a workspace
+type WorkspaceMember struct {
+	WorkspaceID uint          `gorm:"primaryKey;autoIncrement:false"`
+	Workspace   Workspace     `gorm:"foreignKey:WorkspaceID"`
+	UserID      uint          `gorm:"primaryKey;autoIncrement:false;index"`
+	User        User          `gorm:"foreignKey:UserID"`
+	Role        WorkspaceRole `gorm:"size:20;not null"`
+	CreatedAt   time.Time     `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time     `gorm:"autoUpdateTime"`
+}
+
+// NewWorkspace creates a new Workspace entity
+func NewWorkspace(name string) *Workspace {
+	return &Workspace{
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Rename renames the workspace
+func (w *Workspace) Rename(name string) {
+	w.Name = name
+	w.UpdatedAt = time.Now()
+}
+
+// NewWorkspaceMember creates a new WorkspaceMember entity
+func NewWorkspaceMember(workspaceID, userID uint, role WorkspaceRole) *WorkspaceMember {
+	return &WorkspaceMember{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Role:        role,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// ChangeRole changes the role of the member
+func (m *WorkspaceMember) ChangeRole(role WorkspaceRole) {
+	m.Role = role
+	m.UpdatedAt = time.Now()
+}
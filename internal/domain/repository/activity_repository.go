@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"todo-api/internal/domain/entity"
+)
+
+// ActivityRepository defines the interface for the append-only todo activity
+// log. Every operation is scoped to the workspace of the context, see
+// WithWorkspace. Activities are kept after their todo is deleted and are only
+// deleted with their workspace.
+type ActivityRepository interface {
+	// Create appends activities to the log. The activities of todo changes
+	// are appended by the writes of TodoRepository instead, together with
+	// the changes.
+	Create(ctx context.Context, activities ...*entity.Activity) error
+
+	// GetByTodoID retrieves a page of the activities on a todo, oldest first
+	GetByTodoID(ctx context.Context, todoID uint, page, pageSize int) ([]*entity.Activity, error)
+
+	// CountByTodoID counts the activities on a todo
+	CountByTodoID(ctx context.Context, todoID uint) (int64, error)
+
+	// GetByUserID retrieves a page of the activities performed by a user, newest first
+	GetByUserID(ctx context.Context, userID uint, page, pageSize int) ([]*entity.Activity, error)
+
+	// CountByUserID counts the activities performed by a user
+	CountByUserID(ctx context.Context, userID uint) (int64, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"todo-api/internal/domain/entity"
+)
+
+// AuditRepository defines the interface for the append-only security audit
+// log. Audit events belong to accounts rather than workspaces, so they are not
+// scoped to the workspace of the context.
+type AuditRepository interface {
+	// Create appends an event to the log
+	Create(ctx context.Context, event *entity.AuditEvent) error
+
+	// List retrieves a page of the events matching a filter, newest first
+	List(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEvent, error)
+
+	// Count counts the events matching a filter
+	Count(ctx context.Context, filter entity.AuditFilter) (int64, error)
+}
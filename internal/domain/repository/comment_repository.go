@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"todo-api/internal/domain/entity"
+)
+
+// CommentRepository defines the interface for todo comment repository
+// operations. Every operation is scoped to the workspace of the context, see
+// WithWorkspace. Comments are deleted with their todo.
+type CommentRepository interface {
+	// Create creates a new comment
+	Create(ctx context.Context, comment *entity.Comment) error
+
+	// GetByID retrieves a comment by its ID
+	GetByID(ctx context.Context, id uint) (*entity.Comment, error)
+
+	// GetByTodoID retrieves a page of the comments on a todo, oldest first
+	GetByTodoID(ctx context.Context, todoID uint, page, pageSize int) ([]*entity.Comment, error)
+
+	// CountByTodoID counts the comments on a todo
+	CountByTodoID(ctx context.Context, todoID uint) (int64, error)
+
+	// Update updates a comment
+	Update(ctx context.Context, comment *entity.Comment) error
+
+	// Delete deletes a comment
+	Delete(ctx context.Context, id uint) error
+}
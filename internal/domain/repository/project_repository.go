@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"todo-api/internal/domain/entity"
+)
+
+// ProjectRepository defines the interface for project and membership repository
+// operations. Every operation is scoped to the workspace of the context, see
+// WithWorkspace.
+type ProjectRepository interface {
+	// Create creates a new project with its first member in a single transaction
+	Create(ctx context.Context, project *entity.Project, owner *entity.ProjectMember) error
+
+	// GetByID retrieves a project by its ID
+	GetByID(ctx context.Context, id uint) (*entity.Project, error)
+
+	// Update updates a project
+	Update(ctx context.Context, project *entity.Project) error
+
+	// Delete deletes a project with its members and todos in a single transaction
+	Delete(ctx context.Context, id uint) error
+
+	// GetMember retrieves the membership of a user in a project
+	GetMember(ctx context.Context, projectID, userID uint) (*entity.ProjectMember, error)
+
+	// GetMembers retrieves the members of a project in the order they joined
+	GetMembers(ctx context.Context, projectID uint) ([]*entity.ProjectMember, error)
+
+	// GetMemberships retrieves the memberships of a user with their projects
+	// loaded, ordered by project name
+	GetMemberships(ctx context.Context, userID uint) ([]*entity.ProjectMember, error)
+
+	// AddMember adds a member to a project
+	AddMember(ctx context.Context, member *entity.ProjectMember) error
+
+	// UpdateMember updates the role of a member
+	UpdateMember(ctx context.Context, member *entity.ProjectMember) error
+
+	// RemoveMember removes a member from a project and unassigns the todos of
+	// the project assigned to them in a single transaction
+	RemoveMember(ctx context.Context, projectID, userID uint) error
+}
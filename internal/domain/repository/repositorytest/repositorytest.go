@@ -0,0 +1,1592 @@
+// Package repositorytest implements a conformance suite for repository implementations.
+//
+// Every storage backend must pass the suite against an empty store, in the same
+// way testing/fstest is used for fs.FS implementations:
+//
+//	if err := repositorytest.TestRepositories(userRepo, todoRepo, viewRepo, projectRepo, workspaceRepo, commentRepo, activityRepo, auditRepo, webhookRepo); err != nil {
+//		t.Fatal(err)
+//	}
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// TestRepositories runs the user, todo, view, project, todo change, workspace, comment, activity, audit and webhook conformance checks against empty repositories
+func TestRepositories(userRepo repository.UserRepository, todoRepo repository.TodoRepository, viewRepo repository.ViewRepository, projectRepo repository.ProjectRepository, workspaceRepo repository.WorkspaceRepository, commentRepo repository.CommentRepository, activityRepo repository.ActivityRepository, auditRepo repository.AuditRepository, webhookRepo repository.WebhookRepository) error {
+	if err := TestUserRepository(userRepo); err != nil {
+		return fmt.Errorf("user repository: %w", err)
+	}
+	if err := TestTodoRepository(userRepo, todoRepo); err != nil {
+		return fmt.Errorf("todo repository: %w", err)
+	}
+	if err := TestViewRepository(userRepo, viewRepo); err != nil {
+		return fmt.Errorf("view repository: %w", err)
+	}
+	if err := TestProjectRepository(userRepo, todoRepo, projectRepo); err != nil {
+		return fmt.Errorf("project repository: %w", err)
+	}
+	if err := TestTodoChanges(userRepo, todoRepo, projectRepo); err != nil {
+		return fmt.Errorf("todo changes: %w", err)
+	}
+	if err := TestWorkspaceRepository(userRepo, todoRepo, projectRepo, workspaceRepo); err != nil {
+		return fmt.Errorf("workspace repository: %w", err)
+	}
+	if err := TestCommentRepository(userRepo, todoRepo, workspaceRepo, commentRepo); err != nil {
+		return fmt.Errorf("comment repository: %w", err)
+	}
+	if err := TestActivityRepository(userRepo, todoRepo, workspaceRepo, activityRepo); err != nil {
+		return fmt.Errorf("activity repository: %w", err)
+	}
+	if err := TestAuditRepository(userRepo, auditRepo); err != nil {
+		return fmt.Errorf("audit repository: %w", err)
+	}
+	if err := TestWebhookRepository(userRepo, workspaceRepo, webhookRepo); err != nil {
+		return fmt.Errorf("webhook repository: %w", err)
+	}
+	if err := TestWebhookOutbox(userRepo, todoRepo, workspaceRepo, webhookRepo); err != nil {
+		return fmt.Errorf("webhook outbox: %w", err)
+	}
+	return nil
+}
+
+// TestUserRepository checks the behavior of a UserRepository implementation
+func TestUserRepository(repo repository.UserRepository) error {
+	ctx := context.Background()
+
+	user := entity.NewUser("conformance", "conformance@example.com", "hash")
+	if err := repo.Create(ctx, user); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if user.ID == 0 {
+		return errors.New("Create: ID was not assigned")
+	}
+
+	// Unique username and email
+	if err := repo.Create(ctx, entity.NewUser("conformance", "other@example.com", "hash")); err == nil {
+		return errors.New("Create: duplicate username was accepted")
+	}
+	if err := repo.Create(ctx, entity.NewUser("other", "conformance@example.com", "hash")); err == nil {
+		return errors.New("Create: duplicate email was accepted")
+	}
+
+	// Lookups
+	got, err := repo.GetByID(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	if got.Username != user.Username || got.Email != user.Email || got.Password != user.Password {
+		return fmt.Errorf("GetByID: got %+v, want %+v", got, user)
+	}
+	if _, err := repo.GetByUsername(ctx, "conformance"); err != nil {
+		return fmt.Errorf("GetByUsername: %w", err)
+	}
+	if _, err := repo.GetByEmail(ctx, "conformance@example.com"); err != nil {
+		return fmt.Errorf("GetByEmail: %w", err)
+	}
+	if _, err := repo.GetByID(ctx, user.ID+1000); err == nil {
+		return errors.New("GetByID: expected an error for a missing user")
+	}
+	if _, err := repo.GetByUsername(ctx, "missing"); err == nil {
+		return errors.New("GetByUsername: expected an error for a missing user")
+	}
+	if _, err := repo.GetByEmail(ctx, "missing@example.com"); err == nil {
+		return errors.New("GetByEmail: expected an error for a missing user")
+	}
+
+	// Existence checks
+	if exists, err := repo.ExistsByUsername(ctx, "conformance"); err != nil || !exists {
+		return fmt.Errorf("ExistsByUsername: got %v, %v; want true, nil", exists, err)
+	}
+	if exists, err := repo.ExistsByUsername(ctx, "missing"); err != nil || exists {
+		return fmt.Errorf("ExistsByUsername: got %v, %v; want false, nil", exists, err)
+	}
+	if exists, err := repo.ExistsByEmail(ctx, "conformance@example.com"); err != nil || !exists {
+		return fmt.Errorf("ExistsByEmail: got %v, %v; want true, nil", exists, err)
+	}
+	if exists, err := repo.ExistsByEmail(ctx, "missing@example.com"); err != nil || exists {
+		return fmt.Errorf("ExistsByEmail: got %v, %v; want false, nil", exists, err)
+	}
+
+	// Update
+	user.UpdateProfile("renamed", "renamed@example.com")
+	if err := repo.Update(ctx, user); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+	got, err = repo.GetByID(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("GetByID after Update: %w", err)
+	}
+	if got.Username != "renamed" || got.Email != "renamed@example.com" {
+		return fmt.Errorf("Update: changes were not persisted, got %+v", got)
+	}
+
+	// RevokeTokens increments the token version, which Update leaves alone
+	if err := repo.RevokeTokens(ctx, user.ID); err != nil {
+		return fmt.Errorf("RevokeTokens: %w", err)
+	}
+	if got, err = repo.GetByID(ctx, user.ID); err != nil || got.TokenVersion != user.TokenVersion+1 {
+		return fmt.Errorf("RevokeTokens: token version was not incremented (err: %v)", err)
+	}
+	if err := repo.Update(ctx, user); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+	if got, err = repo.GetByID(ctx, user.ID); err != nil || got.TokenVersion != user.TokenVersion+1 {
+		return fmt.Errorf("Update: stale token version was saved (err: %v)", err)
+	}
+	if err := repo.RevokeTokens(ctx, user.ID+1000); err == nil {
+		return errors.New("RevokeTokens: expected an error for a missing user")
+	}
+
+	// Delete
+	if err := repo.Delete(ctx, user.ID); err != nil {
+		return fmt.Errorf("Delete: %w", err)
+	}
+	if _, err := repo.GetByID(ctx, user.ID); err == nil {
+		return errors.New("Delete: user is still retrievable")
+	}
+
+	return nil
+}
+
+// TestTodoRepository checks the behavior of a TodoRepository implementation
+func TestTodoRepository(userRepo repository.UserRepository, todoRepo repository.TodoRepository) error {
+	ctx := context.Background()
+
+	owner := entity.NewUser("todo-owner", "todo-owner@example.com", "hash")
+	if err := userRepo.Create(ctx, owner); err != nil {
+		return fmt.Errorf("creating owner: %w", err)
+	}
+	other := entity.NewUser("todo-other", "todo-other@example.com", "hash")
+	if err := userRepo.Create(ctx, other); err != nil {
+		return fmt.Errorf("creating other user: %w", err)
+	}
+
+	// Create
+	titles := []string{"Buy milk", "Write report", "Call plumber", "Read BOOK"}
+	todos := make([]*entity.Todo, 0, len(titles))
+	for _, title := range titles {
+		todo := entity.NewTodo(title, "details for "+title, owner.ID)
+		if err := todoRepo.Create(ctx, todo); err != nil {
+			return fmt.Errorf("Create: %w", err)
+		}
+		if todo.ID == 0 {
+			return errors.New("Create: ID was not assigned")
+		}
+		todos = append(todos, todo)
+	}
+	if err := todoRepo.Create(ctx, entity.NewTodo("Not mine", "", other.ID)); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	// GetByID
+	got, err := todoRepo.GetByID(ctx, todos[0].ID)
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	if got.Title != todos[0].Title || got.UserID != owner.ID || got.Completed {
+		return fmt.Errorf("GetByID: got %+v, want %+v", got, todos[0])
+	}
+	if _, err := todoRepo.GetByID(ctx, todos[len(todos)-1].ID+1000); err == nil {
+		return errors.New("GetByID: expected an error for a missing todo")
+	}
+
+	// Update
+	todos[1].MarkAsCompleted()
+	if err := todoRepo.Update(ctx, todos[1]); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+	got, err = todoRepo.GetByID(ctx, todos[1].ID)
+	if err != nil {
+		return fmt.Errorf("GetByID after Update: %w", err)
+	}
+	if !got.Completed {
+		return errors.New("Update: completed flag was not persisted")
+	}
+
+	// Listing is scoped to the user and paginated
+	page := entity.TodoFilter{UserID: owner.ID, Page: 1, PageSize: 3}
+	list, err := todoRepo.GetByUserID(ctx, owner.ID, page)
+	if err != nil {
+		return fmt.Errorf("GetByUserID: %w", err)
+	}
+	if len(list) != 3 {
+		return fmt.Errorf("GetByUserID: got %d todos on the first page, want 3", len(list))
+	}
+	for _, todo := range list {
+		if todo.UserID != owner.ID {
+			return fmt.Errorf("GetByUserID: returned todo %d of another user", todo.ID)
+		}
+	}
+	page.Page = 2
+	list, err = todoRepo.GetByUserID(ctx, owner.ID, page)
+	if err != nil {
+		return fmt.Errorf("GetByUserID: %w", err)
+	}
+	if len(list) != 1 {
+		return fmt.Errorf("GetByUserID: got %d todos on the second page, want 1", len(list))
+	}
+
+	// Keyset pagination walks the same order forward and backward
+	if err := checkTodoCursors(ctx, todoRepo, owner.ID, len(todos)); err != nil {
+		return err
+	}
+
+	// Filters
+	completed := true
+	fullText := func(query string) *entity.SearchQuery {
+		parsed := entity.ParseSearchQuery(query)
+		return &parsed
+	}
+	openMilkOrPlumber, err := entity.ParseTodoFilter(`completed:false AND (title:MILK OR title="call plumber")`)
+	if err != nil {
+		return fmt.Errorf("ParseTodoFilter: %w", err)
+	}
+	notMilkSince2000, err := entity.ParseTodoFilter(`NOT title:milk AND created>=2000-01-01 AND updated<3000-01-01`)
+	if err != nil {
+		return fmt.Errorf("ParseTodoFilter: %w", err)
+	}
+	checks := []struct {
+		name   string
+		filter entity.TodoFilter
+		want   int64
+	}{
+		{"all", entity.TodoFilter{UserID: owner.ID}, 4},
+		{"completed", entity.TodoFilter{UserID: owner.ID, Completed: &completed}, 1},
+		{"search is case-insensitive", entity.TodoFilter{UserID: owner.ID, Search: "book"}, 1},
+		{"search matches description", entity.TodoFilter{UserID: owner.ID, Search: "details for call"}, 1},
+		{"search wildcards match literally", entity.TodoFilter{UserID: owner.ID, Search: "%"}, 0},
+		{"expression", entity.TodoFilter{UserID: owner.ID, Expr: openMilkOrPlumber}, 2},
+		{"expression negation and dates", entity.TodoFilter{UserID: owner.ID, Expr: notMilkSince2000}, 3},
+		{"full-text phrase", entity.TodoFilter{UserID: owner.ID, FullText: fullText(`"buy milk"`)}, 1},
+		{"full-text prefix", entity.TodoFilter{UserID: owner.ID, FullText: fullText("plumb*")}, 1},
+		{"full-text negation", entity.TodoFilter{UserID: owner.ID, FullText: fullText("details -book")}, 3},
+		{"other user", entity.TodoFilter{UserID: other.ID}, 1},
+	}
+	for _, check := range checks {
+		count, err := todoRepo.Count(ctx, check.filter)
+		if err != nil {
+			return fmt.Errorf("Count (%s): %w", check.name, err)
+		}
+		if count != check.want {
+			return fmt.Errorf("Count (%s): got %d, want %d", check.name, count, check.want)
+		}
+
+		filter := check.filter
+		filter.Page, filter.PageSize = 1, 10
+		list, err := todoRepo.GetByUserID(ctx, filter.UserID, filter)
+		if err != nil {
+			return fmt.Errorf("GetByUserID (%s): %w", check.name, err)
+		}
+		if int64(len(list)) != check.want {
+			return fmt.Errorf("GetByUserID (%s): got %d todos, want %d", check.name, len(list), check.want)
+		}
+	}
+
+	// Sort orders offset pages
+	sorted, err := todoRepo.GetByUserID(ctx, owner.ID, entity.TodoFilter{UserID: owner.ID, Page: 1, PageSize: 10, Sort: entity.TodoSortTitleAsc})
+	if err != nil {
+		return fmt.Errorf("GetByUserID (sorted by title): %w", err)
+	}
+	if err := sameTodoOrder(sorted, []*entity.Todo{todos[0], todos[2], todos[3], todos[1]}); err != nil {
+		return fmt.Errorf("GetByUserID (sorted by title): %w", err)
+	}
+
+	// Full-text search returns the matches with their highlights
+	matches, err := todoRepo.Search(ctx, entity.TodoFilter{UserID: owner.ID, FullText: fullText("report"), Page: 1, PageSize: 10})
+	if err != nil {
+		return fmt.Errorf("Search: %w", err)
+	}
+	if len(matches) != 1 || matches[0].Todo.ID != todos[1].ID {
+		return fmt.Errorf("Search: got %d matches, want todo %d", len(matches), todos[1].ID)
+	}
+	if want := entity.HighlightStart + "report" + entity.HighlightEnd; !strings.Contains(strings.ToLower(matches[0].TitleHighlight), want) {
+		return fmt.Errorf("Search: title highlight %q does not mark the match", matches[0].TitleHighlight)
+	}
+
+	// GetByIDs skips missing todos
+	list, err = todoRepo.GetByIDs(ctx, []uint{todos[2].ID, todos[3].ID, todos[3].ID + 1000})
+	if err != nil {
+		return fmt.Errorf("GetByIDs: %w", err)
+	}
+	if len(list) != 2 {
+		return fmt.Errorf("GetByIDs: got %d todos, want 2", len(list))
+	}
+
+	// ApplyChanges changes nothing when a todo was changed since it was read
+	stale, err := todoRepo.GetByID(ctx, todos[2].ID)
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	stale.ChangeSeq += 1000
+	stale.MarkAsCompleted()
+	removed, err := todoRepo.GetByID(ctx, todos[3].ID)
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	if err := todoRepo.ApplyChanges(ctx, []*entity.Todo{stale}, []*entity.Todo{removed}); !errors.Is(err, repository.ErrTodoChanged) {
+		return fmt.Errorf("ApplyChanges (stale): got %v, want ErrTodoChanged", err)
+	}
+	if _, err := todoRepo.GetByID(ctx, todos[3].ID); err != nil {
+		return errors.New("ApplyChanges (stale): todo was deleted although another one changed")
+	}
+
+	// ApplyChanges saves and deletes together, giving the saved todos new change sequence numbers
+	tagged, err := todoRepo.GetByID(ctx, todos[2].ID)
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	read := tagged.ChangeSeq
+	tagged.MarkAsCompleted()
+	tagged.AddTags("work", "urgent")
+	if err := todoRepo.ApplyChanges(ctx, []*entity.Todo{tagged}, []*entity.Todo{removed}); err != nil {
+		return fmt.Errorf("ApplyChanges: %w", err)
+	}
+	if tagged.ChangeSeq <= read {
+		return fmt.Errorf("ApplyChanges: change sequence number %d did not move past %d", tagged.ChangeSeq, read)
+	}
+	list, err = todoRepo.GetByIDs(ctx, []uint{todos[2].ID, todos[3].ID})
+	if err != nil {
+		return fmt.Errorf("GetByIDs after ApplyChanges: %w", err)
+	}
+	if len(list) != 1 || list[0].ID != todos[2].ID || !list[0].Completed {
+		return errors.New("ApplyChanges: todo was not tagged or not deleted")
+	}
+	if strings.Join(list[0].Tags, ",") != "urgent,work" {
+		return fmt.Errorf("ApplyChanges: got tags %v, want [urgent work]", list[0].Tags)
+	}
+
+	// Tag filters match whole tags only
+	for expr, want := range map[string]int64{"tag:work": 1, "tag:wor": 0, "tag!=urgent": 2, "NOT tag:urgent": 2} {
+		parsed, err := entity.ParseTodoFilter(expr)
+		if err != nil {
+			return fmt.Errorf("ParseTodoFilter: %w", err)
+		}
+		count, err := todoRepo.Count(ctx, entity.TodoFilter{UserID: owner.ID, Expr: parsed})
+		if err != nil {
+			return fmt.Errorf("Count (%s): %w", expr, err)
+		}
+		if count != want {
+			return fmt.Errorf("Count (%s): got %d, want %d", expr, count, want)
+		}
+	}
+
+	// Delete
+	if err := todoRepo.Delete(ctx, todos[0].ID); err != nil {
+		return fmt.Errorf("Delete: %w", err)
+	}
+	if _, err := todoRepo.GetByID(ctx, todos[0].ID); err == nil {
+		return errors.New("Delete: todo is still retrievable")
+	}
+
+	return nil
+}
+
+// TestViewRepository checks the behavior of a ViewRepository implementation
+func TestViewRepository(userRepo repository.UserRepository, viewRepo repository.ViewRepository) error {
+	ctx := context.Background()
+
+	owner := entity.NewUser("view-owner", "view-owner@example.com", "hash")
+	if err := userRepo.Create(ctx, owner); err != nil {
+		return fmt.Errorf("creating owner: %w", err)
+	}
+	other := entity.NewUser("view-other", "view-other@example.com", "hash")
+	if err := userRepo.Create(ctx, other); err != nil {
+		return fmt.Errorf("creating other user: %w", err)
+	}
+
+	// Create
+	open := false
+	work := entity.NewView(owner.ID, "Work", &open, "", "title:report", entity.TodoSortTitleAsc)
+	if err := viewRepo.Create(ctx, work); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if work.ID == 0 {
+		return errors.New("Create: ID was not assigned")
+	}
+	if err := viewRepo.Create(ctx, entity.NewView(owner.ID, "All", nil, "", "", "")); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if err := viewRepo.Create(ctx, entity.NewView(other.ID, "Work", nil, "", "", "")); err != nil {
+		return fmt.Errorf("Create: names are only unique per user: %w", err)
+	}
+	if err := viewRepo.Create(ctx, entity.NewView(owner.ID, "Work", nil, "", "", "")); err == nil {
+		return errors.New("Create: expected an error for a duplicate name")
+	}
+
+	// GetByID round-trips the filters
+	got, err := viewRepo.GetByID(ctx, work.ID)
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	if got.Name != "Work" || got.UserID != owner.ID || got.Completed == nil || *got.Completed ||
+		got.Filter != "title:report" || got.Sort != entity.TodoSortTitleAsc {
+		return fmt.Errorf("GetByID: got %+v, want %+v", got, work)
+	}
+
+	// GetByUserID is scoped to the user and ordered by name
+	views, err := viewRepo.GetByUserID(ctx, owner.ID)
+	if err != nil {
+		return fmt.Errorf("GetByUserID: %w", err)
+	}
+	if len(views) != 2 || views[0].Name != "All" || views[1].Name != "Work" {
+		return fmt.Errorf("GetByUserID: got %d views, want All and Work", len(views))
+	}
+
+	// Update
+	got.Update("Open work", nil, "quarterly", "", entity.TodoSortCreatedAsc)
+	if err := viewRepo.Update(ctx, got); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+	got, err = viewRepo.GetByID(ctx, work.ID)
+	if err != nil {
+		return fmt.Errorf("GetByID after Update: %w", err)
+	}
+	if got.Name != "Open work" || got.Completed != nil || got.Search != "quarterly" || got.Sort != entity.TodoSortCreatedAsc {
+		return fmt.Errorf("Update: got %+v", got)
+	}
+
+	// Delete
+	if err := viewRepo.Delete(ctx, work.ID); err != nil {
+		return fmt.Errorf("Delete: %w", err)
+	}
+	if _, err := viewRepo.GetByID(ctx, work.ID); err == nil {
+		return errors.New("Delete: view is still retrievable")
+	}
+
+	return nil
+}
+
+// TestProjectRepository checks the behavior of a ProjectRepository implementation
+// and how project todos are scoped by a TodoRepository
+func TestProjectRepository(userRepo repository.UserRepository, todoRepo repository.TodoRepository, projectRepo repository.ProjectRepository) error {
+	ctx := context.Background()
+
+	owner := entity.NewUser("project-owner", "project-owner@example.com", "hash")
+	if err := userRepo.Create(ctx, owner); err != nil {
+		return fmt.Errorf("creating owner: %w", err)
+	}
+	member := entity.NewUser("project-member", "project-member@example.com", "hash")
+	if err := userRepo.Create(ctx, member); err != nil {
+		return fmt.Errorf("creating member: %w", err)
+	}
+
+	// Create adds the owner as the first member
+	project := entity.NewProject("Launch")
+	if err := projectRepo.Create(ctx, project, entity.NewProjectMember(0, owner.ID, entity.ProjectRoleOwner)); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if project.ID == 0 {
+		return errors.New("Create: ID was not assigned")
+	}
+	got, err := projectRepo.GetMember(ctx, project.ID, owner.ID)
+	if err != nil {
+		return fmt.Errorf("GetMember: %w", err)
+	}
+	if got.Role != entity.ProjectRoleOwner {
+		return fmt.Errorf("GetMember: got role %q, want %q", got.Role, entity.ProjectRoleOwner)
+	}
+	if _, err := projectRepo.GetMember(ctx, project.ID, member.ID); err == nil {
+		return errors.New("GetMember: expected an error for a non-member")
+	}
+
+	// Members are unique per project
+	if err := projectRepo.AddMember(ctx, entity.NewProjectMember(project.ID, member.ID, entity.ProjectRoleViewer)); err != nil {
+		return fmt.Errorf("AddMember: %w", err)
+	}
+	if err := projectRepo.AddMember(ctx, entity.NewProjectMember(project.ID, member.ID, entity.ProjectRoleEditor)); err == nil {
+		return errors.New("AddMember: expected an error for a duplicate member")
+	}
+	members, err := projectRepo.GetMembers(ctx, project.ID)
+	if err != nil {
+		return fmt.Errorf("GetMembers: %w", err)
+	}
+	if len(members) != 2 || members[0].UserID != owner.ID || members[1].UserID != member.ID {
+		return fmt.Errorf("GetMembers: got %d members, want the owner then the member", len(members))
+	}
+
+	// GetMemberships loads the projects, ordered by name
+	other := entity.NewProject("Backlog")
+	if err := projectRepo.Create(ctx, other, entity.NewProjectMember(0, member.ID, entity.ProjectRoleOwner)); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	memberships, err := projectRepo.GetMemberships(ctx, member.ID)
+	if err != nil {
+		return fmt.Errorf("GetMemberships: %w", err)
+	}
+	if len(memberships) != 2 || memberships[0].Project.Name != "Backlog" || memberships[1].Project.Name != "Launch" {
+		return fmt.Errorf("GetMemberships: got %d memberships, want Backlog and Launch", len(memberships))
+	}
+
+	// UpdateMember
+	got, err = projectRepo.GetMember(ctx, project.ID, member.ID)
+	if err != nil {
+		return fmt.Errorf("GetMember: %w", err)
+	}
+	got.ChangeRole(entity.ProjectRoleEditor)
+	if err := projectRepo.UpdateMember(ctx, got); err != nil {
+		return fmt.Errorf("UpdateMember: %w", err)
+	}
+	if got, err = projectRepo.GetMember(ctx, project.ID, member.ID); err != nil || got.Role != entity.ProjectRoleEditor {
+		return fmt.Errorf("UpdateMember: role was not saved (err: %v)", err)
+	}
+
+	// Project todos are listed by project, not with the personal todos of their author
+	todo := entity.NewTodo("Ship it", "", member.ID)
+	todo.ProjectID = &project.ID
+	if err := todoRepo.Create(ctx, todo); err != nil {
+		return fmt.Errorf("creating project todo: %w", err)
+	}
+	todos, err := todoRepo.GetByUserID(ctx, owner.ID, entity.TodoFilter{ProjectID: &project.ID, Page: 1, PageSize: 10})
+	if err != nil {
+		return fmt.Errorf("GetByUserID for a project: %w", err)
+	}
+	if len(todos) != 1 || todos[0].ID != todo.ID || todos[0].ProjectID == nil || *todos[0].ProjectID != project.ID {
+		return fmt.Errorf("GetByUserID for a project: got %d todos, want the project todo", len(todos))
+	}
+	if count, err := todoRepo.Count(ctx, entity.TodoFilter{UserID: member.ID}); err != nil || count != 0 {
+		return fmt.Errorf("Count: got %d personal todos, want 0 (err: %v)", count, err)
+	}
+
+	// Todos assigned to a member are listed by assignee across projects
+	todo.AssignTo(member.ID)
+	if err := todoRepo.Update(ctx, todo); err != nil {
+		return fmt.Errorf("assigning project todo: %w", err)
+	}
+	assigned, err := todoRepo.GetByUserID(ctx, member.ID, entity.TodoFilter{AssigneeID: &member.ID, Page: 1, PageSize: 10})
+	if err != nil {
+		return fmt.Errorf("GetByUserID by assignee: %w", err)
+	}
+	if len(assigned) != 1 || assigned[0].ID != todo.ID || !assigned[0].IsAssignedTo(member.ID) {
+		return fmt.Errorf("GetByUserID by assignee: got %d todos, want the assigned todo", len(assigned))
+	}
+	if count, err := todoRepo.Count(ctx, entity.TodoFilter{UserID: owner.ID, AssigneeID: &owner.ID}); err != nil || count != 0 {
+		return fmt.Errorf("Count by assignee: got %d todos assigned to the owner, want 0 (err: %v)", count, err)
+	}
+
+	// RemoveMember also unassigns the todos of the member
+	if err := projectRepo.RemoveMember(ctx, project.ID, member.ID); err != nil {
+		return fmt.Errorf("RemoveMember: %w", err)
+	}
+	if _, err := projectRepo.GetMember(ctx, project.ID, member.ID); err == nil {
+		return errors.New("RemoveMember: member is still retrievable")
+	}
+	if got, err := todoRepo.GetByID(ctx, todo.ID); err != nil || got.AssigneeID != nil {
+		return fmt.Errorf("RemoveMember: todo is still assigned to the member (err: %v)", err)
+	}
+
+	// Delete removes the members and todos of the project
+	if err := projectRepo.Delete(ctx, project.ID); err != nil {
+		return fmt.Errorf("Delete: %w", err)
+	}
+	if _, err := projectRepo.GetByID(ctx, project.ID); err == nil {
+		return errors.New("Delete: project is still retrievable")
+	}
+	if _, err := projectRepo.GetMember(ctx, project.ID, owner.ID); err == nil {
+		return errors.New("Delete: member is still retrievable")
+	}
+	if _, err := todoRepo.GetByID(ctx, todo.ID); err == nil {
+		return errors.New("Delete: project todo is still retrievable")
+	}
+
+	return nil
+}
+
+// TestTodoChanges checks the change sequence numbers, conditional writes and
+// change feed of a TodoRepository implementation
+func TestTodoChanges(userRepo repository.UserRepository, todoRepo repository.TodoRepository, projectRepo repository.ProjectRepository) error {
+	ctx := context.Background()
+
+	owner := entity.NewUser("changes-owner", "changes-owner@example.com", "hash")
+	if err := userRepo.Create(ctx, owner); err != nil {
+		return fmt.Errorf("creating owner: %w", err)
+	}
+	other := entity.NewUser("changes-other", "changes-other@example.com", "hash")
+	if err := userRepo.Create(ctx, other); err != nil {
+		return fmt.Errorf("creating other user: %w", err)
+	}
+	project := entity.NewProject("Changes")
+	if err := projectRepo.Create(ctx, project, entity.NewProjectMember(0, other.ID, entity.ProjectRoleOwner)); err != nil {
+		return fmt.Errorf("creating project: %w", err)
+	}
+
+	start, err := todoRepo.GetChanges(ctx, entity.TodoChangeFilter{UserID: owner.ID, Limit: 10})
+	if err != nil {
+		return fmt.Errorf("GetChanges: %w", err)
+	}
+	if len(start.Todos) != 0 || len(start.Tombstones) != 0 {
+		return fmt.Errorf("GetChanges: got %d todos and %d tombstones for a new user, want none", len(start.Todos), len(start.Tombstones))
+	}
+
+	// Every write allocates a greater change sequence number
+	first := entity.NewTodo("First", "", owner.ID)
+	if err := todoRepo.Create(ctx, first); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	second := entity.NewTodo("Second", "", owner.ID)
+	if err := todoRepo.Create(ctx, second); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	shared := entity.NewTodo("Shared", "", other.ID)
+	shared.ProjectID = &project.ID
+	if err := todoRepo.Create(ctx, shared); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if err := todoRepo.Create(ctx, entity.NewTodo("Not mine", "", other.ID)); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if first.ChangeSeq == 0 || second.ChangeSeq <= first.ChangeSeq {
+		return fmt.Errorf("Create: got change sequence numbers %d then %d, want increasing", first.ChangeSeq, second.ChangeSeq)
+	}
+	read := first.ChangeSeq
+	first.MarkAsCompleted()
+	if err := todoRepo.Update(ctx, first); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+	if first.ChangeSeq <= shared.ChangeSeq {
+		return fmt.Errorf("Update: got change sequence number %d, want more than %d", first.ChangeSeq, shared.ChangeSeq)
+	}
+	if got, err := todoRepo.GetByID(ctx, first.ID); err != nil || got.ChangeSeq != first.ChangeSeq {
+		return fmt.Errorf("GetByID: change sequence number was not saved (err: %v)", err)
+	}
+
+	// Conditional writes are rejected once the todo changed since it was read
+	stale := *first
+	stale.ChangeSeq = read
+	stale.Title = "Stale"
+	if err := todoRepo.UpdateIfUnchanged(ctx, &stale); !errors.Is(err, repository.ErrTodoChanged) {
+		return fmt.Errorf("UpdateIfUnchanged: got %v for a stale todo, want ErrTodoChanged", err)
+	}
+	if stale.ChangeSeq != read {
+		return errors.New("UpdateIfUnchanged: change sequence number of a rejected todo was modified")
+	}
+	if got, err := todoRepo.GetByID(ctx, first.ID); err != nil || got.Title != "First" {
+		return fmt.Errorf("UpdateIfUnchanged: stale todo was saved (err: %v)", err)
+	}
+	seq := first.ChangeSeq
+	first.Title = "First, renamed"
+	if err := todoRepo.UpdateIfUnchanged(ctx, first); err != nil {
+		return fmt.Errorf("UpdateIfUnchanged: %w", err)
+	}
+	if first.ChangeSeq <= seq {
+		return fmt.Errorf("UpdateIfUnchanged: got change sequence number %d, want more than %d", first.ChangeSeq, seq)
+	}
+	if err := todoRepo.DeleteIfUnchanged(ctx, second.ID, second.ChangeSeq+1000); !errors.Is(err, repository.ErrTodoChanged) {
+		return fmt.Errorf("DeleteIfUnchanged: got %v for a stale todo, want ErrTodoChanged", err)
+	}
+	if _, err := todoRepo.GetByID(ctx, second.ID); err != nil {
+		return fmt.Errorf("DeleteIfUnchanged: stale todo was deleted (err: %v)", err)
+	}
+	if err := todoRepo.DeleteIfUnchanged(ctx, second.ID, second.ChangeSeq); err != nil {
+		return fmt.Errorf("DeleteIfUnchanged: %w", err)
+	}
+	if err := todoRepo.DeleteIfUnchanged(ctx, second.ID, second.ChangeSeq); !errors.Is(err, repository.ErrTodoChanged) {
+		return fmt.Errorf("DeleteIfUnchanged: got %v for a deleted todo, want ErrTodoChanged", err)
+	}
+
+	// GetChanges selects the personal todos of the user and the todos of the
+	// projects, with a tombstone for each deletion
+	filter := entity.TodoChangeFilter{UserID: owner.ID, ProjectIDs: []uint{project.ID}, Since: start.Until, Limit: 10}
+	changes, err := todoRepo.GetChanges(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("GetChanges: %w", err)
+	}
+	if len(changes.Todos) != 2 || changes.Todos[0].ID != shared.ID || changes.Todos[1].ID != first.ID {
+		return fmt.Errorf("GetChanges: got %d todos, want the shared todo then the updated one", len(changes.Todos))
+	}
+	if len(changes.Tombstones) != 1 || changes.Tombstones[0].TodoID != second.ID || changes.Tombstones[0].UserID != owner.ID {
+		return fmt.Errorf("GetChanges: got %d tombstones, want one for the deleted todo", len(changes.Tombstones))
+	}
+	tombstone, updated := changes.Tombstones[0], changes.Todos[1].ChangePosition()
+	if !updated.Before(tombstone.ChangePosition()) || changes.Until.Before(tombstone.ChangePosition()) {
+		return fmt.Errorf("GetChanges: got tombstone %+v until %+v after %+v, want increasing", tombstone.ChangePosition(), changes.Until, updated)
+	}
+	if changes.Until.Before(start.Until) {
+		return fmt.Errorf("GetChanges: got changes until %+v after %+v, want increasing", changes.Until, start.Until)
+	}
+
+	// Since and Limit narrow the changes
+	filter.Since = updated
+	if changes, err = todoRepo.GetChanges(ctx, filter); err != nil || len(changes.Todos) != 0 || len(changes.Tombstones) != 1 {
+		return fmt.Errorf("GetChanges: got the changes %+v after the update, want the tombstone (err: %v)", changes, err)
+	}
+	filter.Since, filter.Limit = start.Until, 1
+	if changes, err = todoRepo.GetChanges(ctx, filter); err != nil || len(changes.Todos) != 1 || changes.Todos[0].ID != shared.ID {
+		return fmt.Errorf("GetChanges: got the changes %+v with a limit, want the shared todo (err: %v)", changes, err)
+	}
+	filter.ProjectIDs, filter.Limit = nil, 10
+	if changes, err = todoRepo.GetChanges(ctx, filter); err != nil || len(changes.Todos) != 1 || changes.Todos[0].ID != first.ID {
+		return fmt.Errorf("GetChanges: got the changes %+v without the project, want the personal todo (err: %v)", changes, err)
+	}
+
+	// Unassigning the todos of a removed member is a change
+	if err := projectRepo.AddMember(ctx, entity.NewProjectMember(project.ID, owner.ID, entity.ProjectRoleEditor)); err != nil {
+		return fmt.Errorf("AddMember: %w", err)
+	}
+	shared.AssignTo(owner.ID)
+	if err := todoRepo.Update(ctx, shared); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+	seq = shared.ChangeSeq
+	if err := projectRepo.RemoveMember(ctx, project.ID, owner.ID); err != nil {
+		return fmt.Errorf("RemoveMember: %w", err)
+	}
+	if got, err := todoRepo.GetByID(ctx, shared.ID); err != nil || got.AssigneeID != nil || got.ChangeSeq <= seq {
+		return fmt.Errorf("RemoveMember: unassigned todo has no new change sequence number (err: %v)", err)
+	}
+
+	return nil
+}
+
+// TestWorkspaceRepository checks the behavior of a WorkspaceRepository
+// implementation and the isolation of the todos and projects of a workspace
+func TestWorkspaceRepository(userRepo repository.UserRepository, todoRepo repository.TodoRepository, projectRepo repository.ProjectRepository, workspaceRepo repository.WorkspaceRepository) error {
+	ctx := context.Background()
+
+	owner := entity.NewUser("workspace-owner", "workspace-owner@example.com", "hash")
+	if err := userRepo.Create(ctx, owner); err != nil {
+		return fmt.Errorf("creating owner: %w", err)
+	}
+	member := entity.NewUser("workspace-member", "workspace-member@example.com", "hash")
+	if err := userRepo.Create(ctx, member); err != nil {
+		return fmt.Errorf("creating member: %w", err)
+	}
+
+	// Create adds the owner as the first member
+	workspace := entity.NewWorkspace("Acme")
+	if err := workspaceRepo.Create(ctx, workspace, entity.NewWorkspaceMember(0, owner.ID, entity.WorkspaceRoleOwner)); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if workspace.ID == 0 {
+		return errors.New("Create: ID was not assigned")
+	}
+	got, err := workspaceRepo.GetMember(ctx, workspace.ID, owner.ID)
+	if err != nil {
+		return fmt.Errorf("GetMember: %w", err)
+	}
+	if got.Role != entity.WorkspaceRoleOwner {
+		return fmt.Errorf("GetMember: got role %q, want %q", got.Role, entity.WorkspaceRoleOwner)
+	}
+
+	// Update
+	workspace.Rename("Acme Inc")
+	if err := workspaceRepo.Update(ctx, workspace); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+	if saved, err := workspaceRepo.GetByID(ctx, workspace.ID); err != nil || saved.Name != "Acme Inc" {
+		return fmt.Errorf("Update: name was not saved (err: %v)", err)
+	}
+
+	// Members are unique per workspace
+	if err := workspaceRepo.AddMember(ctx, entity.NewWorkspaceMember(workspace.ID, member.ID, entity.WorkspaceRoleMember)); err != nil {
+		return fmt.Errorf("AddMember: %w", err)
+	}
+	if err := workspaceRepo.AddMember(ctx, entity.NewWorkspaceMember(workspace.ID, member.ID, entity.WorkspaceRoleAdmin)); err == nil {
+		return errors.New("AddMember: expected an error for a duplicate member")
+	}
+	members, err := workspaceRepo.GetMembers(ctx, workspace.ID)
+	if err != nil {
+		return fmt.Errorf("GetMembers: %w", err)
+	}
+	if len(members) != 2 || members[0].UserID != owner.ID || members[1].UserID != member.ID {
+		return fmt.Errorf("GetMembers: got %d members, want the owner then the member", len(members))
+	}
+
+	// UpdateMember
+	got, err = workspaceRepo.GetMember(ctx, workspace.ID, member.ID)
+	if err != nil {
+		return fmt.Errorf("GetMember: %w", err)
+	}
+	got.ChangeRole(entity.WorkspaceRoleAdmin)
+	if err := workspaceRepo.UpdateMember(ctx, got); err != nil {
+		return fmt.Errorf("UpdateMember: %w", err)
+	}
+	if got, err = workspaceRepo.GetMember(ctx, workspace.ID, member.ID); err != nil || got.Role != entity.WorkspaceRoleAdmin {
+		return fmt.Errorf("UpdateMember: role was not saved (err: %v)", err)
+	}
+
+	// GetMemberships loads the workspaces, ordered by name
+	other := entity.NewWorkspace("Beta")
+	if err := workspaceRepo.Create(ctx, other, entity.NewWorkspaceMember(0, member.ID, entity.WorkspaceRoleOwner)); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	memberships, err := workspaceRepo.GetMemberships(ctx, member.ID)
+	if err != nil {
+		return fmt.Errorf("GetMemberships: %w", err)
+	}
+	if len(memberships) != 2 || memberships[0].Workspace.Name != "Acme Inc" || memberships[1].Workspace.Name != "Beta" {
+		return fmt.Errorf("GetMemberships: got %d memberships, want Acme Inc and Beta", len(memberships))
+	}
+
+	// Todos created in a workspace belong to it
+	inside := repository.WithWorkspace(ctx, workspace.ID)
+	elsewhere := repository.WithWorkspace(ctx, other.ID)
+	todo := entity.NewTodo("Quarterly report", "", owner.ID)
+	todo.AddTags("q3")
+	if err := todoRepo.Create(inside, todo); err != nil {
+		return fmt.Errorf("creating workspace todo: %w", err)
+	}
+	if todo.WorkspaceID == nil || *todo.WorkspaceID != workspace.ID {
+		return errors.New("Create: workspace todo was not assigned to the workspace")
+	}
+	if _, err := todoRepo.GetByID(inside, todo.ID); err != nil {
+		return fmt.Errorf("GetByID in the workspace: %w", err)
+	}
+
+	// and are invisible outside of it
+	for name, scope := range map[string]context.Context{"without a workspace": ctx, "in another workspace": elsewhere} {
+		if _, err := todoRepo.GetByID(scope, todo.ID); err == nil {
+			return fmt.Errorf("GetByID %s: workspace todo is retrievable", name)
+		}
+		todos, err := todoRepo.GetByUserID(scope, owner.ID, entity.TodoFilter{Page: 1, PageSize: 10})
+		if err != nil {
+			return fmt.Errorf("GetByUserID %s: %w", name, err)
+		}
+		if len(todos) != 0 {
+			return fmt.Errorf("GetByUserID %s: got %d todos, want 0", name, len(todos))
+		}
+		if count, err := todoRepo.Count(scope, entity.TodoFilter{UserID: owner.ID}); err != nil || count != 0 {
+			return fmt.Errorf("Count %s: got %d todos, want 0 (err: %v)", name, count, err)
+		}
+
+		changed := *todo
+		changed.Title = "Tampered"
+		_ = todoRepo.Update(scope, &changed)
+		_ = todoRepo.Delete(scope, todo.ID)
+		saved, err := todoRepo.GetByID(inside, todo.ID)
+		if err != nil {
+			return fmt.Errorf("Delete %s: workspace todo was deleted (err: %v)", name, err)
+		}
+		if saved.Title != todo.Title {
+			return fmt.Errorf("Update %s: workspace todo was changed", name)
+		}
+	}
+
+	// Tags belong to their todo, so a tag only matches the todos of the same scope
+	personal := entity.NewTodo("Personal report", "", owner.ID)
+	personal.AddTags("q3")
+	if err := todoRepo.Create(ctx, personal); err != nil {
+		return fmt.Errorf("creating personal todo: %w", err)
+	}
+	q3, err := entity.ParseTodoFilter("tag:q3")
+	if err != nil {
+		return fmt.Errorf("ParseTodoFilter: %w", err)
+	}
+	for name, check := range map[string]struct {
+		scope context.Context
+		want  []uint
+	}{"in the workspace": {inside, []uint{todo.ID}}, "without a workspace": {ctx, []uint{personal.ID}}, "in another workspace": {elsewhere, nil}} {
+		todos, err := todoRepo.GetByUserID(check.scope, owner.ID, entity.TodoFilter{UserID: owner.ID, Expr: q3, Page: 1, PageSize: 10})
+		if err != nil {
+			return fmt.Errorf("GetByUserID by tag %s: %w", name, err)
+		}
+		ids := make([]uint, len(todos))
+		for i, todo := range todos {
+			ids[i] = todo.ID
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(check.want) {
+			return fmt.Errorf("GetByUserID by tag %s: got todos %v, want %v", name, ids, check.want)
+		}
+	}
+
+	// Projects are scoped the same way, with their members
+	project := entity.NewProject("Roadmap")
+	if err := projectRepo.Create(inside, project, entity.NewProjectMember(0, owner.ID, entity.ProjectRoleOwner)); err != nil {
+		return fmt.Errorf("creating workspace project: %w", err)
+	}
+	if err := projectRepo.AddMember(inside, entity.NewProjectMember(project.ID, member.ID, entity.ProjectRoleEditor)); err != nil {
+		return fmt.Errorf("AddMember in the workspace: %w", err)
+	}
+	if _, err := projectRepo.GetByID(elsewhere, project.ID); err == nil {
+		return errors.New("GetByID in another workspace: workspace project is retrievable")
+	}
+	if _, err := projectRepo.GetMember(ctx, project.ID, owner.ID); err == nil {
+		return errors.New("GetMember without a workspace: workspace project member is retrievable")
+	}
+	if memberships, err := projectRepo.GetMemberships(ctx, owner.ID); err != nil || len(memberships) != 0 {
+		return fmt.Errorf("GetMemberships without a workspace: got %d memberships, want 0 (err: %v)", len(memberships), err)
+	}
+
+	// RemoveMember also removes the member from the projects of the workspace
+	if err := workspaceRepo.RemoveMember(ctx, workspace.ID, member.ID); err != nil {
+		return fmt.Errorf("RemoveMember: %w", err)
+	}
+	if _, err := workspaceRepo.GetMember(ctx, workspace.ID, member.ID); err == nil {
+		return errors.New("RemoveMember: member is still retrievable")
+	}
+	if _, err := projectRepo.GetMember(inside, project.ID, member.ID); err == nil {
+		return errors.New("RemoveMember: project member is still retrievable")
+	}
+
+	// Delete removes the members, projects and todos of the workspace
+	if err := workspaceRepo.Delete(ctx, workspace.ID); err != nil {
+		return fmt.Errorf("Delete: %w", err)
+	}
+	if _, err := workspaceRepo.GetByID(ctx, workspace.ID); err == nil {
+		return errors.New("Delete: workspace is still retrievable")
+	}
+	if _, err := workspaceRepo.GetMember(ctx, workspace.ID, owner.ID); err == nil {
+		return errors.New("Delete: member is still retrievable")
+	}
+	if _, err := projectRepo.GetByID(inside, project.ID); err == nil {
+		return errors.New("Delete: workspace project is still retrievable")
+	}
+	if _, err := todoRepo.GetByID(inside, todo.ID); err == nil {
+		return errors.New("Delete: workspace todo is still retrievable")
+	}
+
+	return nil
+}
+
+// TestCommentRepository checks the behavior of a CommentRepository implementation
+func TestCommentRepository(userRepo repository.UserRepository, todoRepo repository.TodoRepository, workspaceRepo repository.WorkspaceRepository, commentRepo repository.CommentRepository) error {
+	ctx := context.Background()
+
+	author := entity.NewUser("comment-author", "comment-author@example.com", "hash")
+	if err := userRepo.Create(ctx, author); err != nil {
+		return fmt.Errorf("creating author: %w", err)
+	}
+	todo := entity.NewTodo("Discussed todo", "", author.ID)
+	if err := todoRepo.Create(ctx, todo); err != nil {
+		return fmt.Errorf("creating todo: %w", err)
+	}
+
+	// Comments on a missing todo are rejected
+	if err := commentRepo.Create(ctx, entity.NewComment(todo.ID+1000, author.ID, "Orphan")); err == nil {
+		return errors.New("Create: comment on a missing todo was accepted")
+	}
+
+	// Create
+	comments := make([]*entity.Comment, 0, 3)
+	for _, body := range []string{"First", "Second", "Third"} {
+		comment := entity.NewComment(todo.ID, author.ID, body)
+		if err := commentRepo.Create(ctx, comment); err != nil {
+			return fmt.Errorf("Create: %w", err)
+		}
+		if comment.ID == 0 {
+			return errors.New("Create: ID was not assigned")
+		}
+		comments = append(comments, comment)
+	}
+
+	got, err := commentRepo.GetByID(ctx, comments[0].ID)
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	if got.TodoID != todo.ID || got.UserID != author.ID || got.Body != "First" {
+		return fmt.Errorf("GetByID: got %+v, want %+v", got, comments[0])
+	}
+	if _, err := commentRepo.GetByID(ctx, comments[2].ID+1000); err == nil {
+		return errors.New("GetByID: expected an error for a missing comment")
+	}
+
+	// GetByTodoID pages oldest first
+	page, err := commentRepo.GetByTodoID(ctx, todo.ID, 1, 2)
+	if err != nil {
+		return fmt.Errorf("GetByTodoID: %w", err)
+	}
+	if len(page) != 2 || page[0].ID != comments[0].ID || page[1].ID != comments[1].ID {
+		return fmt.Errorf("GetByTodoID: got %d comments on the first page, want First then Second", len(page))
+	}
+	page, err = commentRepo.GetByTodoID(ctx, todo.ID, 2, 2)
+	if err != nil {
+		return fmt.Errorf("GetByTodoID: %w", err)
+	}
+	if len(page) != 1 || page[0].ID != comments[2].ID {
+		return fmt.Errorf("GetByTodoID: got %d comments on the second page, want Third", len(page))
+	}
+	if count, err := commentRepo.CountByTodoID(ctx, todo.ID); err != nil || count != 3 {
+		return fmt.Errorf("CountByTodoID: got %d comments, want 3 (err: %v)", count, err)
+	}
+
+	// Update
+	got.Edit("First, edited")
+	if err := commentRepo.Update(ctx, got); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+	if saved, err := commentRepo.GetByID(ctx, got.ID); err != nil || saved.Body != "First, edited" {
+		return fmt.Errorf("Update: body was not saved (err: %v)", err)
+	}
+
+	// Delete
+	if err := commentRepo.Delete(ctx, comments[1].ID); err != nil {
+		return fmt.Errorf("Delete: %w", err)
+	}
+	if _, err := commentRepo.GetByID(ctx, comments[1].ID); err == nil {
+		return errors.New("Delete: comment is still retrievable")
+	}
+	if count, err := commentRepo.CountByTodoID(ctx, todo.ID); err != nil || count != 2 {
+		return fmt.Errorf("Delete: got %d comments, want 2 (err: %v)", count, err)
+	}
+
+	// Comments on a workspace todo belong to the workspace
+	workspace := entity.NewWorkspace("Comments")
+	if err := workspaceRepo.Create(ctx, workspace, entity.NewWorkspaceMember(0, author.ID, entity.WorkspaceRoleOwner)); err != nil {
+		return fmt.Errorf("creating workspace: %w", err)
+	}
+	inside := repository.WithWorkspace(ctx, workspace.ID)
+	workspaceTodo := entity.NewTodo("Workspace discussion", "", author.ID)
+	if err := todoRepo.Create(inside, workspaceTodo); err != nil {
+		return fmt.Errorf("creating workspace todo: %w", err)
+	}
+	workspaceComment := entity.NewComment(workspaceTodo.ID, author.ID, "Inside")
+	if err := commentRepo.Create(inside, workspaceComment); err != nil {
+		return fmt.Errorf("Create in the workspace: %w", err)
+	}
+	if workspaceComment.WorkspaceID == nil || *workspaceComment.WorkspaceID != workspace.ID {
+		return errors.New("Create: workspace comment was not assigned to the workspace")
+	}
+	if _, err := commentRepo.GetByID(ctx, workspaceComment.ID); err == nil {
+		return errors.New("GetByID without a workspace: workspace comment is retrievable")
+	}
+	if count, err := commentRepo.CountByTodoID(ctx, workspaceTodo.ID); err != nil || count != 0 {
+		return fmt.Errorf("CountByTodoID without a workspace: got %d comments, want 0 (err: %v)", count, err)
+	}
+	_ = commentRepo.Delete(ctx, workspaceComment.ID)
+	if _, err := commentRepo.GetByID(inside, workspaceComment.ID); err != nil {
+		return fmt.Errorf("Delete without a workspace: workspace comment was deleted (err: %v)", err)
+	}
+
+	// Comments are deleted with their todo
+	if err := todoRepo.Delete(ctx, todo.ID); err != nil {
+		return fmt.Errorf("deleting todo: %w", err)
+	}
+	if _, err := commentRepo.GetByID(ctx, comments[0].ID); err == nil {
+		return errors.New("deleting todo: comment is still retrievable")
+	}
+	if err := workspaceRepo.Delete(ctx, workspace.ID); err != nil {
+		return fmt.Errorf("deleting workspace: %w", err)
+	}
+	if _, err := commentRepo.GetByID(inside, workspaceComment.ID); err == nil {
+		return errors.New("deleting workspace: workspace comment is still retrievable")
+	}
+
+	return nil
+}
+
+// TestActivityRepository checks the behavior of an ActivityRepository implementation
+func TestActivityRepository(userRepo repository.UserRepository, todoRepo repository.TodoRepository, workspaceRepo repository.WorkspaceRepository, activityRepo repository.ActivityRepository) error {
+	ctx := context.Background()
+
+	actor := entity.NewUser("activity-actor", "activity-actor@example.com", "hash")
+	if err := userRepo.Create(ctx, actor); err != nil {
+		return fmt.Errorf("creating actor: %w", err)
+	}
+	todo := entity.NewTodo("Tracked todo", "", actor.ID)
+	if err := todoRepo.Create(ctx, todo); err != nil {
+		return fmt.Errorf("creating todo: %w", err)
+	}
+
+	// Create appends in order, several activities at once
+	created := entity.NewActivity(todo.ID, actor.ID, entity.ActivityCreated, entity.DiffTodos(nil, todo))
+	if err := activityRepo.Create(ctx, created); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if created.ID == 0 {
+		return errors.New("Create: ID was not assigned")
+	}
+	completed := entity.NewActivity(todo.ID, actor.ID, entity.ActivityCompleted, []entity.FieldChange{{Field: "completed", Old: false, New: true}})
+	deleted := entity.NewActivity(todo.ID, actor.ID, entity.ActivityDeleted, nil)
+	if err := activityRepo.Create(ctx, completed, deleted); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if completed.ID <= created.ID || deleted.ID <= completed.ID {
+		return errors.New("Create: IDs do not increase in creation order")
+	}
+
+	// GetByTodoID pages oldest first and keeps the changes
+	history, err := activityRepo.GetByTodoID(ctx, todo.ID, 1, 2)
+	if err != nil {
+		return fmt.Errorf("GetByTodoID: %w", err)
+	}
+	if len(history) != 2 || history[0].ID != created.ID || history[1].ID != completed.ID {
+		return fmt.Errorf("GetByTodoID: got %d activities on the first page, want created then completed", len(history))
+	}
+	if history[0].Action != entity.ActivityCreated || len(history[0].Changes) != 1 || history[0].Changes[0].Field != "title" || history[0].Changes[0].New != todo.Title {
+		return fmt.Errorf("GetByTodoID: got %+v, want the created activity with its title change", history[0])
+	}
+	if history, err = activityRepo.GetByTodoID(ctx, todo.ID, 2, 2); err != nil || len(history) != 1 || history[0].ID != deleted.ID {
+		return fmt.Errorf("GetByTodoID: got %d activities on the second page, want deleted (err: %v)", len(history), err)
+	}
+	if count, err := activityRepo.CountByTodoID(ctx, todo.ID); err != nil || count != 3 {
+		return fmt.Errorf("CountByTodoID: got %d activities, want 3 (err: %v)", count, err)
+	}
+
+	// GetByUserID pages newest first
+	feed, err := activityRepo.GetByUserID(ctx, actor.ID, 1, 2)
+	if err != nil {
+		return fmt.Errorf("GetByUserID: %w", err)
+	}
+	if len(feed) != 2 || feed[0].ID != deleted.ID || feed[1].ID != completed.ID {
+		return fmt.Errorf("GetByUserID: got %d activities on the first page, want deleted then completed", len(feed))
+	}
+	if count, err := activityRepo.CountByUserID(ctx, actor.ID); err != nil || count != 3 {
+		return fmt.Errorf("CountByUserID: got %d activities, want 3 (err: %v)", count, err)
+	}
+
+	// Activities outlive their todo
+	if err := todoRepo.Delete(ctx, todo.ID); err != nil {
+		return fmt.Errorf("deleting todo: %w", err)
+	}
+	if count, err := activityRepo.CountByTodoID(ctx, todo.ID); err != nil || count != 3 {
+		return fmt.Errorf("deleting todo: got %d activities, want 3 (err: %v)", count, err)
+	}
+
+	// Activities in a workspace are invisible outside of it and deleted with it
+	workspace := entity.NewWorkspace("Activity")
+	if err := workspaceRepo.Create(ctx, workspace, entity.NewWorkspaceMember(0, actor.ID, entity.WorkspaceRoleOwner)); err != nil {
+		return fmt.Errorf("creating workspace: %w", err)
+	}
+	inside := repository.WithWorkspace(ctx, workspace.ID)
+	workspaceActivity := entity.NewActivity(todo.ID+1000, actor.ID, entity.ActivityCreated, nil)
+	if err := activityRepo.Create(inside, workspaceActivity); err != nil {
+		return fmt.Errorf("Create in the workspace: %w", err)
+	}
+	if workspaceActivity.WorkspaceID == nil || *workspaceActivity.WorkspaceID != workspace.ID {
+		return errors.New("Create: workspace activity was not assigned to the workspace")
+	}
+	if count, err := activityRepo.CountByUserID(ctx, actor.ID); err != nil || count != 3 {
+		return fmt.Errorf("CountByUserID without a workspace: got %d activities, want 3 (err: %v)", count, err)
+	}
+	if count, err := activityRepo.CountByUserID(inside, actor.ID); err != nil || count != 1 {
+		return fmt.Errorf("CountByUserID in the workspace: got %d activities, want 1 (err: %v)", count, err)
+	}
+	if err := workspaceRepo.Delete(ctx, workspace.ID); err != nil {
+		return fmt.Errorf("deleting workspace: %w", err)
+	}
+	if count, err := activityRepo.CountByUserID(inside, actor.ID); err != nil || count != 0 {
+		return fmt.Errorf("deleting workspace: got %d workspace activities, want 0 (err: %v)", count, err)
+	}
+
+	// The todo writes record their activities with the change, and none when they fail
+	written := entity.NewTodo("Written todo", "", actor.ID)
+	createdWith := entity.NewActivity(0, actor.ID, entity.ActivityCreated, entity.DiffTodos(nil, written))
+	if err := todoRepo.Create(ctx, written, createdWith); err != nil {
+		return fmt.Errorf("creating todo with its activity: %w", err)
+	}
+	if createdWith.ID == 0 || createdWith.TodoID != written.ID {
+		return errors.New("creating todo with its activity: activity was not recorded for the todo")
+	}
+	read := written.ChangeSeq
+	written.MarkAsCompleted()
+	if err := todoRepo.Update(ctx, written, entity.NewActivity(written.ID, actor.ID, entity.ActivityCompleted, nil)); err != nil {
+		return fmt.Errorf("updating todo with its activity: %w", err)
+	}
+	stale := entity.NewActivity(written.ID, actor.ID, entity.ActivityDeleted, nil)
+	if err := todoRepo.DeleteIfUnchanged(ctx, written.ID, read, stale); !errors.Is(err, repository.ErrTodoChanged) {
+		return fmt.Errorf("deleting changed todo: got %v, want ErrTodoChanged", err)
+	}
+	history, err = activityRepo.GetByTodoID(ctx, written.ID, 1, 10)
+	if err != nil {
+		return fmt.Errorf("GetByTodoID: %w", err)
+	}
+	if len(history) != 2 || history[0].Action != entity.ActivityCreated || history[1].Action != entity.ActivityCompleted {
+		return fmt.Errorf("GetByTodoID: got %d activities of the written todo, want created then completed", len(history))
+	}
+
+	return nil
+}
+
+// TestAuditRepository checks the behavior of an AuditRepository implementation
+func TestAuditRepository(userRepo repository.UserRepository, auditRepo repository.AuditRepository) error {
+	ctx := context.Background()
+
+	user := entity.NewUser("audited", "audited@example.com", "hash")
+	if err := userRepo.Create(ctx, user); err != nil {
+		return fmt.Errorf("creating user: %w", err)
+	}
+
+	// Create assigns increasing IDs and keeps the details
+	registered := entity.NewAuditEvent(entity.AuditUserRegistered, &user.ID, map[string]interface{}{"email": user.Email})
+	registered.RemoteIP = "192.0.2.1"
+	if err := auditRepo.Create(ctx, registered); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if registered.ID == 0 {
+		return errors.New("Create: ID was not assigned")
+	}
+	failed := entity.NewAuditEvent(entity.AuditLoginFailed, nil, map[string]interface{}{"email": "unknown@example.com"})
+	if err := auditRepo.Create(ctx, failed); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	loggedIn := entity.NewAuditEvent(entity.AuditLoginSucceeded, &user.ID, nil)
+	if err := auditRepo.Create(ctx, loggedIn); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if failed.ID <= registered.ID || loggedIn.ID <= failed.ID {
+		return errors.New("Create: IDs do not increase in creation order")
+	}
+
+	// List pages newest first
+	events, err := auditRepo.List(ctx, entity.AuditFilter{Page: 1, PageSize: 2})
+	if err != nil {
+		return fmt.Errorf("List: %w", err)
+	}
+	if len(events) != 2 || events[0].ID != loggedIn.ID || events[1].ID != failed.ID {
+		return fmt.Errorf("List: got %d events on the first page, want the login then the failed login", len(events))
+	}
+	if events[1].UserID != nil || events[1].Details["email"] != "unknown@example.com" {
+		return fmt.Errorf("List: got %+v, want the failed login without a user", events[1])
+	}
+	if events, err = auditRepo.List(ctx, entity.AuditFilter{Page: 2, PageSize: 2}); err != nil || len(events) != 1 || events[0].ID != registered.ID {
+		return fmt.Errorf("List: got %d events on the second page, want the registration (err: %v)", len(events), err)
+	}
+	if events[0].RemoteIP != "192.0.2.1" || events[0].Details["email"] != user.Email {
+		return fmt.Errorf("List: got %+v, want the registration with its client and details", events[0])
+	}
+
+	// The filters restrict the user, type and time range
+	userFilter := entity.AuditFilter{UserID: &user.ID, Page: 1, PageSize: 10}
+	if count, err := auditRepo.Count(ctx, userFilter); err != nil || count != 2 {
+		return fmt.Errorf("Count by user: got %d events, want 2 (err: %v)", count, err)
+	}
+	typeFilter := entity.AuditFilter{UserID: &user.ID, Type: entity.AuditLoginSucceeded, Page: 1, PageSize: 10}
+	if events, err := auditRepo.List(ctx, typeFilter); err != nil || len(events) != 1 || events[0].ID != loggedIn.ID {
+		return fmt.Errorf("List by type: got %d events, want the login (err: %v)", len(events), err)
+	}
+	future := entity.AuditFilter{Since: loggedIn.CreatedAt.Add(time.Hour), Page: 1, PageSize: 10}
+	if count, err := auditRepo.Count(ctx, future); err != nil || count != 0 {
+		return fmt.Errorf("Count since the future: got %d events, want 0 (err: %v)", count, err)
+	}
+	past := entity.AuditFilter{Since: registered.CreatedAt.Add(-time.Hour), Until: loggedIn.CreatedAt.Add(time.Hour), Page: 1, PageSize: 10}
+	if count, err := auditRepo.Count(ctx, past); err != nil || count != 3 {
+		return fmt.Errorf("Count in range: got %d events, want 3 (err: %v)", count, err)
+	}
+
+	return nil
+}
+
+// TestWebhookRepository checks the behavior of a WebhookRepository implementation
+func TestWebhookRepository(userRepo repository.UserRepository, workspaceRepo repository.WorkspaceRepository, webhookRepo repository.WebhookRepository) error {
+	ctx := context.Background()
+
+	owner := entity.NewUser("webhook-owner", "webhook-owner@example.com", "hash")
+	if err := userRepo.Create(ctx, owner); err != nil {
+		return fmt.Errorf("creating owner: %w", err)
+	}
+
+	// Create keeps the subscribed events
+	webhook := entity.NewWebhook(owner.ID, "https://example.com/hook", "secret", []entity.TodoEventType{entity.TodoEventCreated, entity.TodoEventCompleted})
+	if err := webhookRepo.Create(ctx, webhook); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if webhook.ID == 0 {
+		return errors.New("Create: ID was not assigned")
+	}
+	saved, err := webhookRepo.GetByID(ctx, webhook.ID)
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	if saved.URL != webhook.URL || saved.Secret != webhook.Secret || !saved.Active || len(saved.Events) != 2 || !saved.Subscribes(entity.TodoEventCompleted) {
+		return fmt.Errorf("GetByID: got %+v, want the created webhook", saved)
+	}
+	if _, err := webhookRepo.GetByID(ctx, webhook.ID+1000); err == nil {
+		return errors.New("GetByID: expected an error for a missing webhook")
+	}
+
+	// GetSubscribed only returns active webhooks subscribed to the event type
+	other := entity.NewWebhook(owner.ID, "https://example.com/other", "secret", []entity.TodoEventType{entity.TodoEventDeleted})
+	if err := webhookRepo.Create(ctx, other); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if webhooks, err := webhookRepo.GetByUserID(ctx, owner.ID); err != nil || len(webhooks) != 2 || webhooks[0].ID != webhook.ID {
+		return fmt.Errorf("GetByUserID: got %d webhooks, want 2 oldest first (err: %v)", len(webhooks), err)
+	}
+	if webhooks, err := webhookRepo.GetSubscribed(ctx, entity.TodoEventCreated); err != nil || len(webhooks) != 1 || webhooks[0].ID != webhook.ID {
+		return fmt.Errorf("GetSubscribed: got %d webhooks, want the subscribed one (err: %v)", len(webhooks), err)
+	}
+	other.Update(other.URL, []entity.TodoEventType{entity.TodoEventCreated}, false)
+	if err := webhookRepo.Update(ctx, other); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+	if webhooks, err := webhookRepo.GetSubscribed(ctx, entity.TodoEventCreated); err != nil || len(webhooks) != 1 {
+		return fmt.Errorf("GetSubscribed: got %d webhooks, want the inactive one to be skipped (err: %v)", len(webhooks), err)
+	}
+
+	// GetDeliveries pages newest first
+	first := entity.NewWebhookDelivery(webhook.ID, 1, entity.TodoEventCreated, `{"id":1}`)
+	second := entity.NewWebhookDelivery(webhook.ID, 2, entity.TodoEventCompleted, `{"id":2}`)
+	inactive := entity.NewWebhookDelivery(other.ID, 1, entity.TodoEventCreated, `{"id":1}`)
+	if err := webhookRepo.CreateDeliveries(ctx, first, second, inactive); err != nil {
+		return fmt.Errorf("CreateDeliveries: %w", err)
+	}
+	if first.ID == 0 || second.ID <= first.ID {
+		return errors.New("CreateDeliveries: IDs do not increase in creation order")
+	}
+	deliveries, err := webhookRepo.GetDeliveries(ctx, webhook.ID, 1, 1)
+	if err != nil {
+		return fmt.Errorf("GetDeliveries: %w", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].ID != second.ID || deliveries[0].Payload != second.Payload {
+		return fmt.Errorf("GetDeliveries: got %d deliveries on the first page, want the second delivery", len(deliveries))
+	}
+	if count, err := webhookRepo.CountDeliveries(ctx, webhook.ID); err != nil || count != 2 {
+		return fmt.Errorf("CountDeliveries: got %d deliveries, want 2 (err: %v)", count, err)
+	}
+
+	// ClaimDueDeliveries skips inactive webhooks and leases the claimed deliveries
+	now := time.Now().Add(time.Second)
+	claimed, err := webhookRepo.ClaimDueDeliveries(ctx, now, now.Add(time.Minute), 10)
+	if err != nil {
+		return fmt.Errorf("ClaimDueDeliveries: %w", err)
+	}
+	if len(claimed) != 2 || claimed[0].Webhook == nil || claimed[0].Webhook.ID != webhook.ID {
+		return fmt.Errorf("ClaimDueDeliveries: got %d deliveries, want the 2 of the active webhook with it loaded", len(claimed))
+	}
+	if claimed, err := webhookRepo.ClaimDueDeliveries(ctx, now, now.Add(time.Minute), 10); err != nil || len(claimed) != 0 {
+		return fmt.Errorf("ClaimDueDeliveries: got %d leased deliveries claimed again (err: %v)", len(claimed), err)
+	}
+
+	// UpdateDelivery stores the outcome of an attempt
+	delivery := claimed[0]
+	delivery.Succeed(204, now)
+	if err := webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		return fmt.Errorf("UpdateDelivery: %w", err)
+	}
+	if got, err := webhookRepo.GetDeliveryByID(ctx, delivery.ID); err != nil || got.Status != entity.DeliverySucceeded || got.Attempts != 1 || got.ResponseStatus != 204 {
+		return fmt.Errorf("UpdateDelivery: outcome was not saved (err: %v)", err)
+	}
+
+	// Webhooks in a workspace are invisible outside of it and deleted with it
+	workspace := entity.NewWorkspace("Webhooks")
+	if err := workspaceRepo.Create(ctx, workspace, entity.NewWorkspaceMember(0, owner.ID, entity.WorkspaceRoleOwner)); err != nil {
+		return fmt.Errorf("creating workspace: %w", err)
+	}
+	inside := repository.WithWorkspace(ctx, workspace.ID)
+	workspaceWebhook := entity.NewWebhook(owner.ID, "https://example.com/workspace", "secret", []entity.TodoEventType{entity.TodoEventCreated})
+	if err := webhookRepo.Create(inside, workspaceWebhook); err != nil {
+		return fmt.Errorf("Create in the workspace: %w", err)
+	}
+	if _, err := webhookRepo.GetByID(ctx, workspaceWebhook.ID); err == nil {
+		return errors.New("GetByID: workspace webhook is visible outside of the workspace")
+	}
+	if webhooks, err := webhookRepo.GetSubscribed(inside, entity.TodoEventCreated); err != nil || len(webhooks) != 1 || webhooks[0].ID != workspaceWebhook.ID {
+		return fmt.Errorf("GetSubscribed in the workspace: got %d webhooks, want the workspace one (err: %v)", len(webhooks), err)
+	}
+	if err := webhookRepo.CreateDeliveries(inside, entity.NewWebhookDelivery(workspaceWebhook.ID, 3, entity.TodoEventCreated, `{"id":3}`)); err != nil {
+		return fmt.Errorf("CreateDeliveries in the workspace: %w", err)
+	}
+	if err := workspaceRepo.Delete(ctx, workspace.ID); err != nil {
+		return fmt.Errorf("deleting workspace: %w", err)
+	}
+	if _, err := webhookRepo.GetByID(inside, workspaceWebhook.ID); err == nil {
+		return errors.New("deleting workspace: workspace webhook was not deleted")
+	}
+	if count, err := webhookRepo.CountDeliveries(inside, workspaceWebhook.ID); err != nil || count != 0 {
+		return fmt.Errorf("deleting workspace: got %d workspace deliveries, want 0 (err: %v)", count, err)
+	}
+
+	// Delete removes the deliveries
+	if err := webhookRepo.Delete(ctx, webhook.ID); err != nil {
+		return fmt.Errorf("Delete: %w", err)
+	}
+	if _, err := webhookRepo.GetByID(ctx, webhook.ID); err == nil {
+		return errors.New("Delete: webhook still exists")
+	}
+	if _, err := webhookRepo.GetDeliveryByID(ctx, first.ID); err == nil {
+		return errors.New("Delete: delivery still exists")
+	}
+
+	return nil
+}
+
+// TestWebhookOutbox tests the outbox of todo events written by the todo
+// repository and drained by the webhook scheduler
+func TestWebhookOutbox(userRepo repository.UserRepository, todoRepo repository.TodoRepository, workspaceRepo repository.WorkspaceRepository, webhookRepo repository.WebhookRepository) error {
+	ctx := context.Background()
+
+	owner := entity.NewUser("outbox-owner", "outbox-owner@example.com", "hash")
+	if err := userRepo.Create(ctx, owner); err != nil {
+		return fmt.Errorf("creating owner: %w", err)
+	}
+	webhook := entity.NewWebhook(owner.ID, "https://example.com/outbox", "secret", entity.TodoEventTypes)
+	if err := webhookRepo.Create(ctx, webhook); err != nil {
+		return fmt.Errorf("creating webhook: %w", err)
+	}
+
+	// The events of earlier writes are claimed out of the way
+	now := time.Now().Add(time.Second)
+	if _, err := webhookRepo.ClaimEvents(ctx, now, now.Add(time.Hour), 10000); err != nil {
+		return fmt.Errorf("ClaimEvents: %w", err)
+	}
+
+	// Every write recording activities adds their events to the outbox, a
+	// failed write adds none
+	todo := entity.NewTodo("Outbox todo", "", owner.ID)
+	if err := todoRepo.Create(ctx, todo, entity.NewActivity(0, owner.ID, entity.ActivityCreated, nil)); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	todo.Title = "Renamed outbox todo"
+	if err := todoRepo.Update(ctx, todo, entity.NewActivity(todo.ID, owner.ID, entity.ActivityUpdated, nil)); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+	if err := todoRepo.DeleteIfUnchanged(ctx, todo.ID, todo.ChangeSeq-1, entity.NewActivity(todo.ID, owner.ID, entity.ActivityDeleted, nil)); !errors.Is(err, repository.ErrTodoChanged) {
+		return fmt.Errorf("DeleteIfUnchanged: got error %v, want %v", err, repository.ErrTodoChanged)
+	}
+	if err := todoRepo.Delete(ctx, todo.ID, entity.NewActivity(todo.ID, owner.ID, entity.ActivityDeleted, nil)); err != nil {
+		return fmt.Errorf("Delete: %w", err)
+	}
+
+	// ClaimEvents returns the events oldest first with the snapshots of the todo
+	now = now.Add(time.Second)
+	events, err := webhookRepo.ClaimEvents(ctx, now, now.Add(time.Minute), 10)
+	if err != nil {
+		return fmt.Errorf("ClaimEvents: %w", err)
+	}
+	wantTypes := []entity.TodoEventType{entity.TodoEventCreated, entity.TodoEventUpdated, entity.TodoEventDeleted}
+	if len(events) != len(wantTypes) {
+		return fmt.Errorf("ClaimEvents: got %d events, want %d", len(events), len(wantTypes))
+	}
+	for i, event := range events {
+		if event.Event.Type() != wantTypes[i] || event.Event.Activity.ID == 0 || event.Event.Todo.ID != todo.ID {
+			return fmt.Errorf("ClaimEvents: got a %s event of todo %d at %d, want a recorded %s event of todo %d",
+				event.Event.Type(), event.Event.Todo.ID, i, wantTypes[i], todo.ID)
+		}
+	}
+	if events[0].Event.Todo.Title != "Outbox todo" || events[2].Event.Todo.Title != "Renamed outbox todo" {
+		return fmt.Errorf("ClaimEvents: got titles %q and %q, want the todo as written", events[0].Event.Todo.Title, events[2].Event.Todo.Title)
+	}
+	if claimed, err := webhookRepo.ClaimEvents(ctx, now, now.Add(time.Minute), 10); err != nil || len(claimed) != 0 {
+		return fmt.Errorf("ClaimEvents: got %d claimed events claimed again (err: %v)", len(claimed), err)
+	}
+
+	// ScheduleDeliveries removes the event with its deliveries created, once
+	delivery := entity.NewWebhookDelivery(webhook.ID, events[0].Event.Activity.ID, entity.TodoEventCreated, `{}`)
+	if err := webhookRepo.ScheduleDeliveries(ctx, events[0], delivery); err != nil {
+		return fmt.Errorf("ScheduleDeliveries: %w", err)
+	}
+	if _, err := webhookRepo.GetDeliveryByID(ctx, delivery.ID); err != nil {
+		return fmt.Errorf("ScheduleDeliveries: delivery was not created: %w", err)
+	}
+	again := entity.NewWebhookDelivery(webhook.ID, events[0].Event.Activity.ID, entity.TodoEventCreated, `{}`)
+	if err := webhookRepo.ScheduleDeliveries(ctx, events[0], again); !errors.Is(err, repository.ErrClaimExpired) {
+		return fmt.Errorf("ScheduleDeliveries: got error %v for a removed event, want %v", err, repository.ErrClaimExpired)
+	}
+	if count, err := webhookRepo.CountDeliveries(ctx, webhook.ID); err != nil || count != 1 {
+		return fmt.Errorf("ScheduleDeliveries: got %d deliveries, want 1 (err: %v)", count, err)
+	}
+
+	// Events whose claim expired are claimed again, the expired claim no
+	// longer schedules them
+	later := now.Add(2 * time.Minute)
+	reclaimed, err := webhookRepo.ClaimEvents(ctx, later, later.Add(time.Minute), 10)
+	if err != nil {
+		return fmt.Errorf("ClaimEvents: %w", err)
+	}
+	if len(reclaimed) != 2 || reclaimed[0].ID != events[1].ID {
+		return fmt.Errorf("ClaimEvents: got %d events after the claim expired, want the 2 left", len(reclaimed))
+	}
+	if err := webhookRepo.ScheduleDeliveries(ctx, events[1]); !errors.Is(err, repository.ErrClaimExpired) {
+		return fmt.Errorf("ScheduleDeliveries: got error %v for an expired claim, want %v", err, repository.ErrClaimExpired)
+	}
+	for _, event := range reclaimed {
+		if err := webhookRepo.ScheduleDeliveries(ctx, event); err != nil {
+			return fmt.Errorf("ScheduleDeliveries: %w", err)
+		}
+	}
+
+	// Events in a workspace schedule their deliveries in it and are deleted
+	// with the workspace
+	workspace := entity.NewWorkspace("Outbox")
+	if err := workspaceRepo.Create(ctx, workspace, entity.NewWorkspaceMember(0, owner.ID, entity.WorkspaceRoleOwner)); err != nil {
+		return fmt.Errorf("creating workspace: %w", err)
+	}
+	inside := repository.WithWorkspace(ctx, workspace.ID)
+	workspaceWebhook := entity.NewWebhook(owner.ID, "https://example.com/outbox-workspace", "secret", entity.TodoEventTypes)
+	if err := webhookRepo.Create(inside, workspaceWebhook); err != nil {
+		return fmt.Errorf("creating workspace webhook: %w", err)
+	}
+	for _, title := range []string{"Scheduled", "Pending"} {
+		workspaceTodo := entity.NewTodo(title, "", owner.ID)
+		if err := todoRepo.Create(inside, workspaceTodo, entity.NewActivity(0, owner.ID, entity.ActivityCreated, nil)); err != nil {
+			return fmt.Errorf("creating workspace todo: %w", err)
+		}
+	}
+	events, err = webhookRepo.ClaimEvents(ctx, later, later.Add(time.Minute), 1)
+	if err != nil {
+		return fmt.Errorf("ClaimEvents: %w", err)
+	}
+	if len(events) != 1 || events[0].WorkspaceID == nil || *events[0].WorkspaceID != workspace.ID {
+		return fmt.Errorf("ClaimEvents: got %d events, want 1 in the workspace", len(events))
+	}
+	delivery = entity.NewWebhookDelivery(workspaceWebhook.ID, events[0].Event.Activity.ID, entity.TodoEventCreated, `{}`)
+	if err := webhookRepo.ScheduleDeliveries(ctx, events[0], delivery); err != nil {
+		return fmt.Errorf("ScheduleDeliveries in the workspace: %w", err)
+	}
+	if count, err := webhookRepo.CountDeliveries(inside, workspaceWebhook.ID); err != nil || count != 1 {
+		return fmt.Errorf("ScheduleDeliveries in the workspace: got %d deliveries in the workspace, want 1 (err: %v)", count, err)
+	}
+	if err := workspaceRepo.Delete(ctx, workspace.ID); err != nil {
+		return fmt.Errorf("deleting workspace: %w", err)
+	}
+	if events, err := webhookRepo.ClaimEvents(ctx, later, later.Add(time.Minute), 10); err != nil || len(events) != 0 {
+		return fmt.Errorf("deleting workspace: got %d events left in the outbox, want 0 (err: %v)", len(events), err)
+	}
+
+	return nil
+}
+
+// checkTodoCursors pages through the todos of userID by cursor, two at a time,
+// and checks that both directions agree with the first offset page
+func checkTodoCursors(ctx context.Context, todoRepo repository.TodoRepository, userID uint, total int) error {
+	all, err := todoRepo.GetByUserID(ctx, userID, entity.TodoFilter{UserID: userID, Page: 1, PageSize: total})
+	if err != nil {
+		return fmt.Errorf("GetByUserID: %w", err)
+	}
+
+	filter := entity.TodoFilter{UserID: userID, PageSize: 2, Cursor: &entity.TodoCursor{}}
+	var forward []*entity.Todo
+	for len(forward) < total {
+		list, err := todoRepo.GetByUserID(ctx, userID, filter)
+		if err != nil {
+			return fmt.Errorf("GetByUserID (cursor): %w", err)
+		}
+		if len(list) == 0 {
+			break
+		}
+		forward = append(forward, list...)
+		filter.Cursor = entity.TodoCursorAfter(list[len(list)-1])
+	}
+	if err := sameTodoOrder(forward, all); err != nil {
+		return fmt.Errorf("GetByUserID (cursor forward): %w", err)
+	}
+
+	filter.Cursor = entity.TodoCursorBefore(all[len(all)-1])
+	backward := []*entity.Todo{all[len(all)-1]}
+	for {
+		list, err := todoRepo.GetByUserID(ctx, userID, filter)
+		if err != nil {
+			return fmt.Errorf("GetByUserID (cursor): %w", err)
+		}
+		if len(list) == 0 {
+			break
+		}
+		backward = append(list, backward...)
+		filter.Cursor = entity.TodoCursorBefore(list[0])
+	}
+	if err := sameTodoOrder(backward, all); err != nil {
+		return fmt.Errorf("GetByUserID (cursor backward): %w", err)
+	}
+
+	return nil
+}
+
+// sameTodoOrder checks that got lists the same todos as want, in the same order
+func sameTodoOrder(got, want []*entity.Todo) error {
+	if len(got) != len(want) {
+		return fmt.Errorf("got %d todos, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID {
+			return fmt.Errorf("todo %d: got ID %d, want %d", i, got[i].ID, want[i].ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+)
+
+// workspaceKey is the context key of the workspace selected for a request
+type workspaceKey struct{}
+
+// WithWorkspace returns a copy of ctx that scopes the todo and project
+// repositories to a workspace. Without it they only see the todos and
+// projects outside any workspace.
+func WithWorkspace(ctx context.Context, workspaceID uint) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, workspaceID)
+}
+
+// WorkspaceFromContext returns the workspace selected by ctx, if any
+func WorkspaceFromContext(ctx context.Context) (uint, bool) {
+	workspaceID, ok := ctx.Value(workspaceKey{}).(uint)
+	return workspaceID, ok
+}
+
+// WorkspaceIDFromContext returns the workspace selected by ctx as stored in
+// the WorkspaceID of todos and projects
+func WorkspaceIDFromContext(ctx context.Context) *uint {
+	workspaceID, ok := WorkspaceFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return &workspaceID
+}
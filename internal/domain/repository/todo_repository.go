@@ -2,27 +2,66 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"todo-api/internal/domain/entity"
 )
 
-// TodoRepository defines the interface for todo repository operations
+// ErrTodoChanged is returned by the conditional writes of a TodoRepository
+// when the todo was changed or deleted since the given change sequence number
+var ErrTodoChanged = errors.New("todo changed")
+
+// TodoRepository defines the interface for todo repository operations. Every
+// operation is scoped to the workspace of the context, see WithWorkspace. The
+// writes give every todo they change a new change sequence number and record
+// the deleted todos as tombstones, see GetChanges. They append the given
+// activities to the history of the todos in the same transaction, see
+// ActivityRepository, and record none when they change nothing.
 type TodoRepository interface {
-	// Create creates a new todo
-	Create(ctx context.Context, todo *entity.Todo) error
+	// Create creates a new todo, setting the TodoID of the activities
+	Create(ctx context.Context, todo *entity.Todo, activities ...*entity.Activity) error
 	
 	// GetByID retrieves a todo by its ID
 	GetByID(ctx context.Context, id uint) (*entity.Todo, error)
 	
-	// GetByUserID retrieves todos for a specific user
+	// GetByIDs retrieves the existing todos among ids, in no particular order
+	GetByIDs(ctx context.Context, ids []uint) ([]*entity.Todo, error)
+
+	// GetByUserID retrieves the personal todos of a user, or the todos of
+	// filter.ProjectID when set, newest first. With a cursor it returns up to
+	// PageSize todos after or before the cursor.
 	GetByUserID(ctx context.Context, userID uint, filter entity.TodoFilter) ([]*entity.Todo, error)
 	
 	// Update updates a todo
-	Update(ctx context.Context, todo *entity.Todo) error
+	Update(ctx context.Context, todo *entity.Todo, activities ...*entity.Activity) error
 	
 	// Delete deletes a todo
-	Delete(ctx context.Context, id uint) error
+	Delete(ctx context.Context, id uint, activities ...*entity.Activity) error
 	
-	// Count counts todos based on filter
+	// Count counts todos based on filter, scoped like GetByUserID
 	Count(ctx context.Context, filter entity.TodoFilter) (int64, error)
+
+	// Search retrieves a page of the todos matching filter.FullText, most
+	// relevant first, with the matched words highlighted
+	Search(ctx context.Context, filter entity.TodoFilter) ([]*entity.TodoMatch, error)
+
+	// ApplyChanges saves the updated todos and deletes the deleted ones in a
+	// single transaction, unless one of them was changed since it was read,
+	// according to its change sequence number. It returns ErrTodoChanged when
+	// a todo was changed or deleted, and then changes none.
+	ApplyChanges(ctx context.Context, updated, deleted []*entity.Todo, activities ...*entity.Activity) error
+
+	// UpdateIfUnchanged updates a todo unless it was changed since it was
+	// read, according to its change sequence number. It returns ErrTodoChanged
+	// when the todo was changed or deleted.
+	UpdateIfUnchanged(ctx context.Context, todo *entity.Todo, activities ...*entity.Activity) error
+
+	// DeleteIfUnchanged deletes a todo unless its change sequence number moved
+	// past changeSeq. It returns ErrTodoChanged when the todo was changed or
+	// deleted.
+	DeleteIfUnchanged(ctx context.Context, id uint, changeSeq uint64, activities ...*entity.Activity) error
+
+	// GetChanges retrieves the todos written and the tombstones of the todos
+	// deleted after filter.Since, in the scope of the filter
+	GetChanges(ctx context.Context, filter entity.TodoChangeFilter) (*entity.TodoChanges, error)
 }
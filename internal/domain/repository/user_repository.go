@@ -20,9 +20,13 @@ type UserRepository interface {
 	// GetByEmail retrieves a user by their email
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	
-	// Update updates a user's information
+	// Update updates a user's information, leaving the token version alone
 	Update(ctx context.Context, user *entity.User) error
 	
+	// RevokeTokens increments the token version of a user, revoking the
+	// access tokens issued to them so far
+	RevokeTokens(ctx context.Context, id uint) error
+	
 	// Delete deletes a user
 	Delete(ctx context.Context, id uint) error
 	
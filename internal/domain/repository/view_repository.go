@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"todo-api/internal/domain/entity"
+)
+
+// ViewRepository defines the interface for saved view repository operations
+type ViewRepository interface {
+	// Create creates a new view
+	Create(ctx context.Context, view *entity.View) error
+
+	// GetByID retrieves a view by its ID
+	GetByID(ctx context.Context, id uint) (*entity.View, error)
+
+	// GetByUserID retrieves all views of a user ordered by name
+	GetByUserID(ctx context.Context, userID uint) ([]*entity.View, error)
+
+	// Update updates a view
+	Update(ctx context.Context, view *entity.View) error
+
+	// Delete deletes a view
+	Delete(ctx context.Context, id uint) error
+}
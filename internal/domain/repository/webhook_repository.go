@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"todo-api/internal/domain/entity"
+)
+
+// ErrClaimExpired is returned when an outbox event is no longer claimed by
+// the scheduler handling it, because its claim expired and another scheduler
+// claimed or handled it since
+var ErrClaimExpired = errors.New("claim expired")
+
+// WebhookRepository defines the interface for webhook and webhook delivery
+// repository operations. Webhooks and deliveries are scoped to the workspace
+// of the context, except for the outbox of todo events and the claiming and
+// updating of due deliveries by the background workers, which serve all
+// workspaces.
+type WebhookRepository interface {
+	// Create creates a new webhook in the workspace of the context
+	Create(ctx context.Context, webhook *entity.Webhook) error
+
+	// GetByID retrieves a webhook by its ID
+	GetByID(ctx context.Context, id uint) (*entity.Webhook, error)
+
+	// GetByUserID retrieves the webhooks of a user, oldest first
+	GetByUserID(ctx context.Context, userID uint) ([]*entity.Webhook, error)
+
+	// GetSubscribed retrieves the active webhooks receiving events of a type
+	GetSubscribed(ctx context.Context, eventType entity.TodoEventType) ([]*entity.Webhook, error)
+
+	// Update updates a webhook
+	Update(ctx context.Context, webhook *entity.Webhook) error
+
+	// Delete deletes a webhook with its deliveries
+	Delete(ctx context.Context, id uint) error
+
+	// CreateDeliveries creates deliveries in the workspace of the context
+	CreateDeliveries(ctx context.Context, deliveries ...*entity.WebhookDelivery) error
+
+	// ClaimEvents retrieves up to limit todo events of the outbox in any
+	// workspace, oldest first, that are unclaimed or whose claim expired at
+	// now. They are claimed until leaseUntil, so that other instances skip
+	// them while their deliveries are scheduled.
+	ClaimEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.OutboxEvent, error)
+
+	// ScheduleDeliveries removes a claimed event from the outbox and creates
+	// its deliveries in the workspace of the event, in a single transaction.
+	// It returns ErrClaimExpired and creates no delivery when the event is no
+	// longer claimed as it was retrieved.
+	ScheduleDeliveries(ctx context.Context, event *entity.OutboxEvent, deliveries ...*entity.WebhookDelivery) error
+
+	// GetDeliveryByID retrieves a delivery by its ID
+	GetDeliveryByID(ctx context.Context, id uint) (*entity.WebhookDelivery, error)
+
+	// GetDeliveries retrieves a page of the deliveries of a webhook, newest first
+	GetDeliveries(ctx context.Context, webhookID uint, page, pageSize int) ([]*entity.WebhookDelivery, error)
+
+	// CountDeliveries counts the deliveries of a webhook
+	CountDeliveries(ctx context.Context, webhookID uint) (int64, error)
+
+	// ClaimDueDeliveries retrieves up to limit pending deliveries of active
+	// webhooks due at now, in any workspace, with their Webhook loaded. They
+	// are postponed to leaseUntil, so that other instances skip them while
+	// they are sent.
+	ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.WebhookDelivery, error)
+
+	// UpdateDelivery stores the outcome of an attempt of a delivery in any workspace
+	UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"todo-api/internal/domain/entity"
+)
+
+// WorkspaceRepository defines the interface for workspace and membership
+// repository operations. Unlike todos and projects, workspaces are not scoped
+// to the workspace of the context.
+type WorkspaceRepository interface {
+	// Create creates a new workspace with its first member in a single transaction
+	Create(ctx context.Context, workspace *entity.Workspace, owner *entity.WorkspaceMember) error
+
+	// GetByID retrieves a workspace by its ID
+	GetByID(ctx context.Context, id uint) (*entity.Workspace, error)
+
+	// Update updates a workspace
+	Update(ctx context.Context, workspace *entity.Workspace) error
+
+	// Delete deletes a workspace with its members, projects, todos, activities and webhooks in a single transaction
+	Delete(ctx context.Context, id uint) error
+
+	// GetMember retrieves the membership of a user in a workspace
+	GetMember(ctx context.Context, workspaceID, userID uint) (*entity.WorkspaceMember, error)
+
+	// GetMembers retrieves the members of a workspace in the order they joined
+	GetMembers(ctx context.Context, workspaceID uint) ([]*entity.WorkspaceMember, error)
+
+	// GetMemberships retrieves the memberships of a user with their workspaces
+	// loaded, ordered by workspace name
+	GetMemberships(ctx context.Context, userID uint) ([]*entity.WorkspaceMember, error)
+
+	// AddMember adds a member to a workspace
+	AddMember(ctx context.Context, member *entity.WorkspaceMember) error
+
+	// UpdateMember updates the role of a member
+	UpdateMember(ctx context.Context, member *entity.WorkspaceMember) error
+
+	// RemoveMember removes a member from a workspace and from its projects, and
+	// unassigns the todos of the workspace assigned to them in a single transaction
+	RemoveMember(ctx context.Context, workspaceID, userID uint) error
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/util/logging"
+)
+
+// Errors related to activity operations
+var (
+	ErrActivityLoadFailed = newError(KindInternal, "activity_load_failed", "failed to load activity")
+)
+
+// ActivityPage is a page of activities
+type ActivityPage struct {
+	Activities []*entity.Activity
+	Page       int
+	PageSize   int
+	Total      int64
+}
+
+// ActivityUseCase defines the interface for todo history use cases. The
+// activities are recorded by the todo use case.
+type ActivityUseCase interface {
+	GetTodoHistory(ctx context.Context, todoID, userID uint, page, pageSize int) (*ActivityPage, error)
+	GetUserActivity(ctx context.Context, userID uint, page, pageSize int) (*ActivityPage, error)
+}
+
+// activityUseCase implements ActivityUseCase
+type activityUseCase struct {
+	activityRepo repository.ActivityRepository
+	todos        TodoUseCase
+}
+
+// NewActivityUseCase creates a new ActivityUseCase, which checks access to the
+// todos through the todo use case
+func NewActivityUseCase(activityRepo repository.ActivityRepository, todos TodoUseCase) ActivityUseCase {
+	return &activityUseCase{
+		activityRepo: activityRepo,
+		todos:        todos,
+	}
+}
+
+// GetTodoHistory retrieves a page of the history of a todo the user can view, oldest first
+func (uc *activityUseCase) GetTodoHistory(ctx context.Context, todoID, userID uint, page, pageSize int) (*ActivityPage, error) {
+	if _, err := uc.todos.GetTodoByID(ctx, todoID, userID); err != nil {
+		return nil, err
+	}
+
+	page, pageSize = normalizeActivityPage(page, pageSize)
+	activities, err := uc.activityRepo.GetByTodoID(ctx, todoID, page, pageSize)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("todo_id", todoID).Error("Failed to list todo history")
+		return nil, ErrActivityLoadFailed
+	}
+
+	count, err := uc.activityRepo.CountByTodoID(ctx, todoID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("todo_id", todoID).Error("Failed to count todo history")
+		return nil, ErrActivityLoadFailed
+	}
+
+	return &ActivityPage{Activities: activities, Page: page, PageSize: pageSize, Total: count}, nil
+}
+
+// GetUserActivity retrieves a page of the changes the user made to todos, newest first
+func (uc *activityUseCase) GetUserActivity(ctx context.Context, userID uint, page, pageSize int) (*ActivityPage, error) {
+	page, pageSize = normalizeActivityPage(page, pageSize)
+	activities, err := uc.activityRepo.GetByUserID(ctx, userID, page, pageSize)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to list user activity")
+		return nil, ErrActivityLoadFailed
+	}
+
+	count, err := uc.activityRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to count user activity")
+		return nil, ErrActivityLoadFailed
+	}
+
+	return &ActivityPage{Activities: activities, Page: page, PageSize: pageSize, Total: count}, nil
+}
+
+// normalizeActivityPage applies the pagination defaults and limits
+func normalizeActivityPage(page, pageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	return page, pageSize
+}
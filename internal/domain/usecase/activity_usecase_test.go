@@ -0,0 +1,45 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+)
+
+func TestTodoHistory(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	activities := usecase.NewActivityUseCase(f.activityRepo, f.todos)
+	alice, bob := f.user(t, "alice"), f.user(t, "bob")
+	todo := f.todo(t, "draft", nil, alice.ID)
+	if _, err := f.todos.UpdateTodo(ctx, todo.ID, "final", "", false, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.todos.CompleteTodo(ctx, todo.ID, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := activities.GetTodoHistory(ctx, todo.ID, alice.ID, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history.Total != 3 {
+		t.Fatalf("got %d activities, want 3", history.Total)
+	}
+	actions := make(map[entity.ActivityAction]bool)
+	for _, activity := range history.Activities {
+		actions[activity.Action] = true
+	}
+	for _, action := range []entity.ActivityAction{entity.ActivityCreated, entity.ActivityUpdated, entity.ActivityCompleted} {
+		if !actions[action] {
+			t.Errorf("history misses the %s activity", action)
+		}
+	}
+
+	if _, err := activities.GetTodoHistory(ctx, todo.ID, bob.ID, 1, 10); !errors.Is(err, usecase.ErrNotAuthorized) {
+		t.Errorf("other user: got error %v, want %v", err, usecase.ErrNotAuthorized)
+	}
+}
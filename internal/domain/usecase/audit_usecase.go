@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/util/logging"
+)
+
+// Errors related to audit log operations
+var (
+	ErrAdminRequired      = newError(KindForbidden, "admin_required", "only administrators can perform this action")
+	ErrInvalidAuditFilter = newError(KindInvalid, "invalid_audit_filter", "since must be before until")
+	ErrAuditLogLoadFailed = newError(KindInternal, "audit_log_load_failed", "failed to load audit log")
+)
+
+// AuditPage is a page of audit events
+type AuditPage struct {
+	Events   []*entity.AuditEvent
+	Page     int
+	PageSize int
+	Total    int64
+}
+
+// AuditUseCase defines the interface for audit log use cases. The events are
+// recorded by the use cases they are about.
+type AuditUseCase interface {
+	GetEvents(ctx context.Context, filter entity.AuditFilter, userID uint) (*AuditPage, error)
+}
+
+// auditUseCase implements AuditUseCase
+type auditUseCase struct {
+	auditRepo repository.AuditRepository
+	auditor   Auditor
+	admins    map[uint]bool
+}
+
+// NewAuditUseCase creates a new AuditUseCase that lets the users in admins
+// query the audit log
+func NewAuditUseCase(auditRepo repository.AuditRepository, auditor Auditor, admins []uint) AuditUseCase {
+	adminSet := make(map[uint]bool, len(admins))
+	for _, id := range admins {
+		adminSet[id] = true
+	}
+	return &auditUseCase{
+		auditRepo: auditRepo,
+		auditor:   auditor,
+		admins:    adminSet,
+	}
+}
+
+// GetEvents retrieves a page of the audit events matching a filter, newest
+// first. Every query is itself recorded in the audit log.
+func (uc *auditUseCase) GetEvents(ctx context.Context, filter entity.AuditFilter, userID uint) (*AuditPage, error) {
+	if !uc.admins[userID] {
+		return nil, ErrAdminRequired
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return nil, ErrInvalidAuditFilter
+	}
+
+	filter.Page, filter.PageSize = normalizeAuditPage(filter.Page, filter.PageSize)
+	uc.auditor.Record(ctx, entity.NewAuditEvent(entity.AuditLogViewed, &userID, auditFilterDetails(filter)))
+
+	events, err := uc.auditRepo.List(ctx, filter)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to list audit events")
+		return nil, ErrAuditLogLoadFailed
+	}
+
+	count, err := uc.auditRepo.Count(ctx, filter)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to count audit events")
+		return nil, ErrAuditLogLoadFailed
+	}
+
+	return &AuditPage{Events: events, Page: filter.Page, PageSize: filter.PageSize, Total: count}, nil
+}
+
+// auditFilterDetails returns the audit details describing a query of the audit log
+func auditFilterDetails(filter entity.AuditFilter) map[string]interface{} {
+	details := map[string]interface{}{
+		"page":      filter.Page,
+		"page_size": filter.PageSize,
+	}
+	if filter.UserID != nil {
+		details["user_id"] = *filter.UserID
+	}
+	if filter.Type != "" {
+		details["type"] = string(filter.Type)
+	}
+	if !filter.Since.IsZero() {
+		details["since"] = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		details["until"] = filter.Until
+	}
+	return details
+}
+
+// normalizeAuditPage applies the pagination defaults and limits
+func normalizeAuditPage(page, pageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	return page, pageSize
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+
+	"todo-api/internal/domain/entity"
+)
+
+// Auditor records security audit events
+type Auditor interface {
+	// Record appends an event to the audit log. The client of the request is
+	// taken from the context. Failures are logged rather than returned, so that
+	// auditing never fails the audited operation.
+	Record(ctx context.Context, event *entity.AuditEvent)
+}
+
+// NoopAuditor is an Auditor implementation that discards all events
+type NoopAuditor struct{}
+
+// Record implements Auditor
+func (NoopAuditor) Record(ctx context.Context, event *entity.AuditEvent) {}
+
+// ClientInfo describes the client of a request for the audit log
+type ClientInfo struct {
+	RemoteIP  string
+	UserAgent string
+	RequestID string
+}
+
+// clientInfoKey is the context key of the client of a request
+type clientInfoKey struct{}
+
+// WithClientInfo returns a copy of ctx carrying the client of the request
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext returns the client of the request carried by ctx, or
+// a zero ClientInfo if there is none
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/util/logging"
+)
+
+// Errors related to bulk todo operations
+var (
+	ErrInvalidBulkAction = newError(KindInvalid, "invalid_bulk_action", "action must be complete, uncomplete, delete, move, tag or untag")
+	ErrBulkMoveProject   = newError(KindInvalid, "bulk_move_project_required", "the move action requires a project_id")
+	ErrBulkTags          = newError(KindInvalid, "bulk_tags_required", "the tag and untag actions require tags")
+	ErrInvalidTag        = newError(KindInvalid, "invalid_tag", "tags must have 1 to 32 letters, digits, - or _")
+	ErrTooManyTags       = newError(KindInvalid, "too_many_tags", "a todo can have at most 20 tags")
+	ErrInvalidBulkTarget = newError(KindInvalid, "invalid_bulk_target", "either ids or a filter must be given, not both")
+	ErrBulkTooManyTodos  = newError(KindInvalid, "bulk_too_many_todos", "a bulk operation can change at most 500 todos")
+	ErrBulkFailed        = newError(KindInternal, "bulk_failed", "failed to apply the bulk operation")
+)
+
+// MaxBulkTodos limits the number of todos a bulk operation changes
+const MaxBulkTodos = 500
+
+// maxBulkAttempts limits how often a bulk operation starts over when one of
+// its todos was changed concurrently
+const maxBulkAttempts = 3
+
+// BulkAction is an action applied to many todos at once
+type BulkAction string
+
+// Bulk actions
+const (
+	BulkComplete   BulkAction = "complete"
+	BulkUncomplete BulkAction = "uncomplete"
+	BulkDelete     BulkAction = "delete"
+	BulkMove       BulkAction = "move"
+	BulkTag        BulkAction = "tag"
+	BulkUntag      BulkAction = "untag"
+)
+
+// BulkOperation is a bulk action with its arguments
+type BulkOperation struct {
+	Action BulkAction
+
+	// ProjectID is the project BulkMove moves the todos to
+	ProjectID uint
+
+	// Tags are the tags BulkTag adds and BulkUntag removes
+	Tags []string
+}
+
+// BulkTarget selects the todos of a bulk operation, either by ID or by filter
+type BulkTarget struct {
+	IDs    []uint
+	Filter *entity.TodoFilter
+}
+
+// BulkItemResult reports the outcome of a bulk operation for one todo. Err is
+// nil on success, otherwise a *Error such as ErrTodoNotFound.
+type BulkItemResult struct {
+	ID  uint
+	Err error
+}
+
+// BulkResult reports the outcome of a bulk operation per todo
+type BulkResult struct {
+	Action BulkAction
+	Items  []BulkItemResult
+}
+
+// Succeeded counts the todos the operation was applied to
+func (r *BulkResult) Succeeded() int {
+	succeeded := 0
+	for _, item := range r.Items {
+		if item.Err == nil {
+			succeeded++
+		}
+	}
+	return succeeded
+}
+
+// bulkChanges are the changes of a bulk operation decided from the loaded todos
+type bulkChanges struct {
+	result    *BulkResult
+	updated   []*entity.Todo
+	deleted   []*entity.Todo
+	events    []*entity.TodoEvent
+	completed int
+}
+
+// activities returns the activities of the events, recorded with the changes
+func (c *bulkChanges) activities() []*entity.Activity {
+	activities := make([]*entity.Activity, len(c.events))
+	for i, event := range c.events {
+		activities[i] = event.Activity
+	}
+	return activities
+}
+
+// BulkUpdate applies an operation to the todos of the target. Todos that do
+// not exist or that the user may not edit are reported as failed items, the
+// others are changed in a single transaction. The todos are loaded and checked
+// before it, the transaction only applies the changes if none of them was
+// changed in the meantime, otherwise the operation starts over. BulkMove
+// requires the editor role in the destination project, and unassigns the
+// todos whose assignee is not a member of it.
+func (uc *todoUseCase) BulkUpdate(ctx context.Context, op BulkOperation, target BulkTarget, userID uint) (*BulkResult, error) {
+	switch op.Action {
+	case BulkComplete, BulkUncomplete, BulkDelete:
+	case BulkMove:
+		if op.ProjectID == 0 {
+			return nil, ErrBulkMoveProject
+		}
+	case BulkTag, BulkUntag:
+		tags, err := normalizeTags(op.Tags)
+		if err != nil {
+			return nil, err
+		}
+		op.Tags = tags
+	default:
+		return nil, ErrInvalidBulkAction
+	}
+
+	ids, err := uc.bulkTargetIDs(ctx, target, userID)
+	if err != nil {
+		return nil, err
+	}
+	if op.Action == BulkMove {
+		if _, err := uc.permissions.AuthorizeProject(ctx, op.ProjectID, userID, entity.PermissionEdit); err != nil {
+			return nil, err
+		}
+	}
+
+	var changes *bulkChanges
+	for attempt := 1; ; attempt++ {
+		changes, err = uc.bulkChanges(ctx, op, ids, userID)
+		if err == nil {
+			err = uc.todoRepo.ApplyChanges(ctx, changes.updated, changes.deleted, changes.activities()...)
+		}
+		if !errors.Is(err, repository.ErrTodoChanged) || attempt == maxBulkAttempts {
+			break
+		}
+	}
+	if errors.Is(err, repository.ErrTodoChanged) {
+		return nil, ErrTodoChanged
+	}
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("action", op.Action).Error("Failed to apply bulk operation")
+		return nil, ErrBulkFailed
+	}
+	for i := 0; i < changes.completed; i++ {
+		uc.metrics.TodoCompleted()
+	}
+	if len(changes.events) > 0 {
+		uc.events.Publish(ctx, changes.events...)
+	}
+	result := changes.result
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"action":    op.Action,
+		"succeeded": result.Succeeded(),
+		"failed":    len(result.Items) - result.Succeeded(),
+	}).Info("Bulk operation applied")
+
+	return result, nil
+}
+
+// bulkChanges loads the todos with the IDs and decides the changes of the
+// operation from them, checking that the user may edit each
+func (uc *todoUseCase) bulkChanges(ctx context.Context, op BulkOperation, ids []uint, userID uint) (*bulkChanges, error) {
+	todos, err := uc.todoRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*entity.Todo, len(todos))
+	for _, todo := range todos {
+		byID[todo.ID] = todo
+	}
+
+	changes := &bulkChanges{result: &BulkResult{Action: op.Action, Items: make([]BulkItemResult, 0, len(ids))}}
+	assignees := make(map[uint]error)
+	for _, id := range ids {
+		todo, ok := byID[id]
+		if !ok {
+			changes.result.Items = append(changes.result.Items, BulkItemResult{ID: id, Err: ErrTodoNotFound})
+			continue
+		}
+		if err := uc.permissions.AuthorizeTodo(ctx, todo, userID, entity.PermissionEdit); err != nil {
+			changes.result.Items = append(changes.result.Items, BulkItemResult{ID: id, Err: err})
+			continue
+		}
+
+		before := *todo
+		action := entity.ActivityUpdated
+		changed := false
+		switch op.Action {
+		case BulkComplete:
+			if changed = !todo.Completed; changed {
+				todo.MarkAsCompleted()
+				action = entity.ActivityCompleted
+				changes.completed++
+			}
+		case BulkUncomplete:
+			if changed = todo.Completed; changed {
+				todo.MarkAsIncomplete()
+			}
+		case BulkDelete:
+			changes.deleted = append(changes.deleted, todo)
+			changes.events = append(changes.events, entity.NewTodoEvent(&before, userID, entity.ActivityDeleted, entity.DiffTodos(&before, nil)))
+		case BulkMove:
+			if changed = todo.ProjectID == nil || *todo.ProjectID != op.ProjectID; changed {
+				todo.MoveTo(op.ProjectID)
+				if todo.AssigneeID != nil {
+					err, checked := assignees[*todo.AssigneeID]
+					if !checked {
+						err = uc.checkAssignee(ctx, todo, *todo.AssigneeID)
+						assignees[*todo.AssigneeID] = err
+					}
+					if err != nil {
+						todo.Unassign()
+					}
+				}
+			}
+		case BulkTag:
+			changed = todo.AddTags(op.Tags...)
+			if len(todo.Tags) > entity.MaxTodoTags {
+				changes.result.Items = append(changes.result.Items, BulkItemResult{ID: id, Err: ErrTooManyTags})
+				continue
+			}
+		case BulkUntag:
+			changed = todo.RemoveTags(op.Tags...)
+		}
+		changes.result.Items = append(changes.result.Items, BulkItemResult{ID: id})
+
+		if changed {
+			changes.updated = append(changes.updated, todo)
+			changes.events = append(changes.events, entity.NewTodoEvent(todo, userID, action, entity.DiffTodos(&before, todo)))
+		}
+	}
+	return changes, nil
+}
+
+// normalizeTags normalizes the tags of a bulk operation, dropping duplicates
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, ErrBulkTags
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag, ok := entity.NormalizeTag(tag)
+		if !ok {
+			return nil, ErrInvalidTag
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > entity.MaxTodoTags {
+		return nil, ErrTooManyTags
+	}
+	return normalized, nil
+}
+
+// bulkTargetIDs returns the distinct IDs of the target in request order, the
+// todos of the user matching a filter target newest first
+func (uc *todoUseCase) bulkTargetIDs(ctx context.Context, target BulkTarget, userID uint) ([]uint, error) {
+	if (target.Filter == nil) == (len(target.IDs) == 0) {
+		return nil, ErrInvalidBulkTarget
+	}
+
+	if target.Filter == nil {
+		if len(target.IDs) > MaxBulkTodos {
+			return nil, ErrBulkTooManyTodos
+		}
+		ids := make([]uint, 0, len(target.IDs))
+		seen := make(map[uint]bool, len(target.IDs))
+		for _, id := range target.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	// Fetch one more todo to find out whether the filter selects too many
+	filter := *target.Filter
+	filter.UserID = userID
+	filter.Page, filter.PageSize = 1, MaxBulkTodos+1
+	filter.Sort, filter.Cursor, filter.FullText = "", nil, nil
+	todos, err := uc.todoRepo.GetByUserID(ctx, userID, filter)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to list todos for bulk operation")
+		return nil, ErrBulkFailed
+	}
+	if len(todos) > MaxBulkTodos {
+		return nil, ErrBulkTooManyTodos
+	}
+
+	ids := make([]uint, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+	return ids, nil
+}
@@ -0,0 +1,225 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+)
+
+func TestBulkUpdateReportsFailedItems(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	alice, bob := f.user(t, "alice"), f.user(t, "bob")
+	own := f.todo(t, "own", nil, alice.ID)
+	other := f.todo(t, "other", nil, bob.ID)
+
+	op := usecase.BulkOperation{Action: usecase.BulkComplete}
+	result, err := f.todos.BulkUpdate(ctx, op, usecase.BulkTarget{IDs: []uint{own.ID, other.ID, 999, own.ID}}, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []error{nil, usecase.ErrNotAuthorized, usecase.ErrTodoNotFound}
+	if len(result.Items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(result.Items), len(want))
+	}
+	for i, item := range result.Items {
+		if !errors.Is(item.Err, want[i]) {
+			t.Errorf("item %d: got error %v, want %v", item.ID, item.Err, want[i])
+		}
+	}
+	if result.Succeeded() != 1 {
+		t.Errorf("got %d succeeded, want 1", result.Succeeded())
+	}
+
+	if todo, _ := f.todoRepo.GetByID(ctx, own.ID); !todo.Completed {
+		t.Error("own todo was not completed")
+	}
+	if todo, _ := f.todoRepo.GetByID(ctx, other.ID); todo.Completed {
+		t.Error("todo of another user was completed")
+	}
+}
+
+func TestBulkUpdateDeletesByFilter(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	alice := f.user(t, "alice")
+	done := f.todo(t, "done", nil, alice.ID)
+	open := f.todo(t, "open", nil, alice.ID)
+	if _, err := f.todos.CompleteTodo(ctx, done.ID, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	completed := true
+	target := usecase.BulkTarget{Filter: &entity.TodoFilter{Completed: &completed}}
+	result, err := f.todos.BulkUpdate(ctx, usecase.BulkOperation{Action: usecase.BulkDelete}, target, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 1 || result.Items[0].ID != done.ID {
+		t.Fatalf("got items %+v, want the completed todo only", result.Items)
+	}
+
+	if _, err := f.todoRepo.GetByID(ctx, done.ID); err == nil {
+		t.Error("completed todo was not deleted")
+	}
+	if _, err := f.todoRepo.GetByID(ctx, open.ID); err != nil {
+		t.Errorf("open todo: %v", err)
+	}
+}
+
+func TestBulkUpdateMovesTodos(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	alice, bob, carol := f.user(t, "alice"), f.user(t, "bob"), f.user(t, "carol")
+	source := f.project(t, alice.ID, map[uint]entity.ProjectRole{bob.ID: entity.ProjectRoleEditor, carol.ID: entity.ProjectRoleViewer})
+	destination := f.project(t, alice.ID, map[uint]entity.ProjectRole{carol.ID: entity.ProjectRoleViewer})
+
+	personal := f.todo(t, "personal", nil, alice.ID)
+	kept := f.todo(t, "kept", &source.ID, alice.ID)
+	unassigned := f.todo(t, "unassigned", &source.ID, alice.ID)
+	if _, err := f.todos.AssignTodo(ctx, kept.ID, &carol.ID, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.todos.AssignTodo(ctx, unassigned.ID, &bob.ID, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	op := usecase.BulkOperation{Action: usecase.BulkMove, ProjectID: destination.ID}
+	result, err := f.todos.BulkUpdate(ctx, op, usecase.BulkTarget{IDs: []uint{personal.ID, kept.ID, unassigned.ID}}, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Succeeded() != 3 {
+		t.Fatalf("got %d succeeded, want 3", result.Succeeded())
+	}
+
+	for _, id := range []uint{personal.ID, kept.ID, unassigned.ID} {
+		todo, err := f.todoRepo.GetByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if todo.ProjectID == nil || *todo.ProjectID != destination.ID {
+			t.Errorf("todo %d was not moved", id)
+		}
+	}
+	if todo, _ := f.todoRepo.GetByID(ctx, kept.ID); !todo.IsAssignedTo(carol.ID) {
+		t.Error("todo assigned to a member of the destination was unassigned")
+	}
+	if todo, _ := f.todoRepo.GetByID(ctx, unassigned.ID); todo.AssigneeID != nil {
+		t.Error("todo assigned to a non-member of the destination is still assigned")
+	}
+}
+
+func TestBulkUpdateTagsTodos(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	alice := f.user(t, "alice")
+	first := f.todo(t, "first", nil, alice.ID)
+	second := f.todo(t, "second", nil, alice.ID)
+	target := usecase.BulkTarget{IDs: []uint{first.ID, second.ID}}
+
+	tag := usecase.BulkOperation{Action: usecase.BulkTag, Tags: []string{" Work", "urgent", "work"}}
+	if result, err := f.todos.BulkUpdate(ctx, tag, target, alice.ID); err != nil || result.Succeeded() != 2 {
+		t.Fatalf("tag: got result %+v, error %v", result, err)
+	}
+	untag := usecase.BulkOperation{Action: usecase.BulkUntag, Tags: []string{"urgent"}}
+	if result, err := f.todos.BulkUpdate(ctx, untag, usecase.BulkTarget{IDs: []uint{second.ID}}, alice.ID); err != nil || result.Succeeded() != 1 {
+		t.Fatalf("untag: got result %+v, error %v", result, err)
+	}
+
+	want := map[uint]string{first.ID: "urgent,work", second.ID: "work"}
+	for id, tags := range want {
+		todo, err := f.todoRepo.GetByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(todo.Tags, ","); got != tags {
+			t.Errorf("todo %d: got tags %q, want %q", id, got, tags)
+		}
+	}
+}
+
+func TestBulkUpdateValidatesTags(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	alice := f.user(t, "alice")
+	todo := f.todo(t, "todo", nil, alice.ID)
+	target := usecase.BulkTarget{IDs: []uint{todo.ID}}
+
+	tooMany := make([]string, entity.MaxTodoTags+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag-%d", i)
+	}
+	tests := []struct {
+		name string
+		op   usecase.BulkOperation
+		want error
+	}{
+		{"no tags", usecase.BulkOperation{Action: usecase.BulkTag}, usecase.ErrBulkTags},
+		{"invalid tag", usecase.BulkOperation{Action: usecase.BulkTag, Tags: []string{"two words"}}, usecase.ErrInvalidTag},
+		{"too long", usecase.BulkOperation{Action: usecase.BulkUntag, Tags: []string{strings.Repeat("a", entity.MaxTagLength+1)}}, usecase.ErrInvalidTag},
+		{"too many", usecase.BulkOperation{Action: usecase.BulkTag, Tags: tooMany}, usecase.ErrTooManyTags},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := f.todos.BulkUpdate(ctx, tt.op, target, alice.ID); !errors.Is(err, tt.want) {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkUpdateMoveRequiresEditor(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	alice, bob := f.user(t, "alice"), f.user(t, "bob")
+	project := f.project(t, alice.ID, map[uint]entity.ProjectRole{bob.ID: entity.ProjectRoleViewer})
+	todo := f.todo(t, "todo", nil, bob.ID)
+	target := usecase.BulkTarget{IDs: []uint{todo.ID}}
+
+	tests := []struct {
+		name string
+		op   usecase.BulkOperation
+		want error
+	}{
+		{"viewer", usecase.BulkOperation{Action: usecase.BulkMove, ProjectID: project.ID}, usecase.ErrProjectAccessDenied},
+		{"missing project", usecase.BulkOperation{Action: usecase.BulkMove, ProjectID: 999}, usecase.ErrProjectNotFound},
+		{"no project", usecase.BulkOperation{Action: usecase.BulkMove}, usecase.ErrBulkMoveProject},
+		{"unknown action", usecase.BulkOperation{Action: "archive"}, usecase.ErrInvalidBulkAction},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := f.todos.BulkUpdate(ctx, tt.op, target, bob.ID); !errors.Is(err, tt.want) {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if stored, _ := f.todoRepo.GetByID(ctx, todo.ID); stored.ProjectID != nil {
+		t.Error("todo was moved")
+	}
+}
+
+func TestBulkUpdateValidatesTarget(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	alice := f.user(t, "alice")
+	op := usecase.BulkOperation{Action: usecase.BulkComplete}
+
+	if _, err := f.todos.BulkUpdate(ctx, op, usecase.BulkTarget{}, alice.ID); !errors.Is(err, usecase.ErrInvalidBulkTarget) {
+		t.Errorf("empty target: got error %v, want %v", err, usecase.ErrInvalidBulkTarget)
+	}
+	tooMany := make([]uint, usecase.MaxBulkTodos+1)
+	for i := range tooMany {
+		tooMany[i] = uint(i + 1)
+	}
+	if _, err := f.todos.BulkUpdate(ctx, op, usecase.BulkTarget{IDs: tooMany}, alice.ID); !errors.Is(err, usecase.ErrBulkTooManyTodos) {
+		t.Errorf("too many todos: got error %v, want %v", err, usecase.ErrBulkTooManyTodos)
+	}
+}
@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/util/logging"
+)
+
+// Errors related to comment operations
+var (
+	ErrCommentNotFound     = newError(KindNotFound, "comment_not_found", "comment not found")
+	ErrCommentAccessDenied = newError(KindForbidden, "comment_access_denied", "only the author can change a comment")
+	ErrInvalidCommentData  = newError(KindInvalid, "invalid_comment_data", "invalid comment data")
+	ErrCommentCreateFailed = newError(KindInternal, "comment_create_failed", "failed to create comment")
+	ErrCommentUpdateFailed = newError(KindInternal, "comment_update_failed", "failed to update comment")
+	ErrCommentDeleteFailed = newError(KindInternal, "comment_delete_failed", "failed to delete comment")
+	ErrCommentLoadFailed   = newError(KindInternal, "comment_load_failed", "failed to load comment")
+)
+
+// Comment limits
+const (
+	MaxCommentLength = 10000
+
+	// MaxCommentMentions bounds the users looked up for the mentions of a comment
+	MaxCommentMentions = 20
+)
+
+// CommentPage is a page of the comments on a todo
+type CommentPage struct {
+	Comments []*entity.Comment
+	Page     int
+	PageSize int
+	Total    int64
+}
+
+// CommentUseCase defines the interface for todo comment use cases. Comments are
+// returned with their author loaded.
+type CommentUseCase interface {
+	AddComment(ctx context.Context, todoID uint, body string, userID uint) (*entity.Comment, error)
+	GetComments(ctx context.Context, todoID, userID uint, page, pageSize int) (*CommentPage, error)
+	EditComment(ctx context.Context, todoID, commentID uint, body string, userID uint) (*entity.Comment, error)
+	DeleteComment(ctx context.Context, todoID, commentID, userID uint) error
+}
+
+// commentUseCase implements CommentUseCase
+type commentUseCase struct {
+	commentRepo repository.CommentRepository
+	userRepo    repository.UserRepository
+	todos       TodoUseCase
+	permissions PermissionService
+	notifier    Notifier
+}
+
+// NewCommentUseCase creates a new CommentUseCase, which checks access to the
+// todos through the todo use case
+func NewCommentUseCase(commentRepo repository.CommentRepository, userRepo repository.UserRepository, todos TodoUseCase, permissions PermissionService, notifier Notifier) CommentUseCase {
+	return &commentUseCase{
+		commentRepo: commentRepo,
+		userRepo:    userRepo,
+		todos:       todos,
+		permissions: permissions,
+		notifier:    notifier,
+	}
+}
+
+// AddComment comments on a todo the user can view and notifies the users it mentions
+func (uc *commentUseCase) AddComment(ctx context.Context, todoID uint, body string, userID uint) (*entity.Comment, error) {
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	todo, err := uc.todos.GetTodoByID(ctx, todoID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := entity.NewComment(todo.ID, userID, body)
+	if err := uc.loadAuthor(ctx, comment, nil); err != nil {
+		return nil, err
+	}
+
+	if err := uc.commentRepo.Create(ctx, comment); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("todo_id", todoID).Error("Failed to store comment")
+		return nil, ErrCommentCreateFailed
+	}
+	logging.FromContext(ctx).WithField("comment_id", comment.ID).Info("Comment created")
+	uc.notifyMentions(ctx, todo, comment, nil)
+
+	return comment, nil
+}
+
+// GetComments retrieves a page of the comments on a todo the user can view, oldest first
+func (uc *commentUseCase) GetComments(ctx context.Context, todoID, userID uint, page, pageSize int) (*CommentPage, error) {
+	if _, err := uc.todos.GetTodoByID(ctx, todoID, userID); err != nil {
+		return nil, err
+	}
+
+	// Ensure pagination defaults and limits
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	comments, err := uc.commentRepo.GetByTodoID(ctx, todoID, page, pageSize)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("todo_id", todoID).Error("Failed to list comments")
+		return nil, err
+	}
+
+	count, err := uc.commentRepo.CountByTodoID(ctx, todoID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("todo_id", todoID).Error("Failed to count comments")
+		return nil, err
+	}
+
+	// Authors usually write several comments of a page
+	authors := make(map[uint]*entity.User)
+	for _, comment := range comments {
+		if err := uc.loadAuthor(ctx, comment, authors); err != nil {
+			return nil, err
+		}
+	}
+
+	return &CommentPage{Comments: comments, Page: page, PageSize: pageSize, Total: count}, nil
+}
+
+// EditComment replaces the body of a comment written by the user and notifies
+// the users it newly mentions
+func (uc *commentUseCase) EditComment(ctx context.Context, todoID, commentID uint, body string, userID uint) (*entity.Comment, error) {
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	todo, comment, err := uc.authorize(ctx, todoID, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.loadAuthor(ctx, comment, nil); err != nil {
+		return nil, err
+	}
+
+	previous := comment.Mentions()
+	comment.Edit(body)
+	if err := uc.commentRepo.Update(ctx, comment); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("comment_id", commentID).Error("Failed to store comment update")
+		return nil, ErrCommentUpdateFailed
+	}
+	uc.notifyMentions(ctx, todo, comment, previous)
+
+	return comment, nil
+}
+
+// DeleteComment deletes a comment written by the user
+func (uc *commentUseCase) DeleteComment(ctx context.Context, todoID, commentID, userID uint) error {
+	if _, _, err := uc.authorize(ctx, todoID, commentID, userID); err != nil {
+		return err
+	}
+
+	if err := uc.commentRepo.Delete(ctx, commentID); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("comment_id", commentID).Error("Failed to delete comment")
+		return ErrCommentDeleteFailed
+	}
+	logging.FromContext(ctx).WithField("comment_id", commentID).Info("Comment deleted")
+
+	return nil
+}
+
+// authorize returns a comment on a todo the user can view, unless the user is
+// not its author
+func (uc *commentUseCase) authorize(ctx context.Context, todoID, commentID, userID uint) (*entity.Todo, *entity.Comment, error) {
+	todo, err := uc.todos.GetTodoByID(ctx, todoID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	comment, err := uc.commentRepo.GetByID(ctx, commentID)
+	if err != nil || comment.TodoID != todo.ID {
+		return nil, nil, ErrCommentNotFound
+	}
+	if !comment.IsAuthor(userID) {
+		return nil, nil, ErrCommentAccessDenied
+	}
+
+	return todo, comment, nil
+}
+
+// notifyMentions notifies the users mentioned by a comment, except for its
+// author, the users in previous and the users who cannot view the todo
+func (uc *commentUseCase) notifyMentions(ctx context.Context, todo *entity.Todo, comment *entity.Comment, previous []string) {
+	notified := make(map[string]bool, len(previous))
+	for _, username := range previous {
+		notified[username] = true
+	}
+
+	lookups := 0
+	for _, username := range comment.Mentions() {
+		if notified[username] || username == comment.User.Username {
+			continue
+		}
+		if lookups == MaxCommentMentions {
+			logging.FromContext(ctx).WithField("comment_id", comment.ID).Warn("Comment mentions too many users, skipping the rest")
+			return
+		}
+		lookups++
+
+		user, err := uc.userRepo.GetByUsername(ctx, username)
+		if err != nil {
+			continue
+		}
+		if err := uc.permissions.AuthorizeTodo(ctx, todo, user.ID, entity.PermissionView); err != nil {
+			continue
+		}
+		uc.notifier.Mentioned(ctx, todo, comment, user.ID)
+	}
+}
+
+// loadAuthor loads the author of a comment, through authors when it is not nil
+func (uc *commentUseCase) loadAuthor(ctx context.Context, comment *entity.Comment, authors map[uint]*entity.User) error {
+	author, ok := authors[comment.UserID]
+	if !ok {
+		user, err := uc.userRepo.GetByID(ctx, comment.UserID)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).WithField("user_id", comment.UserID).Error("Failed to load comment author")
+			return ErrCommentLoadFailed
+		}
+		author = user
+		if authors != nil {
+			authors[comment.UserID] = author
+		}
+	}
+	comment.User = *author
+	return nil
+}
+
+// normalizeCommentBody trims a comment body and checks its length
+func normalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > MaxCommentLength {
+		return "", ErrInvalidCommentData
+	}
+	return body, nil
+}
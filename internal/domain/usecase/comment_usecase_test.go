@@ -0,0 +1,52 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+)
+
+// mentionLog is a Notifier collecting the mentioned users
+type mentionLog struct {
+	usecase.NoopNotifier
+	mentioned []uint
+}
+
+// Mentioned implements usecase.Notifier
+func (l *mentionLog) Mentioned(ctx context.Context, todo *entity.Todo, comment *entity.Comment, mentionedID uint) {
+	l.mentioned = append(l.mentioned, mentionedID)
+}
+
+func TestCommentMentions(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	notifier := &mentionLog{}
+	comments := usecase.NewCommentUseCase(f.commentRepo, f.userRepo, f.todos, f.permissions, notifier)
+	alice, bob, dave := f.user(t, "alice"), f.user(t, "bob"), f.user(t, "dave")
+	f.user(t, "carol")
+	project := f.project(t, alice.ID, map[uint]entity.ProjectRole{bob.ID: entity.ProjectRoleViewer})
+	todo := f.todo(t, "todo", &project.ID, alice.ID)
+
+	// Only the members who can view the todo are notified, the author never
+	comment, err := comments.AddComment(ctx, todo.ID, "@bob @carol @alice @nobody have a look", alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.mentioned) != 1 || notifier.mentioned[0] != bob.ID {
+		t.Fatalf("got mentioned %v, want bob only", notifier.mentioned)
+	}
+
+	// An edit only notifies the users mentioned for the first time
+	if err := f.projectRepo.AddMember(ctx, entity.NewProjectMember(project.ID, dave.ID, entity.ProjectRoleViewer)); err != nil {
+		t.Fatal(err)
+	}
+	notifier.mentioned = nil
+	if _, err := comments.EditComment(ctx, todo.ID, comment.ID, "@bob @carol @dave please have a look", alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.mentioned) != 1 || notifier.mentioned[0] != dave.ID {
+		t.Fatalf("got mentioned %v after the edit, want dave only", notifier.mentioned)
+	}
+}
@@ -0,0 +1,41 @@
+package usecase
+
+// ErrorKind classifies domain errors so that the interface layer can map them to a status
+type ErrorKind int
+
+// Error kinds
+const (
+	KindInternal ErrorKind = iota
+	KindInvalid
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+)
+
+// Error is a domain error with a stable, machine-readable code
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+// newError creates a new Error
+func newError(kind ErrorKind, code, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is an Error with the same code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+
+	"todo-api/internal/domain/entity"
+)
+
+// TodoEventPublisher is told about every change of a todo once it was stored
+// and recorded in the history of the todo
+type TodoEventPublisher interface {
+	// Publish is called with the events of a request in the order they happened.
+	// Failures are logged rather than returned, the changes are already stored.
+	Publish(ctx context.Context, events ...*entity.TodoEvent)
+}
+
+// NoopTodoEventPublisher is a TodoEventPublisher implementation that discards all events
+type NoopTodoEventPublisher struct{}
+
+// Publish implements TodoEventPublisher
+func (NoopTodoEventPublisher) Publish(ctx context.Context, events ...*entity.TodoEvent) {}
+
+// TodoEventSource lets subscribers follow the published todo events
+type TodoEventSource interface {
+	// Subscribe subscribes to the events published from now on, preceded by
+	// the retained events published after the event with ID lastEventID. A
+	// zero lastEventID subscribes to new events only.
+	Subscribe(lastEventID uint) *TodoEventSubscription
+}
+
+// TodoEventSubscription is a subscription to a TodoEventSource
+type TodoEventSubscription struct {
+	// Missed are the retained events published after the last event ID
+	Missed []*entity.TodoEvent
+
+	// Lost is true when events published after the last event ID are no
+	// longer retained, so that Missed is incomplete
+	Lost bool
+
+	// Events receives the events as they are published. It is closed when the
+	// subscriber falls behind, the source is closed or Close is called.
+	Events <-chan *entity.TodoEvent
+
+	// Close ends the subscription
+	Close func()
+}
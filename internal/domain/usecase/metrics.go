@@ -0,0 +1,26 @@
+package usecase
+
+// Reasons reported for failed logins
+const (
+	LoginFailureUnknownUser   = "unknown_user"
+	LoginFailureWrongPassword = "wrong_password"
+)
+
+// Metrics records domain events for monitoring
+type Metrics interface {
+	TodoCreated()
+	TodoCompleted()
+	LoginFailed(reason string)
+}
+
+// NoopMetrics is a Metrics implementation that discards all events
+type NoopMetrics struct{}
+
+// TodoCreated implements Metrics
+func (NoopMetrics) TodoCreated() {}
+
+// TodoCompleted implements Metrics
+func (NoopMetrics) TodoCompleted() {}
+
+// LoginFailed implements Metrics
+func (NoopMetrics) LoginFailed(reason string) {}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+
+	"todo-api/internal/domain/entity"
+)
+
+// Notifier is told about changes that concern other users than the one making them
+type Notifier interface {
+	// TodoAssigned is called after a todo was assigned to assigneeID by userID
+	TodoAssigned(ctx context.Context, todo *entity.Todo, assigneeID, userID uint)
+
+	// TodoUnassigned is called after assigneeID was unassigned from a todo by userID
+	TodoUnassigned(ctx context.Context, todo *entity.Todo, assigneeID, userID uint)
+
+	// Mentioned is called after mentionedID was mentioned in a comment on a todo
+	// by the author of the comment
+	Mentioned(ctx context.Context, todo *entity.Todo, comment *entity.Comment, mentionedID uint)
+}
+
+// NoopNotifier is a Notifier implementation that discards all notifications
+type NoopNotifier struct{}
+
+// TodoAssigned implements Notifier
+func (NoopNotifier) TodoAssigned(ctx context.Context, todo *entity.Todo, assigneeID, userID uint) {}
+
+// TodoUnassigned implements Notifier
+func (NoopNotifier) TodoUnassigned(ctx context.Context, todo *entity.Todo, assigneeID, userID uint) {}
+
+// Mentioned implements Notifier
+func (NoopNotifier) Mentioned(ctx context.Context, todo *entity.Todo, comment *entity.Comment, mentionedID uint) {
+}
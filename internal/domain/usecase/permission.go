@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// PermissionService decides who may access todos, projects and workspaces.
+// Personal todos are only accessible to their creator, shared todos to the
+// members of their project according to their role. Todos and projects are
+// only visible in their workspace, which the repositories enforce.
+type PermissionService interface {
+	// AuthorizeTodo returns ErrNotAuthorized unless the user has the permission on the todo
+	AuthorizeTodo(ctx context.Context, todo *entity.Todo, userID uint, permission entity.Permission) error
+
+	// AuthorizeProject returns the membership of the user in the project, or
+	// ErrProjectNotFound or ErrProjectAccessDenied unless it grants the permission
+	AuthorizeProject(ctx context.Context, projectID, userID uint, permission entity.Permission) (*entity.ProjectMember, error)
+
+	// AuthorizeWorkspace returns the membership of the user in the workspace, or
+	// ErrWorkspaceNotFound or ErrWorkspaceAccessDenied unless it grants the permission
+	AuthorizeWorkspace(ctx context.Context, workspaceID, userID uint, permission entity.Permission) (*entity.WorkspaceMember, error)
+}
+
+// permissionService implements PermissionService
+type permissionService struct {
+	projectRepo   repository.ProjectRepository
+	workspaceRepo repository.WorkspaceRepository
+}
+
+// NewPermissionService creates a new PermissionService
+func NewPermissionService(projectRepo repository.ProjectRepository, workspaceRepo repository.WorkspaceRepository) PermissionService {
+	return &permissionService{
+		projectRepo:   projectRepo,
+		workspaceRepo: workspaceRepo,
+	}
+}
+
+// AuthorizeTodo checks the permission of a user on a todo
+func (s *permissionService) AuthorizeTodo(ctx context.Context, todo *entity.Todo, userID uint, permission entity.Permission) error {
+	if !todo.IsShared() {
+		if !todo.BelongsToUser(userID) {
+			return ErrNotAuthorized
+		}
+		return nil
+	}
+
+	member, err := s.projectRepo.GetMember(ctx, *todo.ProjectID, userID)
+	if err != nil || !member.Role.Allows(permission) {
+		return ErrNotAuthorized
+	}
+	return nil
+}
+
+// AuthorizeProject checks the permission of a user on a project
+func (s *permissionService) AuthorizeProject(ctx context.Context, projectID, userID uint, permission entity.Permission) (*entity.ProjectMember, error) {
+	member, err := s.projectRepo.GetMember(ctx, projectID, userID)
+	if err != nil {
+		if _, err := s.projectRepo.GetByID(ctx, projectID); err != nil {
+			return nil, ErrProjectNotFound
+		}
+		return nil, ErrProjectAccessDenied
+	}
+
+	if !member.Role.Allows(permission) {
+		return nil, ErrProjectAccessDenied
+	}
+	return member, nil
+}
+
+// AuthorizeWorkspace checks the permission of a user on a workspace
+func (s *permissionService) AuthorizeWorkspace(ctx context.Context, workspaceID, userID uint, permission entity.Permission) (*entity.WorkspaceMember, error) {
+	member, err := s.workspaceRepo.GetMember(ctx, workspaceID, userID)
+	if err != nil {
+		// Do not reveal the workspaces of other teams
+		return nil, ErrWorkspaceNotFound
+	}
+
+	if !member.Role.Allows(permission) {
+		return nil, ErrWorkspaceAccessDenied
+	}
+	return member, nil
+}
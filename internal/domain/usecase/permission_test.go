@@ -0,0 +1,65 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+)
+
+func TestAuthorizeTodo(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	alice, bob, carol, dave := f.user(t, "alice"), f.user(t, "bob"), f.user(t, "carol"), f.user(t, "dave")
+	project := f.project(t, alice.ID, map[uint]entity.ProjectRole{bob.ID: entity.ProjectRoleEditor, carol.ID: entity.ProjectRoleViewer})
+	personal := f.todo(t, "personal", nil, alice.ID)
+	shared := f.todo(t, "shared", &project.ID, alice.ID)
+
+	tests := []struct {
+		name       string
+		todo       *entity.Todo
+		userID     uint
+		permission entity.Permission
+		allowed    bool
+	}{
+		{"creator edits personal todo", personal, alice.ID, entity.PermissionEdit, true},
+		{"project member views personal todo", personal, bob.ID, entity.PermissionView, false},
+		{"editor edits shared todo", shared, bob.ID, entity.PermissionEdit, true},
+		{"viewer views shared todo", shared, carol.ID, entity.PermissionView, true},
+		{"viewer edits shared todo", shared, carol.ID, entity.PermissionEdit, false},
+		{"non-member views shared todo", shared, dave.ID, entity.PermissionView, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.permissions.AuthorizeTodo(ctx, tt.todo, tt.userID, tt.permission)
+			if tt.allowed && err != nil {
+				t.Errorf("got error %v, want allowed", err)
+			}
+			if !tt.allowed && !errors.Is(err, usecase.ErrNotAuthorized) {
+				t.Errorf("got error %v, want %v", err, usecase.ErrNotAuthorized)
+			}
+		})
+	}
+}
+
+func TestAuthorizeProject(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	alice, bob, carol := f.user(t, "alice"), f.user(t, "bob"), f.user(t, "carol")
+	project := f.project(t, alice.ID, map[uint]entity.ProjectRole{bob.ID: entity.ProjectRoleViewer})
+
+	if member, err := f.permissions.AuthorizeProject(ctx, project.ID, alice.ID, entity.PermissionManage); err != nil || member.Role != entity.ProjectRoleOwner {
+		t.Errorf("owner: got %v, %v, want the owner membership", member, err)
+	}
+	if _, err := f.permissions.AuthorizeProject(ctx, project.ID, bob.ID, entity.PermissionEdit); !errors.Is(err, usecase.ErrProjectAccessDenied) {
+		t.Errorf("viewer: got error %v, want %v", err, usecase.ErrProjectAccessDenied)
+	}
+	if _, err := f.permissions.AuthorizeProject(ctx, project.ID, carol.ID, entity.PermissionView); !errors.Is(err, usecase.ErrProjectAccessDenied) {
+		t.Errorf("non-member: got error %v, want %v", err, usecase.ErrProjectAccessDenied)
+	}
+	if _, err := f.permissions.AuthorizeProject(ctx, project.ID+1, alice.ID, entity.PermissionView); !errors.Is(err, usecase.ErrProjectNotFound) {
+		t.Errorf("missing project: got error %v, want %v", err, usecase.ErrProjectNotFound)
+	}
+}
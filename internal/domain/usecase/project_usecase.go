@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/util/logging"
+)
+
+// Errors related to project operations
+var (
+	ErrProjectNotFound         = newError(KindNotFound, "project_not_found", "project not found")
+	ErrProjectAccessDenied     = newError(KindForbidden, "project_access_denied", "not authorized to perform this action on the project")
+	ErrInvalidProjectData      = newError(KindInvalid, "invalid_project_data", "invalid project data")
+	ErrInvalidProjectRole      = newError(KindInvalid, "invalid_project_role", "role must be viewer, editor or owner")
+	ErrMemberNotFound          = newError(KindNotFound, "project_member_not_found", "project member not found")
+	ErrMemberExists            = newError(KindConflict, "project_member_exists", "the user is already a member of the project")
+	ErrMemberLimitReached      = newError(KindConflict, "project_member_limit_reached", "the maximum number of project members has been reached")
+	ErrMemberOutsideWorkspace  = newError(KindInvalid, "project_member_outside_workspace", "projects can only be shared with members of their workspace")
+	ErrLastProjectOwner        = newError(KindConflict, "project_last_owner", "a project must keep at least one owner")
+	ErrProjectCreateFailed     = newError(KindInternal, "project_create_failed", "failed to create project")
+	ErrProjectUpdateFailed     = newError(KindInternal, "project_update_failed", "failed to update project")
+	ErrProjectDeleteFailed     = newError(KindInternal, "project_delete_failed", "failed to delete project")
+	ErrProjectMembershipFailed = newError(KindInternal, "project_membership_failed", "failed to change project members")
+)
+
+// Project limits
+const (
+	MaxProjectNameLength = 100
+	MaxProjectMembers    = 100
+)
+
+// ProjectUseCase defines the interface for project use cases. Projects are
+// returned as the membership of the acting user, with the project loaded.
+type ProjectUseCase interface {
+	CreateProject(ctx context.Context, name string, userID uint) (*entity.ProjectMember, error)
+	GetProject(ctx context.Context, id, userID uint) (*entity.ProjectMember, error)
+	GetUserProjects(ctx context.Context, userID uint) ([]*entity.ProjectMember, error)
+	RenameProject(ctx context.Context, id uint, name string, userID uint) (*entity.ProjectMember, error)
+	DeleteProject(ctx context.Context, id, userID uint) error
+	GetMembers(ctx context.Context, projectID, userID uint) ([]*entity.ProjectMember, error)
+	AddMember(ctx context.Context, projectID uint, email string, role entity.ProjectRole, userID uint) (*entity.ProjectMember, error)
+	ChangeMemberRole(ctx context.Context, projectID, memberID uint, role entity.ProjectRole, userID uint) (*entity.ProjectMember, error)
+	RemoveMember(ctx context.Context, projectID, memberID, userID uint) error
+}
+
+// projectUseCase implements ProjectUseCase
+type projectUseCase struct {
+	projectRepo repository.ProjectRepository
+	userRepo    repository.UserRepository
+	permissions PermissionService
+}
+
+// NewProjectUseCase creates a new ProjectUseCase
+func NewProjectUseCase(projectRepo repository.ProjectRepository, userRepo repository.UserRepository, permissions PermissionService) ProjectUseCase {
+	return &projectUseCase{
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+		permissions: permissions,
+	}
+}
+
+// CreateProject creates a new project owned by the user
+func (uc *projectUseCase) CreateProject(ctx context.Context, name string, userID uint) (*entity.ProjectMember, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > MaxProjectNameLength {
+		return nil, ErrInvalidProjectData
+	}
+
+	project := entity.NewProject(name)
+	owner := entity.NewProjectMember(0, userID, entity.ProjectRoleOwner)
+	if err := uc.projectRepo.Create(ctx, project, owner); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to store project")
+		return nil, ErrProjectCreateFailed
+	}
+	logging.FromContext(ctx).WithField("project_id", project.ID).Info("Project created")
+
+	owner.Project = *project
+	return owner, nil
+}
+
+// GetProject retrieves a project the user is a member of
+func (uc *projectUseCase) GetProject(ctx context.Context, id, userID uint) (*entity.ProjectMember, error) {
+	return uc.authorize(ctx, id, userID, entity.PermissionView)
+}
+
+// GetUserProjects retrieves the projects the user is a member of, ordered by name
+func (uc *projectUseCase) GetUserProjects(ctx context.Context, userID uint) ([]*entity.ProjectMember, error) {
+	memberships, err := uc.projectRepo.GetMemberships(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to list projects")
+		return nil, err
+	}
+	return memberships, nil
+}
+
+// RenameProject renames a project, which requires the owner role
+func (uc *projectUseCase) RenameProject(ctx context.Context, id uint, name string, userID uint) (*entity.ProjectMember, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > MaxProjectNameLength {
+		return nil, ErrInvalidProjectData
+	}
+
+	membership, err := uc.authorize(ctx, id, userID, entity.PermissionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	membership.Project.Rename(name)
+	if err := uc.projectRepo.Update(ctx, &membership.Project); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("project_id", id).Error("Failed to store project update")
+		return nil, ErrProjectUpdateFailed
+	}
+
+	return membership, nil
+}
+
+// DeleteProject deletes a project with its todos, which requires the owner role
+func (uc *projectUseCase) DeleteProject(ctx context.Context, id, userID uint) error {
+	if _, err := uc.permissions.AuthorizeProject(ctx, id, userID, entity.PermissionManage); err != nil {
+		return err
+	}
+
+	if err := uc.projectRepo.Delete(ctx, id); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("project_id", id).Error("Failed to delete project")
+		return ErrProjectDeleteFailed
+	}
+	logging.FromContext(ctx).WithField("project_id", id).Info("Project deleted")
+
+	return nil
+}
+
+// GetMembers retrieves the members of a project with their users loaded
+func (uc *projectUseCase) GetMembers(ctx context.Context, projectID, userID uint) ([]*entity.ProjectMember, error) {
+	if _, err := uc.permissions.AuthorizeProject(ctx, projectID, userID, entity.PermissionView); err != nil {
+		return nil, err
+	}
+
+	members, err := uc.projectRepo.GetMembers(ctx, projectID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("project_id", projectID).Error("Failed to list project members")
+		return nil, err
+	}
+	for _, member := range members {
+		if err := uc.loadUser(ctx, member); err != nil {
+			return nil, err
+		}
+	}
+	return members, nil
+}
+
+// AddMember shares a project with the user registered with email, which
+// requires the owner role. Projects in a workspace can only be shared with its
+// members.
+func (uc *projectUseCase) AddMember(ctx context.Context, projectID uint, email string, role entity.ProjectRole, userID uint) (*entity.ProjectMember, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidProjectRole
+	}
+	if _, err := uc.permissions.AuthorizeProject(ctx, projectID, userID, entity.PermissionManage); err != nil {
+		return nil, err
+	}
+
+	invitee, err := uc.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if workspaceID, ok := repository.WorkspaceFromContext(ctx); ok {
+		if _, err := uc.permissions.AuthorizeWorkspace(ctx, workspaceID, invitee.ID, entity.PermissionView); err != nil {
+			return nil, ErrMemberOutsideWorkspace
+		}
+	}
+
+	members, err := uc.projectRepo.GetMembers(ctx, projectID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("project_id", projectID).Error("Failed to list project members")
+		return nil, ErrProjectMembershipFailed
+	}
+	for _, member := range members {
+		if member.UserID == invitee.ID {
+			return nil, ErrMemberExists
+		}
+	}
+	if len(members) >= MaxProjectMembers {
+		return nil, ErrMemberLimitReached
+	}
+
+	member := entity.NewProjectMember(projectID, invitee.ID, role)
+	if err := uc.projectRepo.AddMember(ctx, member); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("project_id", projectID).Error("Failed to store project member")
+		return nil, ErrProjectMembershipFailed
+	}
+	member.User = *invitee
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"project_id": projectID,
+		"member_id":  invitee.ID,
+		"role":       role,
+	}).Info("Project member added")
+
+	return member, nil
+}
+
+// ChangeMemberRole changes the role of a member, which requires the owner
+// role. The last owner cannot be demoted.
+func (uc *projectUseCase) ChangeMemberRole(ctx context.Context, projectID, memberID uint, role entity.ProjectRole, userID uint) (*entity.ProjectMember, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidProjectRole
+	}
+	if _, err := uc.permissions.AuthorizeProject(ctx, projectID, userID, entity.PermissionManage); err != nil {
+		return nil, err
+	}
+
+	member, err := uc.projectRepo.GetMember(ctx, projectID, memberID)
+	if err != nil {
+		return nil, ErrMemberNotFound
+	}
+	if member.Role == entity.ProjectRoleOwner && role != entity.ProjectRoleOwner {
+		if err := uc.checkOtherOwner(ctx, projectID, memberID); err != nil {
+			return nil, err
+		}
+	}
+
+	member.ChangeRole(role)
+	if err := uc.projectRepo.UpdateMember(ctx, member); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("project_id", projectID).Error("Failed to store project member")
+		return nil, ErrProjectMembershipFailed
+	}
+	if err := uc.loadUser(ctx, member); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// RemoveMember removes a member from a project. Owners may remove anyone,
+// other members only themselves. The last owner cannot be removed.
+func (uc *projectUseCase) RemoveMember(ctx context.Context, projectID, memberID, userID uint) error {
+	permission := entity.PermissionManage
+	if memberID == userID {
+		permission = entity.PermissionView
+	}
+	if _, err := uc.permissions.AuthorizeProject(ctx, projectID, userID, permission); err != nil {
+		return err
+	}
+
+	member, err := uc.projectRepo.GetMember(ctx, projectID, memberID)
+	if err != nil {
+		return ErrMemberNotFound
+	}
+	if member.Role == entity.ProjectRoleOwner {
+		if err := uc.checkOtherOwner(ctx, projectID, memberID); err != nil {
+			return err
+		}
+	}
+
+	if err := uc.projectRepo.RemoveMember(ctx, projectID, memberID); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("project_id", projectID).Error("Failed to remove project member")
+		return ErrProjectMembershipFailed
+	}
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"project_id": projectID,
+		"member_id":  memberID,
+	}).Info("Project member removed")
+
+	return nil
+}
+
+// authorize checks the permission of the user on a project and loads the project
+func (uc *projectUseCase) authorize(ctx context.Context, id, userID uint, permission entity.Permission) (*entity.ProjectMember, error) {
+	membership, err := uc.permissions.AuthorizeProject(ctx, id, userID, permission)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := uc.projectRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrProjectNotFound
+	}
+	membership.Project = *project
+
+	return membership, nil
+}
+
+// checkOtherOwner returns ErrLastProjectOwner unless another member than exceptID owns the project
+func (uc *projectUseCase) checkOtherOwner(ctx context.Context, projectID, exceptID uint) error {
+	members, err := uc.projectRepo.GetMembers(ctx, projectID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("project_id", projectID).Error("Failed to list project members")
+		return ErrProjectMembershipFailed
+	}
+	for _, member := range members {
+		if member.UserID != exceptID && member.Role == entity.ProjectRoleOwner {
+			return nil
+		}
+	}
+	return ErrLastProjectOwner
+}
+
+// loadUser loads the user of a member
+func (uc *projectUseCase) loadUser(ctx context.Context, member *entity.ProjectMember) error {
+	user, err := uc.userRepo.GetByID(ctx, member.UserID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("user_id", member.UserID).Error("Failed to load project member")
+		return ErrProjectMembershipFailed
+	}
+	member.User = *user
+	return nil
+}
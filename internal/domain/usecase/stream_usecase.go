@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// TodoStream is a subscription to the events of the todos a user can view
+type TodoStream struct {
+	// Events receives the events, starting with those missed since the last
+	// event ID. It is closed when the subscription ends, after which the
+	// client should reconnect with the ID of the last event it received.
+	Events <-chan *entity.TodoEvent
+
+	// Reset is true when events since the last event ID were lost, so that
+	// the client must reload its todos
+	Reset bool
+}
+
+// StreamUseCase defines the interface for real-time todo event use cases
+type StreamUseCase interface {
+	// Subscribe subscribes a user to the events of the todos they can view in
+	// the workspace of ctx, until ctx is done
+	Subscribe(ctx context.Context, userID, lastEventID uint) *TodoStream
+}
+
+// streamUseCase implements StreamUseCase
+type streamUseCase struct {
+	source      TodoEventSource
+	permissions PermissionService
+}
+
+// NewStreamUseCase creates a new StreamUseCase
+func NewStreamUseCase(source TodoEventSource, permissions PermissionService) StreamUseCase {
+	return &streamUseCase{
+		source:      source,
+		permissions: permissions,
+	}
+}
+
+// Subscribe implements StreamUseCase
+func (uc *streamUseCase) Subscribe(ctx context.Context, userID, lastEventID uint) *TodoStream {
+	subscription := uc.source.Subscribe(lastEventID)
+	events := make(chan *entity.TodoEvent)
+
+	// Forward the visible events until the subscription or ctx ends
+	go func() {
+		defer close(events)
+		defer subscription.Close()
+
+		for _, event := range subscription.Missed {
+			if !uc.forward(ctx, events, event, userID) {
+				return
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-subscription.Events:
+				if !ok || !uc.forward(ctx, events, event, userID) {
+					return
+				}
+			}
+		}
+	}()
+
+	return &TodoStream{
+		Events: events,
+		Reset:  subscription.Lost,
+	}
+}
+
+// forward sends an event to the stream if the user can view it, it reports
+// false once ctx is done
+func (uc *streamUseCase) forward(ctx context.Context, events chan<- *entity.TodoEvent, event *entity.TodoEvent, userID uint) bool {
+	if !uc.visible(ctx, event, userID) {
+		return true
+	}
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// visible reports whether the todo of an event is in the workspace of ctx and
+// can be viewed by the user
+func (uc *streamUseCase) visible(ctx context.Context, event *entity.TodoEvent, userID uint) bool {
+	workspaceID := repository.WorkspaceIDFromContext(ctx)
+	if (workspaceID == nil) != (event.Todo.WorkspaceID == nil) {
+		return false
+	}
+	if workspaceID != nil && *workspaceID != *event.Todo.WorkspaceID {
+		return false
+	}
+	return uc.permissions.AuthorizeTodo(ctx, &event.Todo, userID, entity.PermissionView) == nil
+}
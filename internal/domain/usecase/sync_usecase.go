@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/util/logging"
+)
+
+// Errors related to delta sync
+var (
+	ErrInvalidSyncOp        = newError(KindInvalid, "invalid_sync_op", "op must be create, update or delete")
+	ErrSyncTooManyMutations = newError(KindInvalid, "sync_too_many_mutations", "a sync can apply at most 100 mutations")
+	ErrSyncFailed           = newError(KindInternal, "sync_failed", "failed to load the changes")
+)
+
+// Delta sync limits
+const (
+	DefaultSyncPageSize = 100
+	MaxSyncPageSize     = 500
+	MaxSyncMutations    = 100
+)
+
+// SyncPage is a page of the changes of the todos a user can view, in the
+// order of their positions
+type SyncPage struct {
+	// Todos are the todos written since the token, in their current state
+	Todos []*entity.Todo
+
+	// Tombstones record the todos deleted since the token. A full sync leaves
+	// them out, the client holds none of the todos they record.
+	Tombstones []*entity.TodoTombstone
+
+	// Token is the position after the page, to send with the next sync
+	Token entity.SyncToken
+
+	// HasMore is true when changes remain after Token
+	HasMore bool
+
+	// Reset is true when the changes since the token were incomplete, so that
+	// the page starts a full sync. The client replaces its todos with those
+	// of the full sync.
+	Reset bool
+}
+
+// SyncOp is the kind of a mutation made by an offline client
+type SyncOp string
+
+// Sync operations
+const (
+	SyncCreate SyncOp = "create"
+	SyncUpdate SyncOp = "update"
+	SyncDelete SyncOp = "delete"
+)
+
+// SyncMutation is a change of a todo made by an offline client
+type SyncMutation struct {
+	Op SyncOp
+
+	// ID and ChangeSeq identify the todo and the change the client last
+	// received of it, for updates and deletions
+	ID        uint
+	ChangeSeq uint64
+
+	Title       string
+	Description string
+	Completed   bool
+
+	// ProjectID is the project of a created todo, nil for a personal todo
+	ProjectID *uint
+}
+
+// SyncMutationResult reports the outcome of a mutation. Err is nil on success,
+// ErrTodoChanged on a conflict, otherwise a *Error such as ErrTodoNotFound.
+type SyncMutationResult struct {
+	// Todo is the todo after the mutation or, on a conflict, the current todo,
+	// nil when it was deleted
+	Todo *entity.Todo
+
+	Err error
+}
+
+// SyncUseCase defines the interface for delta sync use cases
+type SyncUseCase interface {
+	// GetChanges retrieves a page of the changes after the token of the todos
+	// the user can view in the workspace of ctx, a zero token starts a full sync
+	GetChanges(ctx context.Context, token entity.SyncToken, pageSize int, userID uint) (*SyncPage, error)
+
+	// ApplyMutations applies the mutations of an offline client in order.
+	// Each applies on its own, a mutation of a todo changed since the client
+	// last received it is rejected as a conflict.
+	ApplyMutations(ctx context.Context, mutations []SyncMutation, userID uint) ([]SyncMutationResult, error)
+}
+
+// syncUseCase implements SyncUseCase
+type syncUseCase struct {
+	todoRepo    repository.TodoRepository
+	projectRepo repository.ProjectRepository
+	todos       TodoUseCase
+}
+
+// NewSyncUseCase creates a new SyncUseCase, which applies mutations through todos
+func NewSyncUseCase(todoRepo repository.TodoRepository, projectRepo repository.ProjectRepository, todos TodoUseCase) SyncUseCase {
+	return &syncUseCase{
+		todoRepo:    todoRepo,
+		projectRepo: projectRepo,
+		todos:       todos,
+	}
+}
+
+// GetChanges retrieves a page of the changes after the token. The changes of
+// the todos of the projects the user joined since the token are older than
+// it, as are those of the projects they left, so a token from another scope
+// starts a full sync.
+func (uc *syncUseCase) GetChanges(ctx context.Context, token entity.SyncToken, pageSize int, userID uint) (*SyncPage, error) {
+	if pageSize <= 0 {
+		pageSize = DefaultSyncPageSize
+	}
+	if pageSize > MaxSyncPageSize {
+		pageSize = MaxSyncPageSize
+	}
+
+	memberships, err := uc.projectRepo.GetMemberships(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to load project memberships for sync")
+		return nil, ErrSyncFailed
+	}
+	projectIDs := make([]uint, len(memberships))
+	for i, membership := range memberships {
+		projectIDs[i] = membership.ProjectID
+	}
+	scope := syncScope(ctx, userID, projectIDs)
+
+	filter := entity.TodoChangeFilter{
+		UserID:     userID,
+		ProjectIDs: projectIDs,
+		Since:      token.Position,
+		Limit:      pageSize + 1,
+	}
+	reset := !token.IsZero() && token.Scope != scope
+	if reset {
+		filter.Since = entity.ChangePosition{}
+	}
+	changes, err := uc.todoRepo.GetChanges(ctx, filter)
+	if err == nil && changes.Until.Before(filter.Since) {
+		// The token was issued by a database that was since replaced
+		reset, filter.Since = true, entity.ChangePosition{}
+		changes, err = uc.todoRepo.GetChanges(ctx, filter)
+	}
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to load todo changes")
+		return nil, ErrSyncFailed
+	}
+	if filter.Since == (entity.ChangePosition{}) {
+		changes.Tombstones = nil
+	}
+
+	page := mergeChanges(changes, pageSize)
+	page.Token.Scope = scope
+	page.Reset = reset
+	return page, nil
+}
+
+// mergeChanges merges up to pageSize of the todos and tombstones in the order
+// of their positions. Each list holds the first changes after the same
+// position, so the page ends before the first change left out of either.
+func mergeChanges(changes *entity.TodoChanges, pageSize int) *SyncPage {
+	page := &SyncPage{
+		Todos:      make([]*entity.Todo, 0),
+		Tombstones: make([]*entity.TodoTombstone, 0),
+	}
+	todos, tombstones := changes.Todos, changes.Tombstones
+	for len(page.Todos)+len(page.Tombstones) < pageSize && (len(todos) > 0 || len(tombstones) > 0) {
+		if len(tombstones) == 0 || (len(todos) > 0 && todos[0].ChangePosition().Before(tombstones[0].ChangePosition())) {
+			page.Todos = append(page.Todos, todos[0])
+			page.Token.Position = todos[0].ChangePosition()
+			todos = todos[1:]
+		} else {
+			page.Tombstones = append(page.Tombstones, tombstones[0])
+			page.Token.Position = tombstones[0].ChangePosition()
+			tombstones = tombstones[1:]
+		}
+	}
+
+	page.HasMore = len(todos) > 0 || len(tombstones) > 0
+	if !page.HasMore {
+		page.Token.Position = changes.Until
+	}
+	return page
+}
+
+// syncScope identifies the user, the workspace of ctx and the projects whose
+// todos a delta sync selects
+func syncScope(ctx context.Context, userID uint, projectIDs []uint) uint64 {
+	sorted := append([]uint(nil), projectIDs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "user:%d;", userID)
+	if workspaceID, ok := repository.WorkspaceFromContext(ctx); ok {
+		fmt.Fprintf(hash, "workspace:%d;", workspaceID)
+	}
+	for _, projectID := range sorted {
+		fmt.Fprintf(hash, "project:%d;", projectID)
+	}
+	return hash.Sum64()
+}
+
+// ApplyMutations applies the mutations of an offline client in order
+func (uc *syncUseCase) ApplyMutations(ctx context.Context, mutations []SyncMutation, userID uint) ([]SyncMutationResult, error) {
+	if len(mutations) > MaxSyncMutations {
+		return nil, ErrSyncTooManyMutations
+	}
+	for _, mutation := range mutations {
+		switch mutation.Op {
+		case SyncCreate, SyncUpdate, SyncDelete:
+		default:
+			return nil, ErrInvalidSyncOp
+		}
+	}
+
+	results := make([]SyncMutationResult, len(mutations))
+	for i, mutation := range mutations {
+		results[i] = uc.apply(ctx, mutation, userID)
+	}
+	return results, nil
+}
+
+// apply applies a single mutation. On a conflict the result holds the current
+// todo, so that the client can resolve it.
+func (uc *syncUseCase) apply(ctx context.Context, mutation SyncMutation, userID uint) SyncMutationResult {
+	var todo *entity.Todo
+	var err error
+	switch mutation.Op {
+	case SyncCreate:
+		todo, err = uc.todos.CreateTodo(ctx, mutation.Title, mutation.Description, mutation.ProjectID, nil, userID)
+		if err == nil && mutation.Completed {
+			// Keep the created todo if it cannot be completed, so that the
+			// client does not create it again
+			completed, completeErr := uc.todos.CompleteTodo(ctx, todo.ID, userID)
+			if completeErr != nil {
+				return SyncMutationResult{Todo: todo, Err: completeErr}
+			}
+			todo = completed
+		}
+	case SyncUpdate:
+		todo, err = uc.todos.UpdateTodoIfUnchanged(ctx, mutation.ID, mutation.ChangeSeq, mutation.Title, mutation.Description, mutation.Completed, userID)
+	case SyncDelete:
+		err = uc.todos.DeleteTodoIfUnchanged(ctx, mutation.ID, mutation.ChangeSeq, userID)
+	}
+
+	if errors.Is(err, ErrTodoChanged) {
+		// The todo may have been deleted since
+		todo, _ = uc.todos.GetTodoByID(ctx, mutation.ID, userID)
+	}
+	return SyncMutationResult{Todo: todo, Err: err}
+}
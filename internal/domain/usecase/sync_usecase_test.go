@@ -0,0 +1,97 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+)
+
+func TestSyncGetChanges(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	sync := usecase.NewSyncUseCase(f.todoRepo, f.projectRepo, f.todos)
+	alice, bob := f.user(t, "alice"), f.user(t, "bob")
+	project := f.project(t, bob.ID, map[uint]entity.ProjectRole{alice.ID: entity.ProjectRoleViewer})
+	own := f.todo(t, "own", nil, alice.ID)
+	shared := f.todo(t, "shared", &project.ID, bob.ID)
+	f.todo(t, "other", nil, bob.ID)
+
+	// A full sync pages through the todos the user can view
+	first, err := sync.GetChanges(ctx, entity.SyncToken{}, 1, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Todos) != 1 || first.Todos[0].ID != own.ID || !first.HasMore {
+		t.Fatalf("first page: got %d todos, has more %t, want the own todo and more", len(first.Todos), first.HasMore)
+	}
+	second, err := sync.GetChanges(ctx, first.Token, 1, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Todos) != 1 || second.Todos[0].ID != shared.ID || second.HasMore {
+		t.Fatalf("second page: got %d todos, has more %t, want the shared todo only", len(second.Todos), second.HasMore)
+	}
+
+	// A delta sync returns the deletions as tombstones
+	if err := f.todos.DeleteTodo(ctx, own.ID, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	delta, err := sync.GetChanges(ctx, second.Token, 10, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delta.Todos) != 0 || len(delta.Tombstones) != 1 || delta.Tombstones[0].TodoID != own.ID || delta.Reset {
+		t.Fatalf("delta: got %d todos and %d tombstones, reset %t, want the tombstone of the own todo", len(delta.Todos), len(delta.Tombstones), delta.Reset)
+	}
+
+	// Leaving a project changes the scope, which starts a full sync
+	if err := f.projectRepo.RemoveMember(ctx, project.ID, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	reset, err := sync.GetChanges(ctx, delta.Token, 10, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reset.Reset || len(reset.Todos) != 0 || len(reset.Tombstones) != 0 {
+		t.Fatalf("after leaving the project: got reset %t with %d todos and %d tombstones, want an empty full sync", reset.Reset, len(reset.Todos), len(reset.Tombstones))
+	}
+}
+
+func TestSyncApplyMutations(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	sync := usecase.NewSyncUseCase(f.todoRepo, f.projectRepo, f.todos)
+	alice := f.user(t, "alice")
+	todo := f.todo(t, "todo", nil, alice.ID)
+	stale := todo.ChangeSeq
+	if _, err := f.todos.UpdateTodo(ctx, todo.ID, "changed online", "", false, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := sync.ApplyMutations(ctx, []usecase.SyncMutation{
+		{Op: usecase.SyncCreate, Title: "created offline", Completed: true},
+		{Op: usecase.SyncUpdate, ID: todo.ID, ChangeSeq: stale, Title: "changed offline"},
+		{Op: usecase.SyncDelete, ID: 999, ChangeSeq: 1},
+	}, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created := results[0]; created.Err != nil || created.Todo == nil || !created.Todo.Completed {
+		t.Errorf("create: got %+v, want a completed todo", created)
+	}
+	if conflict := results[1]; !errors.Is(conflict.Err, usecase.ErrTodoChanged) || conflict.Todo == nil || conflict.Todo.Title != "changed online" {
+		t.Errorf("update: got %+v, want a conflict with the current todo", conflict)
+	}
+	if missing := results[2]; !errors.Is(missing.Err, usecase.ErrTodoNotFound) {
+		t.Errorf("delete: got error %v, want %v", missing.Err, usecase.ErrTodoNotFound)
+	}
+
+	_, err = sync.ApplyMutations(ctx, []usecase.SyncMutation{{Op: "move"}}, alice.ID)
+	if !errors.Is(err, usecase.ErrInvalidSyncOp) {
+		t.Errorf("unknown op: got error %v, want %v", err, usecase.ErrInvalidSyncOp)
+	}
+}
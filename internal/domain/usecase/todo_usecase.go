@@ -7,50 +7,123 @@ import (
 
 	"todo-api/internal/domain/entity"
 	"todo-api/internal/domain/repository"
+	"todo-api/internal/util/logging"
 )
 
 // Errors related to todo operations
 var (
-	ErrTodoNotFound     = errors.New("todo not found")
-	ErrNotAuthorized    = errors.New("not authorized to access this todo")
-	ErrInvalidTodoData  = errors.New("invalid todo data")
-	ErrTodoCreateFailed = errors.New("failed to create todo")
-	ErrTodoUpdateFailed = errors.New("failed to update todo")
-	ErrTodoDeleteFailed = errors.New("failed to delete todo")
+	ErrTodoNotFound     = newError(KindNotFound, "todo_not_found", "todo not found")
+	ErrNotAuthorized    = newError(KindForbidden, "todo_access_denied", "not authorized to access this todo")
+	ErrInvalidTodoData  = newError(KindInvalid, "invalid_todo_data", "invalid todo data")
+	ErrTodoCreateFailed = newError(KindInternal, "todo_create_failed", "failed to create todo")
+	ErrTodoUpdateFailed = newError(KindInternal, "todo_update_failed", "failed to update todo")
+	ErrTodoDeleteFailed = newError(KindInternal, "todo_delete_failed", "failed to delete todo")
+	ErrTodoChanged      = newError(KindConflict, "todo_changed", "the todo was changed since it was read")
+
+	ErrInvalidSearchQuery = newError(KindInvalid, "invalid_search_query", "search query must contain a word that is not excluded")
+	ErrSearchCursor       = newError(KindInvalid, "search_cursor_unsupported", "full-text search results cannot be paginated by cursor")
+	ErrInvalidSort        = newError(KindInvalid, "invalid_sort", "sort must be one of created_desc, created_asc, updated_desc or title_asc")
+	ErrSortCursor         = newError(KindInvalid, "sort_cursor_unsupported", "only todos sorted by created_desc can be paginated by cursor")
+
+	ErrInvalidAssignee = newError(KindInvalid, "invalid_assignee", "todos can only be assigned to users who can access them")
+)
+
+// Pagination limits
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
 )
 
+// TodoPage is a page of todos
+type TodoPage struct {
+	Todos    []*entity.Todo
+	PageSize int
+
+	// Total counts all matching todos, it is only computed for offset pagination
+	Total int64
+
+	// Next and Prev select the adjacent pages for keyset pagination, nil when there are none
+	Next *entity.TodoCursor
+	Prev *entity.TodoCursor
+
+	// Matches holds the ranks and highlights of a full-text search, in the order of Todos
+	Matches []*entity.TodoMatch
+}
+
 // TodoUseCase defines the interface for todo use cases
 type TodoUseCase interface {
-	CreateTodo(ctx context.Context, title, description string, userID uint) (*entity.Todo, error)
+	CreateTodo(ctx context.Context, title, description string, projectID, assigneeID *uint, userID uint) (*entity.Todo, error)
 	GetTodoByID(ctx context.Context, id, userID uint) (*entity.Todo, error)
-	GetUserTodos(ctx context.Context, userID uint, filter entity.TodoFilter) ([]*entity.Todo, int64, error)
+	GetUserTodos(ctx context.Context, userID uint, filter entity.TodoFilter) (*TodoPage, error)
 	UpdateTodo(ctx context.Context, id uint, title, description string, completed bool, userID uint) (*entity.Todo, error)
 	DeleteTodo(ctx context.Context, id, userID uint) error
+
+	// UpdateTodoIfUnchanged and DeleteTodoIfUnchanged update or delete a todo
+	// unless it was changed since its change sequence number was changeSeq,
+	// in which case they return ErrTodoChanged
+	UpdateTodoIfUnchanged(ctx context.Context, id uint, changeSeq uint64, title, description string, completed bool, userID uint) (*entity.Todo, error)
+	DeleteTodoIfUnchanged(ctx context.Context, id uint, changeSeq uint64, userID uint) error
+
 	CompleteTodo(ctx context.Context, id, userID uint) (*entity.Todo, error)
+	AssignTodo(ctx context.Context, id uint, assigneeID *uint, userID uint) (*entity.Todo, error)
+	BulkUpdate(ctx context.Context, op BulkOperation, target BulkTarget, userID uint) (*BulkResult, error)
 }
 
 // todoUseCase implements TodoUseCase
 type todoUseCase struct {
-	todoRepo repository.TodoRepository
+	todoRepo    repository.TodoRepository
+	permissions PermissionService
+	metrics     Metrics
+	notifier    Notifier
+	events      TodoEventPublisher
 }
 
-// NewTodoUseCase creates a new TodoUseCase
-func NewTodoUseCase(todoRepo repository.TodoRepository) TodoUseCase {
+// NewTodoUseCase creates a new TodoUseCase, which records the activity of every
+// change of a todo together with the change and then publishes it to events
+func NewTodoUseCase(todoRepo repository.TodoRepository, permissions PermissionService, metrics Metrics, notifier Notifier, events TodoEventPublisher) TodoUseCase {
 	return &todoUseCase{
-		todoRepo: todoRepo,
+		todoRepo:    todoRepo,
+		permissions: permissions,
+		metrics:     metrics,
+		notifier:    notifier,
+		events:      events,
 	}
 }
 
-// CreateTodo creates a new todo
-func (uc *todoUseCase) CreateTodo(ctx context.Context, title, description string, userID uint) (*entity.Todo, error) {
+// CreateTodo creates a new todo, a personal one unless projectID is set, which
+// requires the editor role in the project. The todo is assigned to assigneeID
+// when it is set.
+func (uc *todoUseCase) CreateTodo(ctx context.Context, title, description string, projectID, assigneeID *uint, userID uint) (*entity.Todo, error) {
 	if title == "" {
 		return nil, ErrInvalidTodoData
 	}
 
+	if projectID != nil {
+		if _, err := uc.permissions.AuthorizeProject(ctx, *projectID, userID, entity.PermissionEdit); err != nil {
+			return nil, err
+		}
+	}
+
 	todo := entity.NewTodo(title, description, userID)
-	if err := uc.todoRepo.Create(ctx, todo); err != nil {
+	todo.ProjectID = projectID
+	if assigneeID != nil {
+		if err := uc.checkAssignee(ctx, todo, *assigneeID); err != nil {
+			return nil, err
+		}
+		todo.AssigneeID = assigneeID
+	}
+
+	activities := changeActivities(nil, todo, entity.ActivityCreated, userID)
+	if err := uc.todoRepo.Create(ctx, todo, activities...); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to store todo")
 		return nil, ErrTodoCreateFailed
 	}
+	uc.metrics.TodoCreated()
+	logging.FromContext(ctx).WithField("todo_id", todo.ID).Info("Todo created")
+	uc.publish(ctx, todo, activities...)
+	if assigneeID != nil {
+		uc.notifier.TodoAssigned(ctx, todo, *assigneeID, userID)
+	}
 
 	return todo, nil
 }
@@ -62,40 +135,170 @@ func (uc *todoUseCase) GetTodoByID(ctx context.Context, id, userID uint) (*entit
 		return nil, ErrTodoNotFound
 	}
 
-	if !todo.BelongsToUser(userID) {
-		return nil, ErrNotAuthorized
+	if err := uc.permissions.AuthorizeTodo(ctx, todo, userID, entity.PermissionView); err != nil {
+		return nil, err
 	}
 
 	return todo, nil
 }
 
-// GetUserTodos retrieves todos for a specific user
-func (uc *todoUseCase) GetUserTodos(ctx context.Context, userID uint, filter entity.TodoFilter) ([]*entity.Todo, int64, error) {
+// GetUserTodos retrieves a page of the personal todos of a user, or of the
+// todos of filter.ProjectID when the user is a member of the project. Only the
+// todos assigned to the user can be selected by filter.AssigneeID.
+func (uc *todoUseCase) GetUserTodos(ctx context.Context, userID uint, filter entity.TodoFilter) (*TodoPage, error) {
 	filter.UserID = userID
-	
-	// Ensure pagination defaults
+
+	if filter.AssigneeID != nil && *filter.AssigneeID != userID {
+		return nil, ErrNotAuthorized
+	}
+	if filter.ProjectID != nil {
+		if _, err := uc.permissions.AuthorizeProject(ctx, *filter.ProjectID, userID, entity.PermissionView); err != nil {
+			return nil, err
+		}
+	}
+
+	// Ensure pagination defaults and limits
 	if filter.Page <= 0 {
 		filter.Page = 1
 	}
 	if filter.PageSize <= 0 {
-		filter.PageSize = 10
+		filter.PageSize = DefaultPageSize
+	}
+	if filter.PageSize > MaxPageSize {
+		filter.PageSize = MaxPageSize
+	}
+
+	if !filter.Sort.IsValid() {
+		return nil, ErrInvalidSort
+	}
+
+	if filter.FullText != nil {
+		return uc.searchUserTodos(ctx, filter)
+	}
+	if filter.Cursor != nil {
+		if !filter.Sort.IsDefault() {
+			return nil, ErrSortCursor
+		}
+		return uc.getUserTodosByCursor(ctx, userID, filter)
 	}
 
 	todos, err := uc.todoRepo.GetByUserID(ctx, userID, filter)
 	if err != nil {
-		return nil, 0, err
+		logging.FromContext(ctx).WithError(err).Error("Failed to list todos")
+		return nil, err
+	}
+
+	count, err := uc.todoRepo.Count(ctx, filter)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to count todos")
+		return nil, err
+	}
+
+	return &TodoPage{Todos: todos, PageSize: filter.PageSize, Total: count}, nil
+}
+
+// searchUserTodos retrieves a page of the todos matching a full-text search, most relevant first
+func (uc *todoUseCase) searchUserTodos(ctx context.Context, filter entity.TodoFilter) (*TodoPage, error) {
+	if filter.Cursor != nil {
+		return nil, ErrSearchCursor
+	}
+	if !filter.FullText.HasPositiveTerms() {
+		return nil, ErrInvalidSearchQuery
+	}
+
+	matches, err := uc.todoRepo.Search(ctx, filter)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to search todos")
+		return nil, err
 	}
 
 	count, err := uc.todoRepo.Count(ctx, filter)
 	if err != nil {
-		return nil, 0, err
+		logging.FromContext(ctx).WithError(err).Error("Failed to count todos")
+		return nil, err
+	}
+
+	todos := make([]*entity.Todo, len(matches))
+	for i, match := range matches {
+		todos[i] = match.Todo
+	}
+
+	return &TodoPage{Todos: todos, PageSize: filter.PageSize, Total: count, Matches: matches}, nil
+}
+
+// getUserTodosByCursor retrieves a page of todos using keyset pagination, which
+// is stable while todos are added and does not need to count them
+func (uc *todoUseCase) getUserTodosByCursor(ctx context.Context, userID uint, filter entity.TodoFilter) (*TodoPage, error) {
+	pageSize := filter.PageSize
+	cursor := *filter.Cursor
+	if cursor.IsZero() {
+		cursor.Backward = false
+	}
+
+	// Fetch one more todo to find out whether there are further pages
+	filter.PageSize++
+	todos, err := uc.todoRepo.GetByUserID(ctx, userID, filter)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to list todos")
+		return nil, err
+	}
+
+	hasMore := len(todos) > pageSize
+	if hasMore {
+		if cursor.Backward {
+			// The extra todo is the newest one, furthest from the cursor
+			todos = todos[1:]
+		} else {
+			todos = todos[:pageSize]
+		}
+	}
+
+	page := &TodoPage{Todos: todos, PageSize: pageSize}
+	if len(todos) == 0 {
+		// Let clients turn around on an empty page past either end
+		if !cursor.IsZero() {
+			turned := cursor
+			turned.Backward = !cursor.Backward
+			if turned.Backward {
+				page.Prev = &turned
+			} else {
+				page.Next = &turned
+			}
+		}
+		return page, nil
+	}
+
+	first, last := todos[0], todos[len(todos)-1]
+	if cursor.Backward {
+		page.Next = entity.TodoCursorAfter(last)
+		if hasMore {
+			page.Prev = entity.TodoCursorBefore(first)
+		}
+	} else {
+		if hasMore {
+			page.Next = entity.TodoCursorAfter(last)
+		}
+		if !cursor.IsZero() {
+			page.Prev = entity.TodoCursorBefore(first)
+		}
 	}
 
-	return todos, count, nil
+	return page, nil
 }
 
 // UpdateTodo updates a todo
 func (uc *todoUseCase) UpdateTodo(ctx context.Context, id uint, title, description string, completed bool, userID uint) (*entity.Todo, error) {
+	return uc.updateTodo(ctx, id, nil, title, description, completed, userID)
+}
+
+// UpdateTodoIfUnchanged updates a todo unless it was changed since changeSeq
+func (uc *todoUseCase) UpdateTodoIfUnchanged(ctx context.Context, id uint, changeSeq uint64, title, description string, completed bool, userID uint) (*entity.Todo, error) {
+	return uc.updateTodo(ctx, id, &changeSeq, title, description, completed, userID)
+}
+
+// updateTodo updates a todo, only while its change sequence number is
+// changeSeq unless changeSeq is nil
+func (uc *todoUseCase) updateTodo(ctx context.Context, id uint, changeSeq *uint64, title, description string, completed bool, userID uint) (*entity.Todo, error) {
 	if title == "" {
 		return nil, ErrInvalidTodoData
 	}
@@ -105,32 +308,77 @@ func (uc *todoUseCase) UpdateTodo(ctx context.Context, id uint, title, descripti
 		return nil, ErrTodoNotFound
 	}
 
-	if !todo.BelongsToUser(userID) {
-		return nil, ErrNotAuthorized
+	if err := uc.permissions.AuthorizeTodo(ctx, todo, userID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	store := uc.todoRepo.Update
+	if changeSeq != nil {
+		if todo.ChangeSeq != *changeSeq {
+			return nil, ErrTodoChanged
+		}
+		store = uc.todoRepo.UpdateIfUnchanged
 	}
 
+	before := *todo
 	todo.Update(title, description, completed)
-	if err := uc.todoRepo.Update(ctx, todo); err != nil {
+	activities := changeActivities(&before, todo, entity.ActivityUpdated, userID)
+	if err := store(ctx, todo, activities...); err != nil {
+		if errors.Is(err, repository.ErrTodoChanged) {
+			return nil, ErrTodoChanged
+		}
+		logging.FromContext(ctx).WithError(err).WithField("todo_id", id).Error("Failed to store todo update")
 		return nil, ErrTodoUpdateFailed
 	}
+	if completed && !before.Completed {
+		uc.metrics.TodoCompleted()
+	}
+	uc.publish(ctx, todo, activities...)
 
 	return todo, nil
 }
 
 // DeleteTodo deletes a todo
 func (uc *todoUseCase) DeleteTodo(ctx context.Context, id, userID uint) error {
+	return uc.deleteTodo(ctx, id, nil, userID)
+}
+
+// DeleteTodoIfUnchanged deletes a todo unless it was changed since changeSeq
+func (uc *todoUseCase) DeleteTodoIfUnchanged(ctx context.Context, id uint, changeSeq uint64, userID uint) error {
+	return uc.deleteTodo(ctx, id, &changeSeq, userID)
+}
+
+// deleteTodo deletes a todo, only while its change sequence number is
+// changeSeq unless changeSeq is nil
+func (uc *todoUseCase) deleteTodo(ctx context.Context, id uint, changeSeq *uint64, userID uint) error {
 	todo, err := uc.todoRepo.GetByID(ctx, id)
 	if err != nil {
 		return ErrTodoNotFound
 	}
 
-	if !todo.BelongsToUser(userID) {
-		return ErrNotAuthorized
+	if err := uc.permissions.AuthorizeTodo(ctx, todo, userID, entity.PermissionEdit); err != nil {
+		return err
+	}
+
+	if changeSeq != nil && todo.ChangeSeq != *changeSeq {
+		return ErrTodoChanged
 	}
 
-	if err := uc.todoRepo.Delete(ctx, id); err != nil {
+	activities := changeActivities(todo, nil, entity.ActivityDeleted, userID)
+	if changeSeq == nil {
+		err = uc.todoRepo.Delete(ctx, id, activities...)
+	} else {
+		err = uc.todoRepo.DeleteIfUnchanged(ctx, id, *changeSeq, activities...)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrTodoChanged) {
+			return ErrTodoChanged
+		}
+		logging.FromContext(ctx).WithError(err).WithField("todo_id", id).Error("Failed to delete todo")
 		return ErrTodoDeleteFailed
 	}
+	logging.FromContext(ctx).WithField("todo_id", id).Info("Todo deleted")
+	uc.publish(ctx, todo, activities...)
 
 	return nil
 }
@@ -142,16 +390,114 @@ func (uc *todoUseCase) CompleteTodo(ctx context.Context, id, userID uint) (*enti
 		return nil, ErrTodoNotFound
 	}
 
-	if !todo.BelongsToUser(userID) {
-		return nil, ErrNotAuthorized
+	if err := uc.permissions.AuthorizeTodo(ctx, todo, userID, entity.PermissionEdit); err != nil {
+		return nil, err
 	}
 
+	before := *todo
 	todo.MarkAsCompleted()
 	todo.UpdatedAt = time.Now()
 
-	if err := uc.todoRepo.Update(ctx, todo); err != nil {
+	activities := changeActivities(&before, todo, entity.ActivityCompleted, userID)
+	if err := uc.todoRepo.Update(ctx, todo, activities...); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("todo_id", id).Error("Failed to store completed todo")
 		return nil, ErrTodoUpdateFailed
 	}
+	if !before.Completed {
+		uc.metrics.TodoCompleted()
+	}
+	uc.publish(ctx, todo, activities...)
 
 	return todo, nil
 }
+
+// AssignTodo assigns a todo to assigneeID, or unassigns it when assigneeID is
+// nil. Personal todos can only be assigned to their creator and project todos
+// to the members of the project.
+func (uc *todoUseCase) AssignTodo(ctx context.Context, id uint, assigneeID *uint, userID uint) (*entity.Todo, error) {
+	todo, err := uc.todoRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrTodoNotFound
+	}
+
+	if err := uc.permissions.AuthorizeTodo(ctx, todo, userID, entity.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	before := *todo
+	previous := todo.AssigneeID
+	if assigneeID == nil {
+		if previous == nil {
+			return todo, nil
+		}
+		todo.Unassign()
+	} else {
+		if todo.IsAssignedTo(*assigneeID) {
+			return todo, nil
+		}
+		if err := uc.checkAssignee(ctx, todo, *assigneeID); err != nil {
+			return nil, err
+		}
+		todo.AssignTo(*assigneeID)
+	}
+
+	activities := changeActivities(&before, todo, entity.ActivityUpdated, userID)
+	if err := uc.todoRepo.Update(ctx, todo, activities...); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("todo_id", id).Error("Failed to store todo assignee")
+		return nil, ErrTodoUpdateFailed
+	}
+	uc.publish(ctx, todo, activities...)
+
+	// Tell the previous assignee first, so that a reassignment reads in order
+	if previous != nil {
+		uc.notifier.TodoUnassigned(ctx, todo, *previous, userID)
+	}
+	if assigneeID != nil {
+		uc.notifier.TodoAssigned(ctx, todo, *assigneeID, userID)
+	}
+
+	return todo, nil
+}
+
+// checkAssignee checks that a user can view the todo they are assigned to
+func (uc *todoUseCase) checkAssignee(ctx context.Context, todo *entity.Todo, assigneeID uint) error {
+	if !todo.IsShared() {
+		if !todo.BelongsToUser(assigneeID) {
+			return ErrInvalidAssignee
+		}
+		return nil
+	}
+
+	if _, err := uc.permissions.AuthorizeProject(ctx, *todo.ProjectID, assigneeID, entity.PermissionView); err != nil {
+		return ErrInvalidAssignee
+	}
+	return nil
+}
+
+// changeActivities returns the activity of the change of a todo from before to
+// after, which the write of the change records with it, or none when nothing
+// changed. A nil before is a created todo, a nil after a deleted one.
+func changeActivities(before, after *entity.Todo, action entity.ActivityAction, userID uint) []*entity.Activity {
+	changes := entity.DiffTodos(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+	todo := after
+	if todo == nil {
+		todo = before
+	}
+	return []*entity.Activity{entity.NewActivity(todo.ID, userID, action, changes)}
+}
+
+// publish publishes the activities recorded with a write of todo as events,
+// with a snapshot of the todo as written
+func (uc *todoUseCase) publish(ctx context.Context, todo *entity.Todo, activities ...*entity.Activity) {
+	if len(activities) == 0 {
+		return
+	}
+	events := make([]*entity.TodoEvent, len(activities))
+	for i, activity := range activities {
+		events[i] = &entity.TodoEvent{Activity: activity, Todo: *todo}
+	}
+	uc.events.Publish(ctx, events...)
+}
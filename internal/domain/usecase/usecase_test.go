@@ -0,0 +1,80 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/infrastructure/repository/memory"
+)
+
+// fixture wires the use cases under test to in-memory repositories
+type fixture struct {
+	userRepo     repository.UserRepository
+	todoRepo     repository.TodoRepository
+	projectRepo  repository.ProjectRepository
+	commentRepo  repository.CommentRepository
+	activityRepo repository.ActivityRepository
+	webhookRepo  repository.WebhookRepository
+	permissions  usecase.PermissionService
+	todos        usecase.TodoUseCase
+}
+
+// newFixture creates a fixture with empty repositories
+func newFixture() *fixture {
+	todoRepo := memory.NewTodoRepository()
+	projectRepo := memory.NewProjectRepository(todoRepo)
+	workspaceRepo := memory.NewWorkspaceRepository(todoRepo, projectRepo)
+	activityRepo := memory.NewActivityRepository(workspaceRepo)
+	permissions := usecase.NewPermissionService(projectRepo, workspaceRepo)
+	return &fixture{
+		userRepo:     memory.NewUserRepository(),
+		todoRepo:     todoRepo,
+		projectRepo:  projectRepo,
+		commentRepo:  memory.NewCommentRepository(todoRepo),
+		activityRepo: activityRepo,
+		webhookRepo:  memory.NewWebhookRepository(workspaceRepo),
+		permissions:  permissions,
+		todos: usecase.NewTodoUseCase(todoRepo, permissions,
+			usecase.NoopMetrics{}, usecase.NoopNotifier{}, usecase.NoopTodoEventPublisher{}),
+	}
+}
+
+// user creates a user named name
+func (f *fixture) user(t *testing.T, name string) *entity.User {
+	t.Helper()
+	user := entity.NewUser(name, name+"@example.com", "hash")
+	if err := f.userRepo.Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// project creates a project owned by ownerID with the members and their roles
+func (f *fixture) project(t *testing.T, ownerID uint, members map[uint]entity.ProjectRole) *entity.Project {
+	t.Helper()
+	ctx := context.Background()
+	project := entity.NewProject(fmt.Sprintf("project of %d", ownerID))
+	if err := f.projectRepo.Create(ctx, project, entity.NewProjectMember(0, ownerID, entity.ProjectRoleOwner)); err != nil {
+		t.Fatal(err)
+	}
+	for userID, role := range members {
+		if err := f.projectRepo.AddMember(ctx, entity.NewProjectMember(project.ID, userID, role)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return project
+}
+
+// todo creates a todo of userID in the project of projectID, nil for a personal todo
+func (f *fixture) todo(t *testing.T, title string, projectID *uint, userID uint) *entity.Todo {
+	t.Helper()
+	todo, err := f.todos.CreateTodo(context.Background(), title, "", projectID, nil, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return todo
+}
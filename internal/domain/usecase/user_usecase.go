@@ -2,24 +2,28 @@ package usecase
 
 import (
 	"context"
-	"errors"
 	"strings"
 
+	"github.com/sirupsen/logrus"
+
 	"todo-api/internal/domain/entity"
 	"todo-api/internal/domain/repository"
 	"todo-api/internal/util/jwt"
+	"todo-api/internal/util/logging"
 	"todo-api/internal/util/password"
 )
 
 // Errors related to user operations
 var (
-	ErrUserNotFound      = errors.New("user not found")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrUsernameExists    = errors.New("username already exists")
-	ErrEmailExists       = errors.New("email already exists")
-	ErrInvalidUserData   = errors.New("invalid user data")
-	ErrUserCreateFailed  = errors.New("failed to create user")
-	ErrUserUpdateFailed  = errors.New("failed to update user")
+	ErrUserNotFound       = newError(KindNotFound, "user_not_found", "user not found")
+	ErrInvalidCredentials = newError(KindUnauthorized, "invalid_credentials", "invalid credentials")
+	ErrUsernameExists     = newError(KindConflict, "username_taken", "username already exists")
+	ErrEmailExists        = newError(KindConflict, "email_taken", "email already exists")
+	ErrInvalidUserData    = newError(KindInvalid, "invalid_user_data", "invalid user data")
+	ErrUserCreateFailed   = newError(KindInternal, "user_create_failed", "failed to create user")
+	ErrUserUpdateFailed   = newError(KindInternal, "user_update_failed", "failed to update user")
+	ErrInvalidToken       = newError(KindUnauthorized, "invalid_token", "invalid or expired token")
+	ErrTokenRevoked       = newError(KindUnauthorized, "token_revoked", "token was revoked")
 )
 
 // LoginResponse represents the response of a successful login
@@ -35,19 +39,30 @@ type UserUseCase interface {
 	GetUserByID(ctx context.Context, id uint) (*entity.User, error)
 	UpdateProfile(ctx context.Context, id uint, username, email string) (*entity.User, error)
 	UpdatePassword(ctx context.Context, id uint, currentPassword, newPassword string) error
+
+	// VerifyToken validates an access token and checks that it was not revoked
+	VerifyToken(ctx context.Context, token string) (*jwt.Claims, error)
+
+	// Logout revokes all access tokens issued to a user
+	Logout(ctx context.Context, id uint) error
 }
 
 // userUseCase implements UserUseCase
 type userUseCase struct {
 	userRepo repository.UserRepository
 	jwt      jwt.JWTService
+	metrics  Metrics
+	auditor  Auditor
 }
 
-// NewUserUseCase creates a new UserUseCase
-func NewUserUseCase(userRepo repository.UserRepository, jwtService jwt.JWTService) UserUseCase {
+// NewUserUseCase creates a new UserUseCase that records the account events in
+// the audit log through auditor
+func NewUserUseCase(userRepo repository.UserRepository, jwtService jwt.JWTService, metrics Metrics, auditor Auditor) UserUseCase {
 	return &userUseCase{
 		userRepo: userRepo,
 		jwt:      jwtService,
+		metrics:  metrics,
+		auditor:  auditor,
 	}
 }
 
@@ -89,8 +104,14 @@ func (uc *userUseCase) Register(ctx context.Context, username, email, pwd string
 	// Create user
 	user := entity.NewUser(username, email, hashedPassword)
 	if err := uc.userRepo.Create(ctx, user); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to store user")
 		return nil, ErrUserCreateFailed
 	}
+	logging.FromContext(ctx).WithField("registered_user_id", user.ID).Info("User registered")
+	uc.auditor.Record(ctx, entity.NewAuditEvent(entity.AuditUserRegistered, &user.ID, map[string]interface{}{
+		"username": user.Username,
+		"email":    user.Email,
+	}))
 
 	return user, nil
 }
@@ -103,19 +124,37 @@ func (uc *userUseCase) Login(ctx context.Context, email, pwd string) (*LoginResp
 	// Get user by email
 	user, err := uc.userRepo.GetByEmail(ctx, email)
 	if err != nil {
+		uc.metrics.LoginFailed(LoginFailureUnknownUser)
+		logging.FromContext(ctx).WithField("reason", LoginFailureUnknownUser).Warn("Login failed")
+		uc.auditor.Record(ctx, entity.NewAuditEvent(entity.AuditLoginFailed, nil, map[string]interface{}{
+			"reason": LoginFailureUnknownUser,
+			"email":  email,
+		}))
 		return nil, ErrInvalidCredentials
 	}
 
 	// Verify password
 	if !password.Verify(pwd, user.Password) {
+		uc.metrics.LoginFailed(LoginFailureWrongPassword)
+		logging.FromContext(ctx).WithFields(logrus.Fields{
+			"reason":        LoginFailureWrongPassword,
+			"login_user_id": user.ID,
+		}).Warn("Login failed")
+		uc.auditor.Record(ctx, entity.NewAuditEvent(entity.AuditLoginFailed, &user.ID, map[string]interface{}{
+			"reason": LoginFailureWrongPassword,
+			"email":  email,
+		}))
 		return nil, ErrInvalidCredentials
 	}
 
 	// Generate JWT token
-	token, err := uc.jwt.GenerateToken(user.ID, user.Username)
+	token, err := uc.jwt.GenerateToken(user.ID, user.Username, user.TokenVersion)
 	if err != nil {
 		return nil, err
 	}
+	logging.FromContext(ctx).WithField("login_user_id", user.ID).Info("User logged in")
+	uc.auditor.Record(ctx, entity.NewAuditEvent(entity.AuditLoginSucceeded, &user.ID, nil))
+	uc.auditor.Record(ctx, entity.NewAuditEvent(entity.AuditTokenIssued, &user.ID, nil))
 
 	return &LoginResponse{
 		Token: token,
@@ -172,10 +211,16 @@ func (uc *userUseCase) UpdateProfile(ctx context.Context, id uint, username, ema
 	}
 
 	// Update profile
+	changes := profileChanges(user, username, email)
 	user.UpdateProfile(username, email)
 	if err := uc.userRepo.Update(ctx, user); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to store profile update")
 		return nil, ErrUserUpdateFailed
 	}
+	logging.FromContext(ctx).Info("Profile updated")
+	if len(changes) > 0 {
+		uc.auditor.Record(ctx, entity.NewAuditEvent(entity.AuditProfileUpdated, &user.ID, changes))
+	}
 
 	return user, nil
 }
@@ -195,6 +240,9 @@ func (uc *userUseCase) UpdatePassword(ctx context.Context, id uint, currentPassw
 
 	// Verify current password
 	if !password.Verify(currentPassword, user.Password) {
+		uc.auditor.Record(ctx, entity.NewAuditEvent(entity.AuditPasswordChangeFailed, &user.ID, map[string]interface{}{
+			"reason": "wrong_password",
+		}))
 		return ErrInvalidCredentials
 	}
 
@@ -207,8 +255,65 @@ func (uc *userUseCase) UpdatePassword(ctx context.Context, id uint, currentPassw
 	// Update password
 	user.UpdatePassword(hashedPassword)
 	if err := uc.userRepo.Update(ctx, user); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to store password update")
 		return ErrUserUpdateFailed
 	}
+	logging.FromContext(ctx).Info("Password updated")
+	uc.auditor.Record(ctx, entity.NewAuditEvent(entity.AuditPasswordChanged, &user.ID, nil))
+
+	// Sign out every session, which may have been opened with the old password
+	return uc.revokeTokens(ctx, user.ID, "password_changed")
+}
+
+// VerifyToken validates an access token and checks that it was not revoked
+func (uc *userUseCase) VerifyToken(ctx context.Context, token string) (*jwt.Claims, error) {
+	claims, err := uc.jwt.ValidateToken(token)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	// Tokens issued before the last revocation carry an older token version
+	user, err := uc.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.TokenVersion != user.TokenVersion {
+		return nil, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
+
+// Logout revokes all access tokens issued to a user
+func (uc *userUseCase) Logout(ctx context.Context, id uint) error {
+	if err := uc.revokeTokens(ctx, id, "logout"); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("User logged out")
+	return nil
+}
 
+// revokeTokens revokes the access tokens of a user and records why
+func (uc *userUseCase) revokeTokens(ctx context.Context, id uint, reason string) error {
+	if err := uc.userRepo.RevokeTokens(ctx, id); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to revoke tokens")
+		return ErrUserUpdateFailed
+	}
+	uc.auditor.Record(ctx, entity.NewAuditEvent(entity.AuditTokensRevoked, &id, map[string]interface{}{
+		"reason": reason,
+	}))
 	return nil
 }
+
+// profileChanges returns the audit details of the fields a profile update
+// changes, keyed by field with the old and new value
+func profileChanges(user *entity.User, username, email string) map[string]interface{} {
+	changes := make(map[string]interface{})
+	if username != user.Username {
+		changes["username"] = map[string]string{"old": user.Username, "new": username}
+	}
+	if email != user.Email {
+		changes["email"] = map[string]string{"old": user.Email, "new": email}
+	}
+	return changes
+}
@@ -0,0 +1,120 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/infrastructure/repository/memory"
+	"todo-api/internal/util/jwt"
+)
+
+// auditLog is an Auditor collecting the recorded events
+type auditLog struct {
+	events []*entity.AuditEvent
+}
+
+// Record implements usecase.Auditor
+func (l *auditLog) Record(ctx context.Context, event *entity.AuditEvent) {
+	l.events = append(l.events, event)
+}
+
+// has reports whether an event of the type was recorded
+func (l *auditLog) has(eventType entity.AuditEventType) bool {
+	for _, event := range l.events {
+		if event.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// newUserUseCase creates a UserUseCase with a registered user whose password is "secret"
+func newUserUseCase(t *testing.T) (usecase.UserUseCase, *auditLog, *entity.User) {
+	t.Helper()
+	audit := &auditLog{}
+	users := usecase.NewUserUseCase(memory.NewUserRepository(), jwt.NewJWTService("test-secret", time.Hour), usecase.NoopMetrics{}, audit)
+	user, err := users.Register(context.Background(), "alice", "alice@example.com", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return users, audit, user
+}
+
+func TestVerifyToken(t *testing.T) {
+	users, _, user := newUserUseCase(t)
+	ctx := context.Background()
+	login, err := users.Login(ctx, "alice@example.com", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := users.VerifyToken(ctx, login.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != user.ID {
+		t.Errorf("got user %d, want %d", claims.UserID, user.ID)
+	}
+
+	if _, err := users.VerifyToken(ctx, "not a token"); !errors.Is(err, usecase.ErrInvalidToken) {
+		t.Errorf("malformed token: got error %v, want %v", err, usecase.ErrInvalidToken)
+	}
+}
+
+func TestLogoutRevokesTokens(t *testing.T) {
+	users, audit, user := newUserUseCase(t)
+	ctx := context.Background()
+	login, err := users.Login(ctx, "alice@example.com", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := users.Logout(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.VerifyToken(ctx, login.Token); !errors.Is(err, usecase.ErrTokenRevoked) {
+		t.Errorf("got error %v, want %v", err, usecase.ErrTokenRevoked)
+	}
+	if !audit.has(entity.AuditTokensRevoked) {
+		t.Error("revocation was not audited")
+	}
+
+	// Tokens issued after the logout are valid
+	login, err = users.Login(ctx, "alice@example.com", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.VerifyToken(ctx, login.Token); err != nil {
+		t.Errorf("new token: %v", err)
+	}
+}
+
+func TestUpdatePasswordRevokesTokens(t *testing.T) {
+	users, _, user := newUserUseCase(t)
+	ctx := context.Background()
+	login, err := users.Login(ctx, "alice@example.com", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := users.UpdatePassword(ctx, user.ID, "wrong", "new secret"); !errors.Is(err, usecase.ErrInvalidCredentials) {
+		t.Fatalf("wrong password: got error %v, want %v", err, usecase.ErrInvalidCredentials)
+	}
+	if _, err := users.VerifyToken(ctx, login.Token); err != nil {
+		t.Fatalf("token revoked by a failed password change: %v", err)
+	}
+
+	if err := users.UpdatePassword(ctx, user.ID, "secret", "new secret"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.VerifyToken(ctx, login.Token); !errors.Is(err, usecase.ErrTokenRevoked) {
+		t.Errorf("got error %v, want %v", err, usecase.ErrTokenRevoked)
+	}
+	if _, err := users.Login(ctx, "alice@example.com", "new secret"); err != nil {
+		t.Errorf("login with the new password: %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/util/logging"
+)
+
+// Errors related to saved view operations
+var (
+	ErrViewNotFound     = newError(KindNotFound, "view_not_found", "view not found")
+	ErrViewAccessDenied = newError(KindForbidden, "view_access_denied", "not authorized to access this view")
+	ErrInvalidViewData  = newError(KindInvalid, "invalid_view_data", "invalid view data")
+	ErrViewNameTaken    = newError(KindConflict, "view_name_taken", "a view with this name already exists")
+	ErrViewLimitReached = newError(KindConflict, "view_limit_reached", "the maximum number of views has been reached")
+	ErrViewCreateFailed = newError(KindInternal, "view_create_failed", "failed to create view")
+	ErrViewUpdateFailed = newError(KindInternal, "view_update_failed", "failed to update view")
+	ErrViewDeleteFailed = newError(KindInternal, "view_delete_failed", "failed to delete view")
+)
+
+// View limits
+const (
+	MaxViewsPerUser   = 50
+	MaxViewNameLength = 100
+)
+
+// ViewInput holds the name and the filters of a view to save
+type ViewInput struct {
+	Name      string
+	Completed *bool
+	Search    string
+	Filter    string
+	Sort      entity.TodoSort
+}
+
+// ViewSummary is a view with the live count of the todos it selects
+type ViewSummary struct {
+	View      *entity.View
+	TodoCount int64
+}
+
+// ViewUseCase defines the interface for saved view use cases
+type ViewUseCase interface {
+	CreateView(ctx context.Context, input ViewInput, userID uint) (*ViewSummary, error)
+	GetView(ctx context.Context, id, userID uint) (*ViewSummary, error)
+	GetUserViews(ctx context.Context, userID uint) ([]*ViewSummary, error)
+	UpdateView(ctx context.Context, id uint, input ViewInput, userID uint) (*ViewSummary, error)
+	DeleteView(ctx context.Context, id, userID uint) error
+	GetViewTodos(ctx context.Context, id, userID uint, page entity.TodoFilter) (*TodoPage, error)
+}
+
+// viewUseCase implements ViewUseCase
+type viewUseCase struct {
+	viewRepo repository.ViewRepository
+	todoRepo repository.TodoRepository
+	todos    TodoUseCase
+}
+
+// NewViewUseCase creates a new ViewUseCase. The todos of views are listed
+// through the todo use case, so that they are paginated the same way.
+func NewViewUseCase(viewRepo repository.ViewRepository, todoRepo repository.TodoRepository, todos TodoUseCase) ViewUseCase {
+	return &viewUseCase{
+		viewRepo: viewRepo,
+		todoRepo: todoRepo,
+		todos:    todos,
+	}
+}
+
+// CreateView saves a new view
+func (uc *viewUseCase) CreateView(ctx context.Context, input ViewInput, userID uint) (*ViewSummary, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if err := validateViewInput(input); err != nil {
+		return nil, err
+	}
+
+	views, err := uc.viewRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to list views")
+		return nil, ErrViewCreateFailed
+	}
+	if len(views) >= MaxViewsPerUser {
+		return nil, ErrViewLimitReached
+	}
+	if nameTaken(views, input.Name, 0) {
+		return nil, ErrViewNameTaken
+	}
+
+	view := entity.NewView(userID, input.Name, input.Completed, input.Search, input.Filter, input.Sort)
+	if err := uc.viewRepo.Create(ctx, view); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to store view")
+		return nil, ErrViewCreateFailed
+	}
+	logging.FromContext(ctx).WithField("view_id", view.ID).Info("View created")
+
+	return uc.summarize(ctx, view)
+}
+
+// GetView retrieves a view with its todo count
+func (uc *viewUseCase) GetView(ctx context.Context, id, userID uint) (*ViewSummary, error) {
+	view, err := uc.getView(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.summarize(ctx, view)
+}
+
+// GetUserViews retrieves all views of a user with their todo counts
+func (uc *viewUseCase) GetUserViews(ctx context.Context, userID uint) ([]*ViewSummary, error) {
+	views, err := uc.viewRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to list views")
+		return nil, err
+	}
+
+	summaries := make([]*ViewSummary, 0, len(views))
+	for _, view := range views {
+		summary, err := uc.summarize(ctx, view)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// UpdateView updates the name and the filters of a view
+func (uc *viewUseCase) UpdateView(ctx context.Context, id uint, input ViewInput, userID uint) (*ViewSummary, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if err := validateViewInput(input); err != nil {
+		return nil, err
+	}
+
+	view, err := uc.getView(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := uc.viewRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to list views")
+		return nil, ErrViewUpdateFailed
+	}
+	if nameTaken(views, input.Name, view.ID) {
+		return nil, ErrViewNameTaken
+	}
+
+	view.Update(input.Name, input.Completed, input.Search, input.Filter, input.Sort)
+	if err := uc.viewRepo.Update(ctx, view); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("view_id", id).Error("Failed to store view update")
+		return nil, ErrViewUpdateFailed
+	}
+
+	return uc.summarize(ctx, view)
+}
+
+// DeleteView deletes a view, its todos are not affected
+func (uc *viewUseCase) DeleteView(ctx context.Context, id, userID uint) error {
+	if _, err := uc.getView(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := uc.viewRepo.Delete(ctx, id); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("view_id", id).Error("Failed to delete view")
+		return ErrViewDeleteFailed
+	}
+	logging.FromContext(ctx).WithField("view_id", id).Info("View deleted")
+
+	return nil
+}
+
+// GetViewTodos retrieves a page of the todos selected by a view. Only the
+// pagination of the page filter is used, the view provides the rest.
+func (uc *viewUseCase) GetViewTodos(ctx context.Context, id, userID uint, page entity.TodoFilter) (*TodoPage, error) {
+	view, err := uc.getView(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := view.TodoFilter()
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("view_id", id).Error("Failed to parse stored view filter")
+		return nil, err
+	}
+	filter.Page = page.Page
+	filter.PageSize = page.PageSize
+	filter.Cursor = page.Cursor
+
+	return uc.todos.GetUserTodos(ctx, userID, filter)
+}
+
+// getView retrieves a view owned by userID
+func (uc *viewUseCase) getView(ctx context.Context, id, userID uint) (*entity.View, error) {
+	view, err := uc.viewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrViewNotFound
+	}
+
+	if !view.BelongsToUser(userID) {
+		return nil, ErrViewAccessDenied
+	}
+
+	return view, nil
+}
+
+// summarize counts the todos currently selected by a view
+func (uc *viewUseCase) summarize(ctx context.Context, view *entity.View) (*ViewSummary, error) {
+	filter, err := view.TodoFilter()
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("view_id", view.ID).Error("Failed to parse stored view filter")
+		return nil, err
+	}
+
+	count, err := uc.todoRepo.Count(ctx, filter)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("view_id", view.ID).Error("Failed to count view todos")
+		return nil, err
+	}
+
+	return &ViewSummary{View: view, TodoCount: count}, nil
+}
+
+// validateViewInput checks the name and the filters of a view. An invalid filter
+// expression is reported as an *entity.FilterError pointing at the offending token.
+func validateViewInput(input ViewInput) error {
+	if input.Name == "" || len(input.Name) > MaxViewNameLength {
+		return ErrInvalidViewData
+	}
+	if !input.Sort.IsValid() {
+		return ErrInvalidSort
+	}
+	if _, err := entity.ParseTodoFilter(input.Filter); err != nil {
+		return err
+	}
+	return nil
+}
+
+// nameTaken reports whether another view than exceptID is named name
+func nameTaken(views []*entity.View, name string, exceptID uint) bool {
+	for _, view := range views {
+		if view.ID != exceptID && view.Name == name {
+			return true
+		}
+	}
+	return false
+}
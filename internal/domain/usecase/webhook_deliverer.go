@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/util/logging"
+)
+
+// maxDeliveryErrorLength bounds the error message kept in the delivery log
+const maxDeliveryErrorLength = 1024
+
+// WebhookSender sends a delivery to its webhook
+type WebhookSender interface {
+	// Send posts the payload of the delivery to the URL of webhook and returns
+	// the response status. An error means that no response was received.
+	Send(ctx context.Context, webhook *entity.Webhook, delivery *entity.WebhookDelivery) (int, error)
+}
+
+// WebhookDeliveryPolicy controls how due deliveries are claimed and retried
+type WebhookDeliveryPolicy struct {
+	// BatchSize is the number of deliveries claimed at once
+	BatchSize int
+
+	// Timeout bounds a single attempt, claimed deliveries are leased for the
+	// time their batch may take
+	Timeout time.Duration
+
+	// MaxAttempts is the number of attempts before a delivery fails for good
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry, doubled for every further
+	// retry up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// RetryDelay returns the delay before the next attempt after attempts failed ones
+func (p WebhookDeliveryPolicy) RetryDelay(attempts int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempts && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// WebhookDeliverer sends the due webhook deliveries of all workspaces
+type WebhookDeliverer interface {
+	// DeliverDue sends a batch of due deliveries and returns how many were attempted
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+// webhookDeliverer implements WebhookDeliverer
+type webhookDeliverer struct {
+	webhookRepo repository.WebhookRepository
+	sender      WebhookSender
+	policy      WebhookDeliveryPolicy
+}
+
+// NewWebhookDeliverer creates a new WebhookDeliverer
+func NewWebhookDeliverer(webhookRepo repository.WebhookRepository, sender WebhookSender, policy WebhookDeliveryPolicy) WebhookDeliverer {
+	return &webhookDeliverer{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+		policy:      policy,
+	}
+}
+
+// DeliverDue claims a batch of due deliveries and attempts each of them. A
+// failed attempt is retried with exponential backoff until MaxAttempts.
+func (d *webhookDeliverer) DeliverDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	lease := d.policy.Timeout * time.Duration(d.policy.BatchSize+1)
+	deliveries, err := d.webhookRepo.ClaimDueDeliveries(ctx, now, now.Add(lease), d.policy.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		d.attempt(ctx, delivery)
+	}
+	return len(deliveries), nil
+}
+
+// attempt sends a claimed delivery and stores the outcome
+func (d *webhookDeliverer) attempt(ctx context.Context, delivery *entity.WebhookDelivery) {
+	status, err := d.sender.Send(ctx, delivery.Webhook, delivery)
+	if err != nil && ctx.Err() != nil {
+		// Shutting down, the attempt is repeated once the lease expires
+		return
+	}
+	at := time.Now()
+	if err == nil && status >= 200 && status < 300 {
+		delivery.Succeed(status, at)
+	} else {
+		message := fmt.Sprintf("unexpected response status %d", status)
+		if err != nil {
+			message = err.Error()
+		}
+		if len(message) > maxDeliveryErrorLength {
+			message = strings.ToValidUTF8(message[:maxDeliveryErrorLength], "")
+		}
+
+		var retryAt *time.Time
+		if delivery.Attempts+1 < d.policy.MaxAttempts {
+			next := at.Add(d.policy.RetryDelay(delivery.Attempts + 1))
+			retryAt = &next
+		}
+		delivery.Fail(status, message, at, retryAt)
+	}
+
+	entry := logging.FromContext(ctx).WithFields(logrus.Fields{
+		"delivery_id": delivery.ID,
+		"webhook_id":  delivery.WebhookID,
+		"status":      delivery.Status,
+		"attempts":    delivery.Attempts,
+	})
+	if err := d.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		entry.WithError(err).Error("Failed to store webhook delivery attempt")
+		return
+	}
+	if delivery.Status == entity.DeliveryFailed {
+		entry.WithField("error", delivery.Error).Warn("Webhook delivery failed")
+	} else {
+		entry.Debug("Webhook delivery attempted")
+	}
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/util/logging"
+)
+
+// webhookPayload is the JSON body delivered to webhooks
+type webhookPayload struct {
+	// ID identifies the event, redeliveries of an event share it
+	ID          uint                 `json:"id"`
+	Type        entity.TodoEventType `json:"type"`
+	OccurredAt  time.Time            `json:"occurred_at"`
+	WorkspaceID *uint                `json:"workspace_id"`
+	ActorID     uint                 `json:"actor_id"`
+	Todo        webhookTodo          `json:"todo"`
+	Changes     []entity.FieldChange `json:"changes"`
+}
+
+// webhookTodo is the todo of a webhook payload
+type webhookTodo struct {
+	ID          uint      `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Completed   bool      `json:"completed"`
+	UserID      uint      `json:"user_id"`
+	ProjectID   *uint     `json:"project_id"`
+	AssigneeID  *uint     `json:"assignee_id"`
+	Tags        []string  `json:"tags"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// webhookEventLease is how long a scheduler claims the outbox events it
+// handles, before another instance may take them over
+const webhookEventLease = time.Minute
+
+// WebhookScheduler schedules the webhook deliveries of the todo events that
+// the todo repository added to the outbox, in all workspaces
+type WebhookScheduler interface {
+	// ScheduleEvents schedules the deliveries of a batch of outbox events and
+	// returns how many events were claimed
+	ScheduleEvents(ctx context.Context) (int, error)
+}
+
+// webhookScheduler implements WebhookScheduler
+type webhookScheduler struct {
+	webhookRepo repository.WebhookRepository
+	permissions PermissionService
+	batchSize   int
+}
+
+// NewWebhookScheduler creates a new WebhookScheduler claiming batchSize
+// events at once. A webhook only receives the events of the todos its owner
+// can view.
+func NewWebhookScheduler(webhookRepo repository.WebhookRepository, permissions PermissionService, batchSize int) WebhookScheduler {
+	return &webhookScheduler{
+		webhookRepo: webhookRepo,
+		permissions: permissions,
+		batchSize:   batchSize,
+	}
+}
+
+// subscriptionKey identifies the webhooks subscribed to a type of event in a workspace
+type subscriptionKey struct {
+	workspaceID uint
+	eventType   entity.TodoEventType
+}
+
+// ScheduleEvents claims a batch of outbox events and schedules a delivery of
+// each of them to the subscribed webhooks of its workspace. An event that
+// fails stays in the outbox and is retried once its claim expired.
+func (s *webhookScheduler) ScheduleEvents(ctx context.Context) (int, error) {
+	now := time.Now()
+	events, err := s.webhookRepo.ClaimEvents(ctx, now, now.Add(webhookEventLease), s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	subscribed := make(map[subscriptionKey][]*entity.Webhook)
+	deliveries := 0
+	for _, event := range events {
+		scheduled, err := s.schedule(ctx, event, subscribed)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).WithField("event_id", event.Event.Activity.ID).Warn("Failed to schedule webhook deliveries")
+			continue
+		}
+		deliveries += scheduled
+	}
+	if len(events) > 0 {
+		logging.FromContext(ctx).WithFields(logrus.Fields{
+			"events":     len(events),
+			"deliveries": deliveries,
+		}).Debug("Webhook deliveries scheduled")
+	}
+	return len(events), nil
+}
+
+// schedule creates the deliveries of an outbox event and removes it from the
+// outbox. subscribed caches the subscribed webhooks across a batch.
+func (s *webhookScheduler) schedule(ctx context.Context, outboxEvent *entity.OutboxEvent, subscribed map[subscriptionKey][]*entity.Webhook) (int, error) {
+	key := subscriptionKey{eventType: outboxEvent.Event.Type()}
+	if outboxEvent.WorkspaceID != nil {
+		key.workspaceID = *outboxEvent.WorkspaceID
+		ctx = repository.WithWorkspace(ctx, key.workspaceID)
+	}
+	webhooks, ok := subscribed[key]
+	if !ok {
+		var err error
+		if webhooks, err = s.webhookRepo.GetSubscribed(ctx, key.eventType); err != nil {
+			return 0, err
+		}
+		subscribed[key] = webhooks
+	}
+
+	event := &outboxEvent.Event
+	deliveries := make([]*entity.WebhookDelivery, 0, len(webhooks))
+	if len(webhooks) > 0 {
+		payload, err := json.Marshal(newWebhookPayload(event))
+		if err != nil {
+			return 0, err
+		}
+		for _, webhook := range webhooks {
+			if err := s.permissions.AuthorizeTodo(ctx, &event.Todo, webhook.UserID, entity.PermissionView); err != nil {
+				continue
+			}
+			deliveries = append(deliveries, entity.NewWebhookDelivery(webhook.ID, event.Activity.ID, key.eventType, string(payload)))
+		}
+	}
+
+	err := s.webhookRepo.ScheduleDeliveries(ctx, outboxEvent, deliveries...)
+	if errors.Is(err, repository.ErrClaimExpired) {
+		// Another instance took the event over
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(deliveries), nil
+}
+
+// newWebhookPayload converts a todo event to a webhook payload
+func newWebhookPayload(event *entity.TodoEvent) webhookPayload {
+	changes := event.Activity.Changes
+	if changes == nil {
+		changes = []entity.FieldChange{}
+	}
+
+	todo := event.Todo
+	tags := todo.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return webhookPayload{
+		ID:          event.Activity.ID,
+		Type:        event.Type(),
+		OccurredAt:  event.Activity.CreatedAt,
+		WorkspaceID: todo.WorkspaceID,
+		ActorID:     event.Activity.UserID,
+		Todo: webhookTodo{
+			ID:          todo.ID,
+			Title:       todo.Title,
+			Description: todo.Description,
+			Completed:   todo.Completed,
+			UserID:      todo.UserID,
+			ProjectID:   todo.ProjectID,
+			AssigneeID:  todo.AssigneeID,
+			Tags:        tags,
+			CreatedAt:   todo.CreatedAt,
+			UpdatedAt:   todo.UpdatedAt,
+		},
+		Changes: changes,
+	}
+}
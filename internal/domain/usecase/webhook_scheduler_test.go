@@ -0,0 +1,72 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+)
+
+func TestScheduleWebhookEvents(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	scheduler := usecase.NewWebhookScheduler(f.webhookRepo, f.permissions, 10)
+	alice, bob, carol := f.user(t, "alice"), f.user(t, "bob"), f.user(t, "carol")
+	project := f.project(t, alice.ID, map[uint]entity.ProjectRole{bob.ID: entity.ProjectRoleViewer})
+
+	webhooks := make(map[uint]*entity.Webhook)
+	for _, user := range []*entity.User{alice, bob, carol} {
+		webhook := entity.NewWebhook(user.ID, "https://hooks.example/"+user.Username, "secret", entity.TodoEventTypes)
+		if err := f.webhookRepo.Create(ctx, webhook); err != nil {
+			t.Fatal(err)
+		}
+		webhooks[user.ID] = webhook
+	}
+	todo := f.todo(t, "shared", &project.ID, alice.ID)
+	if _, err := f.todos.CompleteTodo(ctx, todo.ID, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	scheduled, err := scheduler.ScheduleEvents(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scheduled != 2 {
+		t.Fatalf("got %d events, want 2", scheduled)
+	}
+	if scheduled, err := scheduler.ScheduleEvents(ctx); err != nil || scheduled != 0 {
+		t.Fatalf("got %d events scheduled again (err: %v)", scheduled, err)
+	}
+
+	// Only the members of the project receive the events of its todos
+	for userID, want := range map[uint]int64{alice.ID: 2, bob.ID: 2, carol.ID: 0} {
+		count, err := f.webhookRepo.CountDeliveries(ctx, webhooks[userID].ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != want {
+			t.Errorf("webhook of user %d: got %d deliveries, want %d", userID, count, want)
+		}
+	}
+
+	deliveries, err := f.webhookRepo.GetDeliveries(ctx, webhooks[bob.ID].ID, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload struct {
+		ID   uint                 `json:"id"`
+		Type entity.TodoEventType `json:"type"`
+		Todo struct {
+			ID        uint `json:"id"`
+			Completed bool `json:"completed"`
+		} `json:"todo"`
+	}
+	if err := json.Unmarshal([]byte(deliveries[0].Payload), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.ID != deliveries[0].EventID || payload.Type != entity.TodoEventCompleted || payload.Todo.ID != todo.ID || !payload.Todo.Completed {
+		t.Errorf("got payload %+v, want the completion of todo %d", payload, todo.ID)
+	}
+}
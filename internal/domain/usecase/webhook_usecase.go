@@ -0,0 +1,298 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strings"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/util/logging"
+)
+
+// Errors related to webhook operations
+var (
+	ErrWebhookNotFound       = newError(KindNotFound, "webhook_not_found", "webhook not found")
+	ErrWebhookAccessDenied   = newError(KindForbidden, "webhook_access_denied", "not authorized to access this webhook")
+	ErrInvalidWebhookData    = newError(KindInvalid, "invalid_webhook_data", "webhook url must be an absolute http or https URL and events must be todo event types")
+	ErrWebhookLimitReached   = newError(KindConflict, "webhook_limit_reached", "the maximum number of webhooks has been reached")
+	ErrWebhookHostNotAllowed = newError(KindInvalid, "webhook_host_not_allowed", "webhook url must resolve to public addresses")
+	ErrWebhookCreateFailed   = newError(KindInternal, "webhook_create_failed", "failed to create webhook")
+	ErrWebhookUpdateFailed   = newError(KindInternal, "webhook_update_failed", "failed to update webhook")
+	ErrWebhookDeleteFailed   = newError(KindInternal, "webhook_delete_failed", "failed to delete webhook")
+	ErrDeliveryNotFound      = newError(KindNotFound, "webhook_delivery_not_found", "webhook delivery not found")
+	ErrDeliveryLoadFailed    = newError(KindInternal, "webhook_delivery_load_failed", "failed to load webhook deliveries")
+	ErrRedeliveryFailed      = newError(KindInternal, "webhook_redelivery_failed", "failed to schedule webhook redelivery")
+)
+
+// Webhook limits
+const (
+	MaxWebhooksPerUser  = 20
+	MaxWebhookURLLength = 2048
+	webhookSecretBytes  = 32
+	webhookSecretPrefix = "whsec_"
+)
+
+// WebhookInput holds the endpoint and the subscriptions of a webhook to save
+type WebhookInput struct {
+	URL    string
+	Events []entity.TodoEventType
+	Active bool
+}
+
+// DeliveryPage is a page of webhook deliveries
+type DeliveryPage struct {
+	Deliveries []*entity.WebhookDelivery
+	Page       int
+	PageSize   int
+	Total      int64
+}
+
+// WebhookUseCase defines the interface for webhook use cases. Deliveries are
+// created by the webhook scheduler and sent by the webhook deliverer.
+type WebhookUseCase interface {
+	CreateWebhook(ctx context.Context, input WebhookInput, userID uint) (*entity.Webhook, error)
+	GetWebhook(ctx context.Context, id, userID uint) (*entity.Webhook, error)
+	GetUserWebhooks(ctx context.Context, userID uint) ([]*entity.Webhook, error)
+	UpdateWebhook(ctx context.Context, id uint, input WebhookInput, userID uint) (*entity.Webhook, error)
+	DeleteWebhook(ctx context.Context, id, userID uint) error
+	GetDeliveries(ctx context.Context, webhookID, userID uint, page, pageSize int) (*DeliveryPage, error)
+	Redeliver(ctx context.Context, webhookID, deliveryID, userID uint) (*entity.WebhookDelivery, error)
+}
+
+// WebhookHostChecker checks that deliveries may be sent to the host of a
+// webhook URL, so that webhooks cannot reach internal services
+type WebhookHostChecker interface {
+	CheckHost(ctx context.Context, host string) error
+}
+
+// webhookUseCase implements WebhookUseCase
+type webhookUseCase struct {
+	webhookRepo repository.WebhookRepository
+	hosts       WebhookHostChecker
+}
+
+// NewWebhookUseCase creates a new WebhookUseCase, which only accepts the
+// webhook URLs whose host is allowed by hosts
+func NewWebhookUseCase(webhookRepo repository.WebhookRepository, hosts WebhookHostChecker) WebhookUseCase {
+	return &webhookUseCase{
+		webhookRepo: webhookRepo,
+		hosts:       hosts,
+	}
+}
+
+// CreateWebhook registers a new webhook in the workspace of the context with a
+// generated signing secret, which is only returned here
+func (uc *webhookUseCase) CreateWebhook(ctx context.Context, input WebhookInput, userID uint) (*entity.Webhook, error) {
+	input, err := uc.checkWebhookInput(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := uc.webhookRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to list webhooks")
+		return nil, ErrWebhookCreateFailed
+	}
+	if len(webhooks) >= MaxWebhooksPerUser {
+		return nil, ErrWebhookLimitReached
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to generate webhook secret")
+		return nil, ErrWebhookCreateFailed
+	}
+
+	webhook := entity.NewWebhook(userID, input.URL, secret, input.Events)
+	webhook.Active = input.Active
+	if err := uc.webhookRepo.Create(ctx, webhook); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to store webhook")
+		return nil, ErrWebhookCreateFailed
+	}
+	logging.FromContext(ctx).WithField("webhook_id", webhook.ID).Info("Webhook created")
+
+	return webhook, nil
+}
+
+// GetWebhook retrieves a webhook by its ID
+func (uc *webhookUseCase) GetWebhook(ctx context.Context, id, userID uint) (*entity.Webhook, error) {
+	return uc.getWebhook(ctx, id, userID)
+}
+
+// GetUserWebhooks retrieves all webhooks of a user in the workspace of the context
+func (uc *webhookUseCase) GetUserWebhooks(ctx context.Context, userID uint) ([]*entity.Webhook, error) {
+	webhooks, err := uc.webhookRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to list webhooks")
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhook updates the endpoint and the subscriptions of a webhook. A
+// deactivated webhook keeps its pending deliveries until it is reactivated.
+func (uc *webhookUseCase) UpdateWebhook(ctx context.Context, id uint, input WebhookInput, userID uint) (*entity.Webhook, error) {
+	input, err := uc.checkWebhookInput(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := uc.getWebhook(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Update(input.URL, input.Events, input.Active)
+	if err := uc.webhookRepo.Update(ctx, webhook); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("webhook_id", id).Error("Failed to store webhook update")
+		return nil, ErrWebhookUpdateFailed
+	}
+
+	return webhook, nil
+}
+
+// DeleteWebhook deletes a webhook with its deliveries
+func (uc *webhookUseCase) DeleteWebhook(ctx context.Context, id, userID uint) error {
+	if _, err := uc.getWebhook(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := uc.webhookRepo.Delete(ctx, id); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("webhook_id", id).Error("Failed to delete webhook")
+		return ErrWebhookDeleteFailed
+	}
+	logging.FromContext(ctx).WithField("webhook_id", id).Info("Webhook deleted")
+
+	return nil
+}
+
+// GetDeliveries retrieves a page of the delivery log of a webhook, newest first
+func (uc *webhookUseCase) GetDeliveries(ctx context.Context, webhookID, userID uint, page, pageSize int) (*DeliveryPage, error) {
+	if _, err := uc.getWebhook(ctx, webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	page, pageSize = normalizeDeliveryPage(page, pageSize)
+	deliveries, err := uc.webhookRepo.GetDeliveries(ctx, webhookID, page, pageSize)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("webhook_id", webhookID).Error("Failed to list webhook deliveries")
+		return nil, ErrDeliveryLoadFailed
+	}
+
+	count, err := uc.webhookRepo.CountDeliveries(ctx, webhookID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("webhook_id", webhookID).Error("Failed to count webhook deliveries")
+		return nil, ErrDeliveryLoadFailed
+	}
+
+	return &DeliveryPage{Deliveries: deliveries, Page: page, PageSize: pageSize, Total: count}, nil
+}
+
+// Redeliver schedules a new delivery of the event of a delivery, due
+// immediately. The original delivery is kept in the log unchanged.
+func (uc *webhookUseCase) Redeliver(ctx context.Context, webhookID, deliveryID, userID uint) (*entity.WebhookDelivery, error) {
+	if _, err := uc.getWebhook(ctx, webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	delivery, err := uc.webhookRepo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil || delivery.WebhookID != webhookID {
+		return nil, ErrDeliveryNotFound
+	}
+
+	redelivery := delivery.Redeliver()
+	if err := uc.webhookRepo.CreateDeliveries(ctx, redelivery); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("delivery_id", deliveryID).Error("Failed to store webhook redelivery")
+		return nil, ErrRedeliveryFailed
+	}
+	logging.FromContext(ctx).WithField("delivery_id", redelivery.ID).Info("Webhook redelivery scheduled")
+
+	return redelivery, nil
+}
+
+// getWebhook retrieves a webhook owned by userID
+func (uc *webhookUseCase) getWebhook(ctx context.Context, id, userID uint) (*entity.Webhook, error) {
+	webhook, err := uc.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	if !webhook.BelongsToUser(userID) {
+		return nil, ErrWebhookAccessDenied
+	}
+
+	return webhook, nil
+}
+
+// checkWebhookInput normalizes the input of a webhook and checks that the
+// host of its URL is allowed
+func (uc *webhookUseCase) checkWebhookInput(ctx context.Context, input WebhookInput) (WebhookInput, error) {
+	input, err := normalizeWebhookInput(input)
+	if err != nil {
+		return input, err
+	}
+
+	endpoint, _ := url.Parse(input.URL)
+	if err := uc.hosts.CheckHost(ctx, endpoint.Hostname()); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("webhook_host", endpoint.Hostname()).Warn("Webhook host refused")
+		return input, ErrWebhookHostNotAllowed
+	}
+	return input, nil
+}
+
+// normalizeWebhookInput checks the endpoint and the event types of a webhook
+// and removes duplicate event types
+func normalizeWebhookInput(input WebhookInput) (WebhookInput, error) {
+	input.URL = strings.TrimSpace(input.URL)
+	if input.URL == "" || len(input.URL) > MaxWebhookURLLength {
+		return input, ErrInvalidWebhookData
+	}
+	endpoint, err := url.Parse(input.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return input, ErrInvalidWebhookData
+	}
+
+	if len(input.Events) == 0 {
+		return input, ErrInvalidWebhookData
+	}
+	events := make([]entity.TodoEventType, 0, len(input.Events))
+	seen := make(map[entity.TodoEventType]bool, len(input.Events))
+	for _, eventType := range input.Events {
+		if !eventType.IsValid() {
+			return input, ErrInvalidWebhookData
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			events = append(events, eventType)
+		}
+	}
+	input.Events = events
+
+	return input, nil
+}
+
+// newWebhookSecret generates a random signing secret
+func newWebhookSecret() (string, error) {
+	b := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(b), nil
+}
+
+// normalizeDeliveryPage applies the pagination defaults and limits
+func normalizeDeliveryPage(page, pageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	return page, pageSize
+}
@@ -0,0 +1,52 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/infrastructure/repository/memory"
+)
+
+// hostList is a WebhookHostChecker refusing the listed hosts
+type hostList map[string]bool
+
+// CheckHost implements usecase.WebhookHostChecker
+func (l hostList) CheckHost(ctx context.Context, host string) error {
+	if l[host] {
+		return errors.New("host refused")
+	}
+	return nil
+}
+
+func TestWebhookHostCheck(t *testing.T) {
+	todoRepo := memory.NewTodoRepository()
+	workspaceRepo := memory.NewWorkspaceRepository(todoRepo, memory.NewProjectRepository(todoRepo))
+	webhooks := usecase.NewWebhookUseCase(memory.NewWebhookRepository(workspaceRepo), hostList{"internal.example": true})
+	ctx := context.Background()
+	events := []entity.TodoEventType{entity.TodoEventCreated}
+
+	webhook, err := webhooks.CreateWebhook(ctx, usecase.WebhookInput{URL: "https://hooks.example/todo", Events: events, Active: true}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = webhooks.CreateWebhook(ctx, usecase.WebhookInput{URL: "https://internal.example/todo", Events: events}, 1)
+	if !errors.Is(err, usecase.ErrWebhookHostNotAllowed) {
+		t.Errorf("create: got error %v, want %v", err, usecase.ErrWebhookHostNotAllowed)
+	}
+	_, err = webhooks.UpdateWebhook(ctx, webhook.ID, usecase.WebhookInput{URL: "http://internal.example:8080/", Events: events}, 1)
+	if !errors.Is(err, usecase.ErrWebhookHostNotAllowed) {
+		t.Errorf("update: got error %v, want %v", err, usecase.ErrWebhookHostNotAllowed)
+	}
+
+	stored, err := webhooks.GetWebhook(ctx, webhook.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.URL != "https://hooks.example/todo" {
+		t.Errorf("got URL %q after a refused update", stored.URL)
+	}
+}
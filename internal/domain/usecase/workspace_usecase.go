@@ -0,0 +1,324 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/util/logging"
+)
+
+// Errors related to workspace operations
+var (
+	ErrWorkspaceNotFound           = newError(KindNotFound, "workspace_not_found", "workspace not found")
+	ErrWorkspaceAccessDenied       = newError(KindForbidden, "workspace_access_denied", "not authorized to perform this action on the workspace")
+	ErrInvalidWorkspaceData        = newError(KindInvalid, "invalid_workspace_data", "invalid workspace data")
+	ErrInvalidWorkspaceRole        = newError(KindInvalid, "invalid_workspace_role", "role must be member, admin or owner")
+	ErrWorkspaceMemberNotFound     = newError(KindNotFound, "workspace_member_not_found", "workspace member not found")
+	ErrWorkspaceMemberExists       = newError(KindConflict, "workspace_member_exists", "the user is already a member of the workspace")
+	ErrWorkspaceMemberLimitReached = newError(KindConflict, "workspace_member_limit_reached", "the maximum number of workspace members has been reached")
+	ErrLastWorkspaceOwner          = newError(KindConflict, "workspace_last_owner", "a workspace must keep at least one owner")
+	ErrWorkspaceCreateFailed       = newError(KindInternal, "workspace_create_failed", "failed to create workspace")
+	ErrWorkspaceUpdateFailed       = newError(KindInternal, "workspace_update_failed", "failed to update workspace")
+	ErrWorkspaceDeleteFailed       = newError(KindInternal, "workspace_delete_failed", "failed to delete workspace")
+	ErrWorkspaceMembershipFailed   = newError(KindInternal, "workspace_membership_failed", "failed to change workspace members")
+)
+
+// Workspace limits
+const (
+	MaxWorkspaceNameLength = 100
+	MaxWorkspaceMembers    = 1000
+)
+
+// WorkspaceUseCase defines the interface for workspace use cases. Workspaces
+// are returned as the membership of the acting user, with the workspace loaded.
+type WorkspaceUseCase interface {
+	CreateWorkspace(ctx context.Context, name string, userID uint) (*entity.WorkspaceMember, error)
+	GetWorkspace(ctx context.Context, id, userID uint) (*entity.WorkspaceMember, error)
+	GetUserWorkspaces(ctx context.Context, userID uint) ([]*entity.WorkspaceMember, error)
+	RenameWorkspace(ctx context.Context, id uint, name string, userID uint) (*entity.WorkspaceMember, error)
+	DeleteWorkspace(ctx context.Context, id, userID uint) error
+	GetMembers(ctx context.Context, workspaceID, userID uint) ([]*entity.WorkspaceMember, error)
+	AddMember(ctx context.Context, workspaceID uint, email string, role entity.WorkspaceRole, userID uint) (*entity.WorkspaceMember, error)
+	ChangeMemberRole(ctx context.Context, workspaceID, memberID uint, role entity.WorkspaceRole, userID uint) (*entity.WorkspaceMember, error)
+	RemoveMember(ctx context.Context, workspaceID, memberID, userID uint) error
+}
+
+// workspaceUseCase implements WorkspaceUseCase
+type workspaceUseCase struct {
+	workspaceRepo repository.WorkspaceRepository
+	userRepo      repository.UserRepository
+	permissions   PermissionService
+}
+
+// NewWorkspaceUseCase creates a new WorkspaceUseCase
+func NewWorkspaceUseCase(workspaceRepo repository.WorkspaceRepository, userRepo repository.UserRepository, permissions PermissionService) WorkspaceUseCase {
+	return &workspaceUseCase{
+		workspaceRepo: workspaceRepo,
+		userRepo:      userRepo,
+		permissions:   permissions,
+	}
+}
+
+// CreateWorkspace creates a new workspace owned by the user
+func (uc *workspaceUseCase) CreateWorkspace(ctx context.Context, name string, userID uint) (*entity.WorkspaceMember, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > MaxWorkspaceNameLength {
+		return nil, ErrInvalidWorkspaceData
+	}
+
+	workspace := entity.NewWorkspace(name)
+	owner := entity.NewWorkspaceMember(0, userID, entity.WorkspaceRoleOwner)
+	if err := uc.workspaceRepo.Create(ctx, workspace, owner); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to store workspace")
+		return nil, ErrWorkspaceCreateFailed
+	}
+	logging.FromContext(ctx).WithField("workspace_id", workspace.ID).Info("Workspace created")
+
+	owner.Workspace = *workspace
+	return owner, nil
+}
+
+// GetWorkspace retrieves a workspace the user is a member of
+func (uc *workspaceUseCase) GetWorkspace(ctx context.Context, id, userID uint) (*entity.WorkspaceMember, error) {
+	return uc.authorize(ctx, id, userID, entity.PermissionView)
+}
+
+// GetUserWorkspaces retrieves the workspaces the user is a member of, ordered by name
+func (uc *workspaceUseCase) GetUserWorkspaces(ctx context.Context, userID uint) ([]*entity.WorkspaceMember, error) {
+	memberships, err := uc.workspaceRepo.GetMemberships(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("Failed to list workspaces")
+		return nil, err
+	}
+	return memberships, nil
+}
+
+// RenameWorkspace renames a workspace, which requires the admin or owner role
+func (uc *workspaceUseCase) RenameWorkspace(ctx context.Context, id uint, name string, userID uint) (*entity.WorkspaceMember, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > MaxWorkspaceNameLength {
+		return nil, ErrInvalidWorkspaceData
+	}
+
+	membership, err := uc.authorize(ctx, id, userID, entity.PermissionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	membership.Workspace.Rename(name)
+	if err := uc.workspaceRepo.Update(ctx, &membership.Workspace); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("workspace_id", id).Error("Failed to store workspace update")
+		return nil, ErrWorkspaceUpdateFailed
+	}
+
+	return membership, nil
+}
+
+// DeleteWorkspace deletes a workspace with its projects and todos, which
+// requires the owner role
+func (uc *workspaceUseCase) DeleteWorkspace(ctx context.Context, id, userID uint) error {
+	membership, err := uc.permissions.AuthorizeWorkspace(ctx, id, userID, entity.PermissionManage)
+	if err != nil {
+		return err
+	}
+	if membership.Role != entity.WorkspaceRoleOwner {
+		return ErrWorkspaceAccessDenied
+	}
+
+	if err := uc.workspaceRepo.Delete(ctx, id); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("workspace_id", id).Error("Failed to delete workspace")
+		return ErrWorkspaceDeleteFailed
+	}
+	logging.FromContext(ctx).WithField("workspace_id", id).Info("Workspace deleted")
+
+	return nil
+}
+
+// GetMembers retrieves the members of a workspace with their users loaded
+func (uc *workspaceUseCase) GetMembers(ctx context.Context, workspaceID, userID uint) ([]*entity.WorkspaceMember, error) {
+	if _, err := uc.permissions.AuthorizeWorkspace(ctx, workspaceID, userID, entity.PermissionView); err != nil {
+		return nil, err
+	}
+
+	members, err := uc.workspaceRepo.GetMembers(ctx, workspaceID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("workspace_id", workspaceID).Error("Failed to list workspace members")
+		return nil, err
+	}
+	for _, member := range members {
+		if err := uc.loadUser(ctx, member); err != nil {
+			return nil, err
+		}
+	}
+	return members, nil
+}
+
+// AddMember adds the user registered with email to a workspace, which requires
+// the admin or owner role. Only owners may add owners.
+func (uc *workspaceUseCase) AddMember(ctx context.Context, workspaceID uint, email string, role entity.WorkspaceRole, userID uint) (*entity.WorkspaceMember, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidWorkspaceRole
+	}
+	membership, err := uc.permissions.AuthorizeWorkspace(ctx, workspaceID, userID, entity.PermissionManage)
+	if err != nil {
+		return nil, err
+	}
+	if role == entity.WorkspaceRoleOwner && membership.Role != entity.WorkspaceRoleOwner {
+		return nil, ErrWorkspaceAccessDenied
+	}
+
+	invitee, err := uc.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	members, err := uc.workspaceRepo.GetMembers(ctx, workspaceID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("workspace_id", workspaceID).Error("Failed to list workspace members")
+		return nil, ErrWorkspaceMembershipFailed
+	}
+	for _, member := range members {
+		if member.UserID == invitee.ID {
+			return nil, ErrWorkspaceMemberExists
+		}
+	}
+	if len(members) >= MaxWorkspaceMembers {
+		return nil, ErrWorkspaceMemberLimitReached
+	}
+
+	member := entity.NewWorkspaceMember(workspaceID, invitee.ID, role)
+	if err := uc.workspaceRepo.AddMember(ctx, member); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("workspace_id", workspaceID).Error("Failed to store workspace member")
+		return nil, ErrWorkspaceMembershipFailed
+	}
+	member.User = *invitee
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"workspace_id": workspaceID,
+		"member_id":    invitee.ID,
+		"role":         role,
+	}).Info("Workspace member added")
+
+	return member, nil
+}
+
+// ChangeMemberRole changes the role of a member, which requires the admin or
+// owner role. Only owners may promote to or demote from owner, and the last
+// owner cannot be demoted.
+func (uc *workspaceUseCase) ChangeMemberRole(ctx context.Context, workspaceID, memberID uint, role entity.WorkspaceRole, userID uint) (*entity.WorkspaceMember, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidWorkspaceRole
+	}
+	membership, err := uc.permissions.AuthorizeWorkspace(ctx, workspaceID, userID, entity.PermissionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := uc.workspaceRepo.GetMember(ctx, workspaceID, memberID)
+	if err != nil {
+		return nil, ErrWorkspaceMemberNotFound
+	}
+	if (member.Role == entity.WorkspaceRoleOwner || role == entity.WorkspaceRoleOwner) &&
+		membership.Role != entity.WorkspaceRoleOwner {
+		return nil, ErrWorkspaceAccessDenied
+	}
+	if member.Role == entity.WorkspaceRoleOwner && role != entity.WorkspaceRoleOwner {
+		if err := uc.checkOtherOwner(ctx, workspaceID, memberID); err != nil {
+			return nil, err
+		}
+	}
+
+	member.ChangeRole(role)
+	if err := uc.workspaceRepo.UpdateMember(ctx, member); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("workspace_id", workspaceID).Error("Failed to store workspace member")
+		return nil, ErrWorkspaceMembershipFailed
+	}
+	if err := uc.loadUser(ctx, member); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// RemoveMember removes a member from a workspace and its projects. Admins and
+// owners may remove others, only owners may remove owners and everyone may
+// leave. The last owner cannot be removed.
+func (uc *workspaceUseCase) RemoveMember(ctx context.Context, workspaceID, memberID, userID uint) error {
+	permission := entity.PermissionManage
+	if memberID == userID {
+		permission = entity.PermissionView
+	}
+	membership, err := uc.permissions.AuthorizeWorkspace(ctx, workspaceID, userID, permission)
+	if err != nil {
+		return err
+	}
+
+	member, err := uc.workspaceRepo.GetMember(ctx, workspaceID, memberID)
+	if err != nil {
+		return ErrWorkspaceMemberNotFound
+	}
+	if member.Role == entity.WorkspaceRoleOwner {
+		if membership.Role != entity.WorkspaceRoleOwner {
+			return ErrWorkspaceAccessDenied
+		}
+		if err := uc.checkOtherOwner(ctx, workspaceID, memberID); err != nil {
+			return err
+		}
+	}
+
+	if err := uc.workspaceRepo.RemoveMember(ctx, workspaceID, memberID); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("workspace_id", workspaceID).Error("Failed to remove workspace member")
+		return ErrWorkspaceMembershipFailed
+	}
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"workspace_id": workspaceID,
+		"member_id":    memberID,
+	}).Info("Workspace member removed")
+
+	return nil
+}
+
+// authorize checks the permission of the user on a workspace and loads the workspace
+func (uc *workspaceUseCase) authorize(ctx context.Context, id, userID uint, permission entity.Permission) (*entity.WorkspaceMember, error) {
+	membership, err := uc.permissions.AuthorizeWorkspace(ctx, id, userID, permission)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := uc.workspaceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrWorkspaceNotFound
+	}
+	membership.Workspace = *workspace
+
+	return membership, nil
+}
+
+// checkOtherOwner returns ErrLastWorkspaceOwner unless another member than exceptID owns the workspace
+func (uc *workspaceUseCase) checkOtherOwner(ctx context.Context, workspaceID, exceptID uint) error {
+	members, err := uc.workspaceRepo.GetMembers(ctx, workspaceID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("workspace_id", workspaceID).Error("Failed to list workspace members")
+		return ErrWorkspaceMembershipFailed
+	}
+	for _, member := range members {
+		if member.UserID != exceptID && member.Role == entity.WorkspaceRoleOwner {
+			return nil
+		}
+	}
+	return ErrLastWorkspaceOwner
+}
+
+// loadUser loads the user of a member
+func (uc *workspaceUseCase) loadUser(ctx context.Context, member *entity.WorkspaceMember) error {
+	user, err := uc.userRepo.GetByID(ctx, member.UserID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("user_id", member.UserID).Error("Failed to load workspace member")
+		return ErrWorkspaceMembershipFailed
+	}
+	member.User = *user
+	return nil
+}
@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"todo-api/internal/config"
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/util/logging"
+)
+
+// Recorder implements usecase.Auditor by storing events in the audit
+// repository and, when configured, appending them to a JSON-lines file
+type Recorder struct {
+	repo repository.AuditRepository
+
+	// file is the JSON-lines sink, nil when disabled. mu serializes writes to it.
+	mu   sync.Mutex
+	file *os.File
+}
+
+// line is the JSON-lines representation of an event in the file sink
+type line struct {
+	ID        uint                   `json:"id"`
+	Type      entity.AuditEventType  `json:"type"`
+	UserID    *uint                  `json:"user_id"`
+	RemoteIP  string                 `json:"remote_ip,omitempty"`
+	UserAgent string                 `json:"user_agent,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// New creates a new Recorder, opening the file sink of the configuration if any
+func New(cfg config.AuditConfig, repo repository.AuditRepository) (*Recorder, error) {
+	r := &Recorder{repo: repo}
+	if cfg.FilePath != "" {
+		file, err := os.OpenFile(cfg.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log file: %w", err)
+		}
+		r.file = file
+	}
+	return r, nil
+}
+
+// Record implements usecase.Auditor
+func (r *Recorder) Record(ctx context.Context, event *entity.AuditEvent) {
+	client := usecase.ClientInfoFromContext(ctx)
+	event.RemoteIP = client.RemoteIP
+	event.UserAgent = truncate(client.UserAgent, 255)
+	event.RequestID = client.RequestID
+
+	// The event is recorded even when the request was canceled meanwhile, so
+	// detach from the request but keep its logger and trace
+	ctx = trace.ContextWithSpan(logging.WithLogger(context.Background(), logging.FromContext(ctx)), trace.SpanFromContext(ctx))
+	if err := r.repo.Create(ctx, event); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("audit_type", event.Type).Error("Failed to store audit event")
+	}
+	if err := r.append(event); err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("audit_type", event.Type).Error("Failed to write audit event")
+	}
+}
+
+// append writes an event to the file sink, if enabled
+func (r *Recorder) append(event *entity.AuditEvent) error {
+	if r.file == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(line{
+		ID:        event.ID,
+		Type:      event.Type,
+		UserID:    event.UserID,
+		RemoteIP:  event.RemoteIP,
+		UserAgent: event.UserAgent,
+		RequestID: event.RequestID,
+		Details:   event.Details,
+		CreatedAt: event.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the file sink, if any
+func (r *Recorder) Close() error {
+	if r.file == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// truncate shortens s to at most n bytes without splitting a UTF-8 sequence
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n]
+}
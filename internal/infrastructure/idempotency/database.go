@@ -0,0 +1,103 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// keyRecord is the row of a key kept by DatabaseStore
+type keyRecord struct {
+	Key         string `gorm:"primaryKey;size:320"`
+	Fingerprint string `gorm:"size:64;not null"`
+	Completed   bool   `gorm:"not null"`
+	Status      int
+	ContentType string `gorm:"size:255"`
+	Body        []byte
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
+
+// TableName overrides the table name
+func (keyRecord) TableName() string {
+	return "idempotency_keys"
+}
+
+// DatabaseStore keeps idempotency keys in the database of the storage backend
+// so that retries are recognized by every instance
+type DatabaseStore struct {
+	db *gorm.DB
+}
+
+// NewDatabaseStore creates a new DatabaseStore and migrates its table
+func NewDatabaseStore(db *gorm.DB) (*DatabaseStore, error) {
+	if err := db.AutoMigrate(&keyRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate idempotency keys: %w", err)
+	}
+	return &DatabaseStore{db: db}, nil
+}
+
+// Reserve implements Store
+func (s *DatabaseStore) Reserve(ctx context.Context, key, fingerprint string) (*Record, error) {
+	now := time.Now()
+	db := s.db.WithContext(ctx)
+
+	// Insert the reservation unless the key exists, then take over the key if it
+	// expired. Either statement succeeds for one of concurrent requests only.
+	record := keyRecord{Key: key, Fingerprint: fingerprint, ExpiresAt: now.Add(ReservationTimeout)}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	result = db.Model(&keyRecord{}).Where("key = ? AND expires_at <= ?", key, now).Updates(map[string]interface{}{
+		"fingerprint":  fingerprint,
+		"completed":    false,
+		"status":       0,
+		"content_type": "",
+		"body":         nil,
+		"expires_at":   record.ExpiresAt,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing keyRecord
+	if err := db.Where("key = ?", key).Take(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to load idempotency key: %w", err)
+	}
+	held := &Record{Fingerprint: existing.Fingerprint}
+	if existing.Completed {
+		held.Response = &Response{Status: existing.Status, ContentType: existing.ContentType, Body: existing.Body}
+	}
+	return held, nil
+}
+
+// Complete implements Store
+func (s *DatabaseStore) Complete(ctx context.Context, key string, response Response, ttl time.Duration) error {
+	return s.db.WithContext(ctx).Model(&keyRecord{}).Where("key = ?", key).Updates(map[string]interface{}{
+		"completed":    true,
+		"status":       response.Status,
+		"content_type": response.ContentType,
+		"body":         response.Body,
+		"expires_at":   time.Now().Add(ttl),
+	}).Error
+}
+
+// Release implements Store
+func (s *DatabaseStore) Release(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where("key = ? AND completed = ?", key, false).Delete(&keyRecord{}).Error
+}
+
+// Sweep implements Store
+func (s *DatabaseStore) Sweep(ctx context.Context) error {
+	return s.db.WithContext(ctx).Where("expires_at <= ?", time.Now()).Delete(&keyRecord{}).Error
+}
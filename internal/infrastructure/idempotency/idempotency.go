@@ -0,0 +1,80 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/config"
+	"todo-api/internal/util/logging"
+)
+
+// ReservationTimeout bounds how long a key stays reserved by a request that
+// never completes, for instance because the instance serving it crashed
+const ReservationTimeout = time.Minute
+
+// Response is a response stored to be replayed
+type Response struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// Record is the state of a key. Response is nil while the request that
+// reserved the key is in progress.
+type Record struct {
+	Fingerprint string
+	Response    *Response
+}
+
+// Store keeps idempotency keys with the fingerprint of their request and its response
+type Store interface {
+	// Reserve reserves key for the request identified by fingerprint. It returns
+	// nil when the key was free, or the record of the request that holds it.
+	Reserve(ctx context.Context, key, fingerprint string) (*Record, error)
+
+	// Complete stores the response of the request holding key, it is replayed until ttl elapses
+	Complete(ctx context.Context, key string, response Response, ttl time.Duration) error
+
+	// Release frees key so that the request can be retried
+	Release(ctx context.Context, key string) error
+
+	// Sweep removes expired keys
+	Sweep(ctx context.Context) error
+}
+
+// New creates the store selected by the configuration. The database store
+// requires db, the connection of the storage backend.
+func New(cfg config.IdempotencyConfig, db *gorm.DB) (Store, error) {
+	switch cfg.Store {
+	case config.IdempotencyStoreMemory, "":
+		return NewMemoryStore(), nil
+	case config.IdempotencyStoreDatabase:
+		if db == nil {
+			return nil, errors.New("the database idempotency store requires a database connection")
+		}
+		return NewDatabaseStore(db)
+	default:
+		return nil, fmt.Errorf("unsupported idempotency store %q", cfg.Store)
+	}
+}
+
+// RunSweeper sweeps store every interval until ctx is canceled
+func RunSweeper(ctx context.Context, store Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := store.Sweep(ctx); err != nil && ctx.Err() == nil {
+				logging.FromContext(ctx).WithError(err).Warn("Failed to sweep idempotency keys")
+			}
+		}
+	}
+}
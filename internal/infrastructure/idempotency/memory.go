@@ -0,0 +1,82 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryRecord is a record kept by MemoryStore
+type memoryRecord struct {
+	Record
+	expiresAt time.Time
+}
+
+// MemoryStore keeps idempotency keys in memory, keys are known to one instance only
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]*memoryRecord
+}
+
+// NewMemoryStore creates a new MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]*memoryRecord),
+	}
+}
+
+// Reserve implements Store
+func (s *MemoryStore) Reserve(ctx context.Context, key, fingerprint string) (*Record, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[key]; ok && r.expiresAt.After(now) {
+		record := r.Record
+		return &record, nil
+	}
+
+	s.records[key] = &memoryRecord{
+		Record:    Record{Fingerprint: fingerprint},
+		expiresAt: now.Add(ReservationTimeout),
+	}
+	return nil, nil
+}
+
+// Complete implements Store
+func (s *MemoryStore) Complete(ctx context.Context, key string, response Response, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[key]; ok {
+		r.Response = &response
+		r.expiresAt = time.Now().Add(ttl)
+	}
+	return nil
+}
+
+// Release implements Store
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// Sweep implements Store
+func (s *MemoryStore) Sweep(ctx context.Context) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, r := range s.records {
+		if !r.expiresAt.After(now) {
+			delete(s.records, key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+// Namespace prefixes every metric exposed by the application
+const Namespace = "todo_api"
+
+// NewRegistry creates a registry with the Go runtime and process collectors
+func NewRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return registry
+}
+
+// RegisterDBStats exposes the connection pool statistics of db
+func RegisterDBStats(registerer prometheus.Registerer, db *gorm.DB, dbName string) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return registerer.Register(collectors.NewDBStatsCollector(sqlDB, dbName))
+}
+
+// DomainMetrics implements usecase.Metrics with Prometheus counters
+type DomainMetrics struct {
+	todosCreated   prometheus.Counter
+	todosCompleted prometheus.Counter
+	loginsFailed   *prometheus.CounterVec
+}
+
+// NewDomainMetrics creates the domain counters and registers them
+func NewDomainMetrics(registerer prometheus.Registerer) *DomainMetrics {
+	m := &DomainMetrics{
+		todosCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "todos_created_total",
+			Help:      "Number of todos created.",
+		}),
+		todosCompleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "todos_completed_total",
+			Help:      "Number of todos marked as completed.",
+		}),
+		loginsFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "logins_failed_total",
+			Help:      "Number of failed login attempts by reason.",
+		}, []string{"reason"}),
+	}
+	registerer.MustRegister(m.todosCreated, m.todosCompleted, m.loginsFailed)
+	return m
+}
+
+// TodoCreated implements usecase.Metrics
+func (m *DomainMetrics) TodoCreated() {
+	m.todosCreated.Inc()
+}
+
+// TodoCompleted implements usecase.Metrics
+func (m *DomainMetrics) TodoCompleted() {
+	m.todosCompleted.Inc()
+}
+
+// LoginFailed implements usecase.Metrics
+func (m *DomainMetrics) LoginFailed(reason string) {
+	m.loginsFailed.WithLabelValues(reason).Inc()
+}
@@ -0,0 +1,47 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/util/logging"
+)
+
+// LogNotifier implements usecase.Notifier by logging every notification with
+// the request-scoped logger
+type LogNotifier struct{}
+
+// NewLogNotifier creates a new LogNotifier
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// TodoAssigned implements usecase.Notifier
+func (n *LogNotifier) TodoAssigned(ctx context.Context, todo *entity.Todo, assigneeID, userID uint) {
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"todo_id":     todo.ID,
+		"assignee_id": assigneeID,
+		"assigned_by": userID,
+	}).Info("Todo assigned")
+}
+
+// TodoUnassigned implements usecase.Notifier
+func (n *LogNotifier) TodoUnassigned(ctx context.Context, todo *entity.Todo, assigneeID, userID uint) {
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"todo_id":       todo.ID,
+		"assignee_id":   assigneeID,
+		"unassigned_by": userID,
+	}).Info("Todo unassigned")
+}
+
+// Mentioned implements usecase.Notifier
+func (n *LogNotifier) Mentioned(ctx context.Context, todo *entity.Todo, comment *entity.Comment, mentionedID uint) {
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"todo_id":      todo.ID,
+		"comment_id":   comment.ID,
+		"mentioned_id": mentionedID,
+		"mentioned_by": comment.UserID,
+	}).Info("User mentioned")
+}
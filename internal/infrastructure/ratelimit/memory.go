@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryBucket is a bucket kept by MemoryStore
+type memoryBucket struct {
+	bucket
+	fullAt time.Time
+}
+
+// MemoryStore keeps token buckets in memory, limits apply per instance
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+}
+
+// NewMemoryStore creates a new MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*memoryBucket),
+	}
+}
+
+// Take implements Store
+func (s *MemoryStore) Take(ctx context.Context, key string, rule Rule) (Result, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{bucket: rule.newBucket(now)}
+		s.buckets[key] = b
+	}
+
+	result := rule.take(&b.bucket, now)
+	b.fullAt = rule.fullAt(b.bucket)
+
+	return result, nil
+}
+
+// Sweep implements Store
+func (s *MemoryStore) Sweep(ctx context.Context) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, b := range s.buckets {
+		if !b.fullAt.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+
+	return nil
+}
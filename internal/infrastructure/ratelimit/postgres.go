@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bucketRecord is the row of a bucket kept by PostgresStore
+type bucketRecord struct {
+	Key        string    `gorm:"primaryKey;size:255"`
+	Tokens     float64   `gorm:"not null"`
+	RefilledAt time.Time `gorm:"not null"`
+	FullAt     time.Time `gorm:"not null;index"`
+}
+
+// TableName overrides the table name
+func (bucketRecord) TableName() string {
+	return "rate_limit_buckets"
+}
+
+// PostgresStore keeps token buckets in PostgreSQL so that limits hold across instances
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore creates a new PostgresStore and migrates its table
+func NewPostgresStore(db *gorm.DB) (*PostgresStore, error) {
+	if err := db.AutoMigrate(&bucketRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate rate limit buckets: %w", err)
+	}
+	return &PostgresStore{db: db}, nil
+}
+
+// Take implements Store
+func (s *PostgresStore) Take(ctx context.Context, key string, rule Rule) (Result, error) {
+	var result Result
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// Create the bucket full on first use, then lock it so that concurrent
+		// requests, possibly on other instances, take their tokens in turn
+		initial := rule.newBucket(now)
+		record := bucketRecord{Key: key, Tokens: initial.tokens, RefilledAt: now, FullAt: now}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&record).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).Take(&record).Error; err != nil {
+			return err
+		}
+
+		b := bucket{tokens: record.Tokens, refilledAt: record.RefilledAt}
+		result = rule.take(&b, now)
+
+		return tx.Model(&record).Updates(map[string]interface{}{
+			"tokens":      b.tokens,
+			"refilled_at": b.refilledAt,
+			"full_at":     rule.fullAt(b),
+		}).Error
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+
+	return result, nil
+}
+
+// Sweep implements Store
+func (s *PostgresStore) Sweep(ctx context.Context) error {
+	return s.db.WithContext(ctx).Where("full_at <= ?", time.Now()).Delete(&bucketRecord{}).Error
+}
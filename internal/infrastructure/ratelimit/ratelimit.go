@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/config"
+	"todo-api/internal/util/logging"
+)
+
+// Rule configures a token bucket that holds up to Burst requests and refills Rate requests per second
+type Rule struct {
+	Rate  float64
+	Burst int
+}
+
+// Result describes the outcome of taking a token from a bucket
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+
+	// RetryAfter is how long until the next request will be allowed, zero when allowed
+	RetryAfter time.Duration
+
+	// ResetAfter is how long until the bucket has refilled completely
+	ResetAfter time.Duration
+}
+
+// Store keeps token buckets by key
+type Store interface {
+	// Take takes a token from the bucket of key, creating a full bucket on first use
+	Take(ctx context.Context, key string, rule Rule) (Result, error)
+
+	// Sweep removes buckets that have refilled completely, they are recreated full on demand
+	Sweep(ctx context.Context) error
+}
+
+// New creates the store selected by the configuration. The postgres store
+// requires db, the connection of the postgres storage backend.
+func New(cfg config.RateLimitConfig, db *gorm.DB) (Store, error) {
+	switch cfg.Store {
+	case config.RateLimitStoreMemory, "":
+		return NewMemoryStore(), nil
+	case config.RateLimitStorePostgres:
+		if db == nil {
+			return nil, errors.New("the postgres rate limit store requires a database connection")
+		}
+		return NewPostgresStore(db)
+	default:
+		return nil, fmt.Errorf("unsupported rate limit store %q", cfg.Store)
+	}
+}
+
+// RunSweeper sweeps store every interval until ctx is canceled
+func RunSweeper(ctx context.Context, store Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := store.Sweep(ctx); err != nil && ctx.Err() == nil {
+				logging.FromContext(ctx).WithError(err).Warn("Failed to sweep rate limit buckets")
+			}
+		}
+	}
+}
+
+// bucket is the state of a token bucket as of refilledAt
+type bucket struct {
+	tokens     float64
+	refilledAt time.Time
+}
+
+// newBucket returns a full bucket
+func (r Rule) newBucket(now time.Time) bucket {
+	return bucket{tokens: float64(r.Burst), refilledAt: now}
+}
+
+// take refills b up to now and takes a token from it if one is available
+func (r Rule) take(b *bucket, now time.Time) Result {
+	if elapsed := now.Sub(b.refilledAt).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(r.Burst), b.tokens+elapsed*r.Rate)
+	}
+	b.refilledAt = now
+
+	result := Result{Limit: r.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = r.refillTime(1 - b.tokens)
+	}
+	result.Remaining = int(b.tokens)
+	result.ResetAfter = r.refillTime(float64(r.Burst) - b.tokens)
+
+	return result
+}
+
+// fullAt returns when b will have refilled completely
+func (r Rule) fullAt(b bucket) time.Time {
+	return b.refilledAt.Add(r.refillTime(float64(r.Burst) - b.tokens))
+}
+
+// refillTime returns how long it takes to refill the given number of tokens
+func (r Rule) refillTime(tokens float64) time.Duration {
+	return time.Duration(tokens / r.Rate * float64(time.Second))
+}
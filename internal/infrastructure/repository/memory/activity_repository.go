@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// activityRepository implements repository.ActivityRepository
+type activityRepository struct {
+	mu sync.RWMutex
+
+	// activities are ordered by ID, which is also the order they were created in
+	activities []entity.Activity
+	nextID     uint
+}
+
+// NewActivityRepository creates a new in-memory ActivityRepository. The
+// activities of the workspaces deleted from workspaceRepo, which must have been
+// created by NewWorkspaceRepository, are deleted with them, and the writes of
+// its todo repository append their activities to it.
+func NewActivityRepository(workspaceRepo repository.WorkspaceRepository) repository.ActivityRepository {
+	r := &activityRepository{
+		activities: make([]entity.Activity, 0),
+		nextID:     1,
+	}
+
+	workspaces := workspaceRepo.(*workspaceRepository)
+	workspaces.mu.Lock()
+	workspaces.activities = r
+	workspaces.mu.Unlock()
+
+	workspaces.todos.mu.Lock()
+	workspaces.todos.activities = r
+	workspaces.todos.mu.Unlock()
+	return r
+}
+
+// Create appends activities to the log in the workspace of the context
+func (r *activityRepository) Create(ctx context.Context, activities ...*entity.Activity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, activity := range activities {
+		activity.ID = r.nextID
+		activity.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+		activity.CreatedAt = now
+		r.nextID++
+
+		r.activities = append(r.activities, copyActivity(activity))
+	}
+	return nil
+}
+
+// GetByTodoID retrieves a page of the activities on a todo, oldest first
+func (r *activityRepository) GetByTodoID(ctx context.Context, todoID uint, page, pageSize int) ([]*entity.Activity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	activities := r.filter(ctx, func(activity *entity.Activity) bool { return activity.TodoID == todoID })
+	return activityPage(activities, page, pageSize), nil
+}
+
+// CountByTodoID counts the activities on a todo
+func (r *activityRepository) CountByTodoID(ctx context.Context, todoID uint) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	activities := r.filter(ctx, func(activity *entity.Activity) bool { return activity.TodoID == todoID })
+	return int64(len(activities)), nil
+}
+
+// GetByUserID retrieves a page of the activities performed by a user, newest first
+func (r *activityRepository) GetByUserID(ctx context.Context, userID uint, page, pageSize int) ([]*entity.Activity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	activities := r.filter(ctx, func(activity *entity.Activity) bool { return activity.UserID == userID })
+	for i, j := 0, len(activities)-1; i < j; i, j = i+1, j-1 {
+		activities[i], activities[j] = activities[j], activities[i]
+	}
+	return activityPage(activities, page, pageSize), nil
+}
+
+// CountByUserID counts the activities performed by a user
+func (r *activityRepository) CountByUserID(ctx context.Context, userID uint) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	activities := r.filter(ctx, func(activity *entity.Activity) bool { return activity.UserID == userID })
+	return int64(len(activities)), nil
+}
+
+// filter returns copies of the activities in the workspace of the context
+// matching match, oldest first. The caller must hold the lock.
+func (r *activityRepository) filter(ctx context.Context, match func(*entity.Activity) bool) []*entity.Activity {
+	activities := make([]*entity.Activity, 0)
+	for i := range r.activities {
+		activity := &r.activities[i]
+		if inWorkspace(ctx, activity.WorkspaceID) && match(activity) {
+			stored := copyActivity(activity)
+			activities = append(activities, &stored)
+		}
+	}
+	return activities
+}
+
+// deleteByWorkspaceID deletes the activities of a workspace
+func (r *activityRepository) deleteByWorkspaceID(workspaceID uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.activities[:0]
+	for _, activity := range r.activities {
+		if activity.WorkspaceID == nil || *activity.WorkspaceID != workspaceID {
+			kept = append(kept, activity)
+		}
+	}
+	r.activities = kept
+}
+
+// activityPage returns a page of activities
+func activityPage(activities []*entity.Activity, page, pageSize int) []*entity.Activity {
+	offset := (page - 1) * pageSize
+	if offset < 0 || offset >= len(activities) {
+		return []*entity.Activity{}
+	}
+	end := offset + pageSize
+	if end > len(activities) {
+		end = len(activities)
+	}
+	return activities[offset:end]
+}
+
+// copyActivity returns a copy of the activity that shares no memory with it
+func copyActivity(activity *entity.Activity) entity.Activity {
+	stored := *activity
+	stored.Changes = append([]entity.FieldChange(nil), activity.Changes...)
+	if activity.WorkspaceID != nil {
+		workspaceID := *activity.WorkspaceID
+		stored.WorkspaceID = &workspaceID
+	}
+	return stored
+}
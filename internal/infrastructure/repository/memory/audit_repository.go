@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// auditRepository implements repository.AuditRepository
+type auditRepository struct {
+	mu sync.RWMutex
+
+	// events are ordered by ID, which is also the order they were created in
+	events []entity.AuditEvent
+	nextID uint
+}
+
+// NewAuditRepository creates a new in-memory AuditRepository
+func NewAuditRepository() repository.AuditRepository {
+	return &auditRepository{
+		events: make([]entity.AuditEvent, 0),
+		nextID: 1,
+	}
+}
+
+// Create appends an event to the log
+func (r *auditRepository) Create(ctx context.Context, event *entity.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event.ID = r.nextID
+	event.CreatedAt = time.Now()
+	r.nextID++
+
+	r.events = append(r.events, copyAuditEvent(event))
+	return nil
+}
+
+// List retrieves a page of the events matching a filter, newest first
+func (r *auditRepository) List(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := r.filter(filter)
+	offset := (filter.Page - 1) * filter.PageSize
+	if offset < 0 || offset >= len(events) {
+		return []*entity.AuditEvent{}, nil
+	}
+	end := offset + filter.PageSize
+	if end > len(events) {
+		end = len(events)
+	}
+	return events[offset:end], nil
+}
+
+// Count counts the events matching a filter
+func (r *auditRepository) Count(ctx context.Context, filter entity.AuditFilter) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.filter(filter))), nil
+}
+
+// filter returns copies of the events matching a filter, newest first. The
+// caller must hold the lock.
+func (r *auditRepository) filter(filter entity.AuditFilter) []*entity.AuditEvent {
+	events := make([]*entity.AuditEvent, 0)
+	for i := len(r.events) - 1; i >= 0; i-- {
+		event := &r.events[i]
+		if filter.UserID != nil && (event.UserID == nil || *event.UserID != *filter.UserID) {
+			continue
+		}
+		if filter.Type != "" && event.Type != filter.Type {
+			continue
+		}
+		if !filter.Since.IsZero() && event.CreatedAt.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && !event.CreatedAt.Before(filter.Until) {
+			continue
+		}
+		stored := copyAuditEvent(event)
+		events = append(events, &stored)
+	}
+	return events
+}
+
+// copyAuditEvent returns a copy of the event that shares no memory with it
+func copyAuditEvent(event *entity.AuditEvent) entity.AuditEvent {
+	stored := *event
+	if event.UserID != nil {
+		userID := *event.UserID
+		stored.UserID = &userID
+	}
+	if event.Details != nil {
+		stored.Details = make(map[string]interface{}, len(event.Details))
+		for key, value := range event.Details {
+			stored.Details[key] = value
+		}
+	}
+	return stored
+}
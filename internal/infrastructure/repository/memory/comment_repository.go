@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// commentRepository implements repository.CommentRepository
+type commentRepository struct {
+	mu       sync.RWMutex
+	comments map[uint]entity.Comment
+	nextID   uint
+
+	// todos holds the todos commented on, the lock of todos is always taken
+	// before the lock of the comments
+	todos *todoRepository
+}
+
+// NewCommentRepository creates a new in-memory CommentRepository. Comments can
+// only be created on the todos of todoRepo, which must have been created by
+// NewTodoRepository, and are deleted with them.
+func NewCommentRepository(todoRepo repository.TodoRepository) repository.CommentRepository {
+	r := &commentRepository{
+		comments: make(map[uint]entity.Comment),
+		nextID:   1,
+		todos:    todoRepo.(*todoRepository),
+	}
+
+	r.todos.mu.Lock()
+	r.todos.comments = r
+	r.todos.mu.Unlock()
+	return r
+}
+
+// Create creates a new comment in the workspace of the context
+func (r *commentRepository) Create(ctx context.Context, comment *entity.Comment) error {
+	r.todos.mu.RLock()
+	defer r.todos.mu.RUnlock()
+
+	if !r.todos.exists(ctx, comment.TodoID) {
+		return ErrNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	comment.ID = r.nextID
+	comment.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+	r.nextID++
+
+	r.comments[comment.ID] = copyComment(comment)
+	return nil
+}
+
+// GetByID retrieves a comment by its ID
+func (r *commentRepository) GetByID(ctx context.Context, id uint) (*entity.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comment, ok := r.comments[id]
+	if !ok || !inWorkspace(ctx, comment.WorkspaceID) {
+		return nil, ErrNotFound
+	}
+	return &comment, nil
+}
+
+// GetByTodoID retrieves a page of the comments on a todo, oldest first
+func (r *commentRepository) GetByTodoID(ctx context.Context, todoID uint, page, pageSize int) ([]*entity.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comments := r.filter(ctx, todoID)
+	sort.Slice(comments, func(i, j int) bool {
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		}
+		return comments[i].ID < comments[j].ID
+	})
+
+	offset := (page - 1) * pageSize
+	if offset < 0 || offset >= len(comments) {
+		return []*entity.Comment{}, nil
+	}
+	end := offset + pageSize
+	if end > len(comments) {
+		end = len(comments)
+	}
+	return comments[offset:end], nil
+}
+
+// CountByTodoID counts the comments on a todo
+func (r *commentRepository) CountByTodoID(ctx context.Context, todoID uint) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.filter(ctx, todoID))), nil
+}
+
+// Update updates a comment
+func (r *commentRepository) Update(ctx context.Context, comment *entity.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.comments[comment.ID]
+	if !ok || !inWorkspace(ctx, stored.WorkspaceID) {
+		return ErrNotFound
+	}
+
+	comment.UpdatedAt = time.Now()
+	r.comments[comment.ID] = copyComment(comment)
+	return nil
+}
+
+// Delete deletes a comment
+func (r *commentRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if comment, ok := r.comments[id]; ok && inWorkspace(ctx, comment.WorkspaceID) {
+		delete(r.comments, id)
+	}
+	return nil
+}
+
+// filter returns the comments on a todo in the workspace of the context. The
+// caller must hold the lock.
+func (r *commentRepository) filter(ctx context.Context, todoID uint) []*entity.Comment {
+	comments := make([]*entity.Comment, 0)
+	for _, comment := range r.comments {
+		if comment.TodoID == todoID && inWorkspace(ctx, comment.WorkspaceID) {
+			comment := comment
+			comments = append(comments, &comment)
+		}
+	}
+	return comments
+}
+
+// deleteByTodoID deletes the comments on a todo
+func (r *commentRepository) deleteByTodoID(todoID uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, comment := range r.comments {
+		if comment.TodoID == todoID {
+			delete(r.comments, id)
+		}
+	}
+}
+
+// copyComment returns a copy of the comment without its loaded associations
+func copyComment(comment *entity.Comment) entity.Comment {
+	stored := *comment
+	stored.Todo = entity.Todo{}
+	stored.User = entity.User{}
+	if comment.WorkspaceID != nil {
+		workspaceID := *comment.WorkspaceID
+		stored.WorkspaceID = &workspaceID
+	}
+	return stored
+}
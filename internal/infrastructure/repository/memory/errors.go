@@ -0,0 +1,11 @@
+package memory
+
+import (
+	"errors"
+)
+
+// Errors returned by the in-memory repositories
+var (
+	ErrNotFound     = errors.New("record not found")
+	ErrDuplicateKey = errors.New("duplicated key not allowed")
+)
@@ -0,0 +1,264 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// memberKey identifies a membership
+type memberKey struct {
+	projectID uint
+	userID    uint
+}
+
+// projectRepository implements repository.ProjectRepository
+type projectRepository struct {
+	mu       sync.RWMutex
+	projects map[uint]entity.Project
+	members  map[memberKey]entity.ProjectMember
+	nextID   uint
+
+	// todos holds the todos deleted with their project, or unassigned when
+	// their assignee leaves it
+	todos *todoRepository
+}
+
+// NewProjectRepository creates a new in-memory ProjectRepository. The todos of
+// deleted projects are deleted from todoRepo, which must have been created by
+// NewTodoRepository.
+func NewProjectRepository(todoRepo repository.TodoRepository) repository.ProjectRepository {
+	return &projectRepository{
+		projects: make(map[uint]entity.Project),
+		members:  make(map[memberKey]entity.ProjectMember),
+		nextID:   1,
+		todos:    todoRepo.(*todoRepository),
+	}
+}
+
+// Create creates a new project in the workspace of the context with its first member
+func (r *projectRepository) Create(ctx context.Context, project *entity.Project, owner *entity.ProjectMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	project.ID = r.nextID
+	project.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+	project.CreatedAt = now
+	project.UpdatedAt = now
+	r.nextID++
+	r.projects[project.ID] = copyProject(project)
+
+	owner.ProjectID = project.ID
+	owner.CreatedAt = now
+	owner.UpdatedAt = now
+	r.members[memberKey{owner.ProjectID, owner.UserID}] = copyMember(owner)
+	return nil
+}
+
+// GetByID retrieves a project by its ID
+func (r *projectRepository) GetByID(ctx context.Context, id uint) (*entity.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.exists(ctx, id) {
+		return nil, ErrNotFound
+	}
+	project := r.projects[id]
+	return &project, nil
+}
+
+// Update updates a project
+func (r *projectRepository) Update(ctx context.Context, project *entity.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.exists(ctx, project.ID) {
+		return ErrNotFound
+	}
+
+	project.UpdatedAt = time.Now()
+	r.projects[project.ID] = copyProject(project)
+	return nil
+}
+
+// Delete deletes a project with its members and todos. The todos leave no
+// tombstones, deleting the project changes the sync scope of its members.
+func (r *projectRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.exists(ctx, id) {
+		return nil
+	}
+
+	r.todos.mu.Lock()
+	defer r.todos.mu.Unlock()
+
+	for todoID, todo := range r.todos.todos {
+		if todo.ProjectID != nil && *todo.ProjectID == id {
+			r.todos.remove(todoID)
+		}
+	}
+	for key := range r.members {
+		if key.projectID == id {
+			delete(r.members, key)
+		}
+	}
+	delete(r.projects, id)
+	return nil
+}
+
+// GetMember retrieves the membership of a user in a project
+func (r *projectRepository) GetMember(ctx context.Context, projectID, userID uint) (*entity.ProjectMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	member, ok := r.members[memberKey{projectID, userID}]
+	if !ok || !r.exists(ctx, projectID) {
+		return nil, ErrNotFound
+	}
+	return &member, nil
+}
+
+// GetMembers retrieves the members of a project in the order they joined
+func (r *projectRepository) GetMembers(ctx context.Context, projectID uint) ([]*entity.ProjectMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := make([]*entity.ProjectMember, 0)
+	if !r.exists(ctx, projectID) {
+		return members, nil
+	}
+	for key, member := range r.members {
+		if key.projectID == projectID {
+			member := member
+			members = append(members, &member)
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].CreatedAt.Equal(members[j].CreatedAt) {
+			return members[i].CreatedAt.Before(members[j].CreatedAt)
+		}
+		return members[i].UserID < members[j].UserID
+	})
+	return members, nil
+}
+
+// GetMemberships retrieves the memberships of a user with their projects, ordered by project name
+func (r *projectRepository) GetMemberships(ctx context.Context, userID uint) ([]*entity.ProjectMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := make([]*entity.ProjectMember, 0)
+	for key, member := range r.members {
+		if key.userID == userID && r.exists(ctx, key.projectID) {
+			member := member
+			member.Project = r.projects[key.projectID]
+			members = append(members, &member)
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Project.Name != members[j].Project.Name {
+			return members[i].Project.Name < members[j].Project.Name
+		}
+		return members[i].ProjectID < members[j].ProjectID
+	})
+	return members, nil
+}
+
+// AddMember adds a member to a project
+func (r *projectRepository) AddMember(ctx context.Context, member *entity.ProjectMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := memberKey{member.ProjectID, member.UserID}
+	if _, ok := r.members[key]; ok {
+		return ErrDuplicateKey
+	}
+	if !r.exists(ctx, member.ProjectID) {
+		return ErrNotFound
+	}
+
+	now := time.Now()
+	member.CreatedAt = now
+	member.UpdatedAt = now
+	r.members[key] = copyMember(member)
+	return nil
+}
+
+// UpdateMember updates the role of a member
+func (r *projectRepository) UpdateMember(ctx context.Context, member *entity.ProjectMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := memberKey{member.ProjectID, member.UserID}
+	if _, ok := r.members[key]; !ok || !r.exists(ctx, member.ProjectID) {
+		return ErrNotFound
+	}
+
+	member.UpdatedAt = time.Now()
+	r.members[key] = copyMember(member)
+	return nil
+}
+
+// RemoveMember removes a member from a project and unassigns their todos in it
+func (r *projectRepository) RemoveMember(ctx context.Context, projectID, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.exists(ctx, projectID) {
+		return nil
+	}
+
+	r.todos.mu.Lock()
+	defer r.todos.mu.Unlock()
+
+	for todoID, todo := range r.todos.todos {
+		if todo.ProjectID != nil && *todo.ProjectID == projectID && todo.IsAssignedTo(userID) {
+			todo.Unassign()
+			todo.ChangeSeq = r.todos.nextChangeSeq()
+			r.todos.todos[todoID] = todo
+		}
+	}
+	delete(r.members, memberKey{projectID, userID})
+	return nil
+}
+
+// exists reports whether the project exists in the workspace of the context.
+// The caller must hold the lock.
+func (r *projectRepository) exists(ctx context.Context, id uint) bool {
+	project, ok := r.projects[id]
+	return ok && inWorkspace(ctx, project.WorkspaceID)
+}
+
+// belongsTo reports whether the project belongs to workspaceID. The caller
+// must hold the lock.
+func (r *projectRepository) belongsTo(id, workspaceID uint) bool {
+	project, ok := r.projects[id]
+	return ok && project.WorkspaceID != nil && *project.WorkspaceID == workspaceID
+}
+
+// copyProject returns a copy of the project that shares no memory with it
+func copyProject(project *entity.Project) entity.Project {
+	stored := *project
+	if project.WorkspaceID != nil {
+		workspaceID := *project.WorkspaceID
+		stored.WorkspaceID = &workspaceID
+	}
+	return stored
+}
+
+// copyMember returns a copy of the membership without its loaded associations
+func copyMember(member *entity.ProjectMember) entity.ProjectMember {
+	stored := *member
+	stored.Project = entity.Project{}
+	stored.User = entity.User{}
+	return stored
+}
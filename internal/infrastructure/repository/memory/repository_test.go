@@ -0,0 +1,28 @@
+package memory
+
+import (
+	"testing"
+
+	"todo-api/internal/domain/repository/repositorytest"
+)
+
+func TestRepositories(t *testing.T) {
+	todoRepo := NewTodoRepository()
+	projectRepo := NewProjectRepository(todoRepo)
+	workspaceRepo := NewWorkspaceRepository(todoRepo, projectRepo)
+
+	err := repositorytest.TestRepositories(
+		NewUserRepository(),
+		todoRepo,
+		NewViewRepository(),
+		projectRepo,
+		workspaceRepo,
+		NewCommentRepository(todoRepo),
+		NewActivityRepository(workspaceRepo),
+		NewAuditRepository(),
+		NewWebhookRepository(workspaceRepo),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,17 @@
+package memory
+
+import (
+	"context"
+
+	"todo-api/internal/domain/repository"
+)
+
+// inWorkspace reports whether a row with workspaceID is in the workspace of
+// the context, or outside any workspace when the context selects none
+func inWorkspace(ctx context.Context, workspaceID *uint) bool {
+	selected, ok := repository.WorkspaceFromContext(ctx)
+	if !ok {
+		return workspaceID == nil
+	}
+	return workspaceID != nil && *workspaceID == selected
+}
@@ -0,0 +1,443 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// todoRepository implements repository.TodoRepository
+type todoRepository struct {
+	mu     sync.RWMutex
+	todos  map[uint]entity.Todo
+	nextID uint
+
+	// changeSeq is the last change sequence number allocated
+	changeSeq uint64
+
+	// tombstones record the deleted todos in change sequence order
+	tombstones      []entity.TodoTombstone
+	nextTombstoneID uint
+
+	// comments holds the comments deleted with their todo, nil until a
+	// comment repository is created for the todos
+	comments *commentRepository
+
+	// activities holds the history the writes append to, nil until an
+	// activity repository is created for the workspaces of the todos
+	activities *activityRepository
+
+	// webhooks holds the outbox the writes add their events to, nil until a
+	// webhook repository is created for the workspaces of the todos
+	webhooks *webhookRepository
+}
+
+// NewTodoRepository creates a new in-memory TodoRepository
+func NewTodoRepository() repository.TodoRepository {
+	return &todoRepository{
+		todos:           make(map[uint]entity.Todo),
+		nextID:          1,
+		nextTombstoneID: 1,
+	}
+}
+
+// Create creates a new todo in the workspace of the context
+func (r *todoRepository) Create(ctx context.Context, todo *entity.Todo, activities ...*entity.Activity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	todo.ID = r.nextID
+	todo.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+	if todo.CreatedAt.IsZero() {
+		todo.CreatedAt = now
+	}
+	todo.UpdatedAt = now
+	todo.ChangeSeq = r.nextChangeSeq()
+	r.nextID++
+
+	r.todos[todo.ID] = copyTodo(todo)
+	for _, activity := range activities {
+		activity.TodoID = todo.ID
+	}
+	r.record(ctx, activities, todo)
+	return nil
+}
+
+// GetByID retrieves a todo by its ID
+func (r *todoRepository) GetByID(ctx context.Context, id uint) (*entity.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	todo, ok := r.todos[id]
+	if !ok || !inWorkspace(ctx, todo.WorkspaceID) {
+		return nil, ErrNotFound
+	}
+	return &todo, nil
+}
+
+// GetByIDs retrieves the existing todos among ids
+func (r *todoRepository) GetByIDs(ctx context.Context, ids []uint) ([]*entity.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	todos := make([]*entity.Todo, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if todo, ok := r.todos[id]; ok && !seen[id] && inWorkspace(ctx, todo.WorkspaceID) {
+			seen[id] = true
+			todos = append(todos, &todo)
+		}
+	}
+	return todos, nil
+}
+
+// GetByUserID retrieves todos for a specific user
+func (r *todoRepository) GetByUserID(ctx context.Context, userID uint, filter entity.TodoFilter) ([]*entity.Todo, error) {
+	filter.UserID = userID
+	todos := r.filter(ctx, filter)
+
+	// Apply keyset pagination, which is always newest first
+	if cursor := filter.Cursor; cursor != nil {
+		sort.Slice(todos, func(i, j int) bool {
+			return entity.TodoSortCreatedDesc.Less(todos[i], todos[j])
+		})
+		return cursorPage(todos, *cursor, filter.PageSize), nil
+	}
+
+	// Apply ordering
+	sort.Slice(todos, func(i, j int) bool {
+		return filter.Sort.Less(todos[i], todos[j])
+	})
+
+	// Apply offset pagination
+	start, end := pageBounds(len(todos), filter)
+	return todos[start:end], nil
+}
+
+// Search retrieves a page of the todos matching filter.FullText, most relevant first
+func (r *todoRepository) Search(ctx context.Context, filter entity.TodoFilter) ([]*entity.TodoMatch, error) {
+	if filter.FullText == nil {
+		return []*entity.TodoMatch{}, nil
+	}
+
+	todos := r.filter(ctx, filter)
+	matches := make([]*entity.TodoMatch, 0, len(todos))
+	for _, todo := range todos {
+		if match, ok := filter.FullText.Match(todo); ok {
+			matches = append(matches, match)
+		}
+	}
+
+	entity.SortTodoMatches(matches)
+
+	start, end := pageBounds(len(matches), filter)
+	return matches[start:end], nil
+}
+
+// Update updates a todo
+func (r *todoRepository) Update(ctx context.Context, todo *entity.Todo, activities ...*entity.Activity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.exists(ctx, todo.ID) {
+		return ErrNotFound
+	}
+
+	todo.UpdatedAt = time.Now()
+	todo.ChangeSeq = r.nextChangeSeq()
+	r.todos[todo.ID] = copyTodo(todo)
+	r.record(ctx, activities, todo)
+	return nil
+}
+
+// UpdateIfUnchanged updates a todo unless it was changed since it was read
+func (r *todoRepository) UpdateIfUnchanged(ctx context.Context, todo *entity.Todo, activities ...*entity.Activity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.exists(ctx, todo.ID) || r.todos[todo.ID].ChangeSeq != todo.ChangeSeq {
+		return repository.ErrTodoChanged
+	}
+
+	todo.UpdatedAt = time.Now()
+	todo.ChangeSeq = r.nextChangeSeq()
+	r.todos[todo.ID] = copyTodo(todo)
+	r.record(ctx, activities, todo)
+	return nil
+}
+
+// Delete deletes a todo
+func (r *todoRepository) Delete(ctx context.Context, id uint, activities ...*entity.Activity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.exists(ctx, id) {
+		todo := r.todos[id]
+		r.bury(id)
+		r.record(ctx, activities, &todo)
+	}
+	return nil
+}
+
+// DeleteIfUnchanged deletes a todo unless its change sequence number moved past changeSeq
+func (r *todoRepository) DeleteIfUnchanged(ctx context.Context, id uint, changeSeq uint64, activities ...*entity.Activity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.exists(ctx, id) || r.todos[id].ChangeSeq != changeSeq {
+		return repository.ErrTodoChanged
+	}
+	todo := r.todos[id]
+	r.bury(id)
+	r.record(ctx, activities, &todo)
+	return nil
+}
+
+// ApplyChanges saves the updated todos and deletes the deleted ones
+// atomically unless one of them was changed since it was read
+func (r *todoRepository) ApplyChanges(ctx context.Context, updated, deleted []*entity.Todo, activities ...*entity.Activity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, todos := range [][]*entity.Todo{updated, deleted} {
+		for _, todo := range todos {
+			if !r.exists(ctx, todo.ID) || r.todos[todo.ID].ChangeSeq != todo.ChangeSeq {
+				return repository.ErrTodoChanged
+			}
+		}
+	}
+
+	now := time.Now()
+	for _, todo := range updated {
+		todo.UpdatedAt = now
+		todo.ChangeSeq = r.nextChangeSeq()
+		r.todos[todo.ID] = copyTodo(todo)
+	}
+	for _, todo := range deleted {
+		r.bury(todo.ID)
+	}
+	r.record(ctx, activities, append(append([]*entity.Todo(nil), updated...), deleted...)...)
+	return nil
+}
+
+// GetChanges retrieves the todos written and the tombstones of the todos deleted after filter.Since
+func (r *todoRepository) GetChanges(ctx context.Context, filter entity.TodoChangeFilter) (*entity.TodoChanges, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	changes := &entity.TodoChanges{
+		Todos:      make([]*entity.Todo, 0),
+		Tombstones: make([]*entity.TodoTombstone, 0),
+		Until:      entity.ChangePosition{Seq: r.changeSeq},
+	}
+	for _, todo := range r.todos {
+		if filter.Since.Before(todo.ChangePosition()) && inWorkspace(ctx, todo.WorkspaceID) && inChangeScope(filter, todo.UserID, todo.ProjectID) {
+			todo := todo
+			changes.Todos = append(changes.Todos, &todo)
+		}
+	}
+	sort.Slice(changes.Todos, func(i, j int) bool {
+		return changes.Todos[i].ChangeSeq < changes.Todos[j].ChangeSeq
+	})
+	if len(changes.Todos) > filter.Limit {
+		changes.Todos = changes.Todos[:filter.Limit]
+	}
+
+	for _, tombstone := range r.tombstones {
+		if len(changes.Tombstones) == filter.Limit {
+			break
+		}
+		if filter.Since.Before(tombstone.ChangePosition()) && inWorkspace(ctx, tombstone.WorkspaceID) && inChangeScope(filter, tombstone.UserID, tombstone.ProjectID) {
+			tombstone := tombstone
+			changes.Tombstones = append(changes.Tombstones, &tombstone)
+		}
+	}
+	return changes, nil
+}
+
+// exists reports whether the todo exists in the workspace of the context. The
+// caller must hold the lock.
+func (r *todoRepository) exists(ctx context.Context, id uint) bool {
+	todo, ok := r.todos[id]
+	return ok && inWorkspace(ctx, todo.WorkspaceID)
+}
+
+// Count counts todos based on filter
+func (r *todoRepository) Count(ctx context.Context, filter entity.TodoFilter) (int64, error) {
+	return int64(len(r.filter(ctx, filter))), nil
+}
+
+// filter returns copies of all todos in the workspace of the context matching
+// the filter, in no particular order
+func (r *todoRepository) filter(ctx context.Context, filter entity.TodoFilter) []*entity.Todo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	search := strings.ToLower(filter.Search)
+
+	todos := make([]*entity.Todo, 0)
+	for _, todo := range r.todos {
+		if !inWorkspace(ctx, todo.WorkspaceID) || !inScope(&todo, filter) {
+			continue
+		}
+		if filter.Completed != nil && todo.Completed != *filter.Completed {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(todo.Title), search) &&
+			!strings.Contains(strings.ToLower(todo.Description), search) {
+			continue
+		}
+		if filter.Expr != nil && !filter.Expr.Match(&todo) {
+			continue
+		}
+		if filter.FullText != nil {
+			if _, ok := filter.FullText.Match(&todo); !ok {
+				continue
+			}
+		}
+
+		todo := todo
+		todos = append(todos, &todo)
+	}
+
+	return todos
+}
+
+// remove deletes a todo with its comments. The caller must hold the lock.
+func (r *todoRepository) remove(id uint) {
+	delete(r.todos, id)
+	if r.comments != nil {
+		r.comments.deleteByTodoID(id)
+	}
+}
+
+// bury deletes a todo with its comments and records it as a tombstone. The
+// caller must hold the lock.
+func (r *todoRepository) bury(id uint) {
+	todo := r.todos[id]
+	tombstone := entity.NewTodoTombstone(&todo, r.nextChangeSeq())
+	tombstone.ID = r.nextTombstoneID
+	r.nextTombstoneID++
+	r.tombstones = append(r.tombstones, *tombstone)
+	r.remove(id)
+}
+
+// record appends the activities of a write to the history, and adds their
+// events to the outbox with the snapshots of the written todos. The caller
+// must hold the lock.
+func (r *todoRepository) record(ctx context.Context, activities []*entity.Activity, todos ...*entity.Todo) {
+	if r.activities != nil {
+		_ = r.activities.Create(ctx, activities...)
+	}
+	if r.webhooks != nil {
+		r.webhooks.enqueue(entity.NewOutboxEvents(activities, todos))
+	}
+}
+
+// nextChangeSeq allocates a change sequence number. The caller must hold the lock.
+func (r *todoRepository) nextChangeSeq() uint64 {
+	r.changeSeq++
+	return r.changeSeq
+}
+
+// inChangeScope reports whether a todo of userID in the project projectID, nil
+// for a personal todo, is in the scope of the filter
+func inChangeScope(filter entity.TodoChangeFilter, userID uint, projectID *uint) bool {
+	if projectID == nil {
+		return userID == filter.UserID
+	}
+	for _, id := range filter.ProjectIDs {
+		if id == *projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// inScope reports whether the todo belongs to the project selected by the
+// filter, is assigned to filter.AssigneeID or is a personal todo of filter.UserID
+func inScope(todo *entity.Todo, filter entity.TodoFilter) bool {
+	if filter.AssigneeID != nil && !todo.IsAssignedTo(*filter.AssigneeID) {
+		return false
+	}
+	if filter.ProjectID != nil {
+		return todo.ProjectID != nil && *todo.ProjectID == *filter.ProjectID
+	}
+	if filter.AssigneeID != nil {
+		return true
+	}
+	return todo.UserID == filter.UserID && todo.ProjectID == nil
+}
+
+// pageBounds returns the bounds of the offset page selected by the filter
+func pageBounds(total int, filter entity.TodoFilter) (int, int) {
+	offset := (filter.Page - 1) * filter.PageSize
+	if offset < 0 || offset >= total {
+		return 0, 0
+	}
+	end := offset + filter.PageSize
+	if filter.PageSize <= 0 || end > total {
+		end = total
+	}
+	return offset, end
+}
+
+// cursorPage returns up to pageSize of the ordered todos after or before the cursor
+func cursorPage(todos []*entity.Todo, cursor entity.TodoCursor, pageSize int) []*entity.Todo {
+	// Find the first todo after the cursor
+	start := 0
+	if !cursor.IsZero() {
+		start = sort.Search(len(todos), func(i int) bool {
+			if todos[i].CreatedAt.Equal(cursor.CreatedAt) {
+				return todos[i].ID < cursor.ID
+			}
+			return todos[i].CreatedAt.Before(cursor.CreatedAt)
+		})
+	}
+
+	if cursor.Backward && !cursor.IsZero() {
+		// Skip the todo at the cursor, if it still exists
+		end := start
+		if end > 0 && todos[end-1].ID == cursor.ID {
+			end--
+		}
+		begin := end - pageSize
+		if begin < 0 {
+			begin = 0
+		}
+		return todos[begin:end]
+	}
+
+	end := start + pageSize
+	if end > len(todos) {
+		end = len(todos)
+	}
+	return todos[start:end]
+}
+
+// copyTodo returns a copy of the todo without its loaded associations
+func copyTodo(todo *entity.Todo) entity.Todo {
+	stored := *todo
+	stored.User = entity.User{}
+	stored.Tags = append([]string(nil), todo.Tags...)
+	if todo.ProjectID != nil {
+		projectID := *todo.ProjectID
+		stored.ProjectID = &projectID
+	}
+	if todo.AssigneeID != nil {
+		assigneeID := *todo.AssigneeID
+		stored.AssigneeID = &assigneeID
+	}
+	if todo.WorkspaceID != nil {
+		workspaceID := *todo.WorkspaceID
+		stored.WorkspaceID = &workspaceID
+	}
+	return stored
+}
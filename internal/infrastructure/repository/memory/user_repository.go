@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// userRepository implements repository.UserRepository
+type userRepository struct {
+	mu     sync.RWMutex
+	users  map[uint]entity.User
+	nextID uint
+}
+
+// NewUserRepository creates a new in-memory UserRepository
+func NewUserRepository() repository.UserRepository {
+	return &userRepository{
+		users:  make(map[uint]entity.User),
+		nextID: 1,
+	}
+}
+
+// Create creates a new user
+func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkUnique(user); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	user.ID = r.nextID
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	r.nextID++
+
+	r.users[user.ID] = copyUser(user)
+	return nil
+}
+
+// GetByID retrieves a user by their ID
+func (r *userRepository) GetByID(ctx context.Context, id uint) (*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &user, nil
+}
+
+// GetByUsername retrieves a user by their username
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// GetByEmail retrieves a user by their email
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Update updates a user's information
+func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.users[user.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if err := r.checkUnique(user); err != nil {
+		return err
+	}
+
+	user.UpdatedAt = time.Now()
+	stored := copyUser(user)
+	stored.TokenVersion = existing.TokenVersion
+	r.users[user.ID] = stored
+	return nil
+}
+
+// RevokeTokens increments the token version of a user
+func (r *userRepository) RevokeTokens(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	user.TokenVersion++
+	r.users[id] = user
+	return nil
+}
+
+// Delete deletes a user
+func (r *userRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.users, id)
+	return nil
+}
+
+// ExistsByUsername checks if a user with the given username exists
+func (r *userRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	_, err := r.GetByUsername(ctx, username)
+	return err == nil, nil
+}
+
+// ExistsByEmail checks if a user with the given email exists
+func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	_, err := r.GetByEmail(ctx, email)
+	return err == nil, nil
+}
+
+// checkUnique mirrors the unique indexes on username and email
+func (r *userRepository) checkUnique(user *entity.User) error {
+	for id, existing := range r.users {
+		if id == user.ID {
+			continue
+		}
+		if existing.Username == user.Username || existing.Email == user.Email {
+			return ErrDuplicateKey
+		}
+	}
+	return nil
+}
+
+// copyUser returns a copy of the user without its loaded associations
+func copyUser(user *entity.User) entity.User {
+	stored := *user
+	stored.Todos = nil
+	return stored
+}
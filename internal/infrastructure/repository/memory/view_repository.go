@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// viewRepository implements repository.ViewRepository
+type viewRepository struct {
+	mu     sync.RWMutex
+	views  map[uint]entity.View
+	nextID uint
+}
+
+// NewViewRepository creates a new in-memory ViewRepository
+func NewViewRepository() repository.ViewRepository {
+	return &viewRepository{
+		views:  make(map[uint]entity.View),
+		nextID: 1,
+	}
+}
+
+// Create creates a new view
+func (r *viewRepository) Create(ctx context.Context, view *entity.View) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkUnique(view); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	view.ID = r.nextID
+	view.CreatedAt = now
+	view.UpdatedAt = now
+	r.nextID++
+
+	r.views[view.ID] = copyView(view)
+	return nil
+}
+
+// GetByID retrieves a view by its ID
+func (r *viewRepository) GetByID(ctx context.Context, id uint) (*entity.View, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	view, ok := r.views[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &view, nil
+}
+
+// GetByUserID retrieves all views of a user ordered by name
+func (r *viewRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.View, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	views := make([]*entity.View, 0)
+	for _, view := range r.views {
+		if view.UserID == userID {
+			view := view
+			views = append(views, &view)
+		}
+	}
+
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].Name != views[j].Name {
+			return views[i].Name < views[j].Name
+		}
+		return views[i].ID < views[j].ID
+	})
+	return views, nil
+}
+
+// Update updates a view
+func (r *viewRepository) Update(ctx context.Context, view *entity.View) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.views[view.ID]; !ok {
+		return ErrNotFound
+	}
+	if err := r.checkUnique(view); err != nil {
+		return err
+	}
+
+	view.UpdatedAt = time.Now()
+	r.views[view.ID] = copyView(view)
+	return nil
+}
+
+// Delete deletes a view
+func (r *viewRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.views, id)
+	return nil
+}
+
+// checkUnique mirrors the unique index on the user and name of views
+func (r *viewRepository) checkUnique(view *entity.View) error {
+	for id, existing := range r.views {
+		if id != view.ID && existing.UserID == view.UserID && existing.Name == view.Name {
+			return ErrDuplicateKey
+		}
+	}
+	return nil
+}
+
+// copyView returns a copy of the view without its loaded associations
+func copyView(view *entity.View) entity.View {
+	stored := *view
+	stored.User = entity.User{}
+	if view.Completed != nil {
+		completed := *view.Completed
+		stored.Completed = &completed
+	}
+	return stored
+}
@@ -0,0 +1,413 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// webhookRepository implements repository.WebhookRepository
+type webhookRepository struct {
+	mu         sync.RWMutex
+	webhooks   map[uint]entity.Webhook
+	deliveries map[uint]entity.WebhookDelivery
+	nextID     uint
+
+	// nextDeliveryID is the ID of the next delivery, deliveries have their own sequence
+	nextDeliveryID uint
+
+	// outbox holds the todo events waiting for their deliveries to be
+	// scheduled, oldest first
+	outbox      []entity.OutboxEvent
+	nextEventID uint
+}
+
+// NewWebhookRepository creates a new in-memory WebhookRepository. The webhooks
+// of the workspaces deleted from workspaceRepo, which must have been created by
+// NewWorkspaceRepository, are deleted with them, and the writes of its todo
+// repository add their events to the outbox.
+func NewWebhookRepository(workspaceRepo repository.WorkspaceRepository) repository.WebhookRepository {
+	r := &webhookRepository{
+		webhooks:       make(map[uint]entity.Webhook),
+		deliveries:     make(map[uint]entity.WebhookDelivery),
+		nextID:         1,
+		nextDeliveryID: 1,
+		nextEventID:    1,
+	}
+
+	workspaces := workspaceRepo.(*workspaceRepository)
+	workspaces.mu.Lock()
+	workspaces.webhooks = r
+	workspaces.mu.Unlock()
+
+	workspaces.todos.mu.Lock()
+	workspaces.todos.webhooks = r
+	workspaces.todos.mu.Unlock()
+	return r
+}
+
+// Create creates a new webhook in the workspace of the context
+func (r *webhookRepository) Create(ctx context.Context, webhook *entity.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	webhook.ID = r.nextID
+	webhook.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+	r.nextID++
+
+	r.webhooks[webhook.ID] = copyWebhook(webhook)
+	return nil
+}
+
+// GetByID retrieves a webhook by its ID
+func (r *webhookRepository) GetByID(ctx context.Context, id uint) (*entity.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, ok := r.webhooks[id]
+	if !ok || !inWorkspace(ctx, webhook.WorkspaceID) {
+		return nil, ErrNotFound
+	}
+	stored := copyWebhook(&webhook)
+	return &stored, nil
+}
+
+// GetByUserID retrieves the webhooks of a user, oldest first
+func (r *webhookRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.filter(ctx, func(webhook *entity.Webhook) bool { return webhook.UserID == userID }), nil
+}
+
+// GetSubscribed retrieves the active webhooks receiving events of a type
+func (r *webhookRepository) GetSubscribed(ctx context.Context, eventType entity.TodoEventType) ([]*entity.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.filter(ctx, func(webhook *entity.Webhook) bool { return webhook.Active && webhook.Subscribes(eventType) }), nil
+}
+
+// Update updates a webhook
+func (r *webhookRepository) Update(ctx context.Context, webhook *entity.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.webhooks[webhook.ID]
+	if !ok || !inWorkspace(ctx, stored.WorkspaceID) {
+		return ErrNotFound
+	}
+
+	webhook.WorkspaceID = stored.WorkspaceID
+	webhook.CreatedAt = stored.CreatedAt
+	webhook.UpdatedAt = time.Now()
+	r.webhooks[webhook.ID] = copyWebhook(webhook)
+	return nil
+}
+
+// Delete deletes a webhook with its deliveries
+func (r *webhookRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, ok := r.webhooks[id]
+	if !ok || !inWorkspace(ctx, webhook.WorkspaceID) {
+		return nil
+	}
+	r.remove(id)
+	return nil
+}
+
+// CreateDeliveries creates deliveries in the workspace of the context
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries ...*entity.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, delivery := range deliveries {
+		if _, ok := r.webhooks[delivery.WebhookID]; !ok {
+			return ErrNotFound
+		}
+	}
+	for _, delivery := range deliveries {
+		delivery.ID = r.nextDeliveryID
+		delivery.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+		delivery.CreatedAt = now
+		delivery.UpdatedAt = now
+		r.nextDeliveryID++
+
+		r.deliveries[delivery.ID] = copyDelivery(delivery)
+	}
+	return nil
+}
+
+// ClaimEvents retrieves and claims the unclaimed todo events of the outbox in
+// any workspace
+func (r *webhookRepository) ClaimEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]*entity.OutboxEvent, 0)
+	for i := range r.outbox {
+		if len(events) == limit {
+			break
+		}
+		event := &r.outbox[i]
+		if event.ClaimedUntil.After(now) {
+			continue
+		}
+		event.ClaimedUntil = leaseUntil
+		claimed := copyOutboxEvent(event)
+		events = append(events, &claimed)
+	}
+	return events, nil
+}
+
+// ScheduleDeliveries removes a claimed event from the outbox and creates its
+// deliveries in the workspace of the event
+func (r *webhookRepository) ScheduleDeliveries(ctx context.Context, event *entity.OutboxEvent, deliveries ...*entity.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := -1
+	for i, stored := range r.outbox {
+		if stored.ID == event.ID && stored.ClaimedUntil.Equal(event.ClaimedUntil) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return repository.ErrClaimExpired
+	}
+	for _, delivery := range deliveries {
+		if _, ok := r.webhooks[delivery.WebhookID]; !ok {
+			return ErrNotFound
+		}
+	}
+
+	r.outbox = append(r.outbox[:index], r.outbox[index+1:]...)
+	now := time.Now()
+	for _, delivery := range deliveries {
+		delivery.ID = r.nextDeliveryID
+		delivery.WorkspaceID = event.WorkspaceID
+		delivery.CreatedAt = now
+		delivery.UpdatedAt = now
+		r.nextDeliveryID++
+
+		r.deliveries[delivery.ID] = copyDelivery(delivery)
+	}
+	return nil
+}
+
+// GetDeliveryByID retrieves a delivery by its ID
+func (r *webhookRepository) GetDeliveryByID(ctx context.Context, id uint) (*entity.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	delivery, ok := r.deliveries[id]
+	if !ok || !inWorkspace(ctx, delivery.WorkspaceID) {
+		return nil, ErrNotFound
+	}
+	stored := copyDelivery(&delivery)
+	return &stored, nil
+}
+
+// GetDeliveries retrieves a page of the deliveries of a webhook, newest first
+func (r *webhookRepository) GetDeliveries(ctx context.Context, webhookID uint, page, pageSize int) ([]*entity.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := r.webhookDeliveries(ctx, webhookID)
+	offset := (page - 1) * pageSize
+	if offset < 0 || offset >= len(deliveries) {
+		return []*entity.WebhookDelivery{}, nil
+	}
+	end := offset + pageSize
+	if end > len(deliveries) {
+		end = len(deliveries)
+	}
+	return deliveries[offset:end], nil
+}
+
+// CountDeliveries counts the deliveries of a webhook
+func (r *webhookRepository) CountDeliveries(ctx context.Context, webhookID uint) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.webhookDeliveries(ctx, webhookID))), nil
+}
+
+// ClaimDueDeliveries retrieves and postpones the pending deliveries due at now
+// in any workspace
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := make([]entity.WebhookDelivery, 0)
+	for _, delivery := range r.deliveries {
+		webhook := r.webhooks[delivery.WebhookID]
+		if delivery.Status == entity.DeliveryPending && !delivery.NextAttemptAt.After(now) && webhook.Active {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	deliveries := make([]*entity.WebhookDelivery, 0, len(due))
+	for i := range due {
+		delivery := &due[i]
+		delivery.NextAttemptAt = leaseUntil
+		r.deliveries[delivery.ID] = copyDelivery(delivery)
+
+		claimed := copyDelivery(delivery)
+		webhook := r.webhooks[delivery.WebhookID]
+		webhook = copyWebhook(&webhook)
+		claimed.Webhook = &webhook
+		deliveries = append(deliveries, &claimed)
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery stores the outcome of an attempt of a delivery in any workspace
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.deliveries[delivery.ID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	delivery.WorkspaceID = stored.WorkspaceID
+	delivery.CreatedAt = stored.CreatedAt
+	r.deliveries[delivery.ID] = copyDelivery(delivery)
+	return nil
+}
+
+// filter returns copies of the webhooks in the workspace of the context
+// matching match, oldest first. The caller must hold the lock.
+func (r *webhookRepository) filter(ctx context.Context, match func(*entity.Webhook) bool) []*entity.Webhook {
+	webhooks := make([]*entity.Webhook, 0)
+	for _, webhook := range r.webhooks {
+		if inWorkspace(ctx, webhook.WorkspaceID) && match(&webhook) {
+			stored := copyWebhook(&webhook)
+			webhooks = append(webhooks, &stored)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks
+}
+
+// webhookDeliveries returns copies of the deliveries of a webhook in the
+// workspace of the context, newest first. The caller must hold the lock.
+func (r *webhookRepository) webhookDeliveries(ctx context.Context, webhookID uint) []*entity.WebhookDelivery {
+	deliveries := make([]*entity.WebhookDelivery, 0)
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID == webhookID && inWorkspace(ctx, delivery.WorkspaceID) {
+			stored := copyDelivery(&delivery)
+			deliveries = append(deliveries, &stored)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	return deliveries
+}
+
+// remove deletes a webhook with its deliveries. The caller must hold the lock.
+func (r *webhookRepository) remove(id uint) {
+	for deliveryID, delivery := range r.deliveries {
+		if delivery.WebhookID == id {
+			delete(r.deliveries, deliveryID)
+		}
+	}
+	delete(r.webhooks, id)
+}
+
+// enqueue adds todo events to the outbox
+func (r *webhookRepository) enqueue(events []*entity.OutboxEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, event := range events {
+		event.ID = r.nextEventID
+		event.CreatedAt = now
+		r.nextEventID++
+
+		r.outbox = append(r.outbox, copyOutboxEvent(event))
+	}
+}
+
+// deleteByWorkspaceID deletes the webhooks of a workspace with their
+// deliveries, and the todo events of the workspace from the outbox
+func (r *webhookRepository) deleteByWorkspaceID(workspaceID uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, webhook := range r.webhooks {
+		if webhook.WorkspaceID != nil && *webhook.WorkspaceID == workspaceID {
+			r.remove(id)
+		}
+	}
+
+	outbox := r.outbox[:0]
+	for _, event := range r.outbox {
+		if event.WorkspaceID == nil || *event.WorkspaceID != workspaceID {
+			outbox = append(outbox, event)
+		}
+	}
+	r.outbox = outbox
+}
+
+// copyWebhook returns a copy of the webhook that shares no memory with it
+func copyWebhook(webhook *entity.Webhook) entity.Webhook {
+	stored := *webhook
+	stored.Events = append([]entity.TodoEventType(nil), webhook.Events...)
+	if webhook.WorkspaceID != nil {
+		workspaceID := *webhook.WorkspaceID
+		stored.WorkspaceID = &workspaceID
+	}
+	return stored
+}
+
+// copyDelivery returns a copy of the delivery that shares no memory with it,
+// without its Webhook
+func copyDelivery(delivery *entity.WebhookDelivery) entity.WebhookDelivery {
+	stored := *delivery
+	stored.Webhook = nil
+	if delivery.LastAttemptAt != nil {
+		lastAttemptAt := *delivery.LastAttemptAt
+		stored.LastAttemptAt = &lastAttemptAt
+	}
+	if delivery.WorkspaceID != nil {
+		workspaceID := *delivery.WorkspaceID
+		stored.WorkspaceID = &workspaceID
+	}
+	return stored
+}
+
+// copyOutboxEvent returns a copy of the outbox event that shares no memory with it
+func copyOutboxEvent(event *entity.OutboxEvent) entity.OutboxEvent {
+	stored := *event
+	activity := copyActivity(event.Event.Activity)
+	stored.Event.Activity = &activity
+	stored.Event.Todo = copyTodo(&event.Event.Todo)
+	if event.WorkspaceID != nil {
+		workspaceID := *event.WorkspaceID
+		stored.WorkspaceID = &workspaceID
+	}
+	return stored
+}
@@ -0,0 +1,273 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// workspaceMemberKey identifies a workspace membership
+type workspaceMemberKey struct {
+	workspaceID uint
+	userID      uint
+}
+
+// workspaceRepository implements repository.WorkspaceRepository
+type workspaceRepository struct {
+	mu         sync.RWMutex
+	workspaces map[uint]entity.Workspace
+	members    map[workspaceMemberKey]entity.WorkspaceMember
+	nextID     uint
+
+	// todos and projects hold the rows deleted with their workspace, or
+	// changed when a member leaves it
+	todos    *todoRepository
+	projects *projectRepository
+
+	// activities holds the activity log deleted with the workspace, nil
+	// until NewActivityRepository is given this repository
+	activities *activityRepository
+
+	// webhooks holds the webhooks deleted with the workspace, nil until
+	// NewWebhookRepository is given this repository
+	webhooks *webhookRepository
+}
+
+// NewWorkspaceRepository creates a new in-memory WorkspaceRepository. The todos
+// and projects of deleted workspaces are deleted from todoRepo and projectRepo,
+// which must have been created by NewTodoRepository and NewProjectRepository.
+func NewWorkspaceRepository(todoRepo repository.TodoRepository, projectRepo repository.ProjectRepository) repository.WorkspaceRepository {
+	return &workspaceRepository{
+		workspaces: make(map[uint]entity.Workspace),
+		members:    make(map[workspaceMemberKey]entity.WorkspaceMember),
+		nextID:     1,
+		todos:      todoRepo.(*todoRepository),
+		projects:   projectRepo.(*projectRepository),
+	}
+}
+
+// Create creates a new workspace with its first member
+func (r *workspaceRepository) Create(ctx context.Context, workspace *entity.Workspace, owner *entity.WorkspaceMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	workspace.ID = r.nextID
+	workspace.CreatedAt = now
+	workspace.UpdatedAt = now
+	r.nextID++
+	r.workspaces[workspace.ID] = *workspace
+
+	owner.WorkspaceID = workspace.ID
+	owner.CreatedAt = now
+	owner.UpdatedAt = now
+	r.members[workspaceMemberKey{owner.WorkspaceID, owner.UserID}] = copyWorkspaceMember(owner)
+	return nil
+}
+
+// GetByID retrieves a workspace by its ID
+func (r *workspaceRepository) GetByID(ctx context.Context, id uint) (*entity.Workspace, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	workspace, ok := r.workspaces[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &workspace, nil
+}
+
+// Update updates a workspace
+func (r *workspaceRepository) Update(ctx context.Context, workspace *entity.Workspace) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.workspaces[workspace.ID]; !ok {
+		return ErrNotFound
+	}
+
+	workspace.UpdatedAt = time.Now()
+	r.workspaces[workspace.ID] = *workspace
+	return nil
+}
+
+// Delete deletes a workspace with its members, projects, todos and activities
+func (r *workspaceRepository) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.projects.mu.Lock()
+	defer r.projects.mu.Unlock()
+
+	r.todos.mu.Lock()
+	defer r.todos.mu.Unlock()
+
+	for todoID, todo := range r.todos.todos {
+		if todo.WorkspaceID != nil && *todo.WorkspaceID == id {
+			r.todos.remove(todoID)
+		}
+	}
+	tombstones := r.todos.tombstones[:0]
+	for _, tombstone := range r.todos.tombstones {
+		if tombstone.WorkspaceID == nil || *tombstone.WorkspaceID != id {
+			tombstones = append(tombstones, tombstone)
+		}
+	}
+	r.todos.tombstones = tombstones
+	for key := range r.projects.members {
+		if r.projects.belongsTo(key.projectID, id) {
+			delete(r.projects.members, key)
+		}
+	}
+	for projectID := range r.projects.projects {
+		if r.projects.belongsTo(projectID, id) {
+			delete(r.projects.projects, projectID)
+		}
+	}
+	for key := range r.members {
+		if key.workspaceID == id {
+			delete(r.members, key)
+		}
+	}
+	if r.activities != nil {
+		r.activities.deleteByWorkspaceID(id)
+	}
+	if r.webhooks != nil {
+		r.webhooks.deleteByWorkspaceID(id)
+	}
+	delete(r.workspaces, id)
+	return nil
+}
+
+// GetMember retrieves the membership of a user in a workspace
+func (r *workspaceRepository) GetMember(ctx context.Context, workspaceID, userID uint) (*entity.WorkspaceMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	member, ok := r.members[workspaceMemberKey{workspaceID, userID}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &member, nil
+}
+
+// GetMembers retrieves the members of a workspace in the order they joined
+func (r *workspaceRepository) GetMembers(ctx context.Context, workspaceID uint) ([]*entity.WorkspaceMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := make([]*entity.WorkspaceMember, 0)
+	for key, member := range r.members {
+		if key.workspaceID == workspaceID {
+			member := member
+			members = append(members, &member)
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].CreatedAt.Equal(members[j].CreatedAt) {
+			return members[i].CreatedAt.Before(members[j].CreatedAt)
+		}
+		return members[i].UserID < members[j].UserID
+	})
+	return members, nil
+}
+
+// GetMemberships retrieves the memberships of a user with their workspaces, ordered by workspace name
+func (r *workspaceRepository) GetMemberships(ctx context.Context, userID uint) ([]*entity.WorkspaceMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := make([]*entity.WorkspaceMember, 0)
+	for key, member := range r.members {
+		if key.userID == userID {
+			member := member
+			member.Workspace = r.workspaces[key.workspaceID]
+			members = append(members, &member)
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Workspace.Name != members[j].Workspace.Name {
+			return members[i].Workspace.Name < members[j].Workspace.Name
+		}
+		return members[i].WorkspaceID < members[j].WorkspaceID
+	})
+	return members, nil
+}
+
+// AddMember adds a member to a workspace
+func (r *workspaceRepository) AddMember(ctx context.Context, member *entity.WorkspaceMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := workspaceMemberKey{member.WorkspaceID, member.UserID}
+	if _, ok := r.members[key]; ok {
+		return ErrDuplicateKey
+	}
+	if _, ok := r.workspaces[member.WorkspaceID]; !ok {
+		return ErrNotFound
+	}
+
+	now := time.Now()
+	member.CreatedAt = now
+	member.UpdatedAt = now
+	r.members[key] = copyWorkspaceMember(member)
+	return nil
+}
+
+// UpdateMember updates the role of a member
+func (r *workspaceRepository) UpdateMember(ctx context.Context, member *entity.WorkspaceMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := workspaceMemberKey{member.WorkspaceID, member.UserID}
+	if _, ok := r.members[key]; !ok {
+		return ErrNotFound
+	}
+
+	member.UpdatedAt = time.Now()
+	r.members[key] = copyWorkspaceMember(member)
+	return nil
+}
+
+// RemoveMember removes a member from a workspace and from its projects, and
+// unassigns their todos in it
+func (r *workspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.projects.mu.Lock()
+	defer r.projects.mu.Unlock()
+
+	r.todos.mu.Lock()
+	defer r.todos.mu.Unlock()
+
+	for todoID, todo := range r.todos.todos {
+		if todo.WorkspaceID != nil && *todo.WorkspaceID == workspaceID && todo.IsAssignedTo(userID) {
+			todo.Unassign()
+			todo.ChangeSeq = r.todos.nextChangeSeq()
+			r.todos.todos[todoID] = todo
+		}
+	}
+
+	for key := range r.projects.members {
+		if key.userID == userID && r.projects.belongsTo(key.projectID, workspaceID) {
+			delete(r.projects.members, key)
+		}
+	}
+	delete(r.members, workspaceMemberKey{workspaceID, userID})
+	return nil
+}
+
+// copyWorkspaceMember returns a copy of the membership without its loaded associations
+func copyWorkspaceMember(member *entity.WorkspaceMember) entity.WorkspaceMember {
+	stored := *member
+	stored.Workspace = entity.Workspace{}
+	stored.User = entity.User{}
+	return stored
+}
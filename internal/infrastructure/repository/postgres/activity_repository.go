@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// activityRepository implements repository.ActivityRepository
+type activityRepository struct {
+	db tenantDB
+}
+
+// NewActivityRepository creates a new ActivityRepository. With rowLevelSecurity
+// every query sets the workspace of its context for the row-level security policies.
+func NewActivityRepository(db *gorm.DB, rowLevelSecurity bool) repository.ActivityRepository {
+	return &activityRepository{
+		db: tenantDB{db: db, rowLevelSecurity: rowLevelSecurity},
+	}
+}
+
+// Create appends activities to the log in the workspace of the context
+func (r *activityRepository) Create(ctx context.Context, activities ...*entity.Activity) error {
+	if len(activities) == 0 {
+		return nil
+	}
+	return r.db.run(ctx, func(tx *gorm.DB) error {
+		return createActivities(ctx, tx, activities)
+	})
+}
+
+// createActivities appends activities to the log in the workspace of the
+// context, as part of the transaction tx
+func createActivities(ctx context.Context, tx *gorm.DB, activities []*entity.Activity) error {
+	if len(activities) == 0 {
+		return nil
+	}
+	for _, activity := range activities {
+		activity.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+	}
+	return tx.Create(&activities).Error
+}
+
+// GetByTodoID retrieves a page of the activities on a todo, oldest first
+func (r *activityRepository) GetByTodoID(ctx context.Context, todoID uint, page, pageSize int) ([]*entity.Activity, error) {
+	activities := make([]*entity.Activity, 0)
+	err := r.db.run(ctx, func(tx *gorm.DB) error {
+		return tx.Scopes(inWorkspace(ctx, "activities")).Where("todo_id = ?", todoID).Order("id ASC").
+			Offset((page - 1) * pageSize).Limit(pageSize).Find(&activities).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+// CountByTodoID counts the activities on a todo
+func (r *activityRepository) CountByTodoID(ctx context.Context, todoID uint) (int64, error) {
+	var count int64
+	err := r.db.run(ctx, func(tx *gorm.DB) error {
+		return tx.Model(&entity.Activity{}).Scopes(inWorkspace(ctx, "activities")).Where("todo_id = ?", todoID).Count(&count).Error
+	})
+	return count, err
+}
+
+// GetByUserID retrieves a page of the activities performed by a user, newest first
+func (r *activityRepository) GetByUserID(ctx context.Context, userID uint, page, pageSize int) ([]*entity.Activity, error) {
+	activities := make([]*entity.Activity, 0)
+	err := r.db.run(ctx, func(tx *gorm.DB) error {
+		return tx.Scopes(inWorkspace(ctx, "activities")).Where("user_id = ?", userID).Order("id DESC").
+			Offset((page - 1) * pageSize).Limit(pageSize).Find(&activities).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+// CountByUserID counts the activities performed by a user
+func (r *activityRepository) CountByUserID(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.run(ctx, func(tx *gorm.DB) error {
+		return tx.Model(&entity.Activity{}).Scopes(inWorkspace(ctx, "activities")).Where("user_id = ?", userID).Count(&count).Error
+	})
+	return count, err
+}
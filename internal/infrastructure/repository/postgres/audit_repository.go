@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// auditRepository implements repository.AuditRepository
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new AuditRepository
+func NewAuditRepository(db *gorm.DB) repository.AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+// Create appends an event to the log
+func (r *auditRepository) Create(ctx context.Context, event *entity.AuditEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// List retrieves a page of the events matching a filter, newest first
+func (r *auditRepository) List(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEvent, error) {
+	events := make([]*entity.AuditEvent, 0)
+	err := scopeAuditEvents(r.db.WithContext(ctx), filter).Order("id DESC").
+		Offset((filter.Page - 1) * filter.PageSize).Limit(filter.PageSize).Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Count counts the events matching a filter
+func (r *auditRepository) Count(ctx context.Context, filter entity.AuditFilter) (int64, error) {
+	var count int64
+	err := scopeAuditEvents(r.db.WithContext(ctx).Model(&entity.AuditEvent{}), filter).Count(&count).Error
+	return count, err
+}
+
+// scopeAuditEvents restricts a query to the events matching a filter
+func scopeAuditEvents(query *gorm.DB, filter entity.AuditFilter) *gorm.DB {
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("created_at < ?", filter.Until)
+	}
+	return query
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/domain/entity"
+)
+
+// changeSequenceName is the sequence allocating the change sequence numbers
+// of todos. Concurrent writes do not wait for each other to allocate them, so
+// they may commit out of order. The change_xid column, set by a trigger on
+// every write of todos and tombstones, lets delta sync tell which changes are
+// final, see GetChanges.
+const changeSequenceName = "todo_change_seq"
+
+// migrateChangeSequence creates the change sequence and the triggers setting
+// change_xid. The todos written before there were change sequence numbers are
+// numbered by ID, and the sequence continues after the numbers of the counter
+// table it replaces. The row-level security policies would hide the todos of
+// the workspaces from the numbering, so they stop applying to the owner of
+// the table until migrateRowLevelSecurity runs.
+func migrateChangeSequence(db *gorm.DB) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var exists bool
+		if err := tx.Raw("SELECT to_regclass(?) IS NOT NULL", changeSequenceName).Scan(&exists).Error; err != nil {
+			return err
+		}
+		if !exists {
+			statements := []string{
+				"ALTER TABLE todos NO FORCE ROW LEVEL SECURITY",
+				"ALTER TABLE todo_tombstones NO FORCE ROW LEVEL SECURITY",
+				"UPDATE todos SET change_seq = id WHERE change_seq = 0",
+				"CREATE SEQUENCE " + changeSequenceName,
+				`SELECT setval('` + changeSequenceName + `', GREATEST(
+					(SELECT COALESCE(MAX(change_seq), 0) FROM todos),
+					(SELECT COALESCE(MAX(change_seq), 0) FROM todo_tombstones)
+				) + 1, false)`,
+				"DROP TABLE IF EXISTS todo_change_sequence",
+			}
+			for _, statement := range statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		statements := []string{
+			`CREATE OR REPLACE FUNCTION set_change_xid() RETURNS trigger AS $$
+			BEGIN
+				NEW.change_xid := pg_current_xact_id()::text::bigint;
+				RETURN NEW;
+			END
+			$$ LANGUAGE plpgsql`,
+		}
+		for _, table := range []string{"todos", "todo_tombstones"} {
+			statements = append(statements,
+				fmt.Sprintf("DROP TRIGGER IF EXISTS set_change_xid ON %s", table),
+				fmt.Sprintf("CREATE TRIGGER set_change_xid BEFORE INSERT OR UPDATE ON %s FOR EACH ROW EXECUTE FUNCTION set_change_xid()", table),
+			)
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate change sequence: %w", err)
+	}
+	return nil
+}
+
+// nextChangeSeqs allocates n increasing change sequence numbers, which need
+// not be consecutive
+func nextChangeSeqs(tx *gorm.DB, n int) ([]uint64, error) {
+	seqs := make([]uint64, 0, n)
+	err := tx.Raw("SELECT nextval(?) FROM generate_series(1, ?) ORDER BY 1", changeSequenceName, n).Scan(&seqs).Error
+	if err != nil {
+		return nil, err
+	}
+	return seqs, nil
+}
+
+// unassignTodos unassigns the todos with the IDs from assigneeID in the
+// transaction tx, giving each a new change sequence number. Todos no longer
+// assigned to assigneeID are left alone.
+func unassignTodos(tx *gorm.DB, ids []uint, assigneeID uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	seqs, err := nextChangeSeqs(tx, len(ids))
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		err := tx.Model(&entity.Todo{}).Where("id = ? AND assignee_id = ?", id, assigneeID).
+			Updates(map[string]interface{}{"assignee_id": nil, "change_seq": seqs[i]}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// commentRepository implements repository.CommentRepository
+type commentRepository struct {
+	db tenantDB
+}
+
+// NewCommentRepository creates a new CommentRepository. With rowLevelSecurity
+// every query sets the workspace of its context for the row-level security policies.
+func NewCommentRepository(db *gorm.DB, rowLevelSecurity bool) repository.CommentRepository {
+	return &commentRepository{
+		db: tenantDB{db: db, rowLevelSecurity: rowLevelSecurity},
+	}
+}
+
+// Create creates a new comment in the workspace of the context
+func (r *commentRepository) Create(ctx context.Context, comment *entity.Comment) error {
+	comment.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+	return r.db.run(ctx, func(tx *gorm.DB) error {
+		return tx.Omit(clause.Associations).Create(comment).Error
+	})
+}
+
+// GetByID retrieves a comment by its ID
+func (r *commentRepository) GetByID(ctx context.Context, id uint) (*entity.Comment, error) {
+	var comment entity.Comment
+	err := r.db.run(ctx, func(tx *gorm.DB) error {
+		return tx.Scopes(inWorkspace(ctx, "comments")).First(&comment, id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetByTodoID retrieves a page of the comments on a todo, oldest first
+func (r *commentRepository) GetByTodoID(ctx context.Context, todoID uint, page, pageSize int) ([]*entity.Comment, error) {
+	comments := make([]*entity.Comment, 0)
+	err := r.db.run(ctx, func(tx *gorm.DB) error {
+		return tx.Scopes(inWorkspace(ctx, "comments")).Where("todo_id = ?", todoID).
+			Order("created_at ASC").Order("id ASC").
+			Offset((page - 1) * pageSize).Limit(pageSize).Find(&comments).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// CountByTodoID counts the comments on a todo
+func (r *commentRepository) CountByTodoID(ctx context.Context, todoID uint) (int64, error) {
+	var count int64
+	err := r.db.run(ctx, func(tx *gorm.DB) error {
+		return tx.Model(&entity.Comment{}).Scopes(inWorkspace(ctx, "comments")).Where("todo_id = ?", todoID).Count(&count).Error
+	})
+	return count, err
+}
+
+// Update updates a comment
+func (r *commentRepository) Update(ctx context.Context, comment *entity.Comment) error {
+	return r.db.run(ctx, func(tx *gorm.DB) error {
+		return tx.Scopes(inWorkspace(ctx, "comments")).Where("id = ?", comment.ID).
+			Select("*").Omit(clause.Associations).Updates(comment).Error
+	})
+}
+
+// Delete deletes a comment
+func (r *commentRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.run(ctx, func(tx *gorm.DB) error {
+		return tx.Scopes(inWorkspace(ctx, "comments")).Delete(&entity.Comment{}, id).Error
+	})
+}
@@ -1,7 +1,10 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
+	"net/url"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -11,24 +14,199 @@ import (
 	"todo-api/internal/domain/entity"
 )
 
-// NewPostgresDB creates a new PostgreSQL database connection
-func NewPostgresDB(config config.DatabaseConfig) (*gorm.DB, error) {
-	dsn := config.GetDSN()
-	
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
+// maxConnectBackoff caps the delay between connection attempts
+const maxConnectBackoff = 30 * time.Second
+
+// NewPostgresDB creates a new PostgreSQL database connection.
+// It retries with exponential backoff while the database is not reachable,
+// until ctx is done, and applies the configured connection pool settings.
+func NewPostgresDB(ctx context.Context, config config.DatabaseConfig) (*gorm.DB, error) {
+	dsn := withStatementTimeout(config, config.GetDSN())
+
+	var db *gorm.DB
+	var err error
+	backoff := config.ConnectBackoff
+	for attempt := 1; ; attempt++ {
+		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Info),
+		})
+		if err == nil {
+			break
+		}
+		if attempt >= config.ConnectAttempts {
+			return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", attempt, err)
+		}
+
+		logger.Default.Warn(ctx, "database not reachable (attempt %d/%d), retrying in %s: %v",
+			attempt, config.ConnectAttempts, backoff, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("gave up connecting to database after %d attempts: %w", attempt, ctx.Err())
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > maxConnectBackoff {
+			backoff = maxConnectBackoff
+		}
+	}
+
+	// Apply connection pool settings
+	sqlDB, err := db.DB()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, fmt.Errorf("failed to configure connection pool: %w", err)
 	}
-	
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+
 	return db, nil
 }
 
-// AutoMigrate runs database migrations
-func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+// withStatementTimeout adds the statement_timeout runtime parameter to the DSN
+// so that it applies to every pooled connection
+func withStatementTimeout(config config.DatabaseConfig, dsn string) string {
+	if config.StatementTimeout <= 0 {
+		return dsn
+	}
+	timeout := config.StatementTimeout.Milliseconds()
+
+	if config.URL != "" {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return dsn
+		}
+		query := u.Query()
+		query.Set("statement_timeout", fmt.Sprint(timeout))
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+
+	return fmt.Sprintf("%s statement_timeout=%d", dsn, timeout)
+}
+
+// SchemaVersion is the version of the schema created by AutoMigrate, it is
+// increased with every change of the migrations
+const SchemaVersion = 4
+
+// Models returns the entities managed by AutoMigrate
+func Models() []interface{} {
+	return []interface{}{
 		&entity.User{},
 		&entity.Todo{},
-	)
+		&entity.TodoTombstone{},
+		&entity.Comment{},
+		&entity.Activity{},
+		&entity.Webhook{},
+		&entity.WebhookDelivery{},
+		&entity.OutboxEvent{},
+		&entity.View{},
+		&entity.Project{},
+		&entity.ProjectMember{},
+		&entity.Workspace{},
+		&entity.WorkspaceMember{},
+		&entity.AuditEvent{},
+	}
+}
+
+// AutoMigrate runs database migrations. rowLevelSecurity enables or disables
+// the policies isolating workspaces.
+func AutoMigrate(db *gorm.DB, rowLevelSecurity bool) error {
+	if err := db.AutoMigrate(Models()...); err != nil {
+		return err
+	}
+	if err := migrateSearch(db); err != nil {
+		return err
+	}
+	if err := migrateChangeSequence(db); err != nil {
+		return err
+	}
+	if err := migrateAuditLog(db); err != nil {
+		return err
+	}
+	return migrateRowLevelSecurity(db, rowLevelSecurity)
+}
+
+// migrateAuditLog makes the audit log append-only, rows can neither be changed
+// nor deleted through the application role
+func migrateAuditLog(db *gorm.DB) error {
+	statements := []string{
+		`CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit events are append-only';
+		END
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events`,
+		`CREATE TRIGGER audit_events_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_events
+			FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only()`,
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to migrate audit log: %w", err)
+		}
+	}
+	return nil
+}
+
+// migrateSearch adds the full-text search vector of todos, which Postgres keeps
+// up to date as a generated column, and its GIN index
+func migrateSearch(db *gorm.DB) error {
+	statements := []string{
+		fmt.Sprintf(`ALTER TABLE todos ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('%[1]s'::regconfig, coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('%[1]s'::regconfig, coalesce(description, '')), 'B')
+		) STORED`, searchConfig),
+		`CREATE INDEX IF NOT EXISTS idx_todos_search_vector ON todos USING GIN (search_vector)`,
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to migrate todo search: %w", err)
+		}
+	}
+	return nil
+}
+
+// workspacePolicies are the row-level security policies restricting the rows
+// of each table to the workspace set by setWorkspace. Project members are
+// visible through their projects.
+var workspacePolicies = []struct {
+	table     string
+	condition string
+}{
+	{"todos", "workspace_id IS NOT DISTINCT FROM NULLIF(current_setting('" + workspaceSetting + "', true), '')::bigint"},
+	{"projects", "workspace_id IS NOT DISTINCT FROM NULLIF(current_setting('" + workspaceSetting + "', true), '')::bigint"},
+	{"comments", "workspace_id IS NOT DISTINCT FROM NULLIF(current_setting('" + workspaceSetting + "', true), '')::bigint"},
+	{"activities", "workspace_id IS NOT DISTINCT FROM NULLIF(current_setting('" + workspaceSetting + "', true), '')::bigint"},
+	{"todo_tombstones", "workspace_id IS NOT DISTINCT FROM NULLIF(current_setting('" + workspaceSetting + "', true), '')::bigint"},
+	{"project_members", "EXISTS (SELECT 1 FROM projects WHERE projects.id = project_members.project_id)"},
+}
+
+// migrateRowLevelSecurity creates or drops the workspace isolation policies.
+// They are forced so that they also apply to the owner of the tables, but
+// superusers and roles with BYPASSRLS are never restricted.
+func migrateRowLevelSecurity(db *gorm.DB, enabled bool) error {
+	statements := make([]string, 0, 4*len(workspacePolicies))
+	for _, policy := range workspacePolicies {
+		statements = append(statements, fmt.Sprintf("DROP POLICY IF EXISTS workspace_isolation ON %s", policy.table))
+		if enabled {
+			statements = append(statements,
+				fmt.Sprintf("CREATE POLICY workspace_isolation ON %s USING (%s) WITH CHECK (%s)", policy.table, policy.condition, policy.condition),
+				fmt.Sprintf("ALTER TABLE %s ENABLE ROW LEVEL SECURITY", policy.table),
+				fmt.Sprintf("ALTER TABLE %s FORCE ROW LEVEL SECURITY", policy.table),
+			)
+		} else {
+			statements = append(statements,
+				fmt.Sprintf("ALTER TABLE %s NO FORCE ROW LEVEL SECURITY", policy.table),
+				fmt.Sprintf("ALTER TABLE %s DISABLE ROW LEVEL SECURITY", policy.table),
+			)
+		}
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to migrate row-level security: %w", err)
+		}
+	}
+	return nil
 }
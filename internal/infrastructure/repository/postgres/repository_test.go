@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/config"
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository/repositorytest"
+)
+
+// TestRepositories needs an empty database, whose URL is TEST_DATABASE_URL. It
+// is skipped when the variable is not set.
+func TestRepositories(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := NewPostgresDB(context.Background(), config.DatabaseConfig{URL: url, MaxOpenConns: 10, MaxIdleConns: 2, ConnectAttempts: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	if err := AutoMigrate(db, false); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repositorytest.TestRepositories(
+		NewUserRepository(db),
+		NewTodoRepository(db, false),
+		NewViewRepository(db),
+		NewProjectRepository(db, false),
+		NewWorkspaceRepository(db),
+		NewCommentRepository(db, false),
+		NewActivityRepository(db, false),
+		NewAuditRepository(db),
+		NewWebhookRepository(db),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testChangeWatermark(db); err != nil {
+		t.Fatalf("change watermark: %v", err)
+	}
+}
+
+// testChangeWatermark checks that delta sync does not skip the changes of a
+// transaction that commits after a later one
+func testChangeWatermark(db *gorm.DB) error {
+	ctx := context.Background()
+	user := entity.NewUser("watermark", "watermark@example.com", "hashed")
+	if err := NewUserRepository(db).Create(ctx, user); err != nil {
+		return err
+	}
+	todoRepo := NewTodoRepository(db, false)
+	start, err := todoRepo.GetChanges(ctx, entity.TodoChangeFilter{UserID: user.ID, Limit: 10})
+	if err != nil {
+		return err
+	}
+
+	tx := db.Begin()
+	defer tx.Rollback()
+	if err := NewTodoRepository(tx, false).Create(ctx, &entity.Todo{Title: "first", UserID: user.ID}); err != nil {
+		return err
+	}
+	if err := todoRepo.Create(ctx, &entity.Todo{Title: "second", UserID: user.ID}); err != nil {
+		return err
+	}
+
+	// The second todo waits for the transaction of the first
+	filter := entity.TodoChangeFilter{UserID: user.ID, Since: start.Until, Limit: 10}
+	changes, err := todoRepo.GetChanges(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if len(changes.Todos) != 0 || changes.Until.Before(start.Until) {
+		return fmt.Errorf("GetChanges: got %d todos until %+v while an earlier write is running, want none after %+v", len(changes.Todos), changes.Until, start.Until)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	changes, err = todoRepo.GetChanges(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if len(changes.Todos) != 2 || changes.Todos[0].Title != "first" || changes.Todos[1].Title != "second" {
+		return fmt.Errorf("GetChanges: got %d todos after the commit, want first and second", len(changes.Todos))
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// workspaceSetting is the run-time parameter holding the workspace of a
+// transaction for the row-level security policies
+const workspaceSetting = "app.workspace_id"
+
+// tenantDB runs the queries of the repositories of workspace-scoped tables
+type tenantDB struct {
+	db *gorm.DB
+
+	// rowLevelSecurity sets the workspace of the context for the policies
+	// created by migrateRowLevelSecurity before every query
+	rowLevelSecurity bool
+}
+
+// run calls fn with a session for ctx. With row-level security fn runs in a
+// transaction that has the workspace of the context set.
+func (t tenantDB) run(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	db := t.db.WithContext(ctx)
+	if !t.rowLevelSecurity {
+		return fn(db)
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := setWorkspace(tx, repository.WorkspaceIDFromContext(ctx)); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// setWorkspace sets the workspace seen by the row-level security policies
+// until the end of the transaction, nil selects the rows outside any workspace
+func setWorkspace(tx *gorm.DB, workspaceID *uint) error {
+	value := ""
+	if workspaceID != nil {
+		value = fmt.Sprint(*workspaceID)
+	}
+	return tx.Exec("SELECT set_config(?, ?, true)", workspaceSetting, value).Error
+}
+
+// inWorkspace restricts a query to the rows of table in the workspace of the
+// context, or to those outside any workspace
+func inWorkspace(ctx context.Context, table string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if workspaceID, ok := repository.WorkspaceFromContext(ctx); ok {
+			return db.Where(table+".workspace_id = ?", workspaceID)
+		}
+		return db.Where(table + ".workspace_id IS NULL")
+	}
+}
+
+// inWorkspaceProjects restricts a query on project members to the projects in
+// the workspace of the context
+func inWorkspaceProjects(ctx context.Context, db *gorm.DB) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		projects := db.Session(&gorm.Session{NewDB: true}).Model(&entity.Project{}).
+			Select("projects.id").Scopes(inWorkspace(ctx, "projects"))
+		return query.Where("project_members.project_id IN (?)", projects)
+	}
+}
@@ -3,100 +3,598 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"todo-api/internal/domain/entity"
 	"todo-api/internal/domain/repository"
 )
 
+// searchConfig is the text search configuration that stems the words of todos
+const searchConfig = "english"
+
+// Highlight options of ts_headline. Titles are highlighted in full, descriptions
+// are shortened to the fragments around the matches.
+var (
+	titleHighlightOptions = fmt.Sprintf(`StartSel="%s", StopSel="%s", HighlightAll=true`,
+		entity.HighlightStart, entity.HighlightEnd)
+	descriptionHighlightOptions = fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=" … "`,
+		entity.HighlightStart, entity.HighlightEnd)
+)
+
 // todoRepository implements repository.TodoRepository
 type todoRepository struct {
-	db *gorm.DB
+	db tenantDB
 }
 
-// NewTodoRepository creates a new TodoRepository
-func NewTodoRepository(db *gorm.DB) repository.TodoRepository {
+// NewTodoRepository creates a new TodoRepository. With rowLevelSecurity every
+// query sets the workspace of its context for the row-level security policies.
+func NewTodoRepository(db *gorm.DB, rowLevelSecurity bool) repository.TodoRepository {
 	return &todoRepository{
-		db: db,
+		db: tenantDB{db: db, rowLevelSecurity: rowLevelSecurity},
 	}
 }
 
-// Create creates a new todo
-func (r *todoRepository) Create(ctx context.Context, todo *entity.Todo) error {
-	return r.db.WithContext(ctx).Create(todo).Error
+// Create creates a new todo in the workspace of the context
+func (r *todoRepository) Create(ctx context.Context, todo *entity.Todo, activities ...*entity.Activity) error {
+	todo.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+	return r.db.run(ctx, func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			seqs, err := nextChangeSeqs(tx, 1)
+			if err != nil {
+				return err
+			}
+			todo.ChangeSeq = seqs[0]
+			if err := tx.Create(todo).Error; err != nil {
+				return err
+			}
+			for _, activity := range activities {
+				activity.TodoID = todo.ID
+			}
+			return recordEvents(ctx, tx, activities, todo)
+		})
+	})
 }
 
 // GetByID retrieves a todo by its ID
 func (r *todoRepository) GetByID(ctx context.Context, id uint) (*entity.Todo, error) {
 	var todo entity.Todo
-	err := r.db.WithContext(ctx).First(&todo, id).Error
+	err := r.db.run(ctx, func(tx *gorm.DB) error {
+		return tx.Scopes(inWorkspace(ctx, "todos")).First(&todo, id).Error
+	})
 	if err != nil {
 		return nil, err
 	}
 	return &todo, nil
 }
 
+// GetByIDs retrieves the existing todos among ids
+func (r *todoRepository) GetByIDs(ctx context.Context, ids []uint) ([]*entity.Todo, error) {
+	todos := make([]*entity.Todo, 0, len(ids))
+	if len(ids) == 0 {
+		return todos, nil
+	}
+	err := r.db.run(ctx, func(tx *gorm.DB) error {
+		return tx.Scopes(inWorkspace(ctx, "todos")).Where("id IN ?", ids).Find(&todos).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
 // GetByUserID retrieves todos for a specific user
 func (r *todoRepository) GetByUserID(ctx context.Context, userID uint, filter entity.TodoFilter) ([]*entity.Todo, error) {
 	var todos []*entity.Todo
-	
-	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
-	
-	// Apply filters
-	if filter.Completed != nil {
-		query = query.Where("completed = ?", *filter.Completed)
-	}
-	
-	// Apply search if provided
-	if filter.Search != "" {
-		searchQuery := fmt.Sprintf("%%%s%%", strings.ToLower(filter.Search))
-		query = query.Where("LOWER(title) LIKE ? OR LOWER(description) LIKE ?", searchQuery, searchQuery)
-	}
-	
-	// Apply pagination
-	offset := (filter.Page - 1) * filter.PageSize
-	query = query.Offset(offset).Limit(filter.PageSize)
-	
-	// Apply ordering
-	query = query.Order("created_at DESC")
-	
-	err := query.Find(&todos).Error
+
+	err := r.db.run(ctx, func(tx *gorm.DB) error {
+		query := applyTodoFilter(scopeTodos(tx.Scopes(inWorkspace(ctx, "todos")), userID, filter), filter)
+
+		// Apply pagination and ordering
+		query = applyTodoPage(query, filter)
+
+		return query.Find(&todos).Error
+	})
 	if err != nil {
 		return nil, err
 	}
-	
+	if filter.Cursor != nil && filter.Cursor.Backward && !filter.Cursor.IsZero() {
+		reverseTodos(todos)
+	}
+
 	return todos, nil
 }
 
 // Update updates a todo
-func (r *todoRepository) Update(ctx context.Context, todo *entity.Todo) error {
-	return r.db.WithContext(ctx).Save(todo).Error
+func (r *todoRepository) Update(ctx context.Context, todo *entity.Todo, activities ...*entity.Activity) error {
+	return r.db.run(ctx, func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			seqs, err := nextChangeSeqs(tx, 1)
+			if err != nil {
+				return err
+			}
+			todo.ChangeSeq = seqs[0]
+			result := updateTodo(tx.Scopes(inWorkspace(ctx, "todos")), todo)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			return recordEvents(ctx, tx, activities, todo)
+		})
+	})
+}
+
+// UpdateIfUnchanged updates a todo unless it was changed since it was read
+func (r *todoRepository) UpdateIfUnchanged(ctx context.Context, todo *entity.Todo, activities ...*entity.Activity) error {
+	read := todo.ChangeSeq
+	err := r.db.run(ctx, func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			seqs, err := nextChangeSeqs(tx, 1)
+			if err != nil {
+				return err
+			}
+			todo.ChangeSeq = seqs[0]
+			result := updateTodo(tx.Scopes(inWorkspace(ctx, "todos")).Where("change_seq = ?", read), todo)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return repository.ErrTodoChanged
+			}
+			return recordEvents(ctx, tx, activities, todo)
+		})
+	})
+	if err != nil {
+		todo.ChangeSeq = read
+	}
+	return err
 }
 
 // Delete deletes a todo
-func (r *todoRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&entity.Todo{}, id).Error
+func (r *todoRepository) Delete(ctx context.Context, id uint, activities ...*entity.Activity) error {
+	return r.db.run(ctx, func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			seqs, err := nextChangeSeqs(tx, 1)
+			if err != nil {
+				return err
+			}
+			deleted, err := deleteTodos(ctx, tx, seqs, "id = ?", id)
+			if err != nil || len(deleted) == 0 {
+				return err
+			}
+			return recordEvents(ctx, tx, activities, deleted...)
+		})
+	})
+}
+
+// DeleteIfUnchanged deletes a todo unless its change sequence number moved past changeSeq
+func (r *todoRepository) DeleteIfUnchanged(ctx context.Context, id uint, changeSeq uint64, activities ...*entity.Activity) error {
+	return r.db.run(ctx, func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			seqs, err := nextChangeSeqs(tx, 1)
+			if err != nil {
+				return err
+			}
+			deleted, err := deleteTodos(ctx, tx, seqs, "id = ? AND change_seq = ?", id, changeSeq)
+			if err != nil {
+				return err
+			}
+			if len(deleted) == 0 {
+				return repository.ErrTodoChanged
+			}
+			return recordEvents(ctx, tx, activities, deleted...)
+		})
+	})
+}
+
+// ApplyChanges saves the updated todos and deletes the deleted ones in a
+// single transaction unless one of them was changed since it was read
+func (r *todoRepository) ApplyChanges(ctx context.Context, updated, deleted []*entity.Todo, activities ...*entity.Activity) error {
+	reads := changeSeqsOf(updated)
+	err := r.db.run(ctx, func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			if len(updated) == 0 && len(deleted) == 0 {
+				return nil
+			}
+			seqs, err := nextChangeSeqs(tx, len(updated)+len(deleted))
+			if err != nil {
+				return err
+			}
+			for i, todo := range updated {
+				read := todo.ChangeSeq
+				todo.ChangeSeq = seqs[i]
+				result := updateTodo(tx.Scopes(inWorkspace(ctx, "todos")).Where("change_seq = ?", read), todo)
+				if result.Error != nil {
+					return result.Error
+				}
+				if result.RowsAffected == 0 {
+					return repository.ErrTodoChanged
+				}
+			}
+			for i, todo := range deleted {
+				removed, err := deleteTodos(ctx, tx, seqs[len(updated)+i:], "id = ? AND change_seq = ?", todo.ID, todo.ChangeSeq)
+				if err != nil {
+					return err
+				}
+				if len(removed) == 0 {
+					return repository.ErrTodoChanged
+				}
+			}
+			return recordEvents(ctx, tx, activities, append(append([]*entity.Todo(nil), updated...), deleted...)...)
+		})
+	})
+	if err != nil {
+		restoreChangeSeqs(updated, reads)
+	}
+	return err
+}
+
+// GetChanges retrieves the todos written and the tombstones of the todos deleted after filter.Since
+func (r *todoRepository) GetChanges(ctx context.Context, filter entity.TodoChangeFilter) (*entity.TodoChanges, error) {
+	changes := &entity.TodoChanges{}
+	err := r.db.run(ctx, func(tx *gorm.DB) error {
+		// The transactions before the oldest one still running have ended, so
+		// their changes are final. The later ones may still commit changes
+		// numbered before those already committed.
+		var xmin uint64
+		if err := tx.Raw("SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint").Scan(&xmin).Error; err != nil {
+			return err
+		}
+		changes.Until = entity.ChangePosition{XID: xmin - 1, Seq: math.MaxInt64}
+
+		err := tx.Scopes(inWorkspace(ctx, "todos"), inChangeScope(filter, changes.Until)).
+			Order("change_xid, change_seq").Limit(filter.Limit).Find(&changes.Todos).Error
+		if err != nil {
+			return err
+		}
+		return tx.Scopes(inWorkspace(ctx, "todo_tombstones"), inChangeScope(filter, changes.Until)).
+			Order("change_xid, change_seq").Limit(filter.Limit).Find(&changes.Tombstones).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// changeSeqsOf returns the change sequence numbers the todos were read with
+func changeSeqsOf(todos []*entity.Todo) []uint64 {
+	seqs := make([]uint64, len(todos))
+	for i, todo := range todos {
+		seqs[i] = todo.ChangeSeq
+	}
+	return seqs
+}
+
+// restoreChangeSeqs restores the change sequence numbers of todos whose write failed
+func restoreChangeSeqs(todos []*entity.Todo, seqs []uint64) {
+	for i, todo := range todos {
+		todo.ChangeSeq = seqs[i]
+	}
+}
+
+// updateTodo saves all fields of a todo. Unlike Save it never inserts a todo
+// that the scope of the query excludes.
+func updateTodo(query *gorm.DB, todo *entity.Todo) *gorm.DB {
+	return query.Where("id = ?", todo.ID).Select("*").Omit(clause.Associations).Updates(todo)
+}
+
+// deleteTodos deletes the todos of the workspace of the context matching the
+// conditions in the transaction tx, and records them as tombstones numbered
+// by seqs. The caller allocates a change sequence number for every todo that
+// may match. It returns the deleted todos.
+func deleteTodos(ctx context.Context, tx *gorm.DB, seqs []uint64, query interface{}, args ...interface{}) ([]*entity.Todo, error) {
+	var todos []*entity.Todo
+	if err := tx.Scopes(inWorkspace(ctx, "todos")).Where(query, args...).Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	if len(todos) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, len(todos))
+	tombstones := make([]*entity.TodoTombstone, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+		tombstones[i] = entity.NewTodoTombstone(todo, seqs[i])
+	}
+	if err := tx.Create(&tombstones).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("id IN ?", ids).Delete(&entity.Todo{}).Error; err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// recordEvents records the activities of a write in the transaction tx, and
+// adds their events to the outbox with the snapshots of the written todos
+func recordEvents(ctx context.Context, tx *gorm.DB, activities []*entity.Activity, todos ...*entity.Todo) error {
+	if err := createActivities(ctx, tx, activities); err != nil {
+		return err
+	}
+	events := entity.NewOutboxEvents(activities, todos)
+	if len(events) == 0 {
+		return nil
+	}
+	return tx.Create(&events).Error
+}
+
+// inChangeScope restricts a query on todos or tombstones to the personal todos
+// of filter.UserID and the todos of filter.ProjectIDs, changed after
+// filter.Since and up to until
+func inChangeScope(filter entity.TodoChangeFilter, until entity.ChangePosition) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("(change_xid, change_seq) > (?, ?) AND (change_xid, change_seq) <= (?, ?)",
+			filter.Since.XID, filter.Since.Seq, until.XID, until.Seq)
+		if len(filter.ProjectIDs) == 0 {
+			return db.Where("user_id = ? AND project_id IS NULL", filter.UserID)
+		}
+		return db.Where("((user_id = ? AND project_id IS NULL) OR project_id IN ?)", filter.UserID, filter.ProjectIDs)
+	}
 }
 
 // Count counts todos based on filter
 func (r *todoRepository) Count(ctx context.Context, filter entity.TodoFilter) (int64, error) {
 	var count int64
-	
-	query := r.db.WithContext(ctx).Model(&entity.Todo{}).Where("user_id = ?", filter.UserID)
-	
-	// Apply filters
+
+	err := r.db.run(ctx, func(tx *gorm.DB) error {
+		query := scopeTodos(tx.Model(&entity.Todo{}).Scopes(inWorkspace(ctx, "todos")), filter.UserID, filter)
+		query = applyTodoFilter(query, filter)
+
+		return query.Count(&count).Error
+	})
+	return count, err
+}
+
+// todoSearchRow is a todo matching a full-text search with its rank and highlights
+type todoSearchRow struct {
+	ID                   uint
+	Title                string
+	Description          string
+	Completed            bool
+	UserID               uint
+	ProjectID            *uint
+	AssigneeID           *uint
+	WorkspaceID          *uint
+	Tags                 []string `gorm:"serializer:json"`
+	ChangeSeq            uint64
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Rank                 float64
+	TitleHighlight       string
+	DescriptionHighlight string
+}
+
+// Search retrieves a page of the todos matching filter.FullText, most relevant first
+func (r *todoRepository) Search(ctx context.Context, filter entity.TodoFilter) ([]*entity.TodoMatch, error) {
+	if filter.FullText == nil {
+		return []*entity.TodoMatch{}, nil
+	}
+
+	tsquery := tsQuery(*filter.FullText)
+	var rows []todoSearchRow
+	err := r.db.run(ctx, func(tx *gorm.DB) error {
+		query := tx.Model(&entity.Todo{}).Scopes(inWorkspace(ctx, "todos")).
+			Select(`id, title, description, completed, user_id, project_id, assignee_id, workspace_id, tags, change_seq, created_at, updated_at,
+				ts_rank_cd(search_vector, to_tsquery(?::regconfig, ?)) AS rank,
+				ts_headline(?::regconfig, title, to_tsquery(?::regconfig, ?), ?) AS title_highlight,
+				ts_headline(?::regconfig, coalesce(description, ''), to_tsquery(?::regconfig, ?), ?) AS description_highlight`,
+				searchConfig, tsquery,
+				searchConfig, searchConfig, tsquery, titleHighlightOptions,
+				searchConfig, searchConfig, tsquery, descriptionHighlightOptions,
+			)
+		query = scopeTodos(query, filter.UserID, filter)
+		query = applyTodoFilter(query, filter)
+
+		offset := (filter.Page - 1) * filter.PageSize
+		query = query.Order("rank DESC").Order("created_at DESC").Order("id DESC").Offset(offset).Limit(filter.PageSize)
+
+		return query.Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*entity.TodoMatch, 0, len(rows))
+	for _, row := range rows {
+		matches = append(matches, &entity.TodoMatch{
+			Todo: &entity.Todo{
+				ID:          row.ID,
+				Title:       row.Title,
+				Description: row.Description,
+				Completed:   row.Completed,
+				UserID:      row.UserID,
+				ProjectID:   row.ProjectID,
+				AssigneeID:  row.AssigneeID,
+				WorkspaceID: row.WorkspaceID,
+				Tags:        row.Tags,
+				ChangeSeq:   row.ChangeSeq,
+				CreatedAt:   row.CreatedAt,
+				UpdatedAt:   row.UpdatedAt,
+			},
+			Rank:                 row.Rank,
+			TitleHighlight:       row.TitleHighlight,
+			DescriptionHighlight: row.DescriptionHighlight,
+		})
+	}
+
+	return matches, nil
+}
+
+// scopeTodos restricts a query to the todos of the project selected by the
+// filter, to the todos assigned to filter.AssigneeID or to the personal todos
+// of userID. Assignees can access the todos assigned to them, see RemoveMember.
+func scopeTodos(query *gorm.DB, userID uint, filter entity.TodoFilter) *gorm.DB {
+	if filter.AssigneeID != nil {
+		query = query.Where("assignee_id = ?", *filter.AssigneeID)
+	}
+	if filter.ProjectID != nil {
+		return query.Where("project_id = ?", *filter.ProjectID)
+	}
+	if filter.AssigneeID != nil {
+		return query
+	}
+	return query.Where("user_id = ? AND project_id IS NULL", userID)
+}
+
+// applyTodoFilter applies the completed, search, expression and full-text filters to a query
+func applyTodoFilter(query *gorm.DB, filter entity.TodoFilter) *gorm.DB {
 	if filter.Completed != nil {
 		query = query.Where("completed = ?", *filter.Completed)
 	}
-	
-	// Apply search if provided
+
 	if filter.Search != "" {
-		searchQuery := fmt.Sprintf("%%%s%%", strings.ToLower(filter.Search))
-		query = query.Where("LOWER(title) LIKE ? OR LOWER(description) LIKE ?", searchQuery, searchQuery)
+		pattern := "%" + escapeLike(strings.ToLower(filter.Search)) + "%"
+		query = query.Where(`(LOWER(title) LIKE ? ESCAPE '\' OR LOWER(description) LIKE ? ESCAPE '\')`, pattern, pattern)
+	}
+
+	if filter.Expr != nil {
+		sql, args := filterSQL(filter.Expr)
+		query = query.Where(sql, args...)
+	}
+
+	if filter.FullText != nil {
+		query = query.Where("search_vector @@ to_tsquery(?::regconfig, ?)", searchConfig, tsQuery(*filter.FullText))
+	}
+
+	return query
+}
+
+// tsQuery converts a search query to the to_tsquery syntax. Phrases become
+// followed-by operators, prefixes :* and negated terms are prefixed with !.
+func tsQuery(query entity.SearchQuery) string {
+	parts := make([]string, 0, len(query.Terms))
+	for _, term := range query.Terms {
+		lexemes := make([]string, len(term.Words))
+		for i, word := range term.Words {
+			lexemes[i] = "'" + strings.ReplaceAll(word, "'", "''") + "'"
+		}
+		if term.Prefix {
+			lexemes[len(lexemes)-1] += ":*"
+		}
+
+		part := strings.Join(lexemes, " <-> ")
+		if term.Negated {
+			part = "!(" + part + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " & ")
+}
+
+// filterColumns maps the filterable fields to their columns
+var filterColumns = map[entity.FilterField]string{
+	entity.FilterFieldCompleted:   "completed",
+	entity.FilterFieldTitle:       "title",
+	entity.FilterFieldDescription: "description",
+	entity.FilterFieldCreated:     "created_at",
+	entity.FilterFieldUpdated:     "updated_at",
+	entity.FilterFieldTag:         "tags",
+}
+
+// filterOperators maps the comparison operators to SQL
+var filterOperators = map[entity.FilterOp]string{
+	entity.FilterOpEq: "=",
+	entity.FilterOpNe: "<>",
+	entity.FilterOpLt: "<",
+	entity.FilterOpLe: "<=",
+	entity.FilterOpGt: ">",
+	entity.FilterOpGe: ">=",
+}
+
+// filterSQL translates a filter expression to a parameterized SQL condition.
+// Columns and operators come from fixed tables, values are always bound.
+func filterSQL(expr entity.FilterExpr) (string, []interface{}) {
+	switch e := expr.(type) {
+	case entity.FilterAnd:
+		return joinFilterSQL(e.Exprs, " AND ")
+	case entity.FilterOr:
+		return joinFilterSQL(e.Exprs, " OR ")
+	case entity.FilterNot:
+		sql, args := filterSQL(e.Expr)
+		return "NOT (" + sql + ")", args
+	case entity.FilterCondition:
+		column, ok := filterColumns[e.Field]
+		if !ok {
+			break
+		}
+		if tag, ok := e.Value.(string); ok && e.Field == entity.FilterFieldTag {
+			// tags holds the JSON array of the tags, match the quoted tag in it
+			like := "LIKE"
+			if e.Op == entity.FilterOpNe {
+				like = "NOT LIKE"
+			}
+			return "COALESCE(" + column + ", '') " + like + ` ? ESCAPE '\'`, []interface{}{`%"` + escapeLike(tag) + `"%`}
+		}
+		if text, ok := e.Value.(string); ok {
+			if e.Op == entity.FilterOpContains {
+				return "LOWER(" + column + `) LIKE ? ESCAPE '\'`, []interface{}{"%" + escapeLike(strings.ToLower(text)) + "%"}
+			}
+			column, e.Value = "LOWER("+column+")", strings.ToLower(text)
+		}
+		if op, ok := filterOperators[e.Op]; ok {
+			return column + " " + op + " ?", []interface{}{e.Value}
+		}
+	}
+	// Match nothing rather than everything on an expression that cannot be translated
+	return "1 = 0", nil
+}
+
+// joinFilterSQL translates and joins expressions with a logical operator
+func joinFilterSQL(exprs []entity.FilterExpr, operator string) (string, []interface{}) {
+	parts := make([]string, len(exprs))
+	var args []interface{}
+	for i, expr := range exprs {
+		sql, exprArgs := filterSQL(expr)
+		parts[i] = sql
+		args = append(args, exprArgs...)
+	}
+	return "(" + strings.Join(parts, operator) + ")", args
+}
+
+// escapeLike escapes the LIKE wildcards in s, so that it matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// todoOrders maps the todo sort orders to ORDER BY clauses
+var todoOrders = map[entity.TodoSort]string{
+	entity.TodoSortCreatedDesc: "created_at DESC, id DESC",
+	entity.TodoSortCreatedAsc:  "created_at ASC, id ASC",
+	entity.TodoSortUpdatedDesc: "updated_at DESC, id DESC",
+	entity.TodoSortTitleAsc:    "LOWER(title) ASC, id ASC",
+}
+
+// applyTodoPage applies offset pagination in the order of the filter, or keyset pagination newest first.
+// Backward keyset pages are selected in ascending order and must be reversed.
+func applyTodoPage(query *gorm.DB, filter entity.TodoFilter) *gorm.DB {
+	cursor := filter.Cursor
+	if cursor == nil {
+		order, ok := todoOrders[filter.Sort]
+		if !ok {
+			order = todoOrders[entity.TodoSortCreatedDesc]
+		}
+		offset := (filter.Page - 1) * filter.PageSize
+		return query.Order(order).Offset(offset).Limit(filter.PageSize)
+	}
+
+	if cursor.IsZero() {
+		return query.Order("created_at DESC").Order("id DESC").Limit(filter.PageSize)
+	}
+	if cursor.Backward {
+		return query.Where("(created_at, id) > (?, ?)", cursor.CreatedAt, cursor.ID).
+			Order("created_at ASC").Order("id ASC").Limit(filter.PageSize)
+	}
+	return query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID).
+		Order("created_at DESC").Order("id DESC").Limit(filter.PageSize)
+}
+
+// reverseTodos reverses todos in place
+func reverseTodos(todos []*entity.Todo) {
+	for i, j := 0, len(todos)-1; i < j; i, j = i+1, j-1 {
+		todos[i], todos[j] = todos[j], todos[i]
 	}
-	
-	err := query.Count(&count).Error
-	return count, err
 }
@@ -57,9 +57,23 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.
 	return &user, nil
 }
 
-// Update updates a user's information
+// Update updates a user's information. The token version is only written
+// by RevokeTokens, so that a concurrent update cannot undo a revocation.
 func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	return r.db.WithContext(ctx).Omit("TokenVersion").Save(user).Error
+}
+
+// RevokeTokens increments the token version of a user
+func (r *userRepository) RevokeTokens(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).
+		UpdateColumn("token_version", gorm.Expr("token_version + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
 // Delete deletes a user
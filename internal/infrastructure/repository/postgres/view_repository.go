@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// viewRepository implements repository.ViewRepository
+type viewRepository struct {
+	db *gorm.DB
+}
+
+// NewViewRepository creates a new ViewRepository
+func NewViewRepository(db *gorm.DB) repository.ViewRepository {
+	return &viewRepository{
+		db: db,
+	}
+}
+
+// Create creates a new view
+func (r *viewRepository) Create(ctx context.Context, view *entity.View) error {
+	return r.db.WithContext(ctx).Create(view).Error
+}
+
+// GetByID retrieves a view by its ID
+func (r *viewRepository) GetByID(ctx context.Context, id uint) (*entity.View, error) {
+	var view entity.View
+	err := r.db.WithContext(ctx).First(&view, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// GetByUserID retrieves all views of a user ordered by name
+func (r *viewRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.View, error) {
+	views := make([]*entity.View, 0)
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("name ASC").Order("id ASC").Find(&views).Error
+	if err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+// Update updates a view
+func (r *viewRepository) Update(ctx context.Context, view *entity.View) error {
+	return r.db.WithContext(ctx).Save(view).Error
+}
+
+// Delete deletes a view
+func (r *viewRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.View{}, id).Error
+}
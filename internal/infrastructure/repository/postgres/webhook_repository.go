@@ -0,0 +1,214 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// webhookRepository implements repository.WebhookRepository. Webhooks are not
+// covered by row-level security, since the background deliverer claims the
+// deliveries of all workspaces, so every query is scoped explicitly.
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new WebhookRepository
+func NewWebhookRepository(db *gorm.DB) repository.WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+// Create creates a new webhook in the workspace of the context
+func (r *webhookRepository) Create(ctx context.Context, webhook *entity.Webhook) error {
+	webhook.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(webhook).Error
+}
+
+// GetByID retrieves a webhook by its ID
+func (r *webhookRepository) GetByID(ctx context.Context, id uint) (*entity.Webhook, error) {
+	var webhook entity.Webhook
+	err := r.webhooks(ctx).First(&webhook, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// GetByUserID retrieves the webhooks of a user, oldest first
+func (r *webhookRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.Webhook, error) {
+	webhooks := make([]*entity.Webhook, 0)
+	err := r.webhooks(ctx).Where("user_id = ?", userID).Order("id ASC").Find(&webhooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetSubscribed retrieves the active webhooks receiving events of a type
+func (r *webhookRepository) GetSubscribed(ctx context.Context, eventType entity.TodoEventType) ([]*entity.Webhook, error) {
+	active := make([]*entity.Webhook, 0)
+	if err := r.webhooks(ctx).Where("active = ?", true).Order("id ASC").Find(&active).Error; err != nil {
+		return nil, err
+	}
+
+	// The event types are serialized, so they are matched here
+	webhooks := make([]*entity.Webhook, 0, len(active))
+	for _, webhook := range active {
+		if webhook.Subscribes(eventType) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+// Update updates a webhook
+func (r *webhookRepository) Update(ctx context.Context, webhook *entity.Webhook) error {
+	return r.webhooks(ctx).Where("id = ?", webhook.ID).Select("*").Omit(clause.Associations).Updates(webhook).Error
+}
+
+// Delete deletes a webhook with its deliveries
+func (r *webhookRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Scopes(inWorkspace(ctx, "webhooks")).Delete(&entity.Webhook{}, id)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Where("webhook_id = ?", id).Delete(&entity.WebhookDelivery{}).Error
+	})
+}
+
+// CreateDeliveries creates deliveries in the workspace of the context
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries ...*entity.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	for _, delivery := range deliveries {
+		delivery.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+	}
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(&deliveries).Error
+}
+
+// ClaimEvents retrieves and claims the unclaimed todo events of the outbox in
+// any workspace. Rows locked by a concurrent claim are skipped.
+func (r *webhookRepository) ClaimEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.OutboxEvent, error) {
+	events := make([]*entity.OutboxEvent, 0)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("claimed_until <= ?", now).Order("id ASC").Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(events))
+		for i, event := range events {
+			ids[i] = event.ID
+			event.ClaimedUntil = leaseUntil
+		}
+		return tx.Model(&entity.OutboxEvent{}).Where("id IN ?", ids).Update("claimed_until", leaseUntil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ScheduleDeliveries removes a claimed event from the outbox and creates its
+// deliveries in the workspace of the event
+func (r *webhookRepository) ScheduleDeliveries(ctx context.Context, event *entity.OutboxEvent, deliveries ...*entity.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND claimed_until = ?", event.ID, event.ClaimedUntil).Delete(&entity.OutboxEvent{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrClaimExpired
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+		for _, delivery := range deliveries {
+			delivery.WorkspaceID = event.WorkspaceID
+		}
+		return tx.Omit(clause.Associations).Create(&deliveries).Error
+	})
+}
+
+// GetDeliveryByID retrieves a delivery by its ID
+func (r *webhookRepository) GetDeliveryByID(ctx context.Context, id uint) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := r.deliveries(ctx).First(&delivery, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetDeliveries retrieves a page of the deliveries of a webhook, newest first
+func (r *webhookRepository) GetDeliveries(ctx context.Context, webhookID uint, page, pageSize int) ([]*entity.WebhookDelivery, error) {
+	deliveries := make([]*entity.WebhookDelivery, 0)
+	err := r.deliveries(ctx).Where("webhook_id = ?", webhookID).Order("id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// CountDeliveries counts the deliveries of a webhook
+func (r *webhookRepository) CountDeliveries(ctx context.Context, webhookID uint) (int64, error) {
+	var count int64
+	err := r.deliveries(ctx).Model(&entity.WebhookDelivery{}).Where("webhook_id = ?", webhookID).Count(&count).Error
+	return count, err
+}
+
+// ClaimDueDeliveries retrieves and postpones the pending deliveries due at now
+// in any workspace. Rows locked by a concurrent claim are skipped.
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	deliveries := make([]*entity.WebhookDelivery, 0)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Joins("JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id").
+			Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ? AND webhooks.active = ?", entity.DeliveryPending, now, true).
+			Order("webhook_deliveries.next_attempt_at ASC").Order("webhook_deliveries.id ASC").
+			Limit(limit).Clauses(clause.Locking{
+			Strength: "UPDATE",
+			Table:    clause.Table{Name: "webhook_deliveries"},
+			Options:  "SKIP LOCKED",
+		}).Preload("Webhook").Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+			delivery.NextAttemptAt = leaseUntil
+		}
+		return tx.Model(&entity.WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", leaseUntil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery stores the outcome of an attempt of a delivery in any workspace
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Where("id = ?", delivery.ID).Select("*").Omit(clause.Associations).Updates(delivery).Error
+}
+
+// webhooks starts a query on the webhooks in the workspace of the context
+func (r *webhookRepository) webhooks(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Scopes(inWorkspace(ctx, "webhooks"))
+}
+
+// deliveries starts a query on the deliveries in the workspace of the context
+func (r *webhookRepository) deliveries(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Scopes(inWorkspace(ctx, "webhook_deliveries"))
+}
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// workspaceRepository implements repository.WorkspaceRepository
+type workspaceRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkspaceRepository creates a new WorkspaceRepository
+func NewWorkspaceRepository(db *gorm.DB) repository.WorkspaceRepository {
+	return &workspaceRepository{
+		db: db,
+	}
+}
+
+// Create creates a new workspace with its first member
+func (r *workspaceRepository) Create(ctx context.Context, workspace *entity.Workspace, owner *entity.WorkspaceMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+		owner.WorkspaceID = workspace.ID
+		return tx.Omit(clause.Associations).Create(owner).Error
+	})
+}
+
+// GetByID retrieves a workspace by its ID
+func (r *workspaceRepository) GetByID(ctx context.Context, id uint) (*entity.Workspace, error) {
+	var workspace entity.Workspace
+	err := r.db.WithContext(ctx).First(&workspace, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// Update updates a workspace
+func (r *workspaceRepository) Update(ctx context.Context, workspace *entity.Workspace) error {
+	return r.db.WithContext(ctx).Save(workspace).Error
+}
+
+// Delete deletes a workspace with its members, projects and todos. The
+// workspace is set for the row-level security policies, if they are enabled.
+func (r *workspaceRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setWorkspace(tx, &id); err != nil {
+			return err
+		}
+		projects := tx.Session(&gorm.Session{NewDB: true}).Model(&entity.Project{}).Select("id").Where("workspace_id = ?", id)
+		if err := tx.Where("project_id IN (?)", projects).Delete(&entity.ProjectMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&entity.Todo{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&entity.TodoTombstone{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&entity.Project{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&entity.Activity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&entity.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&entity.OutboxEvent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&entity.Webhook{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&entity.WorkspaceMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Workspace{}, id).Error
+	})
+}
+
+// GetMember retrieves the membership of a user in a workspace
+func (r *workspaceRepository) GetMember(ctx context.Context, workspaceID, userID uint) (*entity.WorkspaceMember, error) {
+	var member entity.WorkspaceMember
+	err := r.db.WithContext(ctx).Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Take(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// GetMembers retrieves the members of a workspace in the order they joined
+func (r *workspaceRepository) GetMembers(ctx context.Context, workspaceID uint) ([]*entity.WorkspaceMember, error) {
+	members := make([]*entity.WorkspaceMember, 0)
+	err := r.db.WithContext(ctx).Where("workspace_id = ?", workspaceID).
+		Order("created_at ASC").Order("user_id ASC").Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// GetMemberships retrieves the memberships of a user with their workspaces, ordered by workspace name
+func (r *workspaceRepository) GetMemberships(ctx context.Context, userID uint) ([]*entity.WorkspaceMember, error) {
+	members := make([]*entity.WorkspaceMember, 0)
+	err := r.db.WithContext(ctx).Preload("Workspace").
+		Joins("JOIN workspaces ON workspaces.id = workspace_members.workspace_id").
+		Where("workspace_members.user_id = ?", userID).
+		Order("workspaces.name ASC").Order("workspaces.id ASC").Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// AddMember adds a member to a workspace
+func (r *workspaceRepository) AddMember(ctx context.Context, member *entity.WorkspaceMember) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(member).Error
+}
+
+// UpdateMember updates the role of a member
+func (r *workspaceRepository) UpdateMember(ctx context.Context, member *entity.WorkspaceMember) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(member).Error
+}
+
+// RemoveMember removes a member from a workspace and from its projects, and
+// unassigns their todos in it. The
+// workspace is set for the row-level security policies, if they are enabled.
+func (r *workspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setWorkspace(tx, &workspaceID); err != nil {
+			return err
+		}
+		projects := tx.Session(&gorm.Session{NewDB: true}).Model(&entity.Project{}).Select("id").Where("workspace_id = ?", workspaceID)
+		if err := tx.Where("user_id = ? AND project_id IN (?)", userID, projects).Delete(&entity.ProjectMember{}).Error; err != nil {
+			return err
+		}
+		var assigned []uint
+		if err := tx.Model(&entity.Todo{}).Where("workspace_id = ? AND assignee_id = ?", workspaceID, userID).Pluck("id", &assigned).Error; err != nil {
+			return err
+		}
+		if err := unassignTodos(tx, assigned, userID); err != nil {
+			return err
+		}
+		return tx.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Delete(&entity.WorkspaceMember{}).Error
+	})
+}
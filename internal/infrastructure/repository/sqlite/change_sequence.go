@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"gorm.io/gorm"
+
+	"todo-api/internal/domain/entity"
+)
+
+// changeSequence is the counter allocating the change sequence numbers of
+// todos. Its single row stays locked by a transaction writing todos until it
+// ends, so that the numbers are allocated in commit order.
+type changeSequence struct {
+	ID    uint   `gorm:"primaryKey"`
+	Value uint64 `gorm:"not null"`
+}
+
+// TableName implements gorm's schema.Tabler
+func (changeSequence) TableName() string {
+	return "todo_change_sequence"
+}
+
+// migrateChangeSequence creates the row of the change sequence counter. The
+// todos written before there were change sequence numbers are numbered by ID.
+func migrateChangeSequence(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&changeSequence{}).Count(&count).Error; err != nil || count > 0 {
+			return err
+		}
+		if err := tx.Exec("UPDATE todos SET change_seq = id WHERE change_seq = 0").Error; err != nil {
+			return err
+		}
+		return tx.Exec("INSERT INTO todo_change_sequence (id, value) SELECT 1, COALESCE(MAX(change_seq), 0) FROM todos").Error
+	})
+}
+
+// nextChangeSeqs allocates n consecutive change sequence numbers in the
+// transaction tx and returns the first one
+func nextChangeSeqs(tx *gorm.DB, n int) (uint64, error) {
+	var last uint64
+	err := tx.Raw("UPDATE todo_change_sequence SET value = value + ? WHERE id = 1 RETURNING value", n).Scan(&last).Error
+	if err != nil {
+		return 0, err
+	}
+	return last - uint64(n) + 1, nil
+}
+
+// unassignTodos unassigns the todos with the IDs from assigneeID in the
+// transaction tx, giving each a new change sequence number. Todos no longer
+// assigned to assigneeID are left alone.
+func unassignTodos(tx *gorm.DB, ids []uint, assigneeID uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	seq, err := nextChangeSeqs(tx, len(ids))
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		err := tx.Model(&entity.Todo{}).Where("id = ? AND assignee_id = ?", id, assigneeID).
+			Updates(map[string]interface{}{"assignee_id": nil, "change_seq": seq + uint64(i)}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// commentRepository implements repository.CommentRepository
+type commentRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentRepository creates a new CommentRepository
+func NewCommentRepository(db *gorm.DB) repository.CommentRepository {
+	return &commentRepository{
+		db: db,
+	}
+}
+
+// Create creates a new comment in the workspace of the context
+func (r *commentRepository) Create(ctx context.Context, comment *entity.Comment) error {
+	comment.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(comment).Error
+}
+
+// GetByID retrieves a comment by its ID
+func (r *commentRepository) GetByID(ctx context.Context, id uint) (*entity.Comment, error) {
+	var comment entity.Comment
+	err := r.comments(ctx).First(&comment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetByTodoID retrieves a page of the comments on a todo, oldest first
+func (r *commentRepository) GetByTodoID(ctx context.Context, todoID uint, page, pageSize int) ([]*entity.Comment, error) {
+	comments := make([]*entity.Comment, 0)
+	err := r.comments(ctx).Where("todo_id = ?", todoID).
+		Order("created_at ASC").Order("id ASC").
+		Offset((page - 1) * pageSize).Limit(pageSize).Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// CountByTodoID counts the comments on a todo
+func (r *commentRepository) CountByTodoID(ctx context.Context, todoID uint) (int64, error) {
+	var count int64
+	err := r.comments(ctx).Model(&entity.Comment{}).Where("todo_id = ?", todoID).Count(&count).Error
+	return count, err
+}
+
+// Update updates a comment
+func (r *commentRepository) Update(ctx context.Context, comment *entity.Comment) error {
+	return r.comments(ctx).Where("id = ?", comment.ID).Select("*").Omit(clause.Associations).Updates(comment).Error
+}
+
+// Delete deletes a comment
+func (r *commentRepository) Delete(ctx context.Context, id uint) error {
+	return r.comments(ctx).Delete(&entity.Comment{}, id).Error
+}
+
+// comments starts a query on the comments in the workspace of the context
+func (r *commentRepository) comments(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Scopes(inWorkspace(ctx, "comments"))
+}
@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"todo-api/internal/config"
+	"todo-api/internal/domain/entity"
+)
+
+// NewSQLiteDB creates a new SQLite database connection
+func NewSQLiteDB(config config.DatabaseConfig) (*gorm.DB, error) {
+	// Enforce foreign keys, which SQLite leaves disabled by default
+	dsn := fmt.Sprintf("%s?_pragma=foreign_keys(1)", config.SQLitePath)
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Info),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	return db, nil
+}
+
+// SchemaVersion is the version of the schema created by AutoMigrate, it is
+// increased with every change of the migrations
+const SchemaVersion = 4
+
+// Models returns the entities managed by AutoMigrate
+func Models() []interface{} {
+	return []interface{}{
+		&entity.User{},
+		&entity.Todo{},
+		&entity.TodoTombstone{},
+		&changeSequence{},
+		&entity.Comment{},
+		&entity.Activity{},
+		&entity.Webhook{},
+		&entity.WebhookDelivery{},
+		&entity.OutboxEvent{},
+		&entity.View{},
+		&entity.Project{},
+		&entity.ProjectMember{},
+		&entity.Workspace{},
+		&entity.WorkspaceMember{},
+		&entity.AuditEvent{},
+	}
+}
+
+// AutoMigrate runs database migrations
+func AutoMigrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(Models()...); err != nil {
+		return err
+	}
+	return migrateChangeSequence(db)
+}
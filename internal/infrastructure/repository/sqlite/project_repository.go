@@ -0,0 +1,151 @@
+package sqlite
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// projectRepository implements repository.ProjectRepository
+type projectRepository struct {
+	db *gorm.DB
+}
+
+// NewProjectRepository creates a new ProjectRepository
+func NewProjectRepository(db *gorm.DB) repository.ProjectRepository {
+	return &projectRepository{
+		db: db,
+	}
+}
+
+// Create creates a new project in the workspace of the context with its first member
+func (r *projectRepository) Create(ctx context.Context, project *entity.Project, owner *entity.ProjectMember) error {
+	project.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(project).Error; err != nil {
+			return err
+		}
+		owner.ProjectID = project.ID
+		return tx.Omit(clause.Associations).Create(owner).Error
+	})
+}
+
+// GetByID retrieves a project by its ID
+func (r *projectRepository) GetByID(ctx context.Context, id uint) (*entity.Project, error) {
+	var project entity.Project
+	err := r.projects(ctx).First(&project, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// Update updates a project
+func (r *projectRepository) Update(ctx context.Context, project *entity.Project) error {
+	return r.projects(ctx).Where("id = ?", project.ID).Select("*").Updates(project).Error
+}
+
+// Delete deletes a project with its members and todos. The todos leave no
+// tombstones, deleting the project changes the sync scope of its members.
+func (r *projectRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entity.Project{}).Scopes(inWorkspace(ctx, "projects")).Where("id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+
+		if err := tx.Scopes(inWorkspace(ctx, "todos")).Where("project_id = ?", id).Delete(&entity.Todo{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", id).Delete(&entity.ProjectMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Project{}, id).Error
+	})
+}
+
+// GetMember retrieves the membership of a user in a project
+func (r *projectRepository) GetMember(ctx context.Context, projectID, userID uint) (*entity.ProjectMember, error) {
+	var member entity.ProjectMember
+	err := r.members(ctx).Where("project_id = ? AND user_id = ?", projectID, userID).Take(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// GetMembers retrieves the members of a project in the order they joined
+func (r *projectRepository) GetMembers(ctx context.Context, projectID uint) ([]*entity.ProjectMember, error) {
+	members := make([]*entity.ProjectMember, 0)
+	err := r.members(ctx).Where("project_id = ?", projectID).
+		Order("created_at ASC").Order("user_id ASC").Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// GetMemberships retrieves the memberships of a user with their projects, ordered by project name
+func (r *projectRepository) GetMemberships(ctx context.Context, userID uint) ([]*entity.ProjectMember, error) {
+	members := make([]*entity.ProjectMember, 0)
+	err := r.db.WithContext(ctx).Preload("Project").
+		Joins("JOIN projects ON projects.id = project_members.project_id").
+		Scopes(inWorkspace(ctx, "projects")).
+		Where("project_members.user_id = ?", userID).
+		Order("projects.name ASC").Order("projects.id ASC").Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// AddMember adds a member to a project
+func (r *projectRepository) AddMember(ctx context.Context, member *entity.ProjectMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Scopes(inWorkspace(ctx, "projects")).Select("id").First(&entity.Project{}, member.ProjectID).Error; err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(member).Error
+	})
+}
+
+// UpdateMember updates the role of a member
+func (r *projectRepository) UpdateMember(ctx context.Context, member *entity.ProjectMember) error {
+	return r.members(ctx).Where("project_id = ? AND user_id = ?", member.ProjectID, member.UserID).
+		Select("*").Omit(clause.Associations).Updates(member).Error
+}
+
+// RemoveMember removes a member from a project and unassigns their todos in it
+func (r *projectRepository) RemoveMember(ctx context.Context, projectID, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var assigned []uint
+		if err := tx.Model(&entity.Todo{}).Scopes(inWorkspace(ctx, "todos")).
+			Where("project_id = ? AND assignee_id = ?", projectID, userID).
+			Pluck("id", &assigned).Error; err != nil {
+			return err
+		}
+		if err := unassignTodos(tx, assigned, userID); err != nil {
+			return err
+		}
+		return tx.Scopes(inWorkspaceProjects(ctx, tx)).Where("project_id = ? AND user_id = ?", projectID, userID).
+			Delete(&entity.ProjectMember{}).Error
+	})
+}
+
+// projects starts a query on the projects in the workspace of the context
+func (r *projectRepository) projects(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Scopes(inWorkspace(ctx, "projects"))
+}
+
+// members starts a query on the members of the projects in the workspace of the context
+func (r *projectRepository) members(ctx context.Context) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	return db.Scopes(inWorkspaceProjects(ctx, db))
+}
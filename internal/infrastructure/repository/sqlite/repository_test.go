@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"testing"
+
+	"todo-api/internal/config"
+	"todo-api/internal/domain/repository/repositorytest"
+)
+
+func TestRepositories(t *testing.T) {
+	db, err := NewSQLiteDB(config.DatabaseConfig{SQLitePath: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	// Every connection opens a database of its own in memory
+	sqlDB.SetMaxOpenConns(1)
+	if err := AutoMigrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repositorytest.TestRepositories(
+		NewUserRepository(db),
+		NewTodoRepository(db),
+		NewViewRepository(db),
+		NewProjectRepository(db),
+		NewWorkspaceRepository(db),
+		NewCommentRepository(db),
+		NewActivityRepository(db),
+		NewAuditRepository(db),
+		NewWebhookRepository(db),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
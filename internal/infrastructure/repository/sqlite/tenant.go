@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// inWorkspace restricts a query to the rows of table in the workspace of the
+// context, or to those outside any workspace
+func inWorkspace(ctx context.Context, table string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if workspaceID, ok := repository.WorkspaceFromContext(ctx); ok {
+			return db.Where(table+".workspace_id = ?", workspaceID)
+		}
+		return db.Where(table + ".workspace_id IS NULL")
+	}
+}
+
+// inWorkspaceProjects restricts a query on project members to the projects in
+// the workspace of the context
+func inWorkspaceProjects(ctx context.Context, db *gorm.DB) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		projects := db.Session(&gorm.Session{NewDB: true}).Model(&entity.Project{}).
+			Select("projects.id").Scopes(inWorkspace(ctx, "projects"))
+		return query.Where("project_members.project_id IN (?)", projects)
+	}
+}
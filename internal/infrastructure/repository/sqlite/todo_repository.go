@@ -0,0 +1,561 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+)
+
+// todoRepository implements repository.TodoRepository
+type todoRepository struct {
+	db *gorm.DB
+}
+
+// NewTodoRepository creates a new TodoRepository
+func NewTodoRepository(db *gorm.DB) repository.TodoRepository {
+	return &todoRepository{
+		db: db,
+	}
+}
+
+// Create creates a new todo in the workspace of the context
+func (r *todoRepository) Create(ctx context.Context, todo *entity.Todo, activities ...*entity.Activity) error {
+	todo.WorkspaceID = repository.WorkspaceIDFromContext(ctx)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		seq, err := nextChangeSeqs(tx, 1)
+		if err != nil {
+			return err
+		}
+		todo.ChangeSeq = seq
+		if err := tx.Create(todo).Error; err != nil {
+			return err
+		}
+		for _, activity := range activities {
+			activity.TodoID = todo.ID
+		}
+		return recordEvents(ctx, tx, activities, todo)
+	})
+}
+
+// GetByID retrieves a todo by its ID
+func (r *todoRepository) GetByID(ctx context.Context, id uint) (*entity.Todo, error) {
+	var todo entity.Todo
+	err := r.todos(ctx).First(&todo, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// GetByIDs retrieves the existing todos among ids
+func (r *todoRepository) GetByIDs(ctx context.Context, ids []uint) ([]*entity.Todo, error) {
+	todos := make([]*entity.Todo, 0, len(ids))
+	if len(ids) == 0 {
+		return todos, nil
+	}
+	err := r.todos(ctx).Where("id IN ?", ids).Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// GetByUserID retrieves todos for a specific user
+func (r *todoRepository) GetByUserID(ctx context.Context, userID uint, filter entity.TodoFilter) ([]*entity.Todo, error) {
+	var todos []*entity.Todo
+
+	if filter.FullText != nil {
+		filter.UserID = userID
+		matches, err := r.matchTodos(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range pageOf(matches, filter) {
+			todos = append(todos, match.Todo)
+		}
+		return todos, nil
+	}
+
+	query := applyTodoFilter(scopeTodos(r.todos(ctx), userID, filter), filter)
+
+	// Apply pagination and ordering
+	query = applyTodoPage(query, filter)
+
+	err := query.Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	if filter.Cursor != nil && filter.Cursor.Backward && !filter.Cursor.IsZero() {
+		reverseTodos(todos)
+	}
+
+	return todos, nil
+}
+
+// Update updates a todo
+func (r *todoRepository) Update(ctx context.Context, todo *entity.Todo, activities ...*entity.Activity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		seq, err := nextChangeSeqs(tx, 1)
+		if err != nil {
+			return err
+		}
+		todo.ChangeSeq = seq
+		result := updateTodo(tx.Scopes(inWorkspace(ctx, "todos")), todo)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return recordEvents(ctx, tx, activities, todo)
+	})
+}
+
+// UpdateIfUnchanged updates a todo unless it was changed since it was read
+func (r *todoRepository) UpdateIfUnchanged(ctx context.Context, todo *entity.Todo, activities ...*entity.Activity) error {
+	read := todo.ChangeSeq
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		seq, err := nextChangeSeqs(tx, 1)
+		if err != nil {
+			return err
+		}
+		todo.ChangeSeq = seq
+		result := updateTodo(tx.Scopes(inWorkspace(ctx, "todos")).Where("change_seq = ?", read), todo)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrTodoChanged
+		}
+		return recordEvents(ctx, tx, activities, todo)
+	})
+	if err != nil {
+		todo.ChangeSeq = read
+	}
+	return err
+}
+
+// Delete deletes a todo
+func (r *todoRepository) Delete(ctx context.Context, id uint, activities ...*entity.Activity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		seq, err := nextChangeSeqs(tx, 1)
+		if err != nil {
+			return err
+		}
+		deleted, err := deleteTodos(ctx, tx, seq, "id = ?", id)
+		if err != nil || len(deleted) == 0 {
+			return err
+		}
+		return recordEvents(ctx, tx, activities, deleted...)
+	})
+}
+
+// DeleteIfUnchanged deletes a todo unless its change sequence number moved past changeSeq
+func (r *todoRepository) DeleteIfUnchanged(ctx context.Context, id uint, changeSeq uint64, activities ...*entity.Activity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		seq, err := nextChangeSeqs(tx, 1)
+		if err != nil {
+			return err
+		}
+		deleted, err := deleteTodos(ctx, tx, seq, "id = ? AND change_seq = ?", id, changeSeq)
+		if err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			return repository.ErrTodoChanged
+		}
+		return recordEvents(ctx, tx, activities, deleted...)
+	})
+}
+
+// ApplyChanges saves the updated todos and deletes the deleted ones in a
+// single transaction unless one of them was changed since it was read
+func (r *todoRepository) ApplyChanges(ctx context.Context, updated, deleted []*entity.Todo, activities ...*entity.Activity) error {
+	reads := changeSeqsOf(updated)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(updated) == 0 && len(deleted) == 0 {
+			return nil
+		}
+		seq, err := nextChangeSeqs(tx, len(updated)+len(deleted))
+		if err != nil {
+			return err
+		}
+		for _, todo := range updated {
+			read := todo.ChangeSeq
+			todo.ChangeSeq = seq
+			seq++
+			result := updateTodo(tx.Scopes(inWorkspace(ctx, "todos")).Where("change_seq = ?", read), todo)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return repository.ErrTodoChanged
+			}
+		}
+		for _, todo := range deleted {
+			removed, err := deleteTodos(ctx, tx, seq, "id = ? AND change_seq = ?", todo.ID, todo.ChangeSeq)
+			if err != nil {
+				return err
+			}
+			if len(removed) == 0 {
+				return repository.ErrTodoChanged
+			}
+			seq++
+		}
+		return recordEvents(ctx, tx, activities, append(append([]*entity.Todo(nil), updated...), deleted...)...)
+	})
+	if err != nil {
+		restoreChangeSeqs(updated, reads)
+	}
+	return err
+}
+
+// GetChanges retrieves the todos written and the tombstones of the todos deleted after filter.Since
+func (r *todoRepository) GetChanges(ctx context.Context, filter entity.TodoChangeFilter) (*entity.TodoChanges, error) {
+	changes := &entity.TodoChanges{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// SQLite serializes writers, so every change up to the last allocated
+		// number was committed and the changes need no transaction IDs
+		if err := tx.Model(&changeSequence{}).Select("value").Where("id = 1").Scan(&changes.Until.Seq).Error; err != nil {
+			return err
+		}
+
+		err := tx.Scopes(inWorkspace(ctx, "todos"), inChangeScope(filter, changes.Until)).
+			Order("change_xid, change_seq").Limit(filter.Limit).Find(&changes.Todos).Error
+		if err != nil {
+			return err
+		}
+		return tx.Scopes(inWorkspace(ctx, "todo_tombstones"), inChangeScope(filter, changes.Until)).
+			Order("change_xid, change_seq").Limit(filter.Limit).Find(&changes.Tombstones).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// todos starts a query on the todos in the workspace of the context
+func (r *todoRepository) todos(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Scopes(inWorkspace(ctx, "todos"))
+}
+
+// changeSeqsOf returns the change sequence numbers the todos were read with
+func changeSeqsOf(todos []*entity.Todo) []uint64 {
+	seqs := make([]uint64, len(todos))
+	for i, todo := range todos {
+		seqs[i] = todo.ChangeSeq
+	}
+	return seqs
+}
+
+// restoreChangeSeqs restores the change sequence numbers of todos whose write failed
+func restoreChangeSeqs(todos []*entity.Todo, seqs []uint64) {
+	for i, todo := range todos {
+		todo.ChangeSeq = seqs[i]
+	}
+}
+
+// updateTodo saves all fields of a todo. Unlike Save it never inserts a todo
+// that the scope of the query excludes.
+func updateTodo(query *gorm.DB, todo *entity.Todo) *gorm.DB {
+	return query.Where("id = ?", todo.ID).Select("*").Omit(clause.Associations).Updates(todo)
+}
+
+// deleteTodos deletes the todos of the workspace of the context matching the
+// conditions in the transaction tx, and records them as tombstones numbered
+// from seq on. The caller allocates a change sequence number for every todo
+// that may match. It returns the deleted todos.
+func deleteTodos(ctx context.Context, tx *gorm.DB, seq uint64, query interface{}, args ...interface{}) ([]*entity.Todo, error) {
+	var todos []*entity.Todo
+	if err := tx.Scopes(inWorkspace(ctx, "todos")).Where(query, args...).Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	if len(todos) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, len(todos))
+	tombstones := make([]*entity.TodoTombstone, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+		tombstones[i] = entity.NewTodoTombstone(todo, seq+uint64(i))
+	}
+	if err := tx.Create(&tombstones).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("id IN ?", ids).Delete(&entity.Todo{}).Error; err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// recordEvents records the activities of a write in the transaction tx, and
+// adds their events to the outbox with the snapshots of the written todos
+func recordEvents(ctx context.Context, tx *gorm.DB, activities []*entity.Activity, todos ...*entity.Todo) error {
+	if err := createActivities(ctx, tx, activities); err != nil {
+		return err
+	}
+	events := entity.NewOutboxEvents(activities, todos)
+	if len(events) == 0 {
+		return nil
+	}
+	return tx.Create(&events).Error
+}
+
+// inChangeScope restricts a query on todos or tombstones to the personal todos
+// of filter.UserID and the todos of filter.ProjectIDs, changed after
+// filter.Since and up to until
+func inChangeScope(filter entity.TodoChangeFilter, until entity.ChangePosition) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("(change_xid, change_seq) > (?, ?) AND (change_xid, change_seq) <= (?, ?)",
+			filter.Since.XID, filter.Since.Seq, until.XID, until.Seq)
+		if len(filter.ProjectIDs) == 0 {
+			return db.Where("user_id = ? AND project_id IS NULL", filter.UserID)
+		}
+		return db.Where("((user_id = ? AND project_id IS NULL) OR project_id IN ?)", filter.UserID, filter.ProjectIDs)
+	}
+}
+
+// Count counts todos based on filter
+func (r *todoRepository) Count(ctx context.Context, filter entity.TodoFilter) (int64, error) {
+	var count int64
+
+	if filter.FullText != nil {
+		matches, err := r.matchTodos(ctx, filter)
+		return int64(len(matches)), err
+	}
+
+	query := scopeTodos(r.todos(ctx).Model(&entity.Todo{}), filter.UserID, filter)
+	query = applyTodoFilter(query, filter)
+
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// Search retrieves a page of the todos matching filter.FullText, most relevant first
+func (r *todoRepository) Search(ctx context.Context, filter entity.TodoFilter) ([]*entity.TodoMatch, error) {
+	if filter.FullText == nil {
+		return []*entity.TodoMatch{}, nil
+	}
+
+	matches, err := r.matchTodos(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	entity.SortTodoMatches(matches)
+
+	return pageOf(matches, filter), nil
+}
+
+// matchTodos matches the candidate todos against filter.FullText, newest first.
+// SQLite has no text search engine here, so the query only narrows the candidates.
+func (r *todoRepository) matchTodos(ctx context.Context, filter entity.TodoFilter) ([]*entity.TodoMatch, error) {
+	var todos []*entity.Todo
+	query := applyTodoFilter(scopeTodos(r.todos(ctx), filter.UserID, filter), filter)
+	if err := query.Order("created_at DESC").Order("id DESC").Find(&todos).Error; err != nil {
+		return nil, err
+	}
+
+	matches := make([]*entity.TodoMatch, 0, len(todos))
+	for _, todo := range todos {
+		if match, ok := filter.FullText.Match(todo); ok {
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
+
+// pageOf returns the offset page of matches selected by the filter
+func pageOf(matches []*entity.TodoMatch, filter entity.TodoFilter) []*entity.TodoMatch {
+	offset := (filter.Page - 1) * filter.PageSize
+	if offset < 0 || offset >= len(matches) {
+		return []*entity.TodoMatch{}
+	}
+	end := offset + filter.PageSize
+	if end > len(matches) {
+		end = len(matches)
+	}
+	return matches[offset:end]
+}
+
+// scopeTodos restricts a query to the todos of the project selected by the
+// filter, to the todos assigned to filter.AssigneeID or to the personal todos
+// of userID. Assignees can access the todos assigned to them, see RemoveMember.
+func scopeTodos(query *gorm.DB, userID uint, filter entity.TodoFilter) *gorm.DB {
+	if filter.AssigneeID != nil {
+		query = query.Where("assignee_id = ?", *filter.AssigneeID)
+	}
+	if filter.ProjectID != nil {
+		return query.Where("project_id = ?", *filter.ProjectID)
+	}
+	if filter.AssigneeID != nil {
+		return query
+	}
+	return query.Where("user_id = ? AND project_id IS NULL", userID)
+}
+
+// applyTodoFilter applies the completed, search and expression filters to a query. A
+// full-text query only narrows the todos down to those containing its words.
+func applyTodoFilter(query *gorm.DB, filter entity.TodoFilter) *gorm.DB {
+	if filter.Completed != nil {
+		query = query.Where("completed = ?", *filter.Completed)
+	}
+
+	if filter.Search != "" {
+		query = whereContains(query, filter.Search)
+	}
+
+	if filter.Expr != nil {
+		sql, args := filterSQL(filter.Expr)
+		query = query.Where(sql, args...)
+	}
+
+	if filter.FullText != nil {
+		for _, term := range filter.FullText.Terms {
+			if term.Negated {
+				continue
+			}
+			for _, word := range term.Words {
+				// SQLite only folds the case of ASCII letters
+				if isASCII(word) {
+					query = whereContains(query, word)
+				}
+			}
+		}
+	}
+
+	return query
+}
+
+// whereContains restricts the query to todos whose title or description contains text, ignoring case
+func whereContains(query *gorm.DB, text string) *gorm.DB {
+	pattern := "%" + escapeLike(strings.ToLower(text)) + "%"
+	return query.Where(`(LOWER(title) LIKE ? ESCAPE '\' OR LOWER(description) LIKE ? ESCAPE '\')`, pattern, pattern)
+}
+
+// isASCII reports whether s only contains ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// filterColumns maps the filterable fields to their columns
+var filterColumns = map[entity.FilterField]string{
+	entity.FilterFieldCompleted:   "completed",
+	entity.FilterFieldTitle:       "title",
+	entity.FilterFieldDescription: "description",
+	entity.FilterFieldCreated:     "created_at",
+	entity.FilterFieldUpdated:     "updated_at",
+	entity.FilterFieldTag:         "tags",
+}
+
+// filterOperators maps the comparison operators to SQL
+var filterOperators = map[entity.FilterOp]string{
+	entity.FilterOpEq: "=",
+	entity.FilterOpNe: "<>",
+	entity.FilterOpLt: "<",
+	entity.FilterOpLe: "<=",
+	entity.FilterOpGt: ">",
+	entity.FilterOpGe: ">=",
+}
+
+// filterSQL translates a filter expression to a parameterized SQL condition.
+// Columns and operators come from fixed tables, values are always bound.
+func filterSQL(expr entity.FilterExpr) (string, []interface{}) {
+	switch e := expr.(type) {
+	case entity.FilterAnd:
+		return joinFilterSQL(e.Exprs, " AND ")
+	case entity.FilterOr:
+		return joinFilterSQL(e.Exprs, " OR ")
+	case entity.FilterNot:
+		sql, args := filterSQL(e.Expr)
+		return "NOT (" + sql + ")", args
+	case entity.FilterCondition:
+		column, ok := filterColumns[e.Field]
+		if !ok {
+			break
+		}
+		if tag, ok := e.Value.(string); ok && e.Field == entity.FilterFieldTag {
+			// tags holds the JSON array of the tags, match the quoted tag in it
+			like := "LIKE"
+			if e.Op == entity.FilterOpNe {
+				like = "NOT LIKE"
+			}
+			return "COALESCE(" + column + ", '') " + like + ` ? ESCAPE '\'`, []interface{}{`%"` + escapeLike(tag) + `"%`}
+		}
+		if text, ok := e.Value.(string); ok {
+			if e.Op == entity.FilterOpContains {
+				return "LOWER(" + column + `) LIKE ? ESCAPE '\'`, []interface{}{"%" + escapeLike(strings.ToLower(text)) + "%"}
+			}
+			column, e.Value = "LOWER("+column+")", strings.ToLower(text)
+		}
+		if op, ok := filterOperators[e.Op]; ok {
+			return column + " " + op + " ?", []interface{}{e.Value}
+		}
+	}
+	// Match nothing rather than everything on an expression that cannot be translated
+	return "1 = 0", nil
+}
+
+// joinFilterSQL translates and joins expressions with a logical operator
+func joinFilterSQL(exprs []entity.FilterExpr, operator string) (string, []interface{}) {
+	parts := make([]string, len(exprs))
+	var args []interface{}
+	for i, expr := range exprs {
+		sql, exprArgs := filterSQL(expr)
+		parts[i] = sql
+		args = append(args, exprArgs...)
+	}
+	return "(" + strings.Join(parts, operator) + ")", args
+}
+
+// escapeLike escapes the LIKE wildcards in s, so that it matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// todoOrders maps the todo sort orders to ORDER BY clauses
+var todoOrders = map[entity.TodoSort]string{
+	entity.TodoSortCreatedDesc: "created_at DESC, id DESC",
+	entity.TodoSortCreatedAsc:  "created_at ASC, id ASC",
+	entity.TodoSortUpdatedDesc: "updated_at DESC, id DESC",
+	entity.TodoSortTitleAsc:    "LOWER(title) ASC, id ASC",
+}
+
+// applyTodoPage applies offset pagination in the order of the filter, or keyset pagination newest first.
+// Backward keyset pages are selected in ascending order and must be reversed.
+func applyTodoPage(query *gorm.DB, filter entity.TodoFilter) *gorm.DB {
+	cursor := filter.Cursor
+	if cursor == nil {
+		order, ok := todoOrders[filter.Sort]
+		if !ok {
+			order = todoOrders[entity.TodoSortCreatedDesc]
+		}
+		offset := (filter.Page - 1) * filter.PageSize
+		return query.Order(order).Offset(offset).Limit(filter.PageSize)
+	}
+
+	if cursor.IsZero() {
+		return query.Order("created_at DESC").Order("id DESC").Limit(filter.PageSize)
+	}
+	if cursor.Backward {
+		return query.Where("(created_at, id) > (?, ?)", cursor.CreatedAt, cursor.ID).
+			Order("created_at ASC").Order("id ASC").Limit(filter.PageSize)
+	}
+	return query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID).
+		Order("created_at DESC").Order("id DESC").Limit(filter.PageSize)
+}
+
+// reverseTodos reverses todos in place
+func reverseTodos(todos []*entity.Todo) {
+	for i, j := 0, len(todos)-1; i < j; i, j = i+1, j-1 {
+		todos[i], todos[j] = todos[j], todos[i]
+	}
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// schemaVersion records the version of the schema of the database. Its single
+// row is written once every migration of the version succeeded, raw SQL ones
+// included.
+type schemaVersion struct {
+	ID         uint      `gorm:"primaryKey"`
+	Version    int       `gorm:"not null"`
+	MigratedAt time.Time `gorm:"not null"`
+}
+
+// TableName implements gorm's schema.Tabler
+func (schemaVersion) TableName() string {
+	return "schema_version"
+}
+
+// MigrationStatus is the state of the schema migrations, determined at startup
+type MigrationStatus struct {
+	// Version is the schema version recorded in the database
+	Version int `json:"version"`
+
+	// Expected is the schema version this build migrates to
+	Expected int `json:"expected"`
+}
+
+// Pending reports whether the database schema is older than this build expects
+func (s MigrationStatus) Pending() bool {
+	return s.Version < s.Expected
+}
+
+// recordSchemaVersion records that the schema was migrated to version and
+// returns the resulting status. A newer version recorded by another instance
+// is kept.
+func recordSchemaVersion(db *gorm.DB, version int) (MigrationStatus, error) {
+	if err := db.AutoMigrate(&schemaVersion{}); err != nil {
+		return MigrationStatus{}, err
+	}
+
+	recorded := schemaVersion{ID: 1, Version: version, MigratedAt: time.Now()}
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"version", "migrated_at"}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Lt{Column: clause.Column{Table: "schema_version", Name: "version"}, Value: version}}},
+	}).Create(&recorded).Error
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+
+	if err := db.First(&recorded, 1).Error; err != nil {
+		return MigrationStatus{}, err
+	}
+	return MigrationStatus{Version: recorded.Version, Expected: version}, nil
+}
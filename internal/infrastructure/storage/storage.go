@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
+
+	"todo-api/internal/config"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/infrastructure/repository/memory"
+	"todo-api/internal/infrastructure/repository/postgres"
+	"todo-api/internal/infrastructure/repository/sqlite"
+)
+
+// Storage bundles the repositories of the configured storage backend
+type Storage struct {
+	Driver        string
+	UserRepo      repository.UserRepository
+	TodoRepo      repository.TodoRepository
+	ViewRepo      repository.ViewRepository
+	ProjectRepo   repository.ProjectRepository
+	WorkspaceRepo repository.WorkspaceRepository
+	CommentRepo   repository.CommentRepository
+	ActivityRepo  repository.ActivityRepository
+	AuditRepo     repository.AuditRepository
+	WebhookRepo   repository.WebhookRepository
+
+	// DB is the underlying connection, nil for the memory driver
+	DB *gorm.DB
+
+	// migrations is the state of the schema migrations at startup
+	migrations MigrationStatus
+}
+
+// New opens the storage backend selected by the configuration and migrates its
+// schema. It gives up waiting for the database when ctx is done.
+func New(ctx context.Context, cfg config.DatabaseConfig) (*Storage, error) {
+	switch cfg.Driver {
+	case config.DriverPostgres, "":
+		db, err := postgres.NewPostgresDB(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if err := instrument(db); err != nil {
+			return nil, err
+		}
+		if err := postgres.AutoMigrate(db, cfg.RowLevelSecurity); err != nil {
+			return nil, fmt.Errorf("failed to migrate database schemas: %w", err)
+		}
+		migrations, err := recordSchemaVersion(db, postgres.SchemaVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record database schema version: %w", err)
+		}
+		return &Storage{
+			Driver:        config.DriverPostgres,
+			UserRepo:      postgres.NewUserRepository(db),
+			TodoRepo:      postgres.NewTodoRepository(db, cfg.RowLevelSecurity),
+			ViewRepo:      postgres.NewViewRepository(db),
+			ProjectRepo:   postgres.NewProjectRepository(db, cfg.RowLevelSecurity),
+			WorkspaceRepo: postgres.NewWorkspaceRepository(db),
+			CommentRepo:   postgres.NewCommentRepository(db, cfg.RowLevelSecurity),
+			ActivityRepo:  postgres.NewActivityRepository(db, cfg.RowLevelSecurity),
+			AuditRepo:     postgres.NewAuditRepository(db),
+			WebhookRepo:   postgres.NewWebhookRepository(db),
+			DB:            db,
+			migrations:    migrations,
+		}, nil
+
+	case config.DriverSQLite:
+		db, err := sqlite.NewSQLiteDB(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if err := instrument(db); err != nil {
+			return nil, err
+		}
+		if err := sqlite.AutoMigrate(db); err != nil {
+			return nil, fmt.Errorf("failed to migrate database schemas: %w", err)
+		}
+		migrations, err := recordSchemaVersion(db, sqlite.SchemaVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record database schema version: %w", err)
+		}
+		return &Storage{
+			Driver:        config.DriverSQLite,
+			UserRepo:      sqlite.NewUserRepository(db),
+			TodoRepo:      sqlite.NewTodoRepository(db),
+			ViewRepo:      sqlite.NewViewRepository(db),
+			ProjectRepo:   sqlite.NewProjectRepository(db),
+			WorkspaceRepo: sqlite.NewWorkspaceRepository(db),
+			CommentRepo:   sqlite.NewCommentRepository(db),
+			ActivityRepo:  sqlite.NewActivityRepository(db),
+			AuditRepo:     sqlite.NewAuditRepository(db),
+			WebhookRepo:   sqlite.NewWebhookRepository(db),
+			DB:            db,
+			migrations:    migrations,
+		}, nil
+
+	case config.DriverMemory:
+		todoRepo := memory.NewTodoRepository()
+		projectRepo := memory.NewProjectRepository(todoRepo)
+		workspaceRepo := memory.NewWorkspaceRepository(todoRepo, projectRepo)
+		return &Storage{
+			Driver:        config.DriverMemory,
+			UserRepo:      memory.NewUserRepository(),
+			TodoRepo:      todoRepo,
+			ViewRepo:      memory.NewViewRepository(),
+			ProjectRepo:   projectRepo,
+			WorkspaceRepo: workspaceRepo,
+			CommentRepo:   memory.NewCommentRepository(todoRepo),
+			ActivityRepo:  memory.NewActivityRepository(workspaceRepo),
+			AuditRepo:     memory.NewAuditRepository(),
+			WebhookRepo:   memory.NewWebhookRepository(workspaceRepo),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+}
+
+// instrument traces every query issued through db
+func instrument(db *gorm.DB) error {
+	if err := db.Use(gormtracing.NewPlugin(gormtracing.WithoutMetrics())); err != nil {
+		return fmt.Errorf("failed to install tracing plugin: %w", err)
+	}
+	return nil
+}
+
+// Close releases the underlying database connection, if any
+func (s *Storage) Close() error {
+	if s.DB == nil {
+		return nil
+	}
+
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// Ping verifies that the database is reachable
+func (s *Storage) Ping(ctx context.Context) error {
+	if s.DB == nil {
+		return nil
+	}
+
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// PoolStats returns connection pool statistics, or nil for the memory driver
+func (s *Storage) PoolStats() map[string]int {
+	if s.DB == nil {
+		return nil
+	}
+
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return nil
+	}
+	stats := sqlDB.Stats()
+	return map[string]int{
+		"max_open_connections": stats.MaxOpenConnections,
+		"open_connections":     stats.OpenConnections,
+		"in_use":               stats.InUse,
+		"idle":                 stats.Idle,
+	}
+}
+
+// Migrations returns the state of the schema migrations determined at
+// startup, the memory driver has no schema
+func (s *Storage) Migrations() MigrationStatus {
+	return s.migrations
+}
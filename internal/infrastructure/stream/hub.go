@@ -0,0 +1,136 @@
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+)
+
+// subscriberBuffer is the number of events a subscriber may fall behind
+// before it is dropped
+const subscriberBuffer = 64
+
+// Hub fans the todo events out to the subscribers of this instance. It keeps
+// the latest events, so that reconnecting clients can resume from the ID of
+// the last event they received.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[chan *entity.TodoEvent]struct{}
+	history     []*entity.TodoEvent
+	historySize int
+	closed      bool
+}
+
+// NewHub creates a new Hub keeping the latest historySize events
+func NewHub(historySize int) *Hub {
+	return &Hub{
+		subscribers: make(map[chan *entity.TodoEvent]struct{}),
+		historySize: historySize,
+	}
+}
+
+// Publish implements usecase.TodoEventPublisher by sending the events to the
+// subscribers of this instance. Subscribers that fell behind are dropped
+// rather than holding up the publisher, they resume from the history.
+func (h *Hub) Publish(ctx context.Context, events ...*entity.TodoEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+	for _, event := range events {
+		h.history = append(h.history, event)
+		if len(h.history) > h.historySize {
+			h.history = h.history[len(h.history)-h.historySize:]
+		}
+
+		for subscriber := range h.subscribers {
+			select {
+			case subscriber <- event:
+			default:
+				delete(h.subscribers, subscriber)
+				close(subscriber)
+			}
+		}
+	}
+}
+
+// Subscribe implements usecase.TodoEventSource
+func (h *Hub) Subscribe(lastEventID uint) *usecase.TodoEventSubscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	events := make(chan *entity.TodoEvent, subscriberBuffer)
+	subscription := &usecase.TodoEventSubscription{
+		Events: events,
+		Close:  func() { h.unsubscribe(events) },
+	}
+	if h.closed {
+		close(events)
+		return subscription
+	}
+
+	subscription.Missed, subscription.Lost = h.since(lastEventID)
+	h.subscribers[events] = struct{}{}
+	return subscription
+}
+
+// since returns the events of the history published after the event with ID
+// lastEventID, and whether that event is no longer in the history
+func (h *Hub) since(lastEventID uint) ([]*entity.TodoEvent, bool) {
+	if lastEventID == 0 {
+		return nil, false
+	}
+
+	// Events are kept in publication order, which the IDs of concurrent
+	// changes do not always follow
+	for i := len(h.history) - 1; i >= 0; i-- {
+		if h.history[i].Activity.ID == lastEventID {
+			return append([]*entity.TodoEvent(nil), h.history[i+1:]...), false
+		}
+	}
+	return nil, true
+}
+
+// unsubscribe ends a subscription, if it was not dropped already
+func (h *Hub) unsubscribe(events chan *entity.TodoEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[events]; ok {
+		delete(h.subscribers, events)
+		close(events)
+	}
+}
+
+// Reset forgets the history and ends all subscriptions, for when events may
+// have been missed. Reconnecting clients are told to reload their todos.
+func (h *Hub) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.history = nil
+	h.dropSubscribers()
+}
+
+// Close ends all subscriptions and rejects new ones, so that the streams of
+// the clients end before the server shuts down
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	h.history = nil
+	h.dropSubscribers()
+}
+
+// dropSubscribers ends all subscriptions, h.mu must be held
+func (h *Hub) dropSubscribers() {
+	for subscriber := range h.subscribers {
+		delete(h.subscribers, subscriber)
+		close(subscriber)
+	}
+}
@@ -0,0 +1,181 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/util/logging"
+)
+
+// notifyChannel is the Postgres notification channel relaying todo events
+// between instances
+const notifyChannel = "todo_events"
+
+// maxNotificationSize keeps payloads below the 8000 byte limit of Postgres
+const maxNotificationSize = 7900
+
+// notification is the payload of a todo event notification
+type notification struct {
+	Activity  entity.Activity  `json:"activity"`
+	Todo      notificationTodo `json:"todo"`
+	Truncated bool             `json:"truncated,omitempty"`
+}
+
+// notificationTodo is the todo snapshot of a notification
+type notificationTodo struct {
+	ID          uint      `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Completed   bool      `json:"completed"`
+	UserID      uint      `json:"user_id"`
+	ProjectID   *uint     `json:"project_id"`
+	AssigneeID  *uint     `json:"assignee_id"`
+	WorkspaceID *uint     `json:"workspace_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PostgresPublisher is a usecase.TodoEventPublisher relaying the todo events
+// to the hubs of all instances through Postgres notifications, see Listen
+type PostgresPublisher struct {
+	db *gorm.DB
+}
+
+// NewPostgresPublisher creates a new PostgresPublisher
+func NewPostgresPublisher(db *gorm.DB) *PostgresPublisher {
+	return &PostgresPublisher{
+		db: db,
+	}
+}
+
+// Publish implements usecase.TodoEventPublisher
+func (p *PostgresPublisher) Publish(ctx context.Context, events ...*entity.TodoEvent) {
+	for _, event := range events {
+		payload, err := encodeNotification(event)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).WithField("event_id", event.Activity.ID).Error("Failed to encode todo event notification")
+			continue
+		}
+		if err := p.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", notifyChannel, payload).Error; err != nil {
+			logging.FromContext(ctx).WithError(err).WithField("event_id", event.Activity.ID).Error("Failed to notify todo event")
+		}
+	}
+}
+
+// Listen publishes the todo events notified by all instances to the hub
+// until ctx is canceled, listening again after connection failures. Events
+// notified while not listening are lost, so the hub is reset.
+func Listen(ctx context.Context, dsn string, hub *Hub, reconnectInterval time.Duration) {
+	for {
+		err := listen(ctx, dsn, hub)
+		if ctx.Err() != nil {
+			return
+		}
+		logging.FromContext(ctx).WithError(err).Warn("Lost the todo event notification connection")
+		hub.Reset()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectInterval):
+		}
+	}
+}
+
+// listen publishes the notified todo events to the hub until the connection fails
+func listen(ctx context.Context, dsn string, hub *Hub) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("Listening for todo event notifications")
+
+	for {
+		received, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		event, err := decodeNotification(received.Payload)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Warn("Discarded malformed todo event notification")
+			continue
+		}
+		hub.Publish(ctx, event)
+	}
+}
+
+// encodeNotification encodes a todo event as a notification payload. The
+// description is left out of events that would exceed the size limit.
+func encodeNotification(event *entity.TodoEvent) (string, error) {
+	todo := event.Todo
+	payload := notification{
+		Activity: *event.Activity,
+		Todo: notificationTodo{
+			ID:          todo.ID,
+			Title:       todo.Title,
+			Description: todo.Description,
+			Completed:   todo.Completed,
+			UserID:      todo.UserID,
+			ProjectID:   todo.ProjectID,
+			AssigneeID:  todo.AssigneeID,
+			WorkspaceID: todo.WorkspaceID,
+			CreatedAt:   todo.CreatedAt,
+			UpdatedAt:   todo.UpdatedAt,
+		},
+		Truncated: event.Truncated,
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil || len(encoded) <= maxNotificationSize {
+		return string(encoded), err
+	}
+
+	payload.Todo.Description = ""
+	payload.Activity.Changes = make([]entity.FieldChange, 0, len(event.Activity.Changes))
+	for _, change := range event.Activity.Changes {
+		if change.Field == "description" {
+			change.Old, change.New = nil, nil
+		}
+		payload.Activity.Changes = append(payload.Activity.Changes, change)
+	}
+	payload.Truncated = true
+	encoded, err = json.Marshal(payload)
+	return string(encoded), err
+}
+
+// decodeNotification decodes a todo event from a notification payload
+func decodeNotification(payload string) (*entity.TodoEvent, error) {
+	var decoded notification
+	if err := json.Unmarshal([]byte(payload), &decoded); err != nil {
+		return nil, err
+	}
+
+	todo := decoded.Todo
+	return &entity.TodoEvent{
+		Activity: &decoded.Activity,
+		Todo: entity.Todo{
+			ID:          todo.ID,
+			Title:       todo.Title,
+			Description: todo.Description,
+			Completed:   todo.Completed,
+			UserID:      todo.UserID,
+			ProjectID:   todo.ProjectID,
+			AssigneeID:  todo.AssigneeID,
+			WorkspaceID: todo.WorkspaceID,
+			CreatedAt:   todo.CreatedAt,
+			UpdatedAt:   todo.UpdatedAt,
+		},
+		Truncated: decoded.Truncated,
+	}, nil
+}
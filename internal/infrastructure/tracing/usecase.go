@@ -0,0 +1,304 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/util/jwt"
+)
+
+// The decorators below trace the use cases whose calls matter on their own:
+// todo writes and bulk operations, authentication and delta sync. The other
+// use cases are covered by the spans of their requests and queries.
+
+// tracerName identifies the spans of the use case calls
+const tracerName = "todo-api/internal/domain/usecase"
+
+// startSpan starts a use case span as a child of the span in ctx
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if workspaceID, ok := repository.WorkspaceFromContext(ctx); ok {
+		attrs = append(attrs, workspaceIDAttr(workspaceID))
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on the span, if any, and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// userIDAttr returns the span attribute for the acting user
+func userIDAttr(userID uint) attribute.KeyValue {
+	return attribute.Int64("user.id", int64(userID))
+}
+
+// todoIDAttr returns the span attribute for the todo being accessed
+func todoIDAttr(todoID uint) attribute.KeyValue {
+	return attribute.Int64("todo.id", int64(todoID))
+}
+
+// assigneeIDAttr returns the span attribute for the assignee of a todo
+func assigneeIDAttr(assigneeID uint) attribute.KeyValue {
+	return attribute.Int64("todo.assignee_id", int64(assigneeID))
+}
+
+// projectIDAttr returns the span attribute for the project being accessed
+func projectIDAttr(projectID uint) attribute.KeyValue {
+	return attribute.Int64("project.id", int64(projectID))
+}
+
+// workspaceIDAttr returns the span attribute for the workspace a call is scoped to
+func workspaceIDAttr(workspaceID uint) attribute.KeyValue {
+	return attribute.Int64("workspace.id", int64(workspaceID))
+}
+
+// todoUseCase wraps a usecase.TodoUseCase with a span per call
+type todoUseCase struct {
+	next usecase.TodoUseCase
+}
+
+// NewTodoUseCase wraps a usecase.TodoUseCase so that every call is traced
+func NewTodoUseCase(next usecase.TodoUseCase) usecase.TodoUseCase {
+	return &todoUseCase{next: next}
+}
+
+// CreateTodo implements usecase.TodoUseCase
+func (t *todoUseCase) CreateTodo(ctx context.Context, title, description string, projectID, assigneeID *uint, userID uint) (*entity.Todo, error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.CreateTodo", userIDAttr(userID))
+	if projectID != nil {
+		span.SetAttributes(projectIDAttr(*projectID))
+	}
+	if assigneeID != nil {
+		span.SetAttributes(assigneeIDAttr(*assigneeID))
+	}
+	todo, err := t.next.CreateTodo(ctx, title, description, projectID, assigneeID, userID)
+	endSpan(span, err)
+	return todo, err
+}
+
+// GetTodoByID implements usecase.TodoUseCase
+func (t *todoUseCase) GetTodoByID(ctx context.Context, id, userID uint) (*entity.Todo, error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.GetTodoByID", todoIDAttr(id), userIDAttr(userID))
+	todo, err := t.next.GetTodoByID(ctx, id, userID)
+	endSpan(span, err)
+	return todo, err
+}
+
+// GetUserTodos implements usecase.TodoUseCase
+func (t *todoUseCase) GetUserTodos(ctx context.Context, userID uint, filter entity.TodoFilter) (*usecase.TodoPage, error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.GetUserTodos",
+		userIDAttr(userID),
+		attribute.Int("page", filter.Page),
+		attribute.Int("page_size", filter.PageSize),
+		attribute.Bool("cursor", filter.Cursor != nil),
+		attribute.Bool("filter.search", filter.Search != ""),
+		attribute.String("sort", string(filter.Sort)),
+		attribute.Bool("filter.expr", filter.Expr != nil),
+		attribute.Bool("filter.full_text", filter.FullText != nil),
+	)
+	if filter.ProjectID != nil {
+		span.SetAttributes(projectIDAttr(*filter.ProjectID))
+	}
+	if filter.AssigneeID != nil {
+		span.SetAttributes(assigneeIDAttr(*filter.AssigneeID))
+	}
+	page, err := t.next.GetUserTodos(ctx, userID, filter)
+	if page != nil {
+		span.SetAttributes(attribute.Int("result.count", len(page.Todos)), attribute.Int64("result.total", page.Total))
+	}
+	endSpan(span, err)
+	return page, err
+}
+
+// UpdateTodo implements usecase.TodoUseCase
+func (t *todoUseCase) UpdateTodo(ctx context.Context, id uint, title, description string, completed bool, userID uint) (*entity.Todo, error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.UpdateTodo", todoIDAttr(id), userIDAttr(userID))
+	todo, err := t.next.UpdateTodo(ctx, id, title, description, completed, userID)
+	endSpan(span, err)
+	return todo, err
+}
+
+// DeleteTodo implements usecase.TodoUseCase
+func (t *todoUseCase) DeleteTodo(ctx context.Context, id, userID uint) error {
+	ctx, span := startSpan(ctx, "TodoUseCase.DeleteTodo", todoIDAttr(id), userIDAttr(userID))
+	err := t.next.DeleteTodo(ctx, id, userID)
+	endSpan(span, err)
+	return err
+}
+
+// UpdateTodoIfUnchanged implements usecase.TodoUseCase
+func (t *todoUseCase) UpdateTodoIfUnchanged(ctx context.Context, id uint, changeSeq uint64, title, description string, completed bool, userID uint) (*entity.Todo, error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.UpdateTodoIfUnchanged", todoIDAttr(id), userIDAttr(userID))
+	todo, err := t.next.UpdateTodoIfUnchanged(ctx, id, changeSeq, title, description, completed, userID)
+	endSpan(span, err)
+	return todo, err
+}
+
+// DeleteTodoIfUnchanged implements usecase.TodoUseCase
+func (t *todoUseCase) DeleteTodoIfUnchanged(ctx context.Context, id uint, changeSeq uint64, userID uint) error {
+	ctx, span := startSpan(ctx, "TodoUseCase.DeleteTodoIfUnchanged", todoIDAttr(id), userIDAttr(userID))
+	err := t.next.DeleteTodoIfUnchanged(ctx, id, changeSeq, userID)
+	endSpan(span, err)
+	return err
+}
+
+// CompleteTodo implements usecase.TodoUseCase
+func (t *todoUseCase) CompleteTodo(ctx context.Context, id, userID uint) (*entity.Todo, error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.CompleteTodo", todoIDAttr(id), userIDAttr(userID))
+	todo, err := t.next.CompleteTodo(ctx, id, userID)
+	endSpan(span, err)
+	return todo, err
+}
+
+// AssignTodo implements usecase.TodoUseCase
+func (t *todoUseCase) AssignTodo(ctx context.Context, id uint, assigneeID *uint, userID uint) (*entity.Todo, error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.AssignTodo", todoIDAttr(id), userIDAttr(userID))
+	if assigneeID != nil {
+		span.SetAttributes(assigneeIDAttr(*assigneeID))
+	}
+	todo, err := t.next.AssignTodo(ctx, id, assigneeID, userID)
+	endSpan(span, err)
+	return todo, err
+}
+
+// BulkUpdate implements usecase.TodoUseCase
+func (t *todoUseCase) BulkUpdate(ctx context.Context, op usecase.BulkOperation, target usecase.BulkTarget, userID uint) (*usecase.BulkResult, error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.BulkUpdate",
+		userIDAttr(userID),
+		attribute.String("bulk.action", string(op.Action)),
+		attribute.Int("bulk.ids", len(target.IDs)),
+		attribute.Bool("bulk.filter", target.Filter != nil),
+	)
+	if op.Action == usecase.BulkMove {
+		span.SetAttributes(projectIDAttr(op.ProjectID))
+	}
+	result, err := t.next.BulkUpdate(ctx, op, target, userID)
+	if result != nil {
+		span.SetAttributes(attribute.Int("result.count", len(result.Items)), attribute.Int("result.succeeded", result.Succeeded()))
+	}
+	endSpan(span, err)
+	return result, err
+}
+
+// userUseCase wraps a usecase.UserUseCase with a span per call
+type userUseCase struct {
+	next usecase.UserUseCase
+}
+
+// NewUserUseCase wraps a usecase.UserUseCase so that every call is traced
+func NewUserUseCase(next usecase.UserUseCase) usecase.UserUseCase {
+	return &userUseCase{next: next}
+}
+
+// Register implements usecase.UserUseCase
+func (t *userUseCase) Register(ctx context.Context, username, email, password string) (*entity.User, error) {
+	ctx, span := startSpan(ctx, "UserUseCase.Register")
+	user, err := t.next.Register(ctx, username, email, password)
+	endSpan(span, err)
+	return user, err
+}
+
+// Login implements usecase.UserUseCase
+func (t *userUseCase) Login(ctx context.Context, email, password string) (*usecase.LoginResponse, error) {
+	ctx, span := startSpan(ctx, "UserUseCase.Login")
+	response, err := t.next.Login(ctx, email, password)
+	endSpan(span, err)
+	return response, err
+}
+
+// GetUserByID implements usecase.UserUseCase
+func (t *userUseCase) GetUserByID(ctx context.Context, id uint) (*entity.User, error) {
+	ctx, span := startSpan(ctx, "UserUseCase.GetUserByID", userIDAttr(id))
+	user, err := t.next.GetUserByID(ctx, id)
+	endSpan(span, err)
+	return user, err
+}
+
+// UpdateProfile implements usecase.UserUseCase
+func (t *userUseCase) UpdateProfile(ctx context.Context, id uint, username, email string) (*entity.User, error) {
+	ctx, span := startSpan(ctx, "UserUseCase.UpdateProfile", userIDAttr(id))
+	user, err := t.next.UpdateProfile(ctx, id, username, email)
+	endSpan(span, err)
+	return user, err
+}
+
+// UpdatePassword implements usecase.UserUseCase
+func (t *userUseCase) UpdatePassword(ctx context.Context, id uint, currentPassword, newPassword string) error {
+	ctx, span := startSpan(ctx, "UserUseCase.UpdatePassword", userIDAttr(id))
+	err := t.next.UpdatePassword(ctx, id, currentPassword, newPassword)
+	endSpan(span, err)
+	return err
+}
+
+// VerifyToken implements usecase.UserUseCase
+func (t *userUseCase) VerifyToken(ctx context.Context, token string) (*jwt.Claims, error) {
+	ctx, span := startSpan(ctx, "UserUseCase.VerifyToken")
+	claims, err := t.next.VerifyToken(ctx, token)
+	endSpan(span, err)
+	return claims, err
+}
+
+// Logout implements usecase.UserUseCase
+func (t *userUseCase) Logout(ctx context.Context, id uint) error {
+	ctx, span := startSpan(ctx, "UserUseCase.Logout", userIDAttr(id))
+	err := t.next.Logout(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+// syncUseCase wraps a usecase.SyncUseCase with a span per call
+type syncUseCase struct {
+	next usecase.SyncUseCase
+}
+
+// NewSyncUseCase wraps a usecase.SyncUseCase so that every call is traced
+func NewSyncUseCase(next usecase.SyncUseCase) usecase.SyncUseCase {
+	return &syncUseCase{next: next}
+}
+
+// GetChanges implements usecase.SyncUseCase
+func (t *syncUseCase) GetChanges(ctx context.Context, token entity.SyncToken, pageSize int, userID uint) (*usecase.SyncPage, error) {
+	ctx, span := startSpan(ctx, "SyncUseCase.GetChanges",
+		userIDAttr(userID),
+		attribute.Int64("sync.since", int64(token.Position.Seq)),
+		attribute.Int64("sync.since_xid", int64(token.Position.XID)),
+		attribute.Int("page_size", pageSize),
+	)
+	page, err := t.next.GetChanges(ctx, token, pageSize, userID)
+	if page != nil {
+		span.SetAttributes(
+			attribute.Int("result.todos", len(page.Todos)),
+			attribute.Int("result.tombstones", len(page.Tombstones)),
+			attribute.Bool("result.reset", page.Reset),
+		)
+	}
+	endSpan(span, err)
+	return page, err
+}
+
+// ApplyMutations implements usecase.SyncUseCase
+func (t *syncUseCase) ApplyMutations(ctx context.Context, mutations []usecase.SyncMutation, userID uint) ([]usecase.SyncMutationResult, error) {
+	ctx, span := startSpan(ctx, "SyncUseCase.ApplyMutations", userIDAttr(userID), attribute.Int("sync.mutations", len(mutations)))
+	results, err := t.next.ApplyMutations(ctx, mutations, userID)
+	conflicts := 0
+	for _, result := range results {
+		if errors.Is(result.Err, usecase.ErrTodoChanged) {
+			conflicts++
+		}
+	}
+	span.SetAttributes(attribute.Int("result.conflicts", conflicts))
+	endSpan(span, err)
+	return results, err
+}
@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/util/logging"
+)
+
+// RunDeliverer sends the due webhook deliveries every interval until ctx is
+// canceled. Full batches are followed by the next one right away.
+func RunDeliverer(ctx context.Context, deliverer usecase.WebhookDeliverer, batchSize int, interval time.Duration) {
+	poll(ctx, deliverer.DeliverDue, batchSize, interval, "Failed to claim webhook deliveries")
+}
+
+// RunScheduler schedules the webhook deliveries of the todo events in the
+// outbox every interval until ctx is canceled. Full batches are followed by
+// the next one right away.
+func RunScheduler(ctx context.Context, scheduler usecase.WebhookScheduler, batchSize int, interval time.Duration) {
+	poll(ctx, scheduler.ScheduleEvents, batchSize, interval, "Failed to claim webhook events")
+}
+
+// poll runs handle every interval until ctx is canceled, and again right away
+// as long as it handles full batches. failure is logged when it fails.
+func poll(ctx context.Context, handle func(context.Context) (int, error), batchSize int, interval time.Duration, failure string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				handled, err := handle(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						logging.FromContext(ctx).WithError(err).Warn(failure)
+					}
+					break
+				}
+				if handled < batchSize {
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// ErrAddressNotAllowed is returned for hosts and connections resolving to
+// loopback, private, link-local or otherwise non-public addresses
+var ErrAddressNotAllowed = errors.New("address is not public")
+
+// nonPublicPrefixes are the special-purpose ranges netip does not classify,
+// including the IPv6 ranges embedding IPv4 addresses
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001::/32"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+	netip.MustParsePrefix("fec0::/10"),
+}
+
+// AddressPolicy restricts webhook deliveries to public addresses, so that
+// webhooks cannot reach the services of the internal network. It implements
+// usecase.WebhookHostChecker and guards the connections of the Sender, which
+// also refuses hosts resolving to another address by the time of a delivery.
+type AddressPolicy struct {
+	allowPrivate bool
+	resolver     *net.Resolver
+}
+
+// NewAddressPolicy creates a new AddressPolicy, allowPrivate lifts the
+// restriction for deployments delivering to their own network
+func NewAddressPolicy(allowPrivate bool) *AddressPolicy {
+	return &AddressPolicy{
+		allowPrivate: allowPrivate,
+		resolver:     net.DefaultResolver,
+	}
+}
+
+// CheckHost implements usecase.WebhookHostChecker. Every address of a host
+// name must be public.
+func (p *AddressPolicy) CheckHost(ctx context.Context, host string) error {
+	if p.allowPrivate {
+		return nil
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return checkAddr(addr)
+	}
+	addrs, err := p.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if err := checkAddr(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// control implements net.Dialer.Control, it refuses connections to
+// non-public addresses once the host name was resolved
+func (p *AddressPolicy) control(network, address string, _ syscall.RawConn) error {
+	if p.allowPrivate {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	return checkAddr(addr)
+}
+
+// checkAddr returns ErrAddressNotAllowed unless addr is a public unicast address
+func checkAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return ErrAddressNotAllowed
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return ErrAddressNotAllowed
+		}
+	}
+	return nil
+}
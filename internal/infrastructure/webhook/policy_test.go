@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestCheckAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		allowed bool
+	}{
+		{"1.1.1.1", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"10.0.0.1", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+	for _, tt := range tests {
+		err := checkAddr(netip.MustParseAddr(tt.addr))
+		if tt.allowed && err != nil {
+			t.Errorf("%s: got error %v, want allowed", tt.addr, err)
+		}
+		if !tt.allowed && !errors.Is(err, ErrAddressNotAllowed) {
+			t.Errorf("%s: got error %v, want %v", tt.addr, err, ErrAddressNotAllowed)
+		}
+	}
+}
+
+func TestAddressPolicy(t *testing.T) {
+	ctx := context.Background()
+	policy := NewAddressPolicy(false)
+	if err := policy.CheckHost(ctx, "localhost"); !errors.Is(err, ErrAddressNotAllowed) {
+		t.Errorf("localhost: got error %v, want %v", err, ErrAddressNotAllowed)
+	}
+	if err := policy.control("tcp4", "10.1.2.3:443", nil); !errors.Is(err, ErrAddressNotAllowed) {
+		t.Errorf("dial 10.1.2.3: got error %v, want %v", err, ErrAddressNotAllowed)
+	}
+	if err := policy.control("tcp4", "1.1.1.1:443", nil); err != nil {
+		t.Errorf("dial 1.1.1.1: %v", err)
+	}
+
+	open := NewAddressPolicy(true)
+	if err := open.CheckHost(ctx, "127.0.0.1"); err != nil {
+		t.Errorf("private networks allowed: %v", err)
+	}
+	if err := open.control("tcp4", "127.0.0.1:80", nil); err != nil {
+		t.Errorf("private networks allowed, dial: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/middleware"
+	"todo-api/internal/interface/api/presenter"
+)
+
+// ActivityHandler handles HTTP requests related to the history of todos
+type ActivityHandler struct {
+	activityUseCase usecase.ActivityUseCase
+}
+
+// NewActivityHandler creates a new ActivityHandler
+func NewActivityHandler(activityUseCase usecase.ActivityUseCase) *ActivityHandler {
+	return &ActivityHandler{
+		activityUseCase: activityUseCase,
+	}
+}
+
+// GetTodoHistory handles retrieving a page of the history of a todo, oldest first
+func (h *ActivityHandler) GetTodoHistory(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse todo ID
+	todoID, err := parseTodoID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse pagination, the use case applies the defaults and limits
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+
+	// Get history
+	activityPage, err := h.activityUseCase.GetTodoHistory(c.Request().Context(), todoID, userID, page, pageSize)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.ActivitiesResponse(activityPage.Activities, activityPage.Total, activityPage.Page, activityPage.PageSize))
+}
+
+// GetActivity handles retrieving a page of the changes the current user made to todos, newest first
+func (h *ActivityHandler) GetActivity(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse pagination, the use case applies the defaults and limits
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+
+	// Get activity
+	activityPage, err := h.activityUseCase.GetUserActivity(c.Request().Context(), userID, page, pageSize)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.ActivitiesResponse(activityPage.Activities, activityPage.Total, activityPage.Page, activityPage.PageSize))
+}
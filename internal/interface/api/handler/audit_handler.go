@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/middleware"
+	"todo-api/internal/interface/api/presenter"
+)
+
+// Request parameter problems
+var (
+	errInvalidAuditUserID = presenter.NewProblem(http.StatusBadRequest, "invalid_user_id", "Invalid user ID")
+	errInvalidAuditTime   = presenter.NewProblem(http.StatusBadRequest, "invalid_time", "since and until must be RFC 3339 timestamps")
+)
+
+// AuditHandler handles HTTP requests related to the security audit log
+type AuditHandler struct {
+	auditUseCase usecase.AuditUseCase
+}
+
+// NewAuditHandler creates a new AuditHandler
+func NewAuditHandler(auditUseCase usecase.AuditUseCase) *AuditHandler {
+	return &AuditHandler{
+		auditUseCase: auditUseCase,
+	}
+}
+
+// GetAuditEvents handles retrieving a page of the audit log, newest first
+func (h *AuditHandler) GetAuditEvents(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse filter parameters
+	filter := entity.AuditFilter{Type: entity.AuditEventType(c.QueryParam("type"))}
+
+	// Parse the account the events are about
+	if c.QueryParams().Has("user_id") {
+		eventUserID, err := strconv.ParseUint(c.QueryParam("user_id"), 10, 32)
+		if err != nil || eventUserID == 0 {
+			return errInvalidAuditUserID
+		}
+		id := uint(eventUserID)
+		filter.UserID = &id
+	}
+
+	// Parse time range
+	var err error
+	if filter.Since, err = parseAuditTime(c.QueryParam("since")); err != nil {
+		return err
+	}
+	if filter.Until, err = parseAuditTime(c.QueryParam("until")); err != nil {
+		return err
+	}
+
+	// Parse pagination, the use case applies the defaults and limits
+	filter.Page, _ = strconv.Atoi(c.QueryParam("page"))
+	filter.PageSize, _ = strconv.Atoi(c.QueryParam("page_size"))
+
+	// Get audit events
+	auditPage, err := h.auditUseCase.GetEvents(c.Request().Context(), filter, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.AuditEventsResponse(auditPage.Events, auditPage.Total, auditPage.Page, auditPage.PageSize))
+}
+
+// parseAuditTime parses an optional RFC 3339 timestamp, empty is the zero time
+func parseAuditTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errInvalidAuditTime
+	}
+	return t, nil
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/middleware"
+	"todo-api/internal/interface/api/presenter"
+)
+
+// errInvalidCommentID is returned for a malformed comment path parameter
+var errInvalidCommentID = presenter.NewProblem(http.StatusBadRequest, "invalid_comment_id", "Invalid comment ID")
+
+// CommentHandler handles HTTP requests related to comments on todos
+type CommentHandler struct {
+	commentUseCase usecase.CommentUseCase
+}
+
+// NewCommentHandler creates a new CommentHandler
+func NewCommentHandler(commentUseCase usecase.CommentUseCase) *CommentHandler {
+	return &CommentHandler{
+		commentUseCase: commentUseCase,
+	}
+}
+
+// CommentRequest represents the request to write or edit a comment
+type CommentRequest struct {
+	Body string `json:"body" validate:"required,max=10000"`
+}
+
+// CreateComment handles commenting on a todo
+func (h *CommentHandler) CreateComment(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse todo ID
+	todoID, err := parseTodoID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse and validate request
+	req := new(CommentRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Add comment
+	comment, err := h.commentUseCase.AddComment(c.Request().Context(), todoID, req.Body, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusCreated, presenter.CommentResponse(comment))
+}
+
+// GetComments handles retrieving a page of the comments on a todo, oldest first
+func (h *CommentHandler) GetComments(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse todo ID
+	todoID, err := parseTodoID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse pagination, the use case applies the defaults and limits
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+
+	// Get comments
+	commentPage, err := h.commentUseCase.GetComments(c.Request().Context(), todoID, userID, page, pageSize)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.CommentsResponse(commentPage.Comments, commentPage.Total, commentPage.Page, commentPage.PageSize))
+}
+
+// UpdateComment handles editing a comment written by the current user
+func (h *CommentHandler) UpdateComment(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse todo and comment IDs
+	todoID, err := parseTodoID(c)
+	if err != nil {
+		return err
+	}
+	commentID, err := parseCommentID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse and validate request
+	req := new(CommentRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Edit comment
+	comment, err := h.commentUseCase.EditComment(c.Request().Context(), todoID, commentID, req.Body, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.CommentResponse(comment))
+}
+
+// DeleteComment handles deleting a comment written by the current user
+func (h *CommentHandler) DeleteComment(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse todo and comment IDs
+	todoID, err := parseTodoID(c)
+	if err != nil {
+		return err
+	}
+	commentID, err := parseCommentID(c)
+	if err != nil {
+		return err
+	}
+
+	// Delete comment
+	if err := h.commentUseCase.DeleteComment(c.Request().Context(), todoID, commentID, userID); err != nil {
+		return err
+	}
+
+	// Return response
+	return c.NoContent(http.StatusNoContent)
+}
+
+// parseCommentID parses the comment ID path parameter
+func parseCommentID(c echo.Context) (uint, error) {
+	commentID, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
+	if err != nil {
+		return 0, errInvalidCommentID
+	}
+	return uint(commentID), nil
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/middleware"
+	"todo-api/internal/interface/api/presenter"
+)
+
+// ErrorHandler is the Echo HTTP error handler. It renders every error returned
+// by handlers and middleware as an RFC 7807 problem details response. Errors
+// are logged by the request logger, internal details are not sent to clients.
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	problem := problemFor(err)
+	problem.Instance = c.Request().URL.Path
+	problem.RequestID = middleware.GetRequestIDFromContext(c)
+
+	c.Response().Header().Set(echo.HeaderContentType, presenter.MIMEApplicationProblemJSON)
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(problem.Status)
+	} else {
+		err = c.JSON(problem.Status, problem)
+	}
+	if err != nil {
+		requestLogger(c).WithError(err).Error("Failed to write error response")
+	}
+}
+
+// problemFor maps err to the problem describing it to the client
+func problemFor(err error) *presenter.Problem {
+	var problem *presenter.Problem
+	if errors.As(err, &problem) {
+		// Copy, the problem may be shared
+		p := *problem
+		return &p
+	}
+
+	var domainErr *usecase.Error
+	if errors.As(err, &domainErr) {
+		return presenter.NewProblem(statusForKind(domainErr.Kind), domainErr.Code, sentence(domainErr.Message))
+	}
+
+	var filterErr *entity.FilterError
+	if errors.As(err, &filterErr) {
+		return presenter.FilterProblem(filterErr)
+	}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return presenter.ValidationProblem(validationErrors)
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		if httpErr.Code >= http.StatusInternalServerError {
+			return internalProblem()
+		}
+		return presenter.NewProblem(httpErr.Code, codeForStatus(httpErr.Code), sentence(fmt.Sprint(httpErr.Message)))
+	}
+
+	return internalProblem()
+}
+
+// internalProblem describes an unexpected error without leaking its details
+func internalProblem() *presenter.Problem {
+	return presenter.NewProblem(http.StatusInternalServerError, "internal_error", "An unexpected error occurred")
+}
+
+// statusForKind returns the HTTP status of a domain error kind
+func statusForKind(kind usecase.ErrorKind) int {
+	switch kind {
+	case usecase.KindInvalid:
+		return http.StatusBadRequest
+	case usecase.KindUnauthorized:
+		return http.StatusUnauthorized
+	case usecase.KindForbidden:
+		return http.StatusForbidden
+	case usecase.KindNotFound:
+		return http.StatusNotFound
+	case usecase.KindConflict:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// codeForStatus derives a problem code for errors raised by Echo itself, e.g. not_found
+func codeForStatus(status int) string {
+	if status == http.StatusBadRequest {
+		return "invalid_request"
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+// sentence capitalizes the first letter of an error message for display
+func sentence(message string) string {
+	r, size := utf8.DecodeRuneInString(message)
+	if size == 0 {
+		return message
+	}
+	return string(unicode.ToUpper(r)) + message[size:]
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/interface/api/presenter"
+)
+
+// HealthCheck checks a single dependency of the server.
+// The returned details are included in the readiness report.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) (details interface{}, err error)
+}
+
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	ready   atomic.Bool
+	timeout time.Duration
+	checks  []HealthCheck
+}
+
+// NewHealthHandler creates a new HealthHandler that starts out ready.
+// Each readiness check must complete within timeout.
+func NewHealthHandler(timeout time.Duration, checks ...HealthCheck) *HealthHandler {
+	h := &HealthHandler{
+		timeout: timeout,
+		checks:  checks,
+	}
+	h.ready.Store(true)
+	return h
+}
+
+// SetReady changes the readiness reported by the readiness probe
+func (h *HealthHandler) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// Live handles the liveness probe, which only reports that the process is serving requests
+func (h *HealthHandler) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, presenter.HealthResponse(presenter.HealthStatusOK, nil))
+}
+
+// Ready handles the readiness probe, which checks every dependency
+func (h *HealthHandler) Ready(c echo.Context) error {
+	if !h.ready.Load() {
+		return c.JSON(http.StatusServiceUnavailable, presenter.HealthResponse(presenter.HealthStatusShuttingDown, nil))
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), h.timeout)
+	defer cancel()
+
+	// Run all checks concurrently so one slow dependency does not hide the others
+	components := make(map[string]presenter.ComponentStatus, len(h.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+			status := runHealthCheck(ctx, check)
+
+			mu.Lock()
+			components[check.Name] = status
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	overall := presenter.HealthStatusOK
+	for _, component := range components {
+		if component.Status != presenter.HealthStatusOK {
+			overall = presenter.HealthStatusUnavailable
+		}
+	}
+
+	code := http.StatusOK
+	if overall != presenter.HealthStatusOK {
+		code = http.StatusServiceUnavailable
+	}
+	return c.JSON(code, presenter.HealthResponse(overall, components))
+}
+
+// runHealthCheck runs a single check and converts its outcome into a component status
+func runHealthCheck(ctx context.Context, check HealthCheck) presenter.ComponentStatus {
+	start := time.Now()
+	details, err := check.Check(ctx)
+
+	status := presenter.ComponentStatus{
+		Status:    presenter.HealthStatusOK,
+		LatencyMS: time.Since(start).Milliseconds(),
+		Details:   details,
+	}
+	if err != nil {
+		status.Status = presenter.HealthStatusUnavailable
+		status.Error = err.Error()
+	}
+	return status
+}
@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+
+	"todo-api/internal/util/logging"
+)
+
+// requestLogger returns the logger scoped to the current request
+func requestLogger(c echo.Context) *logrus.Entry {
+	return logging.FromContext(c.Request().Context())
+}
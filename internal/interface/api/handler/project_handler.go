@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/middleware"
+	"todo-api/internal/interface/api/presenter"
+)
+
+// Project path parameter problems
+var (
+	errInvalidProjectID = presenter.NewProblem(http.StatusBadRequest, "invalid_project_id", "Invalid project ID")
+	errInvalidMemberID  = presenter.NewProblem(http.StatusBadRequest, "invalid_member_id", "Invalid member ID")
+)
+
+// ProjectHandler handles HTTP requests related to shared projects and their members
+type ProjectHandler struct {
+	projectUseCase usecase.ProjectUseCase
+}
+
+// NewProjectHandler creates a new ProjectHandler
+func NewProjectHandler(projectUseCase usecase.ProjectUseCase) *ProjectHandler {
+	return &ProjectHandler{
+		projectUseCase: projectUseCase,
+	}
+}
+
+// ProjectRequest represents the request to create or rename a project
+type ProjectRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// AddMemberRequest represents the request to add a user to a project or workspace
+type AddMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required"`
+}
+
+// MemberRoleRequest represents the request to change the role of a member
+type MemberRoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// CreateProject handles the creation of a project owned by the current user
+func (h *ProjectHandler) CreateProject(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse and validate request
+	req := new(ProjectRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Create project
+	membership, err := h.projectUseCase.CreateProject(c.Request().Context(), req.Name, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusCreated, presenter.ProjectResponse(membership))
+}
+
+// GetProjects handles retrieving the projects the current user is a member of
+func (h *ProjectHandler) GetProjects(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Get projects
+	memberships, err := h.projectUseCase.GetUserProjects(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.ProjectsResponse(memberships))
+}
+
+// GetProject handles retrieving a project by ID
+func (h *ProjectHandler) GetProject(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse project ID
+	projectID, err := parseProjectID(c)
+	if err != nil {
+		return err
+	}
+
+	// Get project
+	membership, err := h.projectUseCase.GetProject(c.Request().Context(), projectID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.ProjectResponse(membership))
+}
+
+// UpdateProject handles renaming a project
+func (h *ProjectHandler) UpdateProject(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse project ID
+	projectID, err := parseProjectID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse and validate request
+	req := new(ProjectRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Rename project
+	membership, err := h.projectUseCase.RenameProject(c.Request().Context(), projectID, req.Name, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.ProjectResponse(membership))
+}
+
+// DeleteProject handles deleting a project with its todos
+func (h *ProjectHandler) DeleteProject(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse project ID
+	projectID, err := parseProjectID(c)
+	if err != nil {
+		return err
+	}
+
+	// Delete project
+	if err := h.projectUseCase.DeleteProject(c.Request().Context(), projectID, userID); err != nil {
+		return err
+	}
+
+	// Return response
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetMembers handles retrieving the members of a project
+func (h *ProjectHandler) GetMembers(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse project ID
+	projectID, err := parseProjectID(c)
+	if err != nil {
+		return err
+	}
+
+	// Get members
+	members, err := h.projectUseCase.GetMembers(c.Request().Context(), projectID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.MembersResponse(members))
+}
+
+// AddMember handles sharing a project with another user
+func (h *ProjectHandler) AddMember(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse project ID
+	projectID, err := parseProjectID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse and validate request
+	req := new(AddMemberRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Add member
+	member, err := h.projectUseCase.AddMember(c.Request().Context(), projectID, req.Email, entity.ProjectRole(req.Role), userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusCreated, presenter.MemberResponse(member))
+}
+
+// UpdateMember handles changing the role of a member
+func (h *ProjectHandler) UpdateMember(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse project and member IDs
+	projectID, err := parseProjectID(c)
+	if err != nil {
+		return err
+	}
+	memberID, err := parseMemberID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse and validate request
+	req := new(MemberRoleRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Change role
+	member, err := h.projectUseCase.ChangeMemberRole(c.Request().Context(), projectID, memberID, entity.ProjectRole(req.Role), userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.MemberResponse(member))
+}
+
+// RemoveMember handles removing a member from a project, or leaving it
+func (h *ProjectHandler) RemoveMember(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse project and member IDs
+	projectID, err := parseProjectID(c)
+	if err != nil {
+		return err
+	}
+	memberID, err := parseMemberID(c)
+	if err != nil {
+		return err
+	}
+
+	// Remove member
+	if err := h.projectUseCase.RemoveMember(c.Request().Context(), projectID, memberID, userID); err != nil {
+		return err
+	}
+
+	// Return response
+	return c.NoContent(http.StatusNoContent)
+}
+
+// parseProjectID parses the project ID path parameter
+func parseProjectID(c echo.Context) (uint, error) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, errInvalidProjectID
+	}
+	return uint(projectID), nil
+}
+
+// parseMemberID parses the member user ID path parameter
+func parseMemberID(c echo.Context) (uint, error) {
+	memberID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		return 0, errInvalidMemberID
+	}
+	return uint(memberID), nil
+}
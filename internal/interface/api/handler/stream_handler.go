@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/middleware"
+	"todo-api/internal/interface/api/presenter"
+)
+
+// HeaderLastEventID is the request header carrying the ID of the last event
+// a reconnecting client received
+const HeaderLastEventID = "Last-Event-ID"
+
+// errInvalidLastEventID is returned for a malformed last event ID
+var errInvalidLastEventID = presenter.NewProblem(http.StatusBadRequest, "invalid_last_event_id", "Last event ID must be an event ID")
+
+// StreamHandler handles the real-time todo event streams
+type StreamHandler struct {
+	streamUseCase usecase.StreamUseCase
+	heartbeat     time.Duration
+}
+
+// NewStreamHandler creates a new StreamHandler sending a keep-alive to idle
+// streams every heartbeat
+func NewStreamHandler(streamUseCase usecase.StreamUseCase, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{
+		streamUseCase: streamUseCase,
+		heartbeat:     heartbeat,
+	}
+}
+
+// StreamEvents handles streaming todo events as Server-Sent Events
+func (h *StreamHandler) StreamEvents(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse the ID of the last event received before reconnecting
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		return err
+	}
+
+	// Streams outlive the write timeout of the server
+	res := c.Response()
+	if err := http.NewResponseController(res).SetWriteDeadline(time.Time{}); err != nil {
+		return err
+	}
+
+	// Subscribe until the client disconnects
+	ctx := c.Request().Context()
+	stream := h.streamUseCase.Subscribe(ctx, userID, lastEventID)
+
+	// Start the stream, telling the client to reload its todos if events were lost
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	if stream.Reset {
+		if err := writeServerSentEvent(res, 0, presenter.StreamResetType, presenter.StreamResetResponseData()); err != nil {
+			return nil
+		}
+	}
+	res.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-stream.Events:
+			if !ok {
+				// The client reconnects with the ID of the last event
+				return nil
+			}
+			data := presenter.TodoEventResponseData(event)
+			if err := writeServerSentEvent(res, data.ID, data.Type, data); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+	}
+}
+
+// StreamEventsWebSocket handles streaming todo events over a WebSocket, as
+// JSON text messages of the same shape as the Server-Sent Events
+func (h *StreamHandler) StreamEventsWebSocket(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse the ID of the last event received before reconnecting
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		return err
+	}
+
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		// Streams outlive the read and write timeouts of the server
+		if err := ws.SetDeadline(time.Time{}); err != nil {
+			return
+		}
+
+		// The hijacked connection is not watched by the server, the stream
+		// ends once reading from the client fails
+		ctx, cancel := context.WithCancel(c.Request().Context())
+		defer cancel()
+		go func() {
+			defer cancel()
+			var discarded []byte
+			for websocket.Message.Receive(ws, &discarded) == nil {
+			}
+		}()
+
+		// Subscribe until the client disconnects, telling it to reload its
+		// todos if events were lost
+		stream := h.streamUseCase.Subscribe(ctx, userID, lastEventID)
+		if stream.Reset {
+			if err := websocket.JSON.Send(ws, presenter.StreamResetResponseData()); err != nil {
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(h.heartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-stream.Events:
+				if !ok {
+					// The client reconnects with the ID of the last event
+					return
+				}
+				if err := websocket.JSON.Send(ws, presenter.TodoEventResponseData(event)); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				ws.PayloadType = websocket.PingFrame
+				_, err := ws.Write(nil)
+				ws.PayloadType = websocket.TextFrame
+				if err != nil {
+					return
+				}
+			}
+		}
+	}}
+	server.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// parseLastEventID parses the ID of the last event received by a
+// reconnecting client, from the Last-Event-ID header sent by EventSource
+// clients or the last_event_id query parameter. It is zero for new clients.
+func parseLastEventID(c echo.Context) (uint, error) {
+	value := c.Request().Header.Get(HeaderLastEventID)
+	if value == "" {
+		value = c.QueryParam("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	lastEventID, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, errInvalidLastEventID
+	}
+	return uint(lastEventID), nil
+}
+
+// writeServerSentEvent writes an event with a JSON data field, a zero ID
+// leaves the last event ID of the client unchanged
+func writeServerSentEvent(w http.ResponseWriter, id uint, event string, data interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, encoded)
+	return err
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/middleware"
+	"todo-api/internal/interface/api/presenter"
+)
+
+// errInvalidSyncToken is returned for a sync token that was not issued by the API
+var errInvalidSyncToken = presenter.NewProblem(http.StatusBadRequest, "invalid_sync_token", "Invalid sync token")
+
+// SyncMutationRequest represents a change of a todo made by an offline client.
+// Updates and deletions carry the ID and the change sequence number of the
+// todo as the client last received it.
+type SyncMutationRequest struct {
+	Op          string `json:"op" validate:"required"`
+	ID          uint   `json:"id"`
+	ChangeSeq   uint64 `json:"change_seq"`
+	Title       string `json:"title" validate:"max=255"`
+	Description string `json:"description"`
+	Completed   bool   `json:"completed"`
+	ProjectID   *uint  `json:"project_id" validate:"omitempty,gt=0"`
+}
+
+// SyncRequest represents the request to apply the mutations of an offline client
+type SyncRequest struct {
+	Mutations []SyncMutationRequest `json:"mutations" validate:"required,max=100,dive"`
+}
+
+// SyncHandler handles the delta sync of offline clients
+type SyncHandler struct {
+	syncUseCase usecase.SyncUseCase
+}
+
+// NewSyncHandler creates a new SyncHandler
+func NewSyncHandler(syncUseCase usecase.SyncUseCase) *SyncHandler {
+	return &SyncHandler{
+		syncUseCase: syncUseCase,
+	}
+}
+
+// GetChanges handles retrieving the changes of todos since a sync token
+func (h *SyncHandler) GetChanges(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse sync token and page size
+	token, err := presenter.DecodeSyncToken(c.QueryParam("sync_token"))
+	if err != nil {
+		return errInvalidSyncToken
+	}
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+
+	// Get changes
+	page, err := h.syncUseCase.GetChanges(c.Request().Context(), token, pageSize, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.SyncChangesResponse(page.Todos, page.Tombstones, page.Token, page.HasMore, page.Reset))
+}
+
+// ApplyMutations handles applying the mutations of an offline client, each on its own
+func (h *SyncHandler) ApplyMutations(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse and validate request
+	req := new(SyncRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	mutations := make([]usecase.SyncMutation, len(req.Mutations))
+	for i, mutation := range req.Mutations {
+		mutations[i] = usecase.SyncMutation{
+			Op:          usecase.SyncOp(mutation.Op),
+			ID:          mutation.ID,
+			ChangeSeq:   mutation.ChangeSeq,
+			Title:       mutation.Title,
+			Description: mutation.Description,
+			Completed:   mutation.Completed,
+			ProjectID:   mutation.ProjectID,
+		}
+	}
+
+	// Apply mutations
+	results, err := h.syncUseCase.ApplyMutations(c.Request().Context(), mutations, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response, failed mutations are described like errors
+	data := make([]presenter.SyncResultData, 0, len(results))
+	for i, result := range results {
+		item := presenter.SyncResultData{Index: i, Status: presenter.SyncApplied}
+		if result.Todo != nil {
+			todo := presenter.TodoResponseData(result.Todo)
+			item.Todo = &todo
+		}
+		if result.Err != nil {
+			problem := problemFor(result.Err)
+			item.Status = presenter.SyncFailed
+			if errors.Is(result.Err, usecase.ErrTodoChanged) {
+				item.Status = presenter.SyncConflict
+			}
+			item.Error = &presenter.BulkItemError{Status: problem.Status, Code: problem.Code, Message: problem.Detail}
+		}
+		data = append(data, item)
+	}
+	return c.JSON(http.StatusOK, presenter.SyncResultsResponse(data))
+}
@@ -5,7 +5,6 @@ import (
 	"strconv"
 
 	"github.com/labstack/echo/v4"
-	"github.com/sirupsen/logrus"
 
 	"todo-api/internal/domain/entity"
 	"todo-api/internal/domain/usecase"
@@ -13,17 +12,34 @@ import (
 	"todo-api/internal/interface/api/presenter"
 )
 
+// Request parameter problems
+var (
+	errInvalidTodoID = presenter.NewProblem(http.StatusBadRequest, "invalid_todo_id", "Invalid todo ID")
+	errInvalidCursor = presenter.NewProblem(http.StatusBadRequest, "invalid_cursor", "Invalid pagination cursor")
+	errInvalidSearch = presenter.NewProblem(http.StatusBadRequest, "invalid_search_mode", "Search mode must be substring or fulltext")
+
+	errInvalidAssigneeFilter = presenter.NewProblem(http.StatusBadRequest, "invalid_assignee_filter", "Assignee filter must be me")
+)
+
+// assigneeMe is the value of the assignee query parameter selecting the todos
+// assigned to the current user
+const assigneeMe = "me"
+
+// Search modes of the search query parameter
+const (
+	searchModeSubstring = "substring"
+	searchModeFullText  = "fulltext"
+)
+
 // TodoHandler handles HTTP requests related to todos
 type TodoHandler struct {
 	todoUseCase usecase.TodoUseCase
-	logger      *logrus.Logger
 }
 
 // NewTodoHandler creates a new TodoHandler
-func NewTodoHandler(todoUseCase usecase.TodoUseCase, logger *logrus.Logger) *TodoHandler {
+func NewTodoHandler(todoUseCase usecase.TodoUseCase) *TodoHandler {
 	return &TodoHandler{
 		todoUseCase: todoUseCase,
-		logger:      logger,
 	}
 }
 
@@ -31,6 +47,8 @@ func NewTodoHandler(todoUseCase usecase.TodoUseCase, logger *logrus.Logger) *Tod
 type CreateTodoRequest struct {
 	Title       string `json:"title" validate:"required,min=1,max=255"`
 	Description string `json:"description"`
+	ProjectID   *uint  `json:"project_id" validate:"omitempty,gt=0"`
+	AssigneeID  *uint  `json:"assignee_id" validate:"omitempty,gt=0"`
 }
 
 // UpdateTodoRequest represents the request to update a todo
@@ -40,29 +58,56 @@ type UpdateTodoRequest struct {
 	Completed   bool   `json:"completed"`
 }
 
+// AssignTodoRequest represents the request to assign a todo to a user
+type AssignTodoRequest struct {
+	AssigneeID uint `json:"assignee_id" validate:"required,gt=0"`
+}
+
+// TodoFilterRequest represents todo filters sent in a request body
+type TodoFilterRequest struct {
+	Completed  *bool  `json:"completed"`
+	Search     string `json:"search" validate:"max=255"`
+	Expression string `json:"expression" validate:"max=1000"`
+}
+
+// BulkTodoRequest represents the request to apply an action to many todos,
+// selected either by ID or by filter. ProjectID is the destination of move,
+// Tags are the tags tag adds and untag removes.
+type BulkTodoRequest struct {
+	Action    string             `json:"action" validate:"required"`
+	ProjectID uint               `json:"project_id"`
+	Tags      []string           `json:"tags"`
+	IDs       []uint             `json:"ids" validate:"max=500,dive,gt=0"`
+	Filter    *TodoFilterRequest `json:"filter"`
+}
+
+// todoFilter converts the request to a todo filter
+func (r *TodoFilterRequest) todoFilter() (entity.TodoFilter, error) {
+	expr, err := entity.ParseTodoFilter(r.Expression)
+	if err != nil {
+		return entity.TodoFilter{}, err
+	}
+	return entity.TodoFilter{Completed: r.Completed, Search: r.Search, Expr: expr}, nil
+}
+
 // CreateTodo handles the creation of a new todo
 func (h *TodoHandler) CreateTodo(c echo.Context) error {
 	// Get user ID from context
 	userID := middleware.GetUserIDFromContext(c)
 
-	// Parse request
+	// Parse and validate request
 	req := new(CreateTodoRequest)
 	if err := c.Bind(req); err != nil {
-		h.logger.WithError(err).Error("Failed to bind request")
-		return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid request"))
+		return err
 	}
-
-	// Validate request
 	if err := c.Validate(req); err != nil {
-		h.logger.WithError(err).Error("Invalid request payload")
-		return c.JSON(http.StatusBadRequest, presenter.ValidationErrorResponse(err))
+		return err
 	}
 
 	// Create todo
-	todo, err := h.todoUseCase.CreateTodo(c.Request().Context(), req.Title, req.Description, userID)
+	todo, err := h.todoUseCase.CreateTodo(c.Request().Context(), req.Title, req.Description, req.ProjectID, req.AssigneeID, userID)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to create todo")
-		return c.JSON(http.StatusInternalServerError, presenter.ErrorResponse("Failed to create todo"))
+		return err
 	}
 
 	// Return response
@@ -75,24 +120,15 @@ func (h *TodoHandler) GetTodo(c echo.Context) error {
 	userID := middleware.GetUserIDFromContext(c)
 
 	// Parse todo ID
-	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	todoID, err := parseTodoID(c)
 	if err != nil {
-		h.logger.WithError(err).Error("Invalid todo ID")
-		return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid todo ID"))
+		return err
 	}
 
 	// Get todo
-	todo, err := h.todoUseCase.GetTodoByID(c.Request().Context(), uint(todoID), userID)
-	if err != nil {
-		switch err {
-		case usecase.ErrTodoNotFound:
-			return c.JSON(http.StatusNotFound, presenter.ErrorResponse("Todo not found"))
-		case usecase.ErrNotAuthorized:
-			return c.JSON(http.StatusForbidden, presenter.ErrorResponse("Not authorized to access this todo"))
-		default:
-			h.logger.WithError(err).Error("Failed to get todo")
-			return c.JSON(http.StatusInternalServerError, presenter.ErrorResponse("Failed to get todo"))
-		}
+	todo, err := h.todoUseCase.GetTodoByID(c.Request().Context(), todoID, userID)
+	if err != nil {
+		return err
 	}
 
 	// Return response
@@ -107,6 +143,25 @@ func (h *TodoHandler) GetTodos(c echo.Context) error {
 	// Parse filter parameters
 	filter := entity.TodoFilter{}
 
+	// Parse project, the personal todos are listed without one
+	if c.QueryParams().Has("project_id") {
+		projectID, err := strconv.ParseUint(c.QueryParam("project_id"), 10, 32)
+		if err != nil || projectID == 0 {
+			return errInvalidProjectID
+		}
+		id := uint(projectID)
+		filter.ProjectID = &id
+	}
+
+	// Parse assignee filter, which lists the todos assigned to the user across projects
+	switch c.QueryParam("assignee") {
+	case "":
+	case assigneeMe:
+		filter.AssigneeID = &userID
+	default:
+		return errInvalidAssigneeFilter
+	}
+
 	// Parse completed filter
 	completed := c.QueryParam("completed")
 	if completed != "" {
@@ -116,31 +171,41 @@ func (h *TodoHandler) GetTodos(c echo.Context) error {
 		}
 	}
 
-	// Parse search query
-	filter.Search = c.QueryParam("search")
+	// Parse search query, full-text mode supports "phrases", prefix* and -negated words
+	search := c.QueryParam("search")
+	switch c.QueryParam("search_mode") {
+	case "", searchModeSubstring:
+		filter.Search = search
+	case searchModeFullText:
+		if search != "" {
+			query := entity.ParseSearchQuery(search)
+			filter.FullText = &query
+		}
+	default:
+		return errInvalidSearch
+	}
 
-	// Parse pagination
-	page, err := strconv.Atoi(c.QueryParam("page"))
-	if err != nil || page <= 0 {
-		page = 1
+	// Parse filter expression and sort order
+	expr, err := entity.ParseTodoFilter(c.QueryParam("filter"))
+	if err != nil {
+		return err
 	}
-	filter.Page = page
+	filter.Expr = expr
+	filter.Sort = entity.TodoSort(c.QueryParam("sort"))
 
-	pageSize, err := strconv.Atoi(c.QueryParam("page_size"))
-	if err != nil || pageSize <= 0 {
-		pageSize = 10
+	// Parse pagination
+	if err := parseTodoPage(c, &filter); err != nil {
+		return err
 	}
-	filter.PageSize = pageSize
 
 	// Get todos
-	todos, count, err := h.todoUseCase.GetUserTodos(c.Request().Context(), userID, filter)
+	todoPage, err := h.todoUseCase.GetUserTodos(c.Request().Context(), userID, filter)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to get todos")
-		return c.JSON(http.StatusInternalServerError, presenter.ErrorResponse("Failed to get todos"))
+		return err
 	}
 
 	// Return response
-	return c.JSON(http.StatusOK, presenter.TodosResponse(todos, count, page, pageSize))
+	return todoPageResponse(c, filter, todoPage)
 }
 
 // UpdateTodo handles updating a todo
@@ -149,39 +214,24 @@ func (h *TodoHandler) UpdateTodo(c echo.Context) error {
 	userID := middleware.GetUserIDFromContext(c)
 
 	// Parse todo ID
-	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	todoID, err := parseTodoID(c)
 	if err != nil {
-		h.logger.WithError(err).Error("Invalid todo ID")
-		return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid todo ID"))
+		return err
 	}
 
-	// Parse request
+	// Parse and validate request
 	req := new(UpdateTodoRequest)
 	if err := c.Bind(req); err != nil {
-		h.logger.WithError(err).Error("Failed to bind request")
-		return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid request"))
+		return err
 	}
-
-	// Validate request
 	if err := c.Validate(req); err != nil {
-		h.logger.WithError(err).Error("Invalid request payload")
-		return c.JSON(http.StatusBadRequest, presenter.ValidationErrorResponse(err))
+		return err
 	}
 
 	// Update todo
-	todo, err := h.todoUseCase.UpdateTodo(c.Request().Context(), uint(todoID), req.Title, req.Description, req.Completed, userID)
-	if err != nil {
-		switch err {
-		case usecase.ErrTodoNotFound:
-			return c.JSON(http.StatusNotFound, presenter.ErrorResponse("Todo not found"))
-		case usecase.ErrNotAuthorized:
-			return c.JSON(http.StatusForbidden, presenter.ErrorResponse("Not authorized to update this todo"))
-		case usecase.ErrInvalidTodoData:
-			return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid todo data"))
-		default:
-			h.logger.WithError(err).Error("Failed to update todo")
-			return c.JSON(http.StatusInternalServerError, presenter.ErrorResponse("Failed to update todo"))
-		}
+	todo, err := h.todoUseCase.UpdateTodo(c.Request().Context(), todoID, req.Title, req.Description, req.Completed, userID)
+	if err != nil {
+		return err
 	}
 
 	// Return response
@@ -194,56 +244,178 @@ func (h *TodoHandler) DeleteTodo(c echo.Context) error {
 	userID := middleware.GetUserIDFromContext(c)
 
 	// Parse todo ID
-	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	todoID, err := parseTodoID(c)
 	if err != nil {
-		h.logger.WithError(err).Error("Invalid todo ID")
-		return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid todo ID"))
+		return err
 	}
 
 	// Delete todo
-	err = h.todoUseCase.DeleteTodo(c.Request().Context(), uint(todoID), userID)
-	if err != nil {
-		switch err {
-		case usecase.ErrTodoNotFound:
-			return c.JSON(http.StatusNotFound, presenter.ErrorResponse("Todo not found"))
-		case usecase.ErrNotAuthorized:
-			return c.JSON(http.StatusForbidden, presenter.ErrorResponse("Not authorized to delete this todo"))
-		default:
-			h.logger.WithError(err).Error("Failed to delete todo")
-			return c.JSON(http.StatusInternalServerError, presenter.ErrorResponse("Failed to delete todo"))
-		}
+	if err := h.todoUseCase.DeleteTodo(c.Request().Context(), todoID, userID); err != nil {
+		return err
 	}
 
 	// Return response
 	return c.NoContent(http.StatusNoContent)
 }
 
+// BulkUpdateTodos handles applying an action to many todos in a single transaction
+func (h *TodoHandler) BulkUpdateTodos(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse and validate request
+	req := new(BulkTodoRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	target := usecase.BulkTarget{IDs: req.IDs}
+	if req.Filter != nil {
+		filter, err := req.Filter.todoFilter()
+		if err != nil {
+			return err
+		}
+		target.Filter = &filter
+	}
+
+	// Apply action
+	op := usecase.BulkOperation{Action: usecase.BulkAction(req.Action), ProjectID: req.ProjectID, Tags: req.Tags}
+	result, err := h.todoUseCase.BulkUpdate(c.Request().Context(), op, target, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response, failed items are described like errors
+	items := make([]presenter.BulkItemData, 0, len(result.Items))
+	for _, item := range result.Items {
+		data := presenter.BulkItemData{ID: item.ID, Status: presenter.BulkItemSucceeded}
+		if item.Err != nil {
+			problem := problemFor(item.Err)
+			data.Status = presenter.BulkItemFailed
+			data.Error = &presenter.BulkItemError{Status: problem.Status, Code: problem.Code, Message: problem.Detail}
+		}
+		items = append(items, data)
+	}
+	return c.JSON(http.StatusOK, presenter.BulkResponse(string(result.Action), items))
+}
+
 // CompleteTodo handles marking a todo as completed
 func (h *TodoHandler) CompleteTodo(c echo.Context) error {
 	// Get user ID from context
 	userID := middleware.GetUserIDFromContext(c)
 
 	// Parse todo ID
-	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	todoID, err := parseTodoID(c)
 	if err != nil {
-		h.logger.WithError(err).Error("Invalid todo ID")
-		return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid todo ID"))
+		return err
 	}
 
 	// Complete todo
-	todo, err := h.todoUseCase.CompleteTodo(c.Request().Context(), uint(todoID), userID)
-	if err != nil {
-		switch err {
-		case usecase.ErrTodoNotFound:
-			return c.JSON(http.StatusNotFound, presenter.ErrorResponse("Todo not found"))
-		case usecase.ErrNotAuthorized:
-			return c.JSON(http.StatusForbidden, presenter.ErrorResponse("Not authorized to update this todo"))
-		default:
-			h.logger.WithError(err).Error("Failed to complete todo")
-			return c.JSON(http.StatusInternalServerError, presenter.ErrorResponse("Failed to complete todo"))
-		}
+	todo, err := h.todoUseCase.CompleteTodo(c.Request().Context(), todoID, userID)
+	if err != nil {
+		return err
 	}
 
 	// Return response
 	return c.JSON(http.StatusOK, presenter.TodoResponse(todo))
 }
+
+// AssignTodo handles assigning a todo to a user who can access it
+func (h *TodoHandler) AssignTodo(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse todo ID
+	todoID, err := parseTodoID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse and validate request
+	req := new(AssignTodoRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Assign todo
+	todo, err := h.todoUseCase.AssignTodo(c.Request().Context(), todoID, &req.AssigneeID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.TodoResponse(todo))
+}
+
+// UnassignTodo handles removing the assignee of a todo
+func (h *TodoHandler) UnassignTodo(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse todo ID
+	todoID, err := parseTodoID(c)
+	if err != nil {
+		return err
+	}
+
+	// Unassign todo
+	todo, err := h.todoUseCase.AssignTodo(c.Request().Context(), todoID, nil, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.TodoResponse(todo))
+}
+
+// parseTodoID parses the todo ID path parameter
+func parseTodoID(c echo.Context) (uint, error) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, errInvalidTodoID
+	}
+	return uint(todoID), nil
+}
+
+// parseTodoPage parses the pagination parameters into filter. A cursor parameter,
+// empty for the first page, selects keyset pagination.
+func parseTodoPage(c echo.Context, filter *entity.TodoFilter) error {
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	filter.Page = page
+
+	pageSize, err := strconv.Atoi(c.QueryParam("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = usecase.DefaultPageSize
+	}
+	filter.PageSize = pageSize
+
+	if c.QueryParams().Has("cursor") {
+		cursor, err := presenter.DecodeTodoCursor(c.QueryParam("cursor"))
+		if err != nil {
+			return errInvalidCursor
+		}
+		filter.Cursor = cursor
+	}
+
+	return nil
+}
+
+// todoPageResponse renders a page of todos in the format of its pagination
+func todoPageResponse(c echo.Context, filter entity.TodoFilter, todoPage *usecase.TodoPage) error {
+	if todoPage.Matches != nil {
+		return c.JSON(http.StatusOK, presenter.TodoSearchResponse(todoPage.Matches, todoPage.Total, filter.Page, todoPage.PageSize))
+	}
+	if filter.Cursor != nil {
+		return c.JSON(http.StatusOK, presenter.TodosCursorResponse(todoPage.Todos, todoPage.PageSize, todoPage.Next, todoPage.Prev))
+	}
+	return c.JSON(http.StatusOK, presenter.TodosResponse(todoPage.Todos, todoPage.Total, filter.Page, todoPage.PageSize))
+}
@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
-	"github.com/sirupsen/logrus"
 
 	"todo-api/internal/domain/usecase"
 	"todo-api/internal/interface/api/middleware"
@@ -14,14 +13,12 @@ import (
 // UserHandler handles HTTP requests related to users
 type UserHandler struct {
 	userUseCase usecase.UserUseCase
-	logger      *logrus.Logger
 }
 
 // NewUserHandler creates a new UserHandler
-func NewUserHandler(userUseCase usecase.UserUseCase, logger *logrus.Logger) *UserHandler {
+func NewUserHandler(userUseCase usecase.UserUseCase) *UserHandler {
 	return &UserHandler{
 		userUseCase: userUseCase,
-		logger:      logger,
 	}
 }
 
@@ -52,33 +49,19 @@ type UpdatePasswordRequest struct {
 
 // Register handles user registration
 func (h *UserHandler) Register(c echo.Context) error {
-	// Parse request
+	// Parse and validate request
 	req := new(RegisterRequest)
 	if err := c.Bind(req); err != nil {
-		h.logger.WithError(err).Error("Failed to bind request")
-		return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid request"))
+		return err
 	}
-
-	// Validate request
 	if err := c.Validate(req); err != nil {
-		h.logger.WithError(err).Error("Invalid request payload")
-		return c.JSON(http.StatusBadRequest, presenter.ValidationErrorResponse(err))
+		return err
 	}
 
 	// Register user
 	user, err := h.userUseCase.Register(c.Request().Context(), req.Username, req.Email, req.Password)
 	if err != nil {
-		switch err {
-		case usecase.ErrUsernameExists:
-			return c.JSON(http.StatusConflict, presenter.ErrorResponse("Username already exists"))
-		case usecase.ErrEmailExists:
-			return c.JSON(http.StatusConflict, presenter.ErrorResponse("Email already exists"))
-		case usecase.ErrInvalidUserData:
-			return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid user data"))
-		default:
-			h.logger.WithError(err).Error("Failed to register user")
-			return c.JSON(http.StatusInternalServerError, presenter.ErrorResponse("Failed to register user"))
-		}
+		return err
 	}
 
 	// Return response
@@ -87,29 +70,19 @@ func (h *UserHandler) Register(c echo.Context) error {
 
 // Login handles user login
 func (h *UserHandler) Login(c echo.Context) error {
-	// Parse request
+	// Parse and validate request
 	req := new(LoginRequest)
 	if err := c.Bind(req); err != nil {
-		h.logger.WithError(err).Error("Failed to bind request")
-		return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid request"))
+		return err
 	}
-
-	// Validate request
 	if err := c.Validate(req); err != nil {
-		h.logger.WithError(err).Error("Invalid request payload")
-		return c.JSON(http.StatusBadRequest, presenter.ValidationErrorResponse(err))
+		return err
 	}
 
 	// Login
 	response, err := h.userUseCase.Login(c.Request().Context(), req.Email, req.Password)
 	if err != nil {
-		switch err {
-		case usecase.ErrInvalidCredentials:
-			return c.JSON(http.StatusUnauthorized, presenter.ErrorResponse("Invalid credentials"))
-		default:
-			h.logger.WithError(err).Error("Failed to login")
-			return c.JSON(http.StatusInternalServerError, presenter.ErrorResponse("Failed to login"))
-		}
+		return err
 	}
 
 	// Return response
@@ -124,13 +97,7 @@ func (h *UserHandler) GetProfile(c echo.Context) error {
 	// Get user
 	user, err := h.userUseCase.GetUserByID(c.Request().Context(), userID)
 	if err != nil {
-		switch err {
-		case usecase.ErrUserNotFound:
-			return c.JSON(http.StatusNotFound, presenter.ErrorResponse("User not found"))
-		default:
-			h.logger.WithError(err).Error("Failed to get user profile")
-			return c.JSON(http.StatusInternalServerError, presenter.ErrorResponse("Failed to get user profile"))
-		}
+		return err
 	}
 
 	// Return response
@@ -142,35 +109,19 @@ func (h *UserHandler) UpdateProfile(c echo.Context) error {
 	// Get user ID from context
 	userID := middleware.GetUserIDFromContext(c)
 
-	// Parse request
+	// Parse and validate request
 	req := new(UpdateProfileRequest)
 	if err := c.Bind(req); err != nil {
-		h.logger.WithError(err).Error("Failed to bind request")
-		return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid request"))
+		return err
 	}
-
-	// Validate request
 	if err := c.Validate(req); err != nil {
-		h.logger.WithError(err).Error("Invalid request payload")
-		return c.JSON(http.StatusBadRequest, presenter.ValidationErrorResponse(err))
+		return err
 	}
 
 	// Update profile
 	user, err := h.userUseCase.UpdateProfile(c.Request().Context(), userID, req.Username, req.Email)
 	if err != nil {
-		switch err {
-		case usecase.ErrUserNotFound:
-			return c.JSON(http.StatusNotFound, presenter.ErrorResponse("User not found"))
-		case usecase.ErrUsernameExists:
-			return c.JSON(http.StatusConflict, presenter.ErrorResponse("Username already exists"))
-		case usecase.ErrEmailExists:
-			return c.JSON(http.StatusConflict, presenter.ErrorResponse("Email already exists"))
-		case usecase.ErrInvalidUserData:
-			return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid user data"))
-		default:
-			h.logger.WithError(err).Error("Failed to update profile")
-			return c.JSON(http.StatusInternalServerError, presenter.ErrorResponse("Failed to update profile"))
-		}
+		return err
 	}
 
 	// Return response
@@ -182,33 +133,33 @@ func (h *UserHandler) UpdatePassword(c echo.Context) error {
 	// Get user ID from context
 	userID := middleware.GetUserIDFromContext(c)
 
-	// Parse request
+	// Parse and validate request
 	req := new(UpdatePasswordRequest)
 	if err := c.Bind(req); err != nil {
-		h.logger.WithError(err).Error("Failed to bind request")
-		return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid request"))
+		return err
 	}
-
-	// Validate request
 	if err := c.Validate(req); err != nil {
-		h.logger.WithError(err).Error("Invalid request payload")
-		return c.JSON(http.StatusBadRequest, presenter.ValidationErrorResponse(err))
+		return err
 	}
 
 	// Update password
 	err := h.userUseCase.UpdatePassword(c.Request().Context(), userID, req.CurrentPassword, req.NewPassword)
 	if err != nil {
-		switch err {
-		case usecase.ErrUserNotFound:
-			return c.JSON(http.StatusNotFound, presenter.ErrorResponse("User not found"))
-		case usecase.ErrInvalidCredentials:
-			return c.JSON(http.StatusUnauthorized, presenter.ErrorResponse("Current password is incorrect"))
-		case usecase.ErrInvalidUserData:
-			return c.JSON(http.StatusBadRequest, presenter.ErrorResponse("Invalid password data"))
-		default:
-			h.logger.WithError(err).Error("Failed to update password")
-			return c.JSON(http.StatusInternalServerError, presenter.ErrorResponse("Failed to update password"))
-		}
+		return err
+	}
+
+	// Return response
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Logout handles revoking the access tokens of the current user
+func (h *UserHandler) Logout(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Revoke tokens
+	if err := h.userUseCase.Logout(c.Request().Context(), userID); err != nil {
+		return err
 	}
 
 	// Return response
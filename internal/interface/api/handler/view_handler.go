@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/middleware"
+	"todo-api/internal/interface/api/presenter"
+)
+
+// errInvalidViewID is returned for a malformed view ID path parameter
+var errInvalidViewID = presenter.NewProblem(http.StatusBadRequest, "invalid_view_id", "Invalid view ID")
+
+// ViewHandler handles HTTP requests related to saved views
+type ViewHandler struct {
+	viewUseCase usecase.ViewUseCase
+}
+
+// NewViewHandler creates a new ViewHandler
+func NewViewHandler(viewUseCase usecase.ViewUseCase) *ViewHandler {
+	return &ViewHandler{
+		viewUseCase: viewUseCase,
+	}
+}
+
+// ViewRequest represents the request to create or update a view
+type ViewRequest struct {
+	Name   string            `json:"name" validate:"required,max=100"`
+	Filter TodoFilterRequest `json:"filter"`
+	Sort   string            `json:"sort"`
+}
+
+// input converts the request to a use case input
+func (r *ViewRequest) input() usecase.ViewInput {
+	return usecase.ViewInput{
+		Name:      r.Name,
+		Completed: r.Filter.Completed,
+		Search:    r.Filter.Search,
+		Filter:    r.Filter.Expression,
+		Sort:      entity.TodoSort(r.Sort),
+	}
+}
+
+// CreateView handles saving a new view
+func (h *ViewHandler) CreateView(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse and validate request
+	req := new(ViewRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Create view
+	summary, err := h.viewUseCase.CreateView(c.Request().Context(), req.input(), userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusCreated, presenter.ViewResponse(summary.View, summary.TodoCount))
+}
+
+// GetViews handles retrieving all views of a user with their todo counts
+func (h *ViewHandler) GetViews(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Get views
+	summaries, err := h.viewUseCase.GetUserViews(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	views := make([]*entity.View, 0, len(summaries))
+	todoCounts := make(map[uint]int64, len(summaries))
+	for _, summary := range summaries {
+		views = append(views, summary.View)
+		todoCounts[summary.View.ID] = summary.TodoCount
+	}
+	return c.JSON(http.StatusOK, presenter.ViewsResponse(views, todoCounts))
+}
+
+// GetView handles retrieving a view by ID
+func (h *ViewHandler) GetView(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse view ID
+	viewID, err := parseViewID(c)
+	if err != nil {
+		return err
+	}
+
+	// Get view
+	summary, err := h.viewUseCase.GetView(c.Request().Context(), viewID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.ViewResponse(summary.View, summary.TodoCount))
+}
+
+// UpdateView handles updating a view
+func (h *ViewHandler) UpdateView(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse view ID
+	viewID, err := parseViewID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse and validate request
+	req := new(ViewRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Update view
+	summary, err := h.viewUseCase.UpdateView(c.Request().Context(), viewID, req.input(), userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.ViewResponse(summary.View, summary.TodoCount))
+}
+
+// DeleteView handles deleting a view
+func (h *ViewHandler) DeleteView(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse view ID
+	viewID, err := parseViewID(c)
+	if err != nil {
+		return err
+	}
+
+	// Delete view
+	if err := h.viewUseCase.DeleteView(c.Request().Context(), viewID, userID); err != nil {
+		return err
+	}
+
+	// Return response
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetViewTodos handles retrieving a page of the todos selected by a view
+func (h *ViewHandler) GetViewTodos(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse view ID
+	viewID, err := parseViewID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse pagination
+	filter := entity.TodoFilter{}
+	if err := parseTodoPage(c, &filter); err != nil {
+		return err
+	}
+
+	// Get todos
+	todoPage, err := h.viewUseCase.GetViewTodos(c.Request().Context(), viewID, userID, filter)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return todoPageResponse(c, filter, todoPage)
+}
+
+// parseViewID parses the view ID path parameter
+func parseViewID(c echo.Context) (uint, error) {
+	viewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, errInvalidViewID
+	}
+	return uint(viewID), nil
+}
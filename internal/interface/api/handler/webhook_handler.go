@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/middleware"
+	"todo-api/internal/interface/api/presenter"
+)
+
+// Request parameter problems
+var (
+	errInvalidWebhookID  = presenter.NewProblem(http.StatusBadRequest, "invalid_webhook_id", "Invalid webhook ID")
+	errInvalidDeliveryID = presenter.NewProblem(http.StatusBadRequest, "invalid_delivery_id", "Invalid delivery ID")
+)
+
+// WebhookHandler handles HTTP requests related to webhooks
+type WebhookHandler struct {
+	webhookUseCase usecase.WebhookUseCase
+}
+
+// NewWebhookHandler creates a new WebhookHandler
+func NewWebhookHandler(webhookUseCase usecase.WebhookUseCase) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUseCase: webhookUseCase,
+	}
+}
+
+// WebhookRequest represents the request to create or update a webhook
+type WebhookRequest struct {
+	URL    string   `json:"url" validate:"required,max=2048"`
+	Events []string `json:"events" validate:"required,min=1"`
+
+	// Active defaults to true
+	Active *bool `json:"active"`
+}
+
+// input converts the request to a use case input
+func (r *WebhookRequest) input() usecase.WebhookInput {
+	events := make([]entity.TodoEventType, 0, len(r.Events))
+	for _, event := range r.Events {
+		events = append(events, entity.TodoEventType(event))
+	}
+	return usecase.WebhookInput{
+		URL:    r.URL,
+		Events: events,
+		Active: r.Active == nil || *r.Active,
+	}
+}
+
+// CreateWebhook handles registering a new webhook
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse and validate request
+	req := new(WebhookRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Create webhook
+	webhook, err := h.webhookUseCase.CreateWebhook(c.Request().Context(), req.input(), userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response, the only one including the secret
+	return c.JSON(http.StatusCreated, presenter.WebhookCreatedResponse(webhook))
+}
+
+// GetWebhooks handles retrieving all webhooks of a user
+func (h *WebhookHandler) GetWebhooks(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Get webhooks
+	webhooks, err := h.webhookUseCase.GetUserWebhooks(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.WebhooksResponse(webhooks))
+}
+
+// GetWebhook handles retrieving a webhook by ID
+func (h *WebhookHandler) GetWebhook(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse webhook ID
+	webhookID, err := parseWebhookID(c)
+	if err != nil {
+		return err
+	}
+
+	// Get webhook
+	webhook, err := h.webhookUseCase.GetWebhook(c.Request().Context(), webhookID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.WebhookResponse(webhook))
+}
+
+// UpdateWebhook handles updating a webhook
+func (h *WebhookHandler) UpdateWebhook(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse webhook ID
+	webhookID, err := parseWebhookID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse and validate request
+	req := new(WebhookRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Update webhook
+	webhook, err := h.webhookUseCase.UpdateWebhook(c.Request().Context(), webhookID, req.input(), userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.WebhookResponse(webhook))
+}
+
+// DeleteWebhook handles deleting a webhook
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse webhook ID
+	webhookID, err := parseWebhookID(c)
+	if err != nil {
+		return err
+	}
+
+	// Delete webhook
+	if err := h.webhookUseCase.DeleteWebhook(c.Request().Context(), webhookID, userID); err != nil {
+		return err
+	}
+
+	// Return response
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetDeliveries handles retrieving a page of the delivery log of a webhook, newest first
+func (h *WebhookHandler) GetDeliveries(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse webhook ID
+	webhookID, err := parseWebhookID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse pagination, the use case applies the defaults and limits
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+
+	// Get deliveries
+	deliveryPage, err := h.webhookUseCase.GetDeliveries(c.Request().Context(), webhookID, userID, page, pageSize)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.DeliveriesResponse(deliveryPage.Deliveries, deliveryPage.Total, deliveryPage.Page, deliveryPage.PageSize))
+}
+
+// Redeliver handles scheduling a new delivery of the event of a delivery
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse webhook and delivery IDs
+	webhookID, err := parseWebhookID(c)
+	if err != nil {
+		return err
+	}
+	deliveryID, err := strconv.ParseUint(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		return errInvalidDeliveryID
+	}
+
+	// Schedule redelivery
+	delivery, err := h.webhookUseCase.Redeliver(c.Request().Context(), webhookID, uint(deliveryID), userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response, the delivery is sent by the background deliverer
+	return c.JSON(http.StatusAccepted, presenter.DeliveryResponse(delivery))
+}
+
+// parseWebhookID parses the webhook ID path parameter
+func parseWebhookID(c echo.Context) (uint, error) {
+	webhookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, errInvalidWebhookID
+	}
+	return uint(webhookID), nil
+}
@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/middleware"
+	"todo-api/internal/interface/api/presenter"
+)
+
+// errInvalidWorkspaceID is returned for a malformed workspace path parameter
+var errInvalidWorkspaceID = presenter.NewProblem(http.StatusBadRequest, "invalid_workspace_id", "Invalid workspace ID")
+
+// WorkspaceHandler handles HTTP requests related to workspaces and their members
+type WorkspaceHandler struct {
+	workspaceUseCase usecase.WorkspaceUseCase
+}
+
+// NewWorkspaceHandler creates a new WorkspaceHandler
+func NewWorkspaceHandler(workspaceUseCase usecase.WorkspaceUseCase) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceUseCase: workspaceUseCase,
+	}
+}
+
+// WorkspaceRequest represents the request to create or rename a workspace
+type WorkspaceRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// CreateWorkspace handles the creation of a workspace owned by the current user
+func (h *WorkspaceHandler) CreateWorkspace(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse and validate request
+	req := new(WorkspaceRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Create workspace
+	membership, err := h.workspaceUseCase.CreateWorkspace(c.Request().Context(), req.Name, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusCreated, presenter.WorkspaceResponse(membership))
+}
+
+// GetWorkspaces handles retrieving the workspaces the current user is a member of
+func (h *WorkspaceHandler) GetWorkspaces(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Get workspaces
+	memberships, err := h.workspaceUseCase.GetUserWorkspaces(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.WorkspacesResponse(memberships))
+}
+
+// GetWorkspace handles retrieving a workspace by ID
+func (h *WorkspaceHandler) GetWorkspace(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse workspace ID
+	workspaceID, err := parseWorkspaceID(c)
+	if err != nil {
+		return err
+	}
+
+	// Get workspace
+	membership, err := h.workspaceUseCase.GetWorkspace(c.Request().Context(), workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.WorkspaceResponse(membership))
+}
+
+// UpdateWorkspace handles renaming a workspace
+func (h *WorkspaceHandler) UpdateWorkspace(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse workspace ID
+	workspaceID, err := parseWorkspaceID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse and validate request
+	req := new(WorkspaceRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Rename workspace
+	membership, err := h.workspaceUseCase.RenameWorkspace(c.Request().Context(), workspaceID, req.Name, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.WorkspaceResponse(membership))
+}
+
+// DeleteWorkspace handles deleting a workspace with its projects and todos
+func (h *WorkspaceHandler) DeleteWorkspace(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse workspace ID
+	workspaceID, err := parseWorkspaceID(c)
+	if err != nil {
+		return err
+	}
+
+	// Delete workspace
+	if err := h.workspaceUseCase.DeleteWorkspace(c.Request().Context(), workspaceID, userID); err != nil {
+		return err
+	}
+
+	// Return response
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetMembers handles retrieving the members of a workspace
+func (h *WorkspaceHandler) GetMembers(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse workspace ID
+	workspaceID, err := parseWorkspaceID(c)
+	if err != nil {
+		return err
+	}
+
+	// Get members
+	members, err := h.workspaceUseCase.GetMembers(c.Request().Context(), workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.WorkspaceMembersResponse(members))
+}
+
+// AddMember handles adding another user to a workspace
+func (h *WorkspaceHandler) AddMember(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse workspace ID
+	workspaceID, err := parseWorkspaceID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse and validate request
+	req := new(AddMemberRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Add member
+	member, err := h.workspaceUseCase.AddMember(c.Request().Context(), workspaceID, req.Email, entity.WorkspaceRole(req.Role), userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusCreated, presenter.WorkspaceMemberResponse(member))
+}
+
+// UpdateMember handles changing the role of a member
+func (h *WorkspaceHandler) UpdateMember(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse workspace and member IDs
+	workspaceID, err := parseWorkspaceID(c)
+	if err != nil {
+		return err
+	}
+	memberID, err := parseMemberID(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse and validate request
+	req := new(MemberRoleRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return err
+	}
+
+	// Change role
+	member, err := h.workspaceUseCase.ChangeMemberRole(c.Request().Context(), workspaceID, memberID, entity.WorkspaceRole(req.Role), userID)
+	if err != nil {
+		return err
+	}
+
+	// Return response
+	return c.JSON(http.StatusOK, presenter.WorkspaceMemberResponse(member))
+}
+
+// RemoveMember handles removing a member from a workspace, or leaving it
+func (h *WorkspaceHandler) RemoveMember(c echo.Context) error {
+	// Get user ID from context
+	userID := middleware.GetUserIDFromContext(c)
+
+	// Parse workspace and member IDs
+	workspaceID, err := parseWorkspaceID(c)
+	if err != nil {
+		return err
+	}
+	memberID, err := parseMemberID(c)
+	if err != nil {
+		return err
+	}
+
+	// Remove member
+	if err := h.workspaceUseCase.RemoveMember(c.Request().Context(), workspaceID, memberID, userID); err != nil {
+		return err
+	}
+
+	// Return response
+	return c.NoContent(http.StatusNoContent)
+}
+
+// parseWorkspaceID parses the workspace ID path parameter
+func parseWorkspaceID(c echo.Context) (uint, error) {
+	workspaceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, errInvalidWorkspaceID
+	}
+	return uint(workspaceID), nil
+}
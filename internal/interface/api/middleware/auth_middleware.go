@@ -1,12 +1,16 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 
+	"todo-api/internal/interface/api/presenter"
 	"todo-api/internal/util/jwt"
+	"todo-api/internal/util/logging"
 )
 
 // User context key
@@ -15,15 +19,27 @@ const (
 	UserUsernameKey = "username"
 )
 
+// Authentication problems
+var (
+	errMissingAuthorization   = presenter.NewProblem(http.StatusUnauthorized, "authorization_required", "Authorization header is required")
+	errMalformedAuthorization = presenter.NewProblem(http.StatusUnauthorized, "malformed_authorization", "Authorization header format must be Bearer {token}")
+	errInvalidToken           = presenter.NewProblem(http.StatusUnauthorized, "invalid_token", "Invalid or expired token")
+)
+
+// TokenVerifier validates access tokens, including their revocation
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context, token string) (*jwt.Claims, error)
+}
+
 // AuthMiddleware is a middleware for authentication
 type AuthMiddleware struct {
-	jwtService jwt.JWTService
+	verifier TokenVerifier
 }
 
 // NewAuthMiddleware creates a new AuthMiddleware
-func NewAuthMiddleware(jwtService jwt.JWTService) *AuthMiddleware {
+func NewAuthMiddleware(verifier TokenVerifier) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtService: jwtService,
+		verifier: verifier,
 	}
 }
 
@@ -33,39 +49,54 @@ func (m *AuthMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 		// Get authorization header
 		authHeader := c.Request().Header.Get("Authorization")
 		if authHeader == "" {
-			return c.JSON(http.StatusUnauthorized, map[string]string{
-				"error": "Authorization header is required",
-			})
+			return errMissingAuthorization
 		}
 
 		// Check if the token is in the correct format
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			return c.JSON(http.StatusUnauthorized, map[string]string{
-				"error": "Authorization header format must be Bearer {token}",
-			})
+			return errMalformedAuthorization
 		}
 
 		// Extract token
 		tokenString := parts[1]
 
-		// Validate token
-		claims, err := m.jwtService.ValidateToken(tokenString)
+		// Validate token, rejecting revoked tokens
+		claims, err := m.verifier.VerifyToken(c.Request().Context(), tokenString)
 		if err != nil {
-			return c.JSON(http.StatusUnauthorized, map[string]string{
-				"error": "Invalid or expired token",
-			})
+			return err
 		}
 
 		// Set user ID in context
 		c.Set(UserIDKey, claims.UserID)
 		c.Set(UserUsernameKey, claims.Username)
 
+		// Attach the user to the request-scoped logger
+		ctx := logging.WithFields(c.Request().Context(), logrus.Fields{"user_id": claims.UserID})
+		c.SetRequest(c.Request().WithContext(ctx))
+
 		// Continue
 		return next(c)
 	}
 }
 
+// QueryCredentials copies the access_token and workspace_id query parameters
+// to the Authorization and X-Workspace-ID headers when those are absent. It
+// serves clients that cannot set request headers, such as browser EventSource
+// and WebSocket clients, and must run before Authenticate.
+func QueryCredentials(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		header := c.Request().Header
+		if token := c.QueryParam("access_token"); token != "" && header.Get("Authorization") == "" {
+			header.Set("Authorization", "Bearer "+token)
+		}
+		if workspaceID := c.QueryParam("workspace_id"); workspaceID != "" && header.Get(HeaderWorkspaceID) == "" {
+			header.Set(HeaderWorkspaceID, workspaceID)
+		}
+		return next(c)
+	}
+}
+
 // GetUserIDFromContext gets the user ID from the context
 func GetUserIDFromContext(c echo.Context) uint {
 	userID := c.Get(UserIDKey)
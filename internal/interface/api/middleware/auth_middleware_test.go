@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/util/jwt"
+)
+
+// errRevoked is returned by tokenList for revoked tokens
+var errRevoked = errors.New("token revoked")
+
+// tokenList is a TokenVerifier accepting the listed tokens
+type tokenList map[string]*jwt.Claims
+
+// VerifyToken implements TokenVerifier
+func (l tokenList) VerifyToken(ctx context.Context, token string) (*jwt.Claims, error) {
+	claims, ok := l[token]
+	if !ok {
+		return nil, errRevoked
+	}
+	return claims, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	auth := NewAuthMiddleware(tokenList{"valid": {UserID: 7, Username: "alice"}})
+
+	tests := []struct {
+		name   string
+		header string
+		want   error
+	}{
+		{"valid token", "Bearer valid", nil},
+		{"lowercase scheme", "bearer valid", nil},
+		{"missing header", "", errMissingAuthorization},
+		{"basic scheme", "Basic dXNlcjpwYXNz", errMalformedAuthorization},
+		{"rejected token", "Bearer revoked", errRevoked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/todos", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			var userID uint
+			err := auth.Authenticate(func(c echo.Context) error {
+				userID = GetUserIDFromContext(c)
+				return nil
+			})(c)
+			if err != tt.want {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && (userID != 7 || GetUsernameFromContext(c) != "alice") {
+				t.Errorf("got user %d %q, want 7 alice", userID, GetUsernameFromContext(c))
+			}
+		})
+	}
+}
+
+func TestQueryCredentials(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/stream?access_token=abc&workspace_id=3", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	if err := QueryCredentials(func(echo.Context) error { return nil })(c); err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer abc" {
+		t.Errorf("got Authorization %q, want the query token", got)
+	}
+	if got := req.Header.Get(HeaderWorkspaceID); got != "3" {
+		t.Errorf("got %s %q, want the query workspace", HeaderWorkspaceID, got)
+	}
+}
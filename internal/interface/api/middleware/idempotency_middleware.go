@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+
+	"todo-api/internal/infrastructure/idempotency"
+	"todo-api/internal/interface/api/presenter"
+	"todo-api/internal/util/logging"
+)
+
+// Idempotency headers
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// maxIdempotencyKeyLength limits the size of idempotency keys
+const maxIdempotencyKeyLength = 255
+
+// Errors returned for requests with an Idempotency-Key header
+var (
+	errInvalidIdempotencyKey = presenter.NewProblem(http.StatusBadRequest, "invalid_idempotency_key",
+		fmt.Sprintf("Idempotency-Key must be 1 to %d printable ASCII characters", maxIdempotencyKeyLength))
+	errIdempotencyKeyReused = presenter.NewProblem(http.StatusUnprocessableEntity, "idempotency_key_reused",
+		"Idempotency-Key was already used for a different request")
+	errIdempotencyKeyInProgress = presenter.NewProblem(http.StatusConflict, "idempotency_key_in_progress",
+		"A request with this Idempotency-Key is still in progress, retry later")
+)
+
+// IdempotencyMiddleware makes mutating requests safe to retry. The response to
+// a request with an Idempotency-Key header is stored per user and replayed when
+// the request is retried with the same key.
+type IdempotencyMiddleware struct {
+	store        idempotency.Store
+	ttl          time.Duration
+	maxBodyBytes int64
+}
+
+// NewIdempotencyMiddleware creates a new IdempotencyMiddleware that replays
+// responses for ttl and rejects request bodies larger than maxBodyBytes
+func NewIdempotencyMiddleware(store idempotency.Store, ttl time.Duration, maxBodyBytes int64) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		store:        store,
+		ttl:          ttl,
+		maxBodyBytes: maxBodyBytes,
+	}
+}
+
+// Handle replays the stored response of a retried request. Only successful
+// responses are stored, failed requests did not change anything and may be
+// retried with the same key. It must run after authentication.
+func (m *IdempotencyMiddleware) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		key := req.Header.Get(HeaderIdempotencyKey)
+		if key == "" || !isMutating(req.Method) {
+			return next(c)
+		}
+		if !validIdempotencyKey(key) {
+			return errInvalidIdempotencyKey
+		}
+
+		// Fingerprint the request, then restore its body for the handler
+		body, err := io.ReadAll(http.MaxBytesReader(c.Response(), req.Body, m.maxBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return presenter.NewProblem(http.StatusRequestEntityTooLarge, "request_too_large",
+					fmt.Sprintf("Requests with an Idempotency-Key are limited to %d bytes", m.maxBodyBytes))
+			}
+			return err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := requestFingerprint(req, body)
+
+		ctx := req.Context()
+		storeKey := fmt.Sprintf("user:%d:%s", GetUserIDFromContext(c), key)
+		held, err := m.store.Reserve(ctx, storeKey, fingerprint)
+		if err != nil {
+			// Fail open, an unavailable store must not take the API down
+			logging.FromContext(ctx).WithError(err).Warn("Idempotency key reservation failed")
+			return next(c)
+		}
+		if held != nil {
+			switch {
+			case held.Fingerprint != fingerprint:
+				return errIdempotencyKeyReused
+			case held.Response == nil:
+				return errIdempotencyKeyInProgress
+			}
+			return replay(c, held.Response)
+		}
+
+		// Record the response while it is written
+		recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = recorder
+		err = next(c)
+		c.Response().Writer = recorder.ResponseWriter
+
+		// The key must be settled even when the client went away, so detach
+		// from the request but keep its logger and trace
+		ctx = trace.ContextWithSpan(logging.WithLogger(context.Background(), logging.FromContext(ctx)), trace.SpanFromContext(ctx))
+		status := c.Response().Status
+		if err != nil || status < 200 || status >= 300 {
+			if releaseErr := m.store.Release(ctx, storeKey); releaseErr != nil {
+				logging.FromContext(ctx).WithError(releaseErr).Warn("Failed to release idempotency key")
+			}
+			return err
+		}
+
+		response := idempotency.Response{
+			Status:      status,
+			ContentType: c.Response().Header().Get(echo.HeaderContentType),
+			Body:        recorder.body.Bytes(),
+		}
+		if err := m.store.Complete(ctx, storeKey, response, m.ttl); err != nil {
+			logging.FromContext(ctx).WithError(err).Warn("Failed to store idempotent response")
+		}
+		return nil
+	}
+}
+
+// responseRecorder copies the response body written through it
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write implements http.ResponseWriter
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// replay writes a stored response
+func replay(c echo.Context, response *idempotency.Response) error {
+	c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	if len(response.Body) == 0 {
+		return c.NoContent(response.Status)
+	}
+	return c.Blob(response.Status, response.ContentType, response.Body)
+}
+
+// isMutating reports whether requests with method change state
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// validIdempotencyKey limits keys to a bounded size of printable ASCII characters
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// requestFingerprint identifies a request by its method, URI, workspace and body
+func requestFingerprint(req *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	h.Write([]byte(req.Header.Get(HeaderWorkspaceID) + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
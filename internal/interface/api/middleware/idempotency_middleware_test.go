@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/infrastructure/idempotency"
+	"todo-api/internal/interface/api/presenter"
+)
+
+// idempotencyTest runs requests through an IdempotencyMiddleware to a handler
+// counting its calls, which fails requests whose body is "fail"
+type idempotencyTest struct {
+	e       *echo.Echo
+	handler echo.HandlerFunc
+	calls   int
+}
+
+// newIdempotencyTest creates an idempotencyTest with an empty store
+func newIdempotencyTest() *idempotencyTest {
+	it := &idempotencyTest{e: echo.New()}
+	it.handler = NewIdempotencyMiddleware(idempotency.NewMemoryStore(), time.Hour, 16).Handle(func(c echo.Context) error {
+		it.calls++
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		if string(body) == "fail" {
+			return echo.NewHTTPError(http.StatusBadRequest)
+		}
+		return c.String(http.StatusCreated, "created "+string(body))
+	})
+	return it
+}
+
+// do sends a POST request with the key and body as userID
+func (it *idempotencyTest) do(key, body string, userID uint) (*httptest.ResponseRecorder, error) {
+	req := httptest.NewRequest(http.MethodPost, "/api/todos", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	rec := httptest.NewRecorder()
+	c := it.e.NewContext(req, rec)
+	c.Set(UserIDKey, userID)
+	return rec, it.handler(c)
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	it := newIdempotencyTest()
+
+	first, err := it.do("key-1", "todo", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retry, err := it.do("key-1", "todo", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if it.calls != 1 {
+		t.Fatalf("handler called %d times, want once", it.calls)
+	}
+	if retry.Code != first.Code || retry.Body.String() != first.Body.String() {
+		t.Errorf("got replay %d %q, want %d %q", retry.Code, retry.Body, first.Code, first.Body)
+	}
+	if retry.Header().Get(HeaderIdempotentReplayed) != "true" {
+		t.Errorf("missing %s header", HeaderIdempotentReplayed)
+	}
+
+	// Keys are scoped per user, and requests without a key always run
+	if _, err := it.do("key-1", "todo", 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := it.do("", "todo", 1); err != nil {
+		t.Fatal(err)
+	}
+	if it.calls != 3 {
+		t.Errorf("handler called %d times, want 3", it.calls)
+	}
+}
+
+func TestIdempotencyRejectsReusedKey(t *testing.T) {
+	it := newIdempotencyTest()
+	if _, err := it.do("key-1", "todo", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := it.do("key-1", "other todo", 1); err != errIdempotencyKeyReused {
+		t.Errorf("got error %v, want %v", err, errIdempotencyKeyReused)
+	}
+	if _, err := it.do("key\n", "todo", 1); err != errInvalidIdempotencyKey {
+		t.Errorf("got error %v, want %v", err, errInvalidIdempotencyKey)
+	}
+}
+
+func TestIdempotencyReleasesFailedRequest(t *testing.T) {
+	it := newIdempotencyTest()
+	if _, err := it.do("key-1", "fail", 1); err == nil {
+		t.Fatal("want the error of the handler")
+	}
+	if _, err := it.do("key-1", "fail", 1); err == nil {
+		t.Fatal("want the error of the handler on retry")
+	}
+	if it.calls != 2 {
+		t.Errorf("handler called %d times, want the failed request to run again", it.calls)
+	}
+}
+
+func TestIdempotencyRejectsLargeBody(t *testing.T) {
+	it := newIdempotencyTest()
+	_, err := it.do("key-1", strings.Repeat("x", 17), 1)
+	var problem *presenter.Problem
+	if !errors.As(err, &problem) || problem.Status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("got error %v, want a %d problem", err, http.StatusRequestEntityTooLarge)
+	}
+	if it.calls != 0 {
+		t.Error("handler called for a request that was too large")
+	}
+
+	// The limit only applies to requests with a key
+	if _, err := it.do("", strings.Repeat("x", 17), 1); err != nil {
+		t.Fatal(err)
+	}
+}
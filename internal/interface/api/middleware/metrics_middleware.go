@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"todo-api/internal/infrastructure/metrics"
+)
+
+// unmatchedRoute labels requests that did not match any route, keeping label cardinality bounded
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware records request counts and latencies per route
+type MetricsMiddleware struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewMetricsMiddleware creates a new MetricsMiddleware and registers its collectors
+func NewMetricsMiddleware(registerer prometheus.Registerer) *MetricsMiddleware {
+	m := &MetricsMiddleware{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route template.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+	registerer.MustRegister(m.requests, m.duration)
+	return m
+}
+
+// Measure records the request once the handler chain has completed
+func (m *MetricsMiddleware) Measure(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		err := next(c)
+
+		// Use the route template rather than the raw path, e.g. /api/todos/:id
+		route := c.Path()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		method := c.Request().Method
+		status := strconv.Itoa(responseStatus(c, err))
+		m.requests.WithLabelValues(method, route, status).Inc()
+		m.duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// RequireMetricsToken only lets scrapers sending the bearer token through to
+// the metrics endpoint, an empty token leaves it public
+func RequireMetricsToken(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if token == "" {
+			return next
+		}
+		return func(c echo.Context) error {
+			authHeader := c.Request().Header.Get("Authorization")
+			if authHeader == "" {
+				return errMissingAuthorization
+			}
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+				return errMalformedAuthorization
+			}
+			if subtle.ConstantTimeCompare([]byte(parts[1]), []byte(token)) != 1 {
+				return errInvalidToken
+			}
+			return next(c)
+		}
+	}
+}
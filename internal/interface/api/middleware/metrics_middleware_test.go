@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRequireMetricsToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   error
+	}{
+		{"public endpoint", "", "", nil},
+		{"matching token", "s3cret", "Bearer s3cret", nil},
+		{"missing header", "s3cret", "", errMissingAuthorization},
+		{"malformed header", "s3cret", "s3cret", errMalformedAuthorization},
+		{"wrong token", "s3cret", "Bearer guess", errInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			called := false
+			err := RequireMetricsToken(tt.token)(func(echo.Context) error {
+				called = true
+				return nil
+			})(c)
+			if err != tt.want {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+			if called != (tt.want == nil) {
+				t.Errorf("handler called %t, want %t", called, tt.want == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/infrastructure/ratelimit"
+	"todo-api/internal/interface/api/presenter"
+	"todo-api/internal/util/logging"
+)
+
+// Rate limit response headers
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+)
+
+// errRateLimited is returned once the bucket of the request is empty
+var errRateLimited = presenter.NewProblem(http.StatusTooManyRequests, "rate_limited", "Rate limit exceeded, retry later")
+
+// RateLimitMiddleware limits the request rate of a route group per user, or
+// per client IP for unauthenticated requests
+type RateLimitMiddleware struct {
+	store ratelimit.Store
+	group string
+	rule  ratelimit.Rule
+}
+
+// NewRateLimitMiddleware creates a new RateLimitMiddleware. Route groups with
+// different names have separate buckets.
+func NewRateLimitMiddleware(store ratelimit.Store, group string, rule ratelimit.Rule) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		store: store,
+		group: group,
+		rule:  rule,
+	}
+}
+
+// Limit rejects the request with 429 once its bucket is empty. It must run after
+// authentication for requests to be limited per user.
+func (m *RateLimitMiddleware) Limit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		result, err := m.store.Take(c.Request().Context(), m.key(c), m.rule)
+		if err != nil {
+			// Fail open, an unavailable store must not take the API down
+			logging.FromContext(c.Request().Context()).WithError(err).Warn("Rate limit check failed")
+			return next(c)
+		}
+
+		header := c.Response().Header()
+		header.Set(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+		header.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+		header.Set(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			header.Set(echo.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			return errRateLimited
+		}
+
+		return next(c)
+	}
+}
+
+// key returns the bucket key of the request
+func (m *RateLimitMiddleware) key(c echo.Context) string {
+	if userID := GetUserIDFromContext(c); userID != 0 {
+		return fmt.Sprintf("%s:user:%d", m.group, userID)
+	}
+	return fmt.Sprintf("%s:ip:%s", m.group, c.RealIP())
+}
+
+// ceilSeconds rounds d up to whole seconds as used by the rate limit headers
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
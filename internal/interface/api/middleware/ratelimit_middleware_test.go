@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/infrastructure/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	limit := NewRateLimitMiddleware(ratelimit.NewMemoryStore(), "api", ratelimit.Rule{Rate: 1, Burst: 2}).
+		Limit(func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+	e := echo.New()
+	request := func(userID uint) (*httptest.ResponseRecorder, error) {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/todos", nil), rec)
+		if userID != 0 {
+			c.Set(UserIDKey, userID)
+		}
+		return rec, limit(c)
+	}
+
+	for i := 0; i < 2; i++ {
+		rec, err := request(1)
+		if err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+		if got := rec.Header().Get(HeaderRateLimitRemaining); got != []string{"1", "0"}[i] {
+			t.Errorf("request %d: got %s %q", i+1, HeaderRateLimitRemaining, got)
+		}
+	}
+
+	rec, err := request(1)
+	if err != errRateLimited {
+		t.Fatalf("got error %v, want %v", err, errRateLimited)
+	}
+	if rec.Header().Get(echo.HeaderRetryAfter) == "" {
+		t.Errorf("missing %s header", echo.HeaderRetryAfter)
+	}
+
+	// Other users and anonymous clients have buckets of their own
+	if _, err := request(2); err != nil {
+		t.Errorf("other user: %v", err)
+	}
+	if _, err := request(0); err != nil {
+		t.Errorf("anonymous client: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/util/logging"
+)
+
+// Request context key
+const RequestIDKey = "request_id"
+
+// validRequestID limits incoming request IDs to a safe size and character set
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID assigns every request an ID, honoring a well-formed incoming X-Request-ID
+// header, and echoes it in the response
+func RequestID(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		requestID := c.Request().Header.Get(echo.HeaderXRequestID)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set(RequestIDKey, requestID)
+		c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+		return next(c)
+	}
+}
+
+// GetRequestIDFromContext gets the request ID from the context
+func GetRequestIDFromContext(c echo.Context) string {
+	requestID := c.Get(RequestIDKey)
+	if requestID == nil {
+		return ""
+	}
+	return requestID.(string)
+}
+
+// ClientInfo makes the client of the request available to the audit log
+func ClientInfo(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		ctx := usecase.WithClientInfo(req.Context(), usecase.ClientInfo{
+			RemoteIP:  c.RealIP(),
+			UserAgent: req.UserAgent(),
+			RequestID: GetRequestIDFromContext(c),
+		})
+		c.SetRequest(req.WithContext(ctx))
+
+		return next(c)
+	}
+}
+
+// newRequestID generates a random request ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// RequestLogger injects a request-scoped logger into the request context and
+// writes a structured access log entry once the request completes
+type RequestLogger struct {
+	logger *logrus.Logger
+}
+
+// NewRequestLogger creates a new RequestLogger
+func NewRequestLogger(logger *logrus.Logger) *RequestLogger {
+	return &RequestLogger{
+		logger: logger,
+	}
+}
+
+// Handle handles the request
+func (m *RequestLogger) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		req := c.Request()
+
+		fields := logrus.Fields{
+			"request_id": GetRequestIDFromContext(c),
+			"method":     req.Method,
+			"route":      c.Path(),
+		}
+		if spanContext := trace.SpanContextFromContext(req.Context()); spanContext.HasTraceID() {
+			fields["trace_id"] = spanContext.TraceID().String()
+		}
+		entry := m.logger.WithFields(fields)
+		c.SetRequest(req.WithContext(logging.WithLogger(req.Context(), entry)))
+
+		err := next(c)
+
+		// Re-read the logger, later middleware may have added fields such as the user ID
+		status := responseStatus(c, err)
+		entry = logging.FromContext(c.Request().Context()).WithFields(logrus.Fields{
+			"path":       req.URL.Path,
+			"status":     status,
+			"latency_ms": time.Since(start).Milliseconds(),
+			"bytes_out":  c.Response().Size,
+			"remote_ip":  c.RealIP(),
+			"user_agent": req.UserAgent(),
+		})
+		if err != nil {
+			entry = entry.WithError(err)
+		}
+		if status >= http.StatusInternalServerError {
+			entry.Error("Request failed")
+		} else {
+			entry.Info("Request completed")
+		}
+
+		return err
+	}
+}
+
+// responseStatus returns the status code sent for the request. An error is
+// handed to the HTTP error handler first, so that its response is committed.
+func responseStatus(c echo.Context, err error) int {
+	if err != nil && !c.Response().Committed {
+		c.Error(err)
+	}
+	return c.Response().Status
+}
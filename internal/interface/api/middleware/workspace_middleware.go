@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/presenter"
+	"todo-api/internal/util/logging"
+)
+
+// HeaderWorkspaceID is the request header selecting the workspace of a request
+const HeaderWorkspaceID = "X-Workspace-ID"
+
+// errInvalidWorkspaceID is returned for a malformed workspace header
+var errInvalidWorkspaceID = presenter.NewProblem(http.StatusBadRequest, "invalid_workspace_id", "X-Workspace-ID header must be a workspace ID")
+
+// WorkspaceMiddleware scopes requests to a workspace
+type WorkspaceMiddleware struct {
+	permissions usecase.PermissionService
+}
+
+// NewWorkspaceMiddleware creates a new WorkspaceMiddleware
+func NewWorkspaceMiddleware(permissions usecase.PermissionService) *WorkspaceMiddleware {
+	return &WorkspaceMiddleware{
+		permissions: permissions,
+	}
+}
+
+// Select scopes the request to the workspace of the X-Workspace-ID header,
+// after checking that the authenticated user is a member of it. Requests
+// without the header only see the todos and projects outside any workspace.
+func (m *WorkspaceMiddleware) Select(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		header := c.Request().Header.Get(HeaderWorkspaceID)
+		if header == "" {
+			return next(c)
+		}
+
+		// Parse workspace ID
+		workspaceID, err := strconv.ParseUint(header, 10, 32)
+		if err != nil || workspaceID == 0 {
+			return errInvalidWorkspaceID
+		}
+
+		// Check membership
+		ctx := c.Request().Context()
+		if _, err := m.permissions.AuthorizeWorkspace(ctx, uint(workspaceID), GetUserIDFromContext(c), entity.PermissionView); err != nil {
+			return err
+		}
+
+		// Scope the repositories and the request-scoped logger to the workspace
+		ctx = repository.WithWorkspace(ctx, uint(workspaceID))
+		ctx = logging.WithFields(ctx, logrus.Fields{"workspace_id": workspaceID})
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		return next(c)
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/entity"
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/infrastructure/repository/memory"
+)
+
+func TestSelectWorkspace(t *testing.T) {
+	todoRepo := memory.NewTodoRepository()
+	projectRepo := memory.NewProjectRepository(todoRepo)
+	workspaceRepo := memory.NewWorkspaceRepository(todoRepo, projectRepo)
+	workspace := entity.NewWorkspace("team")
+	if err := workspaceRepo.Create(context.Background(), workspace, entity.NewWorkspaceMember(0, 1, entity.WorkspaceRoleOwner)); err != nil {
+		t.Fatal(err)
+	}
+	selectWorkspace := NewWorkspaceMiddleware(usecase.NewPermissionService(projectRepo, workspaceRepo)).Select
+
+	tests := []struct {
+		name   string
+		header string
+		userID uint
+		want   error
+		scoped bool
+	}{
+		{"no header", "", 2, nil, false},
+		{"member", "1", 1, nil, true},
+		{"non-member", "1", 2, usecase.ErrWorkspaceNotFound, false},
+		{"missing workspace", "9", 1, usecase.ErrWorkspaceNotFound, false},
+		{"malformed header", "team", 1, errInvalidWorkspaceID, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/todos", nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderWorkspaceID, tt.header)
+			}
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+			c.Set(UserIDKey, tt.userID)
+
+			scoped := false
+			err := selectWorkspace(func(c echo.Context) error {
+				id, ok := repository.WorkspaceFromContext(c.Request().Context())
+				scoped = ok && id == workspace.ID
+				return nil
+			})(c)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+			if scoped != tt.scoped {
+				t.Errorf("scoped to the workspace %t, want %t", scoped, tt.scoped)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package presenter
+
+import (
+	"time"
+
+	"todo-api/internal/domain/entity"
+)
+
+// ActivityData represents a change of a todo
+type ActivityData struct {
+	ID        uint         `json:"id"`
+	TodoID    uint         `json:"todo_id"`
+	UserID    uint         `json:"user_id"`
+	Action    string       `json:"action"`
+	Changes   []ChangeData `json:"changes"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// ChangeData represents the change of a single field of a todo
+type ChangeData struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// ActivitiesResponse converts a page of activities to an activities response
+func ActivitiesResponse(activities []*entity.Activity, totalCount int64, currentPage, pageSize int) map[string]interface{} {
+	activityResponses := make([]ActivityData, 0, len(activities))
+	for _, activity := range activities {
+		activityResponses = append(activityResponses, ActivityResponseData(activity))
+	}
+
+	totalPages := int(totalCount) / pageSize
+	if int(totalCount)%pageSize > 0 {
+		totalPages++
+	}
+
+	return map[string]interface{}{
+		"data": activityResponses,
+		"pagination": PaginationMeta{
+			CurrentPage: currentPage,
+			PageSize:    pageSize,
+			TotalItems:  totalCount,
+			TotalPages:  totalPages,
+		},
+	}
+}
+
+// ActivityResponseData converts an activity to an activity response data
+func ActivityResponseData(activity *entity.Activity) ActivityData {
+	changes := make([]ChangeData, 0, len(activity.Changes))
+	for _, change := range activity.Changes {
+		changes = append(changes, ChangeData{
+			Field: change.Field,
+			Old:   change.Old,
+			New:   change.New,
+		})
+	}
+
+	return ActivityData{
+		ID:        activity.ID,
+		TodoID:    activity.TodoID,
+		UserID:    activity.UserID,
+		Action:    string(activity.Action),
+		Changes:   changes,
+		CreatedAt: activity.CreatedAt,
+	}
+}
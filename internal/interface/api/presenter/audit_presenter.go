@@ -0,0 +1,61 @@
+package presenter
+
+import (
+	"time"
+
+	"todo-api/internal/domain/entity"
+)
+
+// AuditEventData represents a security audit event
+type AuditEventData struct {
+	ID        uint                   `json:"id"`
+	Type      string                 `json:"type"`
+	UserID    *uint                  `json:"user_id"`
+	RemoteIP  string                 `json:"remote_ip"`
+	UserAgent string                 `json:"user_agent"`
+	RequestID string                 `json:"request_id"`
+	Details   map[string]interface{} `json:"details"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// AuditEventsResponse converts a page of audit events to an audit events response
+func AuditEventsResponse(events []*entity.AuditEvent, totalCount int64, currentPage, pageSize int) map[string]interface{} {
+	eventResponses := make([]AuditEventData, 0, len(events))
+	for _, event := range events {
+		eventResponses = append(eventResponses, AuditEventResponseData(event))
+	}
+
+	totalPages := int(totalCount) / pageSize
+	if int(totalCount)%pageSize > 0 {
+		totalPages++
+	}
+
+	return map[string]interface{}{
+		"data": eventResponses,
+		"pagination": PaginationMeta{
+			CurrentPage: currentPage,
+			PageSize:    pageSize,
+			TotalItems:  totalCount,
+			TotalPages:  totalPages,
+		},
+	}
+}
+
+// AuditEventResponseData converts an audit event to an audit event response data
+func AuditEventResponseData(event *entity.AuditEvent) AuditEventData {
+	details := event.Details
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+
+	return AuditEventData{
+		ID:        event.ID,
+		Type:      string(event.Type),
+		UserID:    event.UserID,
+		RemoteIP:  event.RemoteIP,
+		UserAgent: event.UserAgent,
+		RequestID: event.RequestID,
+		Details:   details,
+		CreatedAt: event.CreatedAt,
+	}
+}
@@ -0,0 +1,45 @@
+package presenter
+
+// Statuses of the items of a bulk operation
+const (
+	BulkItemSucceeded = "succeeded"
+	BulkItemFailed    = "failed"
+)
+
+// BulkItemData represents the outcome of a bulk operation for one todo
+type BulkItemData struct {
+	ID     uint           `json:"id"`
+	Status string         `json:"status"`
+	Error  *BulkItemError `json:"error,omitempty"`
+}
+
+// BulkItemError describes why a bulk operation failed for a todo
+type BulkItemError struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// BulkResultData represents the report of a bulk operation
+type BulkResultData struct {
+	Action    string         `json:"action"`
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+	Results   []BulkItemData `json:"results"`
+}
+
+// BulkResponse converts the outcomes of a bulk operation to a bulk response
+func BulkResponse(action string, items []BulkItemData) map[string]interface{} {
+	result := BulkResultData{Action: action, Results: items}
+	for _, item := range items {
+		if item.Status == BulkItemSucceeded {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+
+	return map[string]interface{}{
+		"data": result,
+	}
+}
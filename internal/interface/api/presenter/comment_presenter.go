@@ -0,0 +1,61 @@
+package presenter
+
+import (
+	"time"
+
+	"todo-api/internal/domain/entity"
+)
+
+// CommentData represents a comment on a todo with its author
+type CommentData struct {
+	ID        uint      `json:"id"`
+	TodoID    uint      `json:"todo_id"`
+	UserID    uint      `json:"user_id"`
+	Username  string    `json:"username"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CommentResponse converts a comment with its author to a comment response
+func CommentResponse(comment *entity.Comment) map[string]interface{} {
+	return map[string]interface{}{
+		"data": CommentResponseData(comment),
+	}
+}
+
+// CommentsResponse converts a page of comments with their authors to a comments response
+func CommentsResponse(comments []*entity.Comment, totalCount int64, currentPage, pageSize int) map[string]interface{} {
+	commentResponses := make([]CommentData, 0, len(comments))
+	for _, comment := range comments {
+		commentResponses = append(commentResponses, CommentResponseData(comment))
+	}
+
+	totalPages := int(totalCount) / pageSize
+	if int(totalCount)%pageSize > 0 {
+		totalPages++
+	}
+
+	return map[string]interface{}{
+		"data": commentResponses,
+		"pagination": PaginationMeta{
+			CurrentPage: currentPage,
+			PageSize:    pageSize,
+			TotalItems:  totalCount,
+			TotalPages:  totalPages,
+		},
+	}
+}
+
+// CommentResponseData converts a comment with its author to a comment response data
+func CommentResponseData(comment *entity.Comment) CommentData {
+	return CommentData{
+		ID:        comment.ID,
+		TodoID:    comment.TodoID,
+		UserID:    comment.UserID,
+		Username:  comment.User.Username,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
@@ -0,0 +1,60 @@
+package presenter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"todo-api/internal/domain/entity"
+)
+
+// ErrInvalidCursor is returned for a cursor that was not issued by the API
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// todoCursor is the encoded form of a todo cursor, clients treat it as opaque
+type todoCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uint      `json:"i"`
+	Backward  bool      `json:"b,omitempty"`
+}
+
+// EncodeTodoCursor encodes a todo cursor as an opaque string, nil encodes as ""
+func EncodeTodoCursor(cursor *entity.TodoCursor) string {
+	if cursor == nil {
+		return ""
+	}
+
+	data, err := json.Marshal(todoCursor{
+		CreatedAt: cursor.CreatedAt,
+		ID:        cursor.ID,
+		Backward:  cursor.Backward,
+	})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeTodoCursor decodes a cursor returned by EncodeTodoCursor. The empty
+// string decodes as the zero cursor, which selects the first page.
+func DecodeTodoCursor(encoded string) (*entity.TodoCursor, error) {
+	if encoded == "" {
+		return &entity.TodoCursor{}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor todoCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &entity.TodoCursor{
+		CreatedAt: cursor.CreatedAt,
+		ID:        cursor.ID,
+		Backward:  cursor.Backward,
+	}, nil
+}
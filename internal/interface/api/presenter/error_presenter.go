@@ -1,50 +1,106 @@
 package presenter
 
 import (
-	"strings"
+	"net/http"
 
 	"github.com/go-playground/validator/v10"
+
+	"todo-api/internal/domain/entity"
 )
 
-// ErrorResponse creates an error response
-func ErrorResponse(message string) map[string]interface{} {
-	return map[string]interface{}{
-		"error": map[string]interface{}{
-			"message": message,
-		},
+// MIMEApplicationProblemJSON is the media type of problem details (RFC 7807)
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// problemTypePrefix namespaces the problem type URIs, the code completes them
+const problemTypePrefix = "urn:todo-api:problem:"
+
+// Problem is an RFC 7807 problem details response. It implements error so that
+// handlers and middleware can return it to the HTTP error handler.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Extension members
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+
+	// Position and Token point at the offending token of an invalid filter expression
+	Position int    `json:"position,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// FieldError describes an invalid request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewProblem creates a problem of the type identified by code
+func NewProblem(status int, code, detail string) *Problem {
+	return &Problem{
+		Type:   ProblemType(code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
 	}
 }
 
-// ValidationErrorResponse creates a validation error response
-func ValidationErrorResponse(err error) map[string]interface{} {
-	// Check if the error is a validator error
-	validationErrors, ok := err.(validator.ValidationErrors)
-	if !ok {
-		return ErrorResponse(err.Error())
+// ProblemType returns the type URI of the problem identified by code
+func ProblemType(code string) string {
+	return problemTypePrefix + code
+}
+
+// Error implements the error interface
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return p.Title
 	}
+	return p.Title + ": " + p.Detail
+}
 
-	// Create a map of field errors
-	fieldErrors := make(map[string]string)
+// ValidationProblem creates a problem listing the fields that failed validation
+func ValidationProblem(validationErrors validator.ValidationErrors) *Problem {
+	problem := NewProblem(http.StatusBadRequest, "validation_failed", "Validation failed")
 	for _, validationErr := range validationErrors {
-		field := strings.ToLower(validationErr.Field())
-		switch validationErr.Tag() {
-		case "required":
-			fieldErrors[field] = "This field is required"
-		case "email":
-			fieldErrors[field] = "Must be a valid email address"
-		case "min":
-			fieldErrors[field] = "Value must be at least " + validationErr.Param() + " characters long"
-		case "max":
-			fieldErrors[field] = "Value must be at most " + validationErr.Param() + " characters long"
-		default:
-			fieldErrors[field] = "Invalid value"
-		}
+		problem.Errors = append(problem.Errors, FieldError{
+			Field:   validationErr.Field(),
+			Code:    validationErr.Tag(),
+			Message: fieldErrorMessage(validationErr),
+		})
 	}
+	return problem
+}
+
+// FilterProblem creates a problem pointing at the offending token of a filter expression
+func FilterProblem(filterErr *entity.FilterError) *Problem {
+	code := filterErr.Code
+	if code == "" {
+		code = entity.FilterErrorInvalid
+	}
+	problem := NewProblem(http.StatusBadRequest, code, "Invalid filter: "+filterErr.Error())
+	problem.Position = filterErr.Position
+	problem.Token = filterErr.Token
+	return problem
+}
 
-	return map[string]interface{}{
-		"error": map[string]interface{}{
-			"message": "Validation failed",
-			"fields":  fieldErrors,
-		},
+// fieldErrorMessage describes a failed validation rule
+func fieldErrorMessage(validationErr validator.FieldError) string {
+	switch validationErr.Tag() {
+	case "required":
+		return "This field is required"
+	case "email":
+		return "Must be a valid email address"
+	case "min":
+		return "Value must be at least " + validationErr.Param() + " characters long"
+	case "max":
+		return "Value must be at most " + validationErr.Param() + " characters long"
+	default:
+		return "Invalid value"
 	}
 }
@@ -0,0 +1,32 @@
+package presenter
+
+import (
+	"time"
+)
+
+// Health statuses reported by the probes
+const (
+	HealthStatusOK           = "ok"
+	HealthStatusUnavailable  = "unavailable"
+	HealthStatusShuttingDown = "shutting_down"
+)
+
+// ComponentStatus represents the health of a single dependency
+type ComponentStatus struct {
+	Status    string      `json:"status"`
+	LatencyMS int64       `json:"latency_ms"`
+	Error     string      `json:"error,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// HealthResponse creates a health probe response
+func HealthResponse(status string, components map[string]ComponentStatus) map[string]interface{} {
+	response := map[string]interface{}{
+		"status":     status,
+		"checked_at": time.Now().UTC(),
+	}
+	if components != nil {
+		response["components"] = components
+	}
+	return response
+}
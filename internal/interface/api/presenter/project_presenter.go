@@ -0,0 +1,87 @@
+package presenter
+
+import (
+	"time"
+
+	"todo-api/internal/domain/entity"
+)
+
+// ProjectData represents a project and the role of the current user in it
+type ProjectData struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	WorkspaceID *uint     `json:"workspace_id"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// MemberData represents a member of a project or workspace
+type MemberData struct {
+	UserID   uint      `json:"user_id"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// ProjectResponse converts a membership with its project to a project response
+func ProjectResponse(membership *entity.ProjectMember) map[string]interface{} {
+	return map[string]interface{}{
+		"data": ProjectResponseData(membership),
+	}
+}
+
+// ProjectsResponse converts memberships with their projects to a projects response
+func ProjectsResponse(memberships []*entity.ProjectMember) map[string]interface{} {
+	projectResponses := make([]ProjectData, 0, len(memberships))
+	for _, membership := range memberships {
+		projectResponses = append(projectResponses, ProjectResponseData(membership))
+	}
+
+	return map[string]interface{}{
+		"data": projectResponses,
+	}
+}
+
+// ProjectResponseData converts a membership with its project to a project response data
+func ProjectResponseData(membership *entity.ProjectMember) ProjectData {
+	return ProjectData{
+		ID:          membership.Project.ID,
+		Name:        membership.Project.Name,
+		WorkspaceID: membership.Project.WorkspaceID,
+		Role:        string(membership.Role),
+		CreatedAt:   membership.Project.CreatedAt,
+		UpdatedAt:   membership.Project.UpdatedAt,
+	}
+}
+
+// MemberResponse converts a member with its user to a member response
+func MemberResponse(member *entity.ProjectMember) map[string]interface{} {
+	return map[string]interface{}{
+		"data": MemberResponseData(member),
+	}
+}
+
+// MembersResponse converts members with their users to a members response
+func MembersResponse(members []*entity.ProjectMember) map[string]interface{} {
+	memberResponses := make([]MemberData, 0, len(members))
+	for _, member := range members {
+		memberResponses = append(memberResponses, MemberResponseData(member))
+	}
+
+	return map[string]interface{}{
+		"data": memberResponses,
+	}
+}
+
+// MemberResponseData converts a member with its user to a member response data
+func MemberResponseData(member *entity.ProjectMember) MemberData {
+	return MemberData{
+		UserID:   member.UserID,
+		Username: member.User.Username,
+		Email:    member.User.Email,
+		Role:     string(member.Role),
+		JoinedAt: member.CreatedAt,
+	}
+}
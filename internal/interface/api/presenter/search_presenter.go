@@ -0,0 +1,105 @@
+package presenter
+
+import (
+	"html"
+	"strings"
+	"time"
+
+	"todo-api/internal/domain/entity"
+)
+
+// TodoSearchResult represents a todo matching a full-text search
+type TodoSearchResult struct {
+	ID          uint           `json:"id"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Completed   bool           `json:"completed"`
+	UserID      uint           `json:"user_id"`
+	ProjectID   *uint          `json:"project_id"`
+	AssigneeID  *uint          `json:"assignee_id"`
+	WorkspaceID *uint          `json:"workspace_id"`
+	Tags        []string       `json:"tags"`
+	ChangeSeq   uint64         `json:"change_seq"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	Rank        float64        `json:"rank"`
+	Highlights  TodoHighlights `json:"highlights"`
+}
+
+// TodoHighlights contains HTML escaped excerpts with the matched words wrapped in <mark> elements
+type TodoHighlights struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// TodoSearchResponse converts a page of full-text search matches to a todos response
+func TodoSearchResponse(matches []*entity.TodoMatch, totalCount int64, currentPage, pageSize int) map[string]interface{} {
+	results := make([]TodoSearchResult, 0, len(matches))
+	for _, match := range matches {
+		todo := match.Todo
+		results = append(results, TodoSearchResult{
+			ID:          todo.ID,
+			Title:       todo.Title,
+			Description: todo.Description,
+			Completed:   todo.Completed,
+			UserID:      todo.UserID,
+			ProjectID:   todo.ProjectID,
+			AssigneeID:  todo.AssigneeID,
+			WorkspaceID: todo.WorkspaceID,
+			Tags:        TagList(todo.Tags),
+			ChangeSeq:   todo.ChangeSeq,
+			CreatedAt:   todo.CreatedAt,
+			UpdatedAt:   todo.UpdatedAt,
+			Rank:        match.Rank,
+			Highlights: TodoHighlights{
+				Title:       HighlightHTML(match.TitleHighlight),
+				Description: HighlightHTML(match.DescriptionHighlight),
+			},
+		})
+	}
+
+	totalPages := int(totalCount) / pageSize
+	if int(totalCount)%pageSize > 0 {
+		totalPages++
+	}
+
+	return map[string]interface{}{
+		"data": results,
+		"pagination": PaginationMeta{
+			CurrentPage: currentPage,
+			PageSize:    pageSize,
+			TotalItems:  totalCount,
+			TotalPages:  totalPages,
+		},
+	}
+}
+
+// HighlightHTML escapes a highlighted text and replaces its highlight markers
+// with <mark> elements, so that clients can render it as HTML safely
+func HighlightHTML(text string) string {
+	var b strings.Builder
+	open := false
+	for text != "" {
+		i := strings.IndexAny(text, entity.HighlightStart+entity.HighlightEnd)
+		if i < 0 {
+			b.WriteString(html.EscapeString(text))
+			break
+		}
+		b.WriteString(html.EscapeString(text[:i]))
+
+		// Ignore unbalanced markers
+		switch start := text[i:i+1] == entity.HighlightStart; {
+		case start && !open:
+			b.WriteString("<mark>")
+			open = true
+		case !start && open:
+			b.WriteString("</mark>")
+			open = false
+		}
+		text = text[i+1:]
+	}
+	if open {
+		b.WriteString("</mark>")
+	}
+	return b.String()
+}
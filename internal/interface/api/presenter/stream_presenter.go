@@ -0,0 +1,52 @@
+package presenter
+
+import (
+	"time"
+
+	"todo-api/internal/domain/entity"
+)
+
+// StreamResetType is the type of the stream message telling a client that
+// events were lost, so that it must reload its todos
+const StreamResetType = "reset"
+
+// TodoEventData represents a todo event of a real-time stream
+type TodoEventData struct {
+	ID          uint         `json:"id"`
+	Type        string       `json:"type"`
+	OccurredAt  time.Time    `json:"occurred_at"`
+	WorkspaceID *uint        `json:"workspace_id"`
+	ActorID     uint         `json:"actor_id"`
+	Todo        TodoResponse `json:"todo"`
+	Changes     []ChangeData `json:"changes"`
+
+	// Truncated is true when the description was left out of the todo and of
+	// the changes, the client should fetch the todo for it
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// StreamResetData represents the stream message telling a client that events were lost
+type StreamResetData struct {
+	Type string `json:"type"`
+}
+
+// TodoEventResponseData converts a todo event to a todo event data
+func TodoEventResponseData(event *entity.TodoEvent) TodoEventData {
+	return TodoEventData{
+		ID:          event.Activity.ID,
+		Type:        string(event.Type()),
+		OccurredAt:  event.Activity.CreatedAt,
+		WorkspaceID: event.Todo.WorkspaceID,
+		ActorID:     event.Activity.UserID,
+		Todo:        TodoResponseData(&event.Todo),
+		Changes:     ActivityResponseData(event.Activity).Changes,
+		Truncated:   event.Truncated,
+	}
+}
+
+// StreamResetResponseData returns the stream message telling a client that events were lost
+func StreamResetResponseData() StreamResetData {
+	return StreamResetData{
+		Type: StreamResetType,
+	}
+}
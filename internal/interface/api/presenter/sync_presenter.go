@@ -0,0 +1,122 @@
+package presenter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"todo-api/internal/domain/entity"
+)
+
+// ErrInvalidSyncToken is returned for a sync token that was not issued by the API
+var ErrInvalidSyncToken = errors.New("invalid sync token")
+
+// Statuses of the results of sync mutations
+const (
+	SyncApplied  = "applied"
+	SyncConflict = "conflict"
+	SyncFailed   = "failed"
+)
+
+// syncToken is the encoded form of a sync token, clients treat it as opaque.
+// Tokens issued before there were transaction IDs have none, their changes
+// come first.
+type syncToken struct {
+	XID   uint64 `json:"x,omitempty"`
+	Seq   uint64 `json:"s"`
+	Scope uint64 `json:"c"`
+}
+
+// EncodeSyncToken encodes a sync token as an opaque string
+func EncodeSyncToken(token entity.SyncToken) string {
+	data, err := json.Marshal(syncToken{XID: token.Position.XID, Seq: token.Position.Seq, Scope: token.Scope})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeSyncToken decodes a sync token returned by EncodeSyncToken. The empty
+// string decodes as the zero token, which starts a full sync.
+func DecodeSyncToken(encoded string) (entity.SyncToken, error) {
+	if encoded == "" {
+		return entity.SyncToken{}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return entity.SyncToken{}, ErrInvalidSyncToken
+	}
+	var token syncToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return entity.SyncToken{}, ErrInvalidSyncToken
+	}
+
+	return entity.SyncToken{
+		Position: entity.ChangePosition{XID: token.XID, Seq: token.Seq},
+		Scope:    token.Scope,
+	}, nil
+}
+
+// TombstoneData represents a todo deleted since the sync token
+type TombstoneData struct {
+	ID        uint      `json:"id"`
+	ProjectID *uint     `json:"project_id"`
+	ChangeSeq uint64    `json:"change_seq"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// SyncChangesData represents a page of the changes of todos since a sync token
+type SyncChangesData struct {
+	Todos     []TodoResponse  `json:"todos"`
+	Deleted   []TombstoneData `json:"deleted"`
+	SyncToken string          `json:"sync_token"`
+	HasMore   bool            `json:"has_more"`
+
+	// Reset tells the client to replace its todos with those of the full sync
+	// the page starts
+	Reset bool `json:"reset"`
+}
+
+// SyncResultData represents the outcome of a sync mutation. Todo is the todo
+// after the mutation or, on a conflict, the current todo, nil when it was deleted.
+type SyncResultData struct {
+	Index  int            `json:"index"`
+	Status string         `json:"status"`
+	Todo   *TodoResponse  `json:"todo"`
+	Error  *BulkItemError `json:"error,omitempty"`
+}
+
+// SyncChangesResponse converts a page of todo changes to a sync response
+func SyncChangesResponse(todos []*entity.Todo, tombstones []*entity.TodoTombstone, token entity.SyncToken, hasMore, reset bool) map[string]interface{} {
+	data := SyncChangesData{
+		Todos:     make([]TodoResponse, 0, len(todos)),
+		Deleted:   make([]TombstoneData, 0, len(tombstones)),
+		SyncToken: EncodeSyncToken(token),
+		HasMore:   hasMore,
+		Reset:     reset,
+	}
+	for _, todo := range todos {
+		data.Todos = append(data.Todos, TodoResponseData(todo))
+	}
+	for _, tombstone := range tombstones {
+		data.Deleted = append(data.Deleted, TombstoneData{
+			ID:        tombstone.TodoID,
+			ProjectID: tombstone.ProjectID,
+			ChangeSeq: tombstone.ChangeSeq,
+			DeletedAt: tombstone.CreatedAt,
+		})
+	}
+
+	return map[string]interface{}{
+		"data": data,
+	}
+}
+
+// SyncResultsResponse converts the outcomes of sync mutations to a sync response
+func SyncResultsResponse(results []SyncResultData) map[string]interface{} {
+	return map[string]interface{}{
+		"data": results,
+	}
+}
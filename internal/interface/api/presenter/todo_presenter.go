@@ -13,6 +13,11 @@ type TodoResponse struct {
 	Description string    `json:"description"`
 	Completed   bool      `json:"completed"`
 	UserID      uint      `json:"user_id"`
+	ProjectID   *uint     `json:"project_id"`
+	AssigneeID  *uint     `json:"assignee_id"`
+	WorkspaceID *uint     `json:"workspace_id"`
+	Tags        []string  `json:"tags"`
+	ChangeSeq   uint64    `json:"change_seq"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -31,6 +36,13 @@ type PaginationMeta struct {
 	TotalPages  int   `json:"total_pages"`
 }
 
+// CursorPaginationMeta contains the cursors of the adjacent pages
+type CursorPaginationMeta struct {
+	PageSize   int    `json:"page_size"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
 // TodoResponse converts a todo entity to a todo response
 func TodoResponse(todo *entity.Todo) map[string]interface{} {
 	return map[string]interface{}{
@@ -61,6 +73,23 @@ func TodosResponse(todos []*entity.Todo, totalCount int64, currentPage, pageSize
 	}
 }
 
+// TodosCursorResponse converts a page of todos selected by cursor to a todos response
+func TodosCursorResponse(todos []*entity.Todo, pageSize int, next, prev *entity.TodoCursor) map[string]interface{} {
+	todoResponses := make([]TodoResponse, 0, len(todos))
+	for _, todo := range todos {
+		todoResponses = append(todoResponses, TodoResponseData(todo))
+	}
+
+	return map[string]interface{}{
+		"data": todoResponses,
+		"pagination": CursorPaginationMeta{
+			PageSize:   pageSize,
+			NextCursor: EncodeTodoCursor(next),
+			PrevCursor: EncodeTodoCursor(prev),
+		},
+	}
+}
+
 // TodoResponseData converts a todo entity to a todo response data
 func TodoResponseData(todo *entity.Todo) TodoResponse {
 	return TodoResponse{
@@ -69,7 +98,20 @@ func TodoResponseData(todo *entity.Todo) TodoResponse {
 		Description: todo.Description,
 		Completed:   todo.Completed,
 		UserID:      todo.UserID,
+		ProjectID:   todo.ProjectID,
+		AssigneeID:  todo.AssigneeID,
+		WorkspaceID: todo.WorkspaceID,
+		Tags:        TagList(todo.Tags),
+		ChangeSeq:   todo.ChangeSeq,
 		CreatedAt:   todo.CreatedAt,
 		UpdatedAt:   todo.UpdatedAt,
 	}
 }
+
+// TagList returns the tags of a todo, rendering no tags as an empty list rather than null
+func TagList(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
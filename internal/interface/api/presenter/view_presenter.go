@@ -0,0 +1,66 @@
+package presenter
+
+import (
+	"time"
+
+	"todo-api/internal/domain/entity"
+)
+
+// ViewData represents a saved view and the live count of its todos
+type ViewData struct {
+	ID        uint           `json:"id"`
+	Name      string         `json:"name"`
+	Filter    ViewFilterData `json:"filter"`
+	Sort      string         `json:"sort"`
+	TodoCount int64          `json:"todo_count"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// ViewFilterData represents the filters saved in a view
+type ViewFilterData struct {
+	Completed  *bool  `json:"completed"`
+	Search     string `json:"search"`
+	Expression string `json:"expression"`
+}
+
+// ViewResponse converts a view entity and its todo count to a view response
+func ViewResponse(view *entity.View, todoCount int64) map[string]interface{} {
+	return map[string]interface{}{
+		"data": ViewResponseData(view, todoCount),
+	}
+}
+
+// ViewsResponse converts view entities and their todo counts, by view ID, to a views response
+func ViewsResponse(views []*entity.View, todoCounts map[uint]int64) map[string]interface{} {
+	viewResponses := make([]ViewData, 0, len(views))
+	for _, view := range views {
+		viewResponses = append(viewResponses, ViewResponseData(view, todoCounts[view.ID]))
+	}
+
+	return map[string]interface{}{
+		"data": viewResponses,
+	}
+}
+
+// ViewResponseData converts a view entity and its todo count to a view response data
+func ViewResponseData(view *entity.View, todoCount int64) ViewData {
+	sort := view.Sort
+	if sort == "" {
+		sort = entity.TodoSortCreatedDesc
+	}
+
+	return ViewData{
+		ID:   view.ID,
+		Name: view.Name,
+		Filter: ViewFilterData{
+			Completed:  view.Completed,
+			Search:     view.Search,
+			Expression: view.Filter,
+		},
+		Sort:      string(sort),
+		TodoCount: todoCount,
+		CreatedAt: view.CreatedAt,
+		UpdatedAt: view.UpdatedAt,
+	}
+}
@@ -0,0 +1,138 @@
+package presenter
+
+import (
+	"encoding/json"
+	"time"
+
+	"todo-api/internal/domain/entity"
+)
+
+// WebhookData represents a webhook, its secret is only included on creation
+type WebhookData struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DeliveryData represents a delivery of an event to a webhook
+type DeliveryData struct {
+	ID             uint            `json:"id"`
+	WebhookID      uint            `json:"webhook_id"`
+	EventID        uint            `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	ResponseStatus *int            `json:"response_status"`
+	Error          string          `json:"error,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// WebhookResponse converts a webhook entity to a webhook response
+func WebhookResponse(webhook *entity.Webhook) map[string]interface{} {
+	return map[string]interface{}{
+		"data": WebhookResponseData(webhook),
+	}
+}
+
+// WebhookCreatedResponse converts a created webhook entity to a webhook
+// response including its secret
+func WebhookCreatedResponse(webhook *entity.Webhook) map[string]interface{} {
+	data := WebhookResponseData(webhook)
+	data.Secret = webhook.Secret
+	return map[string]interface{}{
+		"data": data,
+	}
+}
+
+// WebhooksResponse converts webhook entities to a webhooks response
+func WebhooksResponse(webhooks []*entity.Webhook) map[string]interface{} {
+	webhookResponses := make([]WebhookData, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		webhookResponses = append(webhookResponses, WebhookResponseData(webhook))
+	}
+
+	return map[string]interface{}{
+		"data": webhookResponses,
+	}
+}
+
+// WebhookResponseData converts a webhook entity to a webhook response data without its secret
+func WebhookResponseData(webhook *entity.Webhook) WebhookData {
+	events := make([]string, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		events = append(events, string(event))
+	}
+
+	return WebhookData{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    events,
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+// DeliveryResponse converts a delivery entity to a delivery response
+func DeliveryResponse(delivery *entity.WebhookDelivery) map[string]interface{} {
+	return map[string]interface{}{
+		"data": DeliveryResponseData(delivery),
+	}
+}
+
+// DeliveriesResponse converts a page of deliveries to a deliveries response
+func DeliveriesResponse(deliveries []*entity.WebhookDelivery, totalCount int64, currentPage, pageSize int) map[string]interface{} {
+	deliveryResponses := make([]DeliveryData, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryResponses = append(deliveryResponses, DeliveryResponseData(delivery))
+	}
+
+	totalPages := int(totalCount) / pageSize
+	if int(totalCount)%pageSize > 0 {
+		totalPages++
+	}
+
+	return map[string]interface{}{
+		"data": deliveryResponses,
+		"pagination": PaginationMeta{
+			CurrentPage: currentPage,
+			PageSize:    pageSize,
+			TotalItems:  totalCount,
+			TotalPages:  totalPages,
+		},
+	}
+}
+
+// DeliveryResponseData converts a delivery entity to a delivery response data
+func DeliveryResponseData(delivery *entity.WebhookDelivery) DeliveryData {
+	data := DeliveryData{
+		ID:            delivery.ID,
+		WebhookID:     delivery.WebhookID,
+		EventID:       delivery.EventID,
+		EventType:     string(delivery.EventType),
+		Status:        string(delivery.Status),
+		Attempts:      delivery.Attempts,
+		LastAttemptAt: delivery.LastAttemptAt,
+		Error:         delivery.Error,
+		Payload:       json.RawMessage(delivery.Payload),
+		CreatedAt:     delivery.CreatedAt,
+	}
+
+	// Only pending deliveries are due again
+	if delivery.Status == entity.DeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		data.NextAttemptAt = &nextAttemptAt
+	}
+	if delivery.LastAttemptAt != nil && delivery.ResponseStatus != 0 {
+		responseStatus := delivery.ResponseStatus
+		data.ResponseStatus = &responseStatus
+	}
+	return data
+}
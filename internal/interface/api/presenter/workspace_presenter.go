@@ -0,0 +1,76 @@
+package presenter
+
+import (
+	"time"
+
+	"todo-api/internal/domain/entity"
+)
+
+// WorkspaceData represents a workspace and the role of the current user in it
+type WorkspaceData struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WorkspaceResponse converts a membership with its workspace to a workspace response
+func WorkspaceResponse(membership *entity.WorkspaceMember) map[string]interface{} {
+	return map[string]interface{}{
+		"data": WorkspaceResponseData(membership),
+	}
+}
+
+// WorkspacesResponse converts memberships with their workspaces to a workspaces response
+func WorkspacesResponse(memberships []*entity.WorkspaceMember) map[string]interface{} {
+	workspaceResponses := make([]WorkspaceData, 0, len(memberships))
+	for _, membership := range memberships {
+		workspaceResponses = append(workspaceResponses, WorkspaceResponseData(membership))
+	}
+
+	return map[string]interface{}{
+		"data": workspaceResponses,
+	}
+}
+
+// WorkspaceResponseData converts a membership with its workspace to a workspace response data
+func WorkspaceResponseData(membership *entity.WorkspaceMember) WorkspaceData {
+	return WorkspaceData{
+		ID:        membership.Workspace.ID,
+		Name:      membership.Workspace.Name,
+		Role:      string(membership.Role),
+		CreatedAt: membership.Workspace.CreatedAt,
+		UpdatedAt: membership.Workspace.UpdatedAt,
+	}
+}
+
+// WorkspaceMemberResponse converts a workspace member with its user to a member response
+func WorkspaceMemberResponse(member *entity.WorkspaceMember) map[string]interface{} {
+	return map[string]interface{}{
+		"data": WorkspaceMemberResponseData(member),
+	}
+}
+
+// WorkspaceMembersResponse converts workspace members with their users to a members response
+func WorkspaceMembersResponse(members []*entity.WorkspaceMember) map[string]interface{} {
+	memberResponses := make([]MemberData, 0, len(members))
+	for _, member := range members {
+		memberResponses = append(memberResponses, WorkspaceMemberResponseData(member))
+	}
+
+	return map[string]interface{}{
+		"data": memberResponses,
+	}
+}
+
+// WorkspaceMemberResponseData converts a workspace member with its user to a member response data
+func WorkspaceMemberResponseData(member *entity.WorkspaceMember) MemberData {
+	return MemberData{
+		UserID:   member.UserID,
+		Username: member.User.Username,
+		Email:    member.User.Email,
+		Role:     string(member.Role),
+		JoinedAt: member.CreatedAt,
+	}
+}
@@ -0,0 +1,38 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/handler"
+	"todo-api/internal/interface/api/middleware"
+)
+
+// SetupActivityRoutes sets up routes related to the history of todos
+func SetupActivityRoutes(
+	e *echo.Echo,
+	activityRepo repository.ActivityRepository,
+	todoUseCase usecase.TodoUseCase,
+	authMiddleware *middleware.AuthMiddleware,
+	apiLimit echo.MiddlewareFunc,
+	workspace echo.MiddlewareFunc,
+) {
+	// Initialize activity use case, which checks access to todos through the todo use case
+	activityUseCase := usecase.NewActivityUseCase(activityRepo, todoUseCase)
+
+	// Initialize activity handler
+	activityHandler := handler.NewActivityHandler(activityUseCase)
+
+	// Define activity routes, which are read-only and need no idempotency keys
+	historyGroup := e.Group("/api/todos/:id/history")
+	activityGroup := e.Group("/api/activity")
+
+	// Add authentication, per-user rate limiting and workspace scoping to all activity routes
+	historyGroup.Use(authMiddleware.Authenticate, apiLimit, workspace)
+	activityGroup.Use(authMiddleware.Authenticate, apiLimit, workspace)
+
+	// Routes
+	historyGroup.GET("", activityHandler.GetTodoHistory)
+	activityGroup.GET("", activityHandler.GetActivity)
+}
@@ -0,0 +1,35 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/handler"
+	"todo-api/internal/interface/api/middleware"
+)
+
+// SetupAdminRoutes sets up routes reserved to the administrators in admins
+func SetupAdminRoutes(
+	e *echo.Echo,
+	auditRepo repository.AuditRepository,
+	authMiddleware *middleware.AuthMiddleware,
+	apiLimit echo.MiddlewareFunc,
+	auditor usecase.Auditor,
+	admins []uint,
+) {
+	// Initialize audit use case
+	auditUseCase := usecase.NewAuditUseCase(auditRepo, auditor, admins)
+
+	// Initialize audit handler
+	auditHandler := handler.NewAuditHandler(auditUseCase)
+
+	// Define admin routes, which are read-only and outside any workspace
+	adminGroup := e.Group("/api/admin")
+
+	// Add authentication and per-user rate limiting to all admin routes
+	adminGroup.Use(authMiddleware.Authenticate, apiLimit)
+
+	// Routes
+	adminGroup.GET("/audit-events", auditHandler.GetAuditEvents)
+}
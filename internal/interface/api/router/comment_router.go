@@ -0,0 +1,42 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/handler"
+	"todo-api/internal/interface/api/middleware"
+)
+
+// SetupCommentRoutes sets up routes related to comments on todos
+func SetupCommentRoutes(
+	e *echo.Echo,
+	commentRepo repository.CommentRepository,
+	userRepo repository.UserRepository,
+	todoUseCase usecase.TodoUseCase,
+	permissions usecase.PermissionService,
+	authMiddleware *middleware.AuthMiddleware,
+	apiLimit echo.MiddlewareFunc,
+	workspace echo.MiddlewareFunc,
+	idempotent echo.MiddlewareFunc,
+	notifier usecase.Notifier,
+) {
+	// Initialize comment use case, which checks access to todos through the todo use case
+	commentUseCase := usecase.NewCommentUseCase(commentRepo, userRepo, todoUseCase, permissions, notifier)
+
+	// Initialize comment handler
+	commentHandler := handler.NewCommentHandler(commentUseCase)
+
+	// Define comment routes
+	commentGroup := e.Group("/api/todos/:id/comments")
+
+	// Add authentication, per-user rate limiting, workspace scoping and idempotency keys to all comment routes
+	commentGroup.Use(authMiddleware.Authenticate, apiLimit, workspace, idempotent)
+
+	// Routes
+	commentGroup.POST("", commentHandler.CreateComment)
+	commentGroup.GET("", commentHandler.GetComments)
+	commentGroup.PUT("/:commentId", commentHandler.UpdateComment)
+	commentGroup.DELETE("/:commentId", commentHandler.DeleteComment)
+}
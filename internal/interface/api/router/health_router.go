@@ -0,0 +1,50 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/infrastructure/storage"
+	"todo-api/internal/interface/api/handler"
+)
+
+// NewHealthHandler creates the health handler with readiness checks for the storage backend
+func NewHealthHandler(store *storage.Storage, timeout time.Duration) *handler.HealthHandler {
+	return handler.NewHealthHandler(timeout,
+		handler.HealthCheck{
+			Name: "database",
+			Check: func(ctx context.Context) (interface{}, error) {
+				details := map[string]interface{}{
+					"driver": store.Driver,
+				}
+				if err := store.Ping(ctx); err != nil {
+					return details, err
+				}
+				if stats := store.PoolStats(); stats != nil {
+					details["pool"] = stats
+				}
+				return details, nil
+			},
+		},
+		handler.HealthCheck{
+			Name: "migrations",
+			Check: func(ctx context.Context) (interface{}, error) {
+				// Determined at startup, so that probes only ping the database
+				migrations := store.Migrations()
+				if migrations.Pending() {
+					return migrations, fmt.Errorf("schema version %d is older than version %d", migrations.Version, migrations.Expected)
+				}
+				return migrations, nil
+			},
+		},
+	)
+}
+
+// SetupHealthRoutes sets up the liveness and readiness probes
+func SetupHealthRoutes(e *echo.Echo, healthHandler *handler.HealthHandler) {
+	e.GET("/livez", healthHandler.Live)
+	e.GET("/readyz", healthHandler.Ready)
+}
@@ -0,0 +1,45 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/handler"
+	"todo-api/internal/interface/api/middleware"
+)
+
+// SetupProjectRoutes sets up routes related to shared projects and their members
+func SetupProjectRoutes(
+	e *echo.Echo,
+	projectRepo repository.ProjectRepository,
+	userRepo repository.UserRepository,
+	permissions usecase.PermissionService,
+	authMiddleware *middleware.AuthMiddleware,
+	apiLimit echo.MiddlewareFunc,
+	workspace echo.MiddlewareFunc,
+	idempotent echo.MiddlewareFunc,
+) {
+	// Initialize project use case
+	projectUseCase := usecase.NewProjectUseCase(projectRepo, userRepo, permissions)
+
+	// Initialize project handler
+	projectHandler := handler.NewProjectHandler(projectUseCase)
+
+	// Define project routes
+	projectGroup := e.Group("/api/projects")
+
+	// Add authentication, per-user rate limiting, workspace scoping and idempotency keys to all project routes
+	projectGroup.Use(authMiddleware.Authenticate, apiLimit, workspace, idempotent)
+
+	// Routes
+	projectGroup.POST("", projectHandler.CreateProject)
+	projectGroup.GET("", projectHandler.GetProjects)
+	projectGroup.GET("/:id", projectHandler.GetProject)
+	projectGroup.PUT("/:id", projectHandler.UpdateProject)
+	projectGroup.DELETE("/:id", projectHandler.DeleteProject)
+	projectGroup.GET("/:id/members", projectHandler.GetMembers)
+	projectGroup.POST("/:id/members", projectHandler.AddMember)
+	projectGroup.PUT("/:id/members/:userId", projectHandler.UpdateMember)
+	projectGroup.DELETE("/:id/members/:userId", projectHandler.RemoveMember)
+}
@@ -2,41 +2,125 @@ package router
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
-	"gorm.io/gorm"
 
 	"todo-api/internal/config"
-	"todo-api/internal/infrastructure/repository/postgres"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/infrastructure/idempotency"
+	"todo-api/internal/infrastructure/metrics"
+	"todo-api/internal/infrastructure/notification"
+	"todo-api/internal/infrastructure/ratelimit"
+	"todo-api/internal/infrastructure/storage"
+	"todo-api/internal/infrastructure/tracing"
+	"todo-api/internal/infrastructure/webhook"
+	"todo-api/internal/interface/api/handler"
 	"todo-api/internal/interface/api/middleware"
 	"todo-api/internal/interface/api/validator"
 	"todo-api/internal/util/jwt"
 )
 
 // SetupRoutes sets up all routes for the application
-func SetupRoutes(e *echo.Echo, db *gorm.DB, cfg *config.Config, logger *logrus.Logger) {
+func SetupRoutes(
+	e *echo.Echo,
+	store *storage.Storage,
+	cfg *config.Config,
+	healthHandler *handler.HealthHandler,
+	registry *prometheus.Registry,
+	limiter ratelimit.Store,
+	keys idempotency.Store,
+	auditor usecase.Auditor,
+	streams usecase.TodoEventSource,
+	streamEvents usecase.TodoEventPublisher,
+	logger *logrus.Logger,
+) {
 	// Set custom validator
 	e.Validator = validator.NewCustomValidator()
 
+	// Render errors as problem details
+	e.HTTPErrorHandler = handler.ErrorHandler
+
+	// Record request metrics per route
+	e.Use(middleware.NewMetricsMiddleware(registry).Measure)
+
+	// Initialize domain metrics and notifications
+	domainMetrics := metrics.NewDomainMetrics(registry)
+	notifier := notification.NewLogNotifier()
+	if store.DB != nil {
+		if err := metrics.RegisterDBStats(registry, store.DB, store.Driver); err != nil {
+			logger.WithError(err).Error("Failed to register database metrics")
+		}
+	}
+
 	// Initialize JWT service
 	jwtService := jwt.NewJWTService(cfg.JWT.SecretKey, cfg.JWT.Expiration)
 
+	// Initialize the user use case, which also verifies the access tokens
+	userUseCase := tracing.NewUserUseCase(usecase.NewUserUseCase(store.UserRepo, jwtService, domainMetrics, auditor))
+
 	// Initialize auth middleware
-	authMiddleware := middleware.NewAuthMiddleware(jwtService)
+	authMiddleware := middleware.NewAuthMiddleware(userUseCase)
+
+	// Initialize rate limits, a nil store disables them
+	authLimit, apiLimit := noRateLimit, noRateLimit
+	if limiter != nil {
+		authLimit = middleware.NewRateLimitMiddleware(limiter, "auth", ratelimit.Rule{
+			Rate:  cfg.RateLimit.Auth.Rate,
+			Burst: cfg.RateLimit.Auth.Burst,
+		}).Limit
+		apiLimit = middleware.NewRateLimitMiddleware(limiter, "api", ratelimit.Rule{
+			Rate:  cfg.RateLimit.API.Rate,
+			Burst: cfg.RateLimit.API.Burst,
+		}).Limit
+	}
+
+	// Initialize idempotency keys, a nil store disables them
+	idempotent := noIdempotency
+	if keys != nil {
+		idempotent = middleware.NewIdempotencyMiddleware(keys, cfg.Idempotency.TTL, int64(cfg.Idempotency.MaxBodyBytes)).Handle
+	}
 
 	// Initialize repositories
-	userRepo := postgres.NewUserRepository(db)
-	todoRepo := postgres.NewTodoRepository(db)
+	userRepo := store.UserRepo
+	todoRepo := store.TodoRepo
+	viewRepo := store.ViewRepo
+	projectRepo := store.ProjectRepo
+	workspaceRepo := store.WorkspaceRepo
+	commentRepo := store.CommentRepo
+	activityRepo := store.ActivityRepo
+	auditRepo := store.AuditRepo
+	webhookRepo := store.WebhookRepo
+
+	// Initialize workspace scoping, selected by the X-Workspace-ID header
+	permissions := usecase.NewPermissionService(projectRepo, workspaceRepo)
+	workspace := middleware.NewWorkspaceMiddleware(permissions).Select
+
+	// Initialize the todo use case shared by the routes acting on todos. Its
+	// events are pushed to the real-time streams, while the todo repository
+	// adds them to the outbox of the webhook scheduler.
+	todoUseCase := tracing.NewTodoUseCase(usecase.NewTodoUseCase(todoRepo, permissions, domainMetrics, notifier, streamEvents))
 
 	// Set up routes
-	SetupUserRoutes(e, userRepo, jwtService, authMiddleware, logger)
-	SetupTodoRoutes(e, todoRepo, authMiddleware, logger)
+	SetupUserRoutes(e, userUseCase, authMiddleware, authLimit, apiLimit, idempotent)
+	SetupTodoRoutes(e, todoUseCase, authMiddleware, apiLimit, workspace, idempotent)
+	SetupViewRoutes(e, viewRepo, todoRepo, todoUseCase, authMiddleware, apiLimit, workspace, idempotent)
+	SetupCommentRoutes(e, commentRepo, userRepo, todoUseCase, permissions, authMiddleware, apiLimit, workspace, idempotent, notifier)
+	SetupActivityRoutes(e, activityRepo, todoUseCase, authMiddleware, apiLimit, workspace)
+	SetupProjectRoutes(e, projectRepo, userRepo, permissions, authMiddleware, apiLimit, workspace, idempotent)
+	SetupWorkspaceRoutes(e, workspaceRepo, userRepo, permissions, authMiddleware, apiLimit, idempotent)
+	SetupWebhookRoutes(e, webhookRepo, webhook.NewAddressPolicy(cfg.Webhooks.AllowPrivateNetworks), authMiddleware, apiLimit, workspace, idempotent)
+	SetupSyncRoutes(e, todoRepo, projectRepo, todoUseCase, authMiddleware, apiLimit, workspace, idempotent)
+	SetupStreamRoutes(e, streams, permissions, authMiddleware, apiLimit, workspace, cfg.Stream.HeartbeatInterval)
+	SetupAdminRoutes(e, auditRepo, authMiddleware, apiLimit, auditor, cfg.Audit.Admins)
 
-	// Set up health check route
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(200, map[string]string{
-			"status": "ok",
-		})
-	})
+	// Set up health check routes
+	SetupHealthRoutes(e, healthHandler)
+
+	// Set up metrics route, behind the metrics token when one is configured
+	if cfg.Metrics.Enabled {
+		e.GET("/metrics", echo.WrapHandler(promhttp.HandlerFor(registry, promhttp.HandlerOpts{})), middleware.RequireMetricsToken(cfg.Metrics.Token))
+	}
 
 	// Set up API version info
 	e.GET("/", func(c echo.Context) error {
@@ -46,3 +130,13 @@ func SetupRoutes(e *echo.Echo, db *gorm.DB, cfg *config.Config, logger *logrus.L
 		})
 	})
 }
+
+// noRateLimit is used in place of the rate limit middleware when rate limiting is disabled
+func noRateLimit(next echo.HandlerFunc) echo.HandlerFunc {
+	return next
+}
+
+// noIdempotency is used in place of the idempotency middleware when idempotency keys are disabled
+func noIdempotency(next echo.HandlerFunc) echo.HandlerFunc {
+	return next
+}
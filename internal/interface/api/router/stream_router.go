@@ -0,0 +1,39 @@
+package router
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/handler"
+	"todo-api/internal/interface/api/middleware"
+)
+
+// SetupStreamRoutes sets up the routes of the real-time todo event streams
+func SetupStreamRoutes(
+	e *echo.Echo,
+	source usecase.TodoEventSource,
+	permissions usecase.PermissionService,
+	authMiddleware *middleware.AuthMiddleware,
+	apiLimit echo.MiddlewareFunc,
+	workspace echo.MiddlewareFunc,
+	heartbeat time.Duration,
+) {
+	// Initialize stream use case, not traced as subscriptions outlive their call
+	streamUseCase := usecase.NewStreamUseCase(source, permissions)
+
+	// Initialize stream handler
+	streamHandler := handler.NewStreamHandler(streamUseCase, heartbeat)
+
+	// Define stream routes, which are read-only and need no idempotency keys
+	streamGroup := e.Group("/api/stream")
+
+	// Accept credentials in the query for browser clients, then add
+	// authentication, per-user rate limiting and workspace scoping
+	streamGroup.Use(middleware.QueryCredentials, authMiddleware.Authenticate, apiLimit, workspace)
+
+	// Routes
+	streamGroup.GET("", streamHandler.StreamEvents)
+	streamGroup.GET("/ws", streamHandler.StreamEventsWebSocket)
+}
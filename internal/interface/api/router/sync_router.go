@@ -0,0 +1,39 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/infrastructure/tracing"
+	"todo-api/internal/interface/api/handler"
+	"todo-api/internal/interface/api/middleware"
+)
+
+// SetupSyncRoutes sets up routes related to the delta sync of offline clients
+func SetupSyncRoutes(
+	e *echo.Echo,
+	todoRepo repository.TodoRepository,
+	projectRepo repository.ProjectRepository,
+	todoUseCase usecase.TodoUseCase,
+	authMiddleware *middleware.AuthMiddleware,
+	apiLimit echo.MiddlewareFunc,
+	workspace echo.MiddlewareFunc,
+	idempotent echo.MiddlewareFunc,
+) {
+	// Initialize sync use case, which applies mutations through the todo use case
+	syncUseCase := tracing.NewSyncUseCase(usecase.NewSyncUseCase(todoRepo, projectRepo, todoUseCase))
+
+	// Initialize sync handler
+	syncHandler := handler.NewSyncHandler(syncUseCase)
+
+	// Define sync routes
+	syncGroup := e.Group("/api/sync")
+
+	// Add authentication, per-user rate limiting, workspace scoping and idempotency keys to all sync routes
+	syncGroup.Use(authMiddleware.Authenticate, apiLimit, workspace, idempotent)
+
+	// Routes
+	syncGroup.GET("", syncHandler.GetChanges)
+	syncGroup.POST("", syncHandler.ApplyMutations)
+}
@@ -2,9 +2,7 @@ package router
 
 import (
 	"github.com/labstack/echo/v4"
-	"github.com/sirupsen/logrus"
 
-	"todo-api/internal/domain/repository"
 	"todo-api/internal/domain/usecase"
 	"todo-api/internal/interface/api/handler"
 	"todo-api/internal/interface/api/middleware"
@@ -13,27 +11,29 @@ import (
 // SetupTodoRoutes sets up routes related to todo operations
 func SetupTodoRoutes(
 	e *echo.Echo,
-	todoRepo repository.TodoRepository,
+	todoUseCase usecase.TodoUseCase,
 	authMiddleware *middleware.AuthMiddleware,
-	logger *logrus.Logger,
+	apiLimit echo.MiddlewareFunc,
+	workspace echo.MiddlewareFunc,
+	idempotent echo.MiddlewareFunc,
 ) {
-	// Initialize todo use case
-	todoUseCase := usecase.NewTodoUseCase(todoRepo)
-
 	// Initialize todo handler
-	todoHandler := handler.NewTodoHandler(todoUseCase, logger)
+	todoHandler := handler.NewTodoHandler(todoUseCase)
 
 	// Define todo routes
 	todoGroup := e.Group("/api/todos")
 	
-	// Add authentication middleware to all todo routes
-	todoGroup.Use(authMiddleware.Authenticate)
+	// Add authentication, per-user rate limiting, workspace scoping and idempotency keys to all todo routes
+	todoGroup.Use(authMiddleware.Authenticate, apiLimit, workspace, idempotent)
 
 	// Routes
 	todoGroup.POST("", todoHandler.CreateTodo)
 	todoGroup.GET("", todoHandler.GetTodos)
+	todoGroup.POST("/bulk", todoHandler.BulkUpdateTodos)
 	todoGroup.GET("/:id", todoHandler.GetTodo)
 	todoGroup.PUT("/:id", todoHandler.UpdateTodo)
 	todoGroup.DELETE("/:id", todoHandler.DeleteTodo)
 	todoGroup.PATCH("/:id/complete", todoHandler.CompleteTodo)
+	todoGroup.PUT("/:id/assignee", todoHandler.AssignTodo)
+	todoGroup.DELETE("/:id/assignee", todoHandler.UnassignTodo)
 }
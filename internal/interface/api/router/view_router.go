@@ -0,0 +1,42 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/handler"
+	"todo-api/internal/interface/api/middleware"
+)
+
+// SetupViewRoutes sets up routes related to saved views
+func SetupViewRoutes(
+	e *echo.Echo,
+	viewRepo repository.ViewRepository,
+	todoRepo repository.TodoRepository,
+	todoUseCase usecase.TodoUseCase,
+	authMiddleware *middleware.AuthMiddleware,
+	apiLimit echo.MiddlewareFunc,
+	workspace echo.MiddlewareFunc,
+	idempotent echo.MiddlewareFunc,
+) {
+	// Initialize view use case, which lists todos through the todo use case
+	viewUseCase := usecase.NewViewUseCase(viewRepo, todoRepo, todoUseCase)
+
+	// Initialize view handler
+	viewHandler := handler.NewViewHandler(viewUseCase)
+
+	// Define view routes
+	viewGroup := e.Group("/api/views")
+
+	// Add authentication, per-user rate limiting, workspace scoping and idempotency keys to all view routes
+	viewGroup.Use(authMiddleware.Authenticate, apiLimit, workspace, idempotent)
+
+	// Routes
+	viewGroup.POST("", viewHandler.CreateView)
+	viewGroup.GET("", viewHandler.GetViews)
+	viewGroup.GET("/:id", viewHandler.GetView)
+	viewGroup.PUT("/:id", viewHandler.UpdateView)
+	viewGroup.DELETE("/:id", viewHandler.DeleteView)
+	viewGroup.GET("/:id/todos", viewHandler.GetViewTodos)
+}
@@ -0,0 +1,42 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/handler"
+	"todo-api/internal/interface/api/middleware"
+)
+
+// SetupWebhookRoutes sets up routes related to webhooks and their deliveries
+func SetupWebhookRoutes(
+	e *echo.Echo,
+	webhookRepo repository.WebhookRepository,
+	hosts usecase.WebhookHostChecker,
+	authMiddleware *middleware.AuthMiddleware,
+	apiLimit echo.MiddlewareFunc,
+	workspace echo.MiddlewareFunc,
+	idempotent echo.MiddlewareFunc,
+) {
+	// Initialize webhook use case
+	webhookUseCase := usecase.NewWebhookUseCase(webhookRepo, hosts)
+
+	// Initialize webhook handler
+	webhookHandler := handler.NewWebhookHandler(webhookUseCase)
+
+	// Define webhook routes
+	webhookGroup := e.Group("/api/webhooks")
+
+	// Add authentication, per-user rate limiting, workspace scoping and idempotency keys to all webhook routes
+	webhookGroup.Use(authMiddleware.Authenticate, apiLimit, workspace, idempotent)
+
+	// Routes
+	webhookGroup.POST("", webhookHandler.CreateWebhook)
+	webhookGroup.GET("", webhookHandler.GetWebhooks)
+	webhookGroup.GET("/:id", webhookHandler.GetWebhook)
+	webhookGroup.PUT("/:id", webhookHandler.UpdateWebhook)
+	webhookGroup.DELETE("/:id", webhookHandler.DeleteWebhook)
+	webhookGroup.GET("/:id/deliveries", webhookHandler.GetDeliveries)
+	webhookGroup.POST("/:id/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver)
+}
@@ -0,0 +1,44 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"todo-api/internal/domain/repository"
+	"todo-api/internal/domain/usecase"
+	"todo-api/internal/interface/api/handler"
+	"todo-api/internal/interface/api/middleware"
+)
+
+// SetupWorkspaceRoutes sets up routes related to workspaces and their members
+func SetupWorkspaceRoutes(
+	e *echo.Echo,
+	workspaceRepo repository.WorkspaceRepository,
+	userRepo repository.UserRepository,
+	permissions usecase.PermissionService,
+	authMiddleware *middleware.AuthMiddleware,
+	apiLimit echo.MiddlewareFunc,
+	idempotent echo.MiddlewareFunc,
+) {
+	// Initialize workspace use case
+	workspaceUseCase := usecase.NewWorkspaceUseCase(workspaceRepo, userRepo, permissions)
+
+	// Initialize workspace handler
+	workspaceHandler := handler.NewWorkspaceHandler(workspaceUseCase)
+
+	// Define workspace routes
+	workspaceGroup := e.Group("/api/workspaces")
+
+	// Add authentication, per-user rate limiting and idempotency keys to all workspace routes
+	workspaceGroup.Use(authMiddleware.Authenticate, apiLimit, idempotent)
+
+	// Routes
+	workspaceGroup.POST("", workspaceHandler.CreateWorkspace)
+	workspaceGroup.GET("", workspaceHandler.GetWorkspaces)
+	workspaceGroup.GET("/:id", workspaceHandler.GetWorkspace)
+	workspaceGroup.PUT("/:id", workspaceHandler.UpdateWorkspace)
+	workspaceGroup.DELETE("/:id", workspaceHandler.DeleteWorkspace)
+	workspaceGroup.GET("/:id/members", workspaceHandler.GetMembers)
+	workspaceGroup.POST("/:id/members", workspaceHandler.AddMember)
+	workspaceGroup.PUT("/:id/members/:userId", workspaceHandler.UpdateMember)
+	workspaceGroup.DELETE("/:id/members/:userId", workspaceHandler.RemoveMember)
+}
@@ -1,6 +1,9 @@
 package validator
 
 import (
+	"reflect"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -11,8 +14,19 @@ type CustomValidator struct {
 
 // NewCustomValidator creates a new custom validator
 func NewCustomValidator() *CustomValidator {
+	v := validator.New()
+
+	// Report fields by their JSON names, as clients send them
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
 	return &CustomValidator{
-		validator: validator.New(),
+		validator: v,
 	}
 }
 
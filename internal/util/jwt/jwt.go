@@ -17,12 +17,16 @@ var (
 type Claims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
+
+	// TokenVersion is the token version of the user when the token was issued
+	TokenVersion uint `json:"ver"`
+
 	jwt.RegisteredClaims
 }
 
 // JWTService handles JWT operations
 type JWTService interface {
-	GenerateToken(userID uint, username string) (string, error)
+	GenerateToken(userID uint, username string, tokenVersion uint) (string, error)
 	ValidateToken(tokenString string) (*Claims, error)
 }
 
@@ -41,11 +45,12 @@ func NewJWTService(secretKey string, expiration time.Duration) JWTService {
 }
 
 // GenerateToken generates a new JWT token
-func (s *jwtService) GenerateToken(userID uint, username string) (string, error) {
+func (s *jwtService) GenerateToken(userID uint, username string, tokenVersion uint) (string, error) {
 	// Create claims
 	claims := &Claims{
-		UserID:   userID,
-		Username: username,
+		UserID:       userID,
+		Username:     username,
+		TokenVersion: tokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.expiration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// contextKey is the type of the context key holding the request-scoped logger
+type contextKey struct{}
+
+// defaultLogger is used when the context carries no logger
+var defaultLogger atomic.Pointer[logrus.Logger]
+
+// SetDefault sets the logger used when the context carries no request-scoped logger
+func SetDefault(logger *logrus.Logger) {
+	defaultLogger.Store(logger)
+}
+
+// WithLogger returns a copy of ctx carrying the logger entry
+func WithLogger(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, entry)
+}
+
+// FromContext returns the request-scoped logger carried by ctx,
+// or an entry of the default logger if there is none
+func FromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+	if logger := defaultLogger.Load(); logger != nil {
+		return logrus.NewEntry(logger)
+	}
+	return logrus.NewEntry(logrus.StandardLogger())
+}
+
+// WithFields returns a copy of ctx whose logger carries the additional fields
+func WithFields(ctx context.Context, fields logrus.Fields) context.Context {
+	return WithLogger(ctx, FromContext(ctx).WithFields(fields))
+}